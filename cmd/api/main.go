@@ -2,16 +2,23 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/igoventura/fintrack-api/domain"
 	"github.com/igoventura/fintrack-api/internal/api/handler"
 	"github.com/igoventura/fintrack-api/internal/api/middleware"
 	"github.com/igoventura/fintrack-api/internal/api/router"
 	"github.com/igoventura/fintrack-api/internal/auth"
 	"github.com/igoventura/fintrack-api/internal/db/postgres"
+	"github.com/igoventura/fintrack-api/internal/jobs"
 	"github.com/igoventura/fintrack-api/internal/service"
 	"github.com/joho/godotenv"
 )
@@ -33,11 +40,26 @@ import (
 // @host localhost:8080
 // @BasePath /
 func main() {
+	jobsOnly := flag.Bool("jobs-only", false, "Run the background jobs without serving the API")
+	flag.Parse()
+
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system environment variables")
 	}
 
-	ctx := context.Background()
+	// Cancelled on SIGINT/SIGTERM, which starts the shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Money JSON encoding: "string" (default, e.g. "1234.56") or "cents" (e.g. 123456)
+	switch format := os.Getenv("MONEY_JSON_FORMAT"); format {
+	case "", "string":
+		domain.SetMoneyEncoding(domain.MoneyEncodingString)
+	case "cents":
+		domain.SetMoneyEncoding(domain.MoneyEncodingCents)
+	default:
+		log.Fatalf("Invalid MONEY_JSON_FORMAT %q (expected \"string\" or \"cents\")", format)
+	}
 
 	// Database initialization
 	connStr := os.Getenv("DATABASE_URL")
@@ -53,11 +75,79 @@ func main() {
 
 	// Initialize Repositories
 	accountRepo := postgres.NewAccountRepository(db)
+	budgetRepo := postgres.NewBudgetRepository(db)
 	categoryRepo := postgres.NewCategoryRepository(db)
+	goalRepo := postgres.NewGoalRepository(db)
+	importRepo := postgres.NewImportRepository(db)
+	importProfileRepo := postgres.NewImportProfileRepository(db)
 	tagRepo := postgres.NewTagRepository(db)
 	userRepo := postgres.NewUserRepository(db)
 	tenantRepo := postgres.NewTenantRepository(db)
 	transactionRepo := postgres.NewTransactionRepository(db)
+	statementRepo := postgres.NewStatementRepository(db)
+	reportRepo := postgres.NewReportRepository(db)
+	invitationRepo := postgres.NewInvitationRepository(db)
+	recurrenceRepo := postgres.NewRecurrenceRuleRepository(db)
+	jobRunRepo := postgres.NewJobRunRepository(db)
+	idempotencyKeyRepo := postgres.NewIdempotencyKeyRepository(db)
+
+	// Initialize Services
+	accountService := service.NewAccountService(accountRepo)
+	budgetService := service.NewBudgetService(budgetRepo, categoryRepo, tagRepo)
+	categoryService := service.NewCategoryService(categoryRepo)
+	goalService := service.NewGoalService(goalRepo, accountRepo, tagRepo)
+	tagService := service.NewTagService(tagRepo)
+	statementService := service.NewStatementService(statementRepo, accountRepo)
+	reportService := service.NewReportService(reportRepo, accountRepo)
+	transactionService := service.NewTransactionService(transactionRepo, accountRepo, categoryRepo, tagRepo, db)
+	importService := service.NewImportService(importRepo, importProfileRepo, accountRepo, transactionService, db)
+	userService := service.NewUserService(userRepo)
+	// The tenant middleware caches memberships; the tenant service clears them when they change.
+	membershipCacheTTL := durationEnv("TENANT_MEMBERSHIP_CACHE_TTL", middleware.DefaultMembershipCacheTTL)
+	tenantMiddleware := middleware.NewTenantMiddleware(tenantRepo, userRepo, membershipCacheTTL)
+	tenantService := service.NewTenantService(tenantRepo, userService, tenantMiddleware)
+	invitationService := service.NewInvitationService(invitationRepo, userRepo, db)
+	recurrenceHorizon := domain.DefaultRecurrenceHorizon
+	if days := os.Getenv("RECURRENCE_HORIZON_DAYS"); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			log.Fatalf("Invalid RECURRENCE_HORIZON_DAYS: %q", days)
+		}
+		recurrenceHorizon = time.Duration(n) * 24 * time.Hour
+	}
+	recurrenceService := service.NewRecurrenceService(recurrenceRepo, accountRepo, categoryRepo, tagRepo, recurrenceHorizon)
+
+	// Background jobs
+	scheduler := jobs.NewScheduler(jobRunRepo)
+	for _, job := range []jobs.Job{
+		jobs.ExpireInvitations(invitationService),
+		jobs.MaterializeRecurrences(recurrenceService),
+		jobs.PurgeIdempotencyKeys(idempotencyKeyRepo),
+	} {
+		if err := scheduler.Register(job); err != nil {
+			log.Fatalf("Failed to register job: %v", err)
+		}
+	}
+
+	if *jobsOnly {
+		log.Println("Running background jobs only")
+		scheduler.Run(ctx)
+		log.Println("Background jobs stopped")
+		return
+	}
+
+	// In the API process, jobs are stopped only after the HTTP server has drained.
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobsDone := make(chan struct{})
+	if os.Getenv("JOBS_ENABLED") != "false" {
+		go func() {
+			defer close(jobsDone)
+			scheduler.Run(jobsCtx)
+		}()
+	} else {
+		close(jobsDone)
+	}
 
 	// Construct JWKS URL: https://<project-ref>.supabase.co/auth/v1/.well-known/jwks.json
 	projectRef := os.Getenv("SUPABASE_PROJECT_REF")
@@ -71,14 +161,6 @@ func main() {
 		log.Fatalf("Failed to initialize auth validator: %v", err)
 	}
 
-	// Initialize Services
-	accountService := service.NewAccountService(accountRepo)
-	categoryService := service.NewCategoryService(categoryRepo)
-	tagService := service.NewTagService(tagRepo)
-	transactionService := service.NewTransactionService(transactionRepo, accountRepo, categoryRepo, tagRepo)
-	userService := service.NewUserService(userRepo)
-	tenantService := service.NewTenantService(tenantRepo, userService)
-
 	// Auth Service (Supabase)
 	anonKey := os.Getenv("SUPABASE_ANON_KEY")
 	if anonKey == "" {
@@ -88,19 +170,37 @@ func main() {
 
 	// Initialize Handlers
 	accountHandler := handler.NewAccountHandler(accountService)
+	budgetHandler := handler.NewBudgetHandler(budgetService)
 	categoryHandler := handler.NewCategoryHandler(categoryService)
+	goalHandler := handler.NewGoalHandler(goalService)
+	importHandler := handler.NewImportHandler(importService)
 	tagHandler := handler.NewTagHandler(tagService)
+	statementHandler := handler.NewStatementHandler(statementService)
+	reportHandler := handler.NewReportHandler(reportService)
 	transactionHandler := handler.NewTransactionHandler(transactionService)
 	tenantHandler := handler.NewTenantHandler(tenantService)
+	invitationHandler := handler.NewInvitationHandler(invitationService)
+	recurrenceHandler := handler.NewRecurrenceHandler(recurrenceService)
 	authHandler := handler.NewAuthHandler(authService)
 	userHandler := handler.NewUserHandler(userService, authService)
+	adminHandler := handler.NewAdminHandler(scheduler)
+	healthHandler := handler.NewHealthHandler(db)
 
 	// Create Middleware
 	authMiddleware := middleware.NewAuthMiddleware(userRepo, authValidator)
-	tenantMiddleware := middleware.NewTenantMiddleware(tenantRepo)
+	idempotencyKeyTTL := durationEnv("IDEMPOTENCY_KEY_TTL", middleware.DefaultIdempotencyKeyTTL)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyKeyRepo, idempotencyKeyTTL)
+
+	// Operators allowed on the /admin endpoints (comma-separated user IDs)
+	var adminUserIDs []string
+	for _, id := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			adminUserIDs = append(adminUserIDs, id)
+		}
+	}
 
 	// Router setup
-	r := router.NewRouter(accountHandler, authHandler, categoryHandler, tagHandler, tenantHandler, transactionHandler, authMiddleware, tenantMiddleware, userHandler)
+	r := router.NewRouter(accountHandler, adminHandler, authHandler, budgetHandler, categoryHandler, goalHandler, healthHandler, importHandler, invitationHandler, recurrenceHandler, reportHandler, statementHandler, tagHandler, tenantHandler, transactionHandler, authMiddleware, tenantMiddleware, idempotencyMiddleware, userHandler, adminUserIDs)
 
 	// Server configuration
 	port := os.Getenv("PORT")
@@ -116,8 +216,63 @@ func main() {
 		IdleTimeout:  120 * time.Second,
 	}
 
-	log.Printf("Server starting on port %s", port)
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Server failed: %s", err)
+	// Shutdown timing: how long readiness reports draining before the listener closes,
+	// and how long requests in flight get to complete after that
+	shutdownDelay := durationEnv("SHUTDOWN_DELAY", 0)
+	drainTimeout := durationEnv("SHUTDOWN_DRAIN_TIMEOUT", 30*time.Second)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serverErr <- err
+		}
+	}()
+
+	failed := false
+	select {
+	case err := <-serverErr:
+		log.Printf("Server failed: %s", err)
+		failed = true
+	case <-ctx.Done():
+		log.Println("Shutdown signal received")
+	}
+	stop() // A second signal kills the process
+
+	// 1. Stop receiving traffic: readiness fails, then the listener closes
+	healthHandler.SetDraining()
+	time.Sleep(shutdownDelay)
+
+	// 2. Let requests in flight complete. Past the timeout their connections are closed,
+	// cancelling their contexts so open database transactions roll back instead of half-applying.
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancelDrain()
+	if err := srv.Shutdown(drainCtx); err != nil {
+		log.Printf("Server did not drain within %s: %v", drainTimeout, err)
+		srv.Close()
+	}
+
+	// 3. Stop the background jobs, letting the running one finish recording its outcome
+	stopJobs()
+	<-jobsDone
+
+	// 4. Close the database pool
+	db.Close()
+	log.Println("Server stopped")
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// durationEnv parses a duration environment variable (e.g. "30s"), returning def when it is not set.
+func durationEnv(name string, def time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		log.Fatalf("Invalid %s: %q", name, value)
 	}
+	return d
 }
@@ -8,7 +8,9 @@ import (
 )
 
 var (
-	ErrAccountNotFound = errors.New("account not found")
+	ErrAccountNotFound        = NewNotFoundError("account not found")
+	ErrCreditCardInfoNotFound = NewNotFoundError("credit card info not found")
+	ErrNotCreditCardAccount   = NewValidationError("account is not a credit card")
 )
 
 // AccountType represents the type of a financial account.
@@ -42,7 +44,7 @@ type Account struct {
 	ID             string      `json:"id"`
 	TenantID       string      `json:"tenant_id"`
 	Name           string      `json:"name"`
-	InitialBalance float64     `json:"initial_balance"`
+	InitialBalance Money       `json:"initial_balance"`
 	Color          string      `json:"color"`
 	Currency       string      `json:"currency"`
 	Icon           string      `json:"icon"`
@@ -51,8 +53,12 @@ type Account struct {
 	CreatedBy      string      `json:"created_by"`
 	UpdatedAt      time.Time   `json:"updated_at"`
 	UpdatedBy      string      `json:"updated_by"`
+	Version        int         `json:"version"` // Incremented on every update
 	DeactivatedAt  *time.Time  `json:"deactivated_at,omitempty"`
 	DeactivatedBy  *string     `json:"deactivated_by,omitempty"`
+
+	// CreditCard holds the current card details of credit card accounts, when set.
+	CreditCard *CreditCardInfo `json:"credit_card,omitempty"`
 }
 
 // CreditCardInfo represents detailed information for a credit card account.
@@ -72,16 +78,57 @@ type CreditCardInfo struct {
 	DeactivatedBy *string         `json:"deactivated_by,omitempty"`
 }
 
+// AccountBalance represents the balance of an account derived from its transactions.
+// Cleared only counts paid transactions, Current also counts unpaid transactions already due,
+// and Projected counts every unpaid transaction regardless of its due date.
+type AccountBalance struct {
+	AccountID      string    `json:"account_id"`
+	Currency       string    `json:"currency"`
+	InitialBalance Money     `json:"initial_balance"`
+	Current        Money     `json:"current"`
+	Cleared        Money     `json:"cleared"`
+	Projected      Money     `json:"projected"`
+	AsOf           time.Time `json:"as_of"`
+}
+
 // AccountRepository defines the interface for account persistence.
 type AccountRepository interface {
 	GetByID(ctx context.Context, id, tenantID string) (*Account, error)
-	List(ctx context.Context, tenantID string) ([]Account, error)
+	List(ctx context.Context, tenantID string, page PageRequest) (*Page[Account], error)
 	Create(ctx context.Context, acc *Account) error
+	// Update only applies to acc.Version when it is set (ErrVersionMismatch otherwise) and sets it to the new version.
 	Update(ctx context.Context, acc *Account) error
 	Delete(ctx context.Context, id, tenantID, userID string) error
 
-	GetCreditCardInfo(ctx context.Context, accountID string) (*CreditCardInfo, error)
+	// Balances
+	GetBalance(ctx context.Context, id, tenantID string, asOf time.Time) (*AccountBalance, error)
+	// ListBalances returns the balances of the given accounts of the tenant.
+	ListBalances(ctx context.Context, tenantID string, accountIDs []string, asOf time.Time) ([]AccountBalance, error)
+
+	// Credit card info. Each change creates a new version and deactivates the previous one.
+	GetCreditCardInfo(ctx context.Context, accountID, tenantID string) (*CreditCardInfo, error)
+	// ListCreditCardVersions returns every version of the card details of an account, deactivated ones
+	// included, oldest first.
+	ListCreditCardVersions(ctx context.Context, accountID, tenantID string) ([]CreditCardInfo, error)
+	ListCreditCardInfo(ctx context.Context, tenantID string) ([]CreditCardInfo, error)
 	UpsertCreditCardInfo(ctx context.Context, info *CreditCardInfo) error
+	DeleteCreditCardInfo(ctx context.Context, accountID, tenantID, userID string) error
+}
+
+// NewCreditCardDay returns the date stored for a closing or due day of month.
+// Only the day is meaningful; it is anchored to a 31-day month so every day is representable.
+func NewCreditCardDay(day int) time.Time {
+	return time.Date(2000, time.January, day, 0, 0, 0, 0, time.UTC)
+}
+
+// ClosingDay returns the day of month the card statement closes.
+func (cci *CreditCardInfo) ClosingDay() int {
+	return cci.ClosingDate.Day()
+}
+
+// DueDay returns the day of month the card statement is due.
+func (cci *CreditCardInfo) DueDay() int {
+	return cci.DueDate.Day()
 }
 
 func (a *Account) IsValid() (bool, map[string]error) {
@@ -92,7 +139,7 @@ func (a *Account) IsValid() (bool, map[string]error) {
 	if a.TenantID == "" {
 		err["tenant_id"] = errors.New("tenant_id is required")
 	}
-	if a.InitialBalance < 0 {
+	if a.InitialBalance.IsNegative() {
 		err["initial_balance"] = errors.New("initial_balance must be non-negative")
 	}
 	if a.Currency == "" {
@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	ErrBudgetNotFound     = NewNotFoundError("budget not found")
+	ErrBudgetCategoryType = InvalidField("category_id", "budgets apply to expense categories")
+)
+
+// Budget caps the spend of an expense category, subcategories included, or of a tag, in the
+// transactions of an accrual month. A one-off budget covers Month only; a recurring one covers every
+// month from Month on, up to EndMonth when set. Spend is debits minus refunds (credits) in expense
+// categories, in the currency of the budget.
+type Budget struct {
+	ID         string  `json:"id"`
+	TenantID   string  `json:"tenant_id"`
+	CategoryID *string `json:"category_id,omitempty"` // Either a category or a tag
+	TagID      *string `json:"tag_id,omitempty"`
+	Month      string  `json:"month"` // YYYYMM
+	EndMonth   *string `json:"end_month,omitempty"`
+	Recurring  bool    `json:"recurring"`
+	Currency   string  `json:"currency"`
+	Amount     Money   `json:"amount"`
+	// Rollover carries what a recurring budget left unspent, or overspent, into the next month.
+	Rollover bool `json:"rollover"`
+
+	CreatedAt     time.Time  `json:"created_at"`
+	CreatedBy     string     `json:"created_by"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	UpdatedBy     string     `json:"updated_by"`
+	Version       int        `json:"version"` // Incremented on every update
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	DeactivatedBy *string    `json:"deactivated_by,omitempty"`
+}
+
+// BudgetSpending is the spend counted against a budget in one month.
+type BudgetSpending struct {
+	BudgetID string
+	Month    string
+	Amount   Money
+}
+
+// BudgetStatus is the progress of a budget in a month: Available is the planned amount plus what
+// was carried over from the previous months, and Remaining is what is left of it.
+type BudgetStatus struct {
+	Budget      Budget   `json:"budget"`
+	Month       string   `json:"month"`
+	Planned     Money    `json:"planned"`
+	CarriedOver Money    `json:"carried_over"`
+	Available   Money    `json:"available"`
+	Actual      Money    `json:"actual"`
+	Remaining   Money    `json:"remaining"`
+	PercentUsed *float64 `json:"percent_used,omitempty"` // Actual over Available; nil when nothing is available
+}
+
+// BudgetRepository defines the interface for budget persistence.
+type BudgetRepository interface {
+	GetByID(ctx context.Context, id, tenantID string) (*Budget, error)
+	List(ctx context.Context, tenantID string, page PageRequest) (*Page[Budget], error)
+	// ListForMonth returns the budgets covering month.
+	ListForMonth(ctx context.Context, tenantID, month string) ([]Budget, error)
+	Create(ctx context.Context, budget *Budget) error
+	// Update only applies to budget.Version when it is set (ErrVersionMismatch otherwise) and sets it to the new version.
+	Update(ctx context.Context, budget *Budget) error
+	Delete(ctx context.Context, id, tenantID, userID string) error
+	// Spending returns the spend of each budget per month, up to month: from the budget's first
+	// month when it rolls over, or in month only otherwise. Months without spend are left out.
+	Spending(ctx context.Context, tenantID string, budgets []Budget, month string) ([]BudgetSpending, error)
+}
+
+func (b *Budget) IsValid() (bool, map[string]error) {
+	err := make(map[string]error)
+	if b.TenantID == "" {
+		err["tenant_id"] = errors.New("tenant_id is required")
+	}
+	if (b.CategoryID == nil) == (b.TagID == nil) {
+		err["category_id"] = errors.New("either category_id or tag_id is required")
+	}
+	if _, perr := ParsePeriod(b.Month); perr != nil {
+		err["month"] = errors.New("month must be YYYYMM")
+	}
+	if b.EndMonth != nil {
+		if !b.Recurring {
+			err["end_month"] = errors.New("end_month only applies to recurring budgets")
+		} else if _, perr := ParsePeriod(*b.EndMonth); perr != nil {
+			err["end_month"] = errors.New("end_month must be YYYYMM")
+		} else if *b.EndMonth < b.Month {
+			err["end_month"] = errors.New("end_month must not be before month")
+		}
+	}
+	if b.Rollover && !b.Recurring {
+		err["rollover"] = errors.New("rollover only applies to recurring budgets")
+	}
+	if len(b.Currency) != 3 {
+		err["currency"] = errors.New("currency must be a 3-letter ISO code")
+	}
+	if !b.Amount.IsPositive() {
+		err["amount"] = errors.New("amount must be greater than 0")
+	}
+	if len(err) == 0 {
+		return true, nil
+	}
+	return false, err
+}
+
+// Status returns the progress of the budget in month, given its spend per month. With rollover, the
+// balance of every earlier month (the amount, plus what it carried, minus its spend) carries over.
+func (b *Budget) Status(month string, spent map[string]Money) BudgetStatus {
+	carried := NewMoney(0, b.Currency)
+	if b.Rollover {
+		for m := b.Month; m < month; {
+			carried = carried.Add(b.Amount).Sub(spent[m].WithCurrency(b.Currency))
+			next, err := AddPeriodMonths(m, 1)
+			if err != nil {
+				break
+			}
+			m = next
+		}
+	}
+
+	s := BudgetStatus{
+		Budget:      *b,
+		Month:       month,
+		Planned:     b.Amount,
+		CarriedOver: carried,
+		Available:   b.Amount.Add(carried),
+		Actual:      spent[month].WithCurrency(b.Currency),
+	}
+	s.Remaining = s.Available.Sub(s.Actual)
+	if s.Available.IsPositive() {
+		percent := math.Round(float64(s.Actual.Cents)*10000/float64(s.Available.Cents)) / 100
+		s.PercentUsed = &percent
+	}
+	return s
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestBudget_IsValid(t *testing.T) {
+	categoryID, tagID, endMonth, badMonth := "cat-1", "tag-1", "202406", "202312"
+	valid := func() Budget {
+		return Budget{TenantID: "tenant-1", CategoryID: &categoryID, Month: "202401", Currency: "BRL", Amount: NewMoney(80000, "BRL")}
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(b *Budget)
+		wantField string
+	}{
+		{name: "one-off category budget", modify: func(b *Budget) {}},
+		{name: "recurring tag budget with rollover", modify: func(b *Budget) {
+			b.CategoryID, b.TagID, b.Recurring, b.Rollover, b.EndMonth = nil, &tagID, true, true, &endMonth
+		}},
+		{name: "category and tag", modify: func(b *Budget) { b.TagID = &tagID }, wantField: "category_id"},
+		{name: "neither category nor tag", modify: func(b *Budget) { b.CategoryID = nil }, wantField: "category_id"},
+		{name: "invalid month", modify: func(b *Budget) { b.Month = "2024-01" }, wantField: "month"},
+		{name: "end month of a one-off budget", modify: func(b *Budget) { b.EndMonth = &endMonth }, wantField: "end_month"},
+		{name: "end month before month", modify: func(b *Budget) { b.Recurring, b.EndMonth = true, &badMonth }, wantField: "end_month"},
+		{name: "rollover of a one-off budget", modify: func(b *Budget) { b.Rollover = true }, wantField: "rollover"},
+		{name: "zero amount", modify: func(b *Budget) { b.Amount = NewMoney(0, "BRL") }, wantField: "amount"},
+		{name: "missing currency", modify: func(b *Budget) { b.Currency = "" }, wantField: "currency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := valid()
+			tt.modify(&b)
+			ok, errs := b.IsValid()
+			if tt.wantField == "" {
+				if !ok {
+					t.Errorf("IsValid() = %v, want valid", errs)
+				}
+				return
+			}
+			if ok || errs[tt.wantField] == nil {
+				t.Errorf("IsValid() = %v, %v, want an error on %s", ok, errs, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestBudget_Status(t *testing.T) {
+	spent := map[string]Money{
+		"202401": NewMoney(60000, "BRL"),  // 200.00 left
+		"202402": NewMoney(110000, "BRL"), // 300.00 over
+		"202403": NewMoney(45000, "BRL"),
+	}
+
+	t.Run("without rollover", func(t *testing.T) {
+		b := Budget{Month: "202401", Recurring: true, Currency: "BRL", Amount: NewMoney(80000, "BRL")}
+		s := b.Status("202403", spent)
+		if !s.CarriedOver.IsZero() || s.Available != NewMoney(80000, "BRL") || s.Remaining != NewMoney(35000, "BRL") {
+			t.Errorf("Status() = %+v, want 350.00 of 800.00 remaining", s)
+		}
+		if s.PercentUsed == nil || *s.PercentUsed != 56.25 {
+			t.Errorf("PercentUsed = %v, want 56.25", s.PercentUsed)
+		}
+	})
+
+	t.Run("with rollover", func(t *testing.T) {
+		b := Budget{Month: "202401", Recurring: true, Rollover: true, Currency: "BRL", Amount: NewMoney(80000, "BRL")}
+		s := b.Status("202403", spent)
+		if s.CarriedOver != NewMoney(-10000, "BRL") || s.Available != NewMoney(70000, "BRL") || s.Remaining != NewMoney(25000, "BRL") {
+			t.Errorf("Status() = %+v, want -100.00 carried over and 250.00 remaining", s)
+		}
+	})
+
+	t.Run("nothing available", func(t *testing.T) {
+		b := Budget{Month: "202401", Recurring: true, Rollover: true, Currency: "BRL", Amount: NewMoney(10000, "BRL")}
+		s := b.Status("202403", spent)
+		if s.Available.IsPositive() || s.PercentUsed != nil {
+			t.Errorf("Status() = %+v, want no percentage when nothing is available", s)
+		}
+	})
+
+	t.Run("no spend", func(t *testing.T) {
+		b := Budget{Month: "202404", Currency: "BRL", Amount: NewMoney(80000, "BRL")}
+		s := b.Status("202404", nil)
+		if s.Actual != NewMoney(0, "BRL") || s.Remaining != NewMoney(80000, "BRL") || *s.PercentUsed != 0 {
+			t.Errorf("Status() = %+v, want the whole amount remaining", s)
+		}
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 )
 
+var ErrCategoryNotFound = NewNotFoundError("category not found")
+
 // CategoryType represents the type of a category.
 type CategoryType string
 
@@ -30,14 +32,16 @@ type Category struct {
 	CreatedBy        string       `json:"created_by"`
 	UpdatedAt        time.Time    `json:"updated_at"`
 	UpdatedBy        string       `json:"updated_by"`
+	Version          int          `json:"version"` // Incremented on every update
 	DeactivatedBy    *string      `json:"deactivated_by,omitempty"`
 }
 
 // CategoryRepository defines the interface for category persistence.
 type CategoryRepository interface {
 	GetByID(ctx context.Context, id, tenantID string) (*Category, error)
-	List(ctx context.Context, tenantID string) ([]Category, error)
+	List(ctx context.Context, tenantID string, page PageRequest) (*Page[Category], error)
 	Create(ctx context.Context, cat *Category) error
+	// Update only applies to cat.Version when it is set (ErrVersionMismatch otherwise) and sets it to the new version.
 	Update(ctx context.Context, cat *Category) error
 	Delete(ctx context.Context, id, tenantID, userID string) error
 }
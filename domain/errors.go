@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// The error types below classify failures so the API can map them to HTTP statuses without
+// knowing every sentinel. Sentinels are declared with them (e.g. ErrAccountNotFound is a
+// *NotFoundError): errors.Is matches the sentinel and errors.As its kind.
+
+// NotFoundError reports a resource that does not exist or does not belong to the tenant.
+type NotFoundError struct {
+	Message string
+}
+
+func NewNotFoundError(message string) *NotFoundError {
+	return &NotFoundError{Message: message}
+}
+
+func (e *NotFoundError) Error() string { return e.Message }
+
+// ValidationError reports invalid input. Fields holds a message per invalid field, keyed by its JSON name.
+type ValidationError struct {
+	Message string
+	Fields  map[string]string
+}
+
+func NewValidationError(message string) *ValidationError {
+	return &ValidationError{Message: message}
+}
+
+// InvalidField returns a validation error for a single field.
+func InvalidField(field, message string) *ValidationError {
+	return &ValidationError{Message: "validation failed", Fields: map[string]string{field: message}}
+}
+
+// InvalidFields returns the validation error of an IsValid result.
+func InvalidFields(errs map[string]error) *ValidationError {
+	fields := make(map[string]string, len(errs))
+	for field, err := range errs {
+		fields[field] = err.Error()
+	}
+	return &ValidationError{Message: "validation failed", Fields: fields}
+}
+
+// Error returns the message followed by the field messages, sorted by field.
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	messages := make([]string, len(names))
+	for i, name := range names {
+		messages[i] = e.Fields[name]
+	}
+	return e.Message + ": " + strings.Join(messages, "; ")
+}
+
+// ConflictError reports a request that conflicts with the current state of a resource,
+// such as a duplicate or an operation the resource no longer allows.
+type ConflictError struct {
+	Message string
+}
+
+func NewConflictError(message string) *ConflictError {
+	return &ConflictError{Message: message}
+}
+
+func (e *ConflictError) Error() string { return e.Message }
+
+// PreconditionFailedError reports a conditional request whose precondition does not hold,
+// such as an update of a version that is no longer current.
+type PreconditionFailedError struct {
+	Message string
+}
+
+func NewPreconditionFailedError(message string) *PreconditionFailedError {
+	return &PreconditionFailedError{Message: message}
+}
+
+func (e *PreconditionFailedError) Error() string { return e.Message }
+
+// ErrVersionMismatch is returned when an update requires a version of a resource that is no longer current.
+var ErrVersionMismatch = NewPreconditionFailedError("resource was modified since it was read")
+
+// ForbiddenError reports an operation the user is not allowed to perform.
+type ForbiddenError struct {
+	Message string
+}
+
+func NewForbiddenError(message string) *ForbiddenError {
+	return &ForbiddenError{Message: message}
+}
+
+func (e *ForbiddenError) Error() string { return e.Message }
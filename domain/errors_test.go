@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestInvalidFields(t *testing.T) {
+	err := InvalidFields(map[string]error{
+		"name":   errors.New("name is required"),
+		"amount": errors.New("amount must be greater than 0"),
+	})
+
+	if want := "validation failed: amount must be greater than 0; name is required"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if err.Fields["name"] != "name is required" {
+		t.Errorf("Fields[name] = %q, want %q", err.Fields["name"], "name is required")
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	wrapped := fmt.Errorf("service failed to get account: %w", ErrAccountNotFound)
+
+	var notFound *NotFoundError
+	if !errors.As(wrapped, &notFound) {
+		t.Fatalf("errors.As(%v) did not find a *NotFoundError", wrapped)
+	}
+	if !errors.Is(wrapped, ErrAccountNotFound) {
+		t.Errorf("errors.Is(%v, ErrAccountNotFound) = false", wrapped)
+	}
+	if errors.Is(wrapped, ErrCategoryNotFound) {
+		t.Errorf("errors.Is(%v, ErrCategoryNotFound) = true", wrapped)
+	}
+
+	var validation *ValidationError
+	if err := fmt.Errorf("%w: more than 2 decimal places", ErrInvalidMoney); !errors.As(err, &validation) {
+		t.Errorf("errors.As(%v) did not find a *ValidationError", err)
+	}
+}
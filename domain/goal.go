@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"time"
+)
+
+// goalAverageMonths is the number of complete months the average monthly contribution is taken over.
+const goalAverageMonths = 6
+
+var (
+	ErrGoalNotFound        = NewNotFoundError("goal not found")
+	ErrGoalAccountCurrency = InvalidField("account_ids", "accounts must be in the currency of the goal")
+)
+
+// Goal is a savings target. The money saved towards it is either the balance of its linked accounts,
+// so transfers into them count automatically, or the net of the transactions tagged with its tag:
+// tagged debits and outgoing transfers set money aside and tagged credits take it back.
+type Goal struct {
+	ID           string     `json:"id"`
+	TenantID     string     `json:"tenant_id"`
+	Name         string     `json:"name"`
+	Currency     string     `json:"currency"`
+	TargetAmount Money      `json:"target_amount"`
+	TargetDate   *time.Time `json:"target_date,omitempty"`
+	AccountIDs   []string   `json:"account_ids,omitempty"` // Either linked accounts or a tag
+	TagID        *string    `json:"tag_id,omitempty"`
+
+	CreatedAt     time.Time  `json:"created_at"`
+	CreatedBy     string     `json:"created_by"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	UpdatedBy     string     `json:"updated_by"`
+	Version       int        `json:"version"` // Incremented on every update
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	DeactivatedBy *string    `json:"deactivated_by,omitempty"`
+}
+
+// GoalContribution is the net amount a goal received in a month (YYYYMM), by payment date.
+type GoalContribution struct {
+	Month  string `json:"month"`
+	Amount Money  `json:"amount"`
+}
+
+// GoalProgress is how far a goal is from its target as of a date, and when it is projected to be
+// reached at the average monthly contribution of the last complete months.
+type GoalProgress struct {
+	Goal            Goal      `json:"goal"`
+	AsOf            time.Time `json:"as_of"`
+	Saved           Money     `json:"saved"`
+	Remaining       Money     `json:"remaining"` // Zero once the target is reached
+	PercentComplete float64   `json:"percent_complete"`
+	Achieved        bool      `json:"achieved"`
+	// AverageMonthlyContribution is nil until the goal has a complete month of history.
+	AverageMonthlyContribution *Money `json:"average_monthly_contribution,omitempty"`
+	// ProjectedDate is the end of the month the target is reached in at that average; nil when
+	// the goal is achieved or the average is not positive.
+	ProjectedDate *time.Time `json:"projected_date,omitempty"`
+	// RequiredMonthlyContribution reaches the target by TargetDate, from the current month on.
+	RequiredMonthlyContribution *Money `json:"required_monthly_contribution,omitempty"`
+	OnTrack                     *bool  `json:"on_track,omitempty"` // Only set with a target date
+}
+
+// GoalRepository defines the interface for goal persistence.
+type GoalRepository interface {
+	GetByID(ctx context.Context, id, tenantID string) (*Goal, error)
+	List(ctx context.Context, tenantID string, page PageRequest) (*Page[Goal], error)
+	// Create and Update save the linked accounts of the goal with it.
+	Create(ctx context.Context, goal *Goal) error
+	// Update only applies to goal.Version when it is set (ErrVersionMismatch otherwise) and sets it to the new version.
+	Update(ctx context.Context, goal *Goal) error
+	Delete(ctx context.Context, id, tenantID, userID string) error
+	// Contributions returns the net contributions to the goal per month, paid up to asOf, in
+	// ascending order. Months without contributions are left out.
+	Contributions(ctx context.Context, goal *Goal, asOf time.Time) ([]GoalContribution, error)
+}
+
+func (g *Goal) IsValid() (bool, map[string]error) {
+	err := make(map[string]error)
+	if g.TenantID == "" {
+		err["tenant_id"] = errors.New("tenant_id is required")
+	}
+	if g.Name == "" {
+		err["name"] = errors.New("name is required")
+	} else if len(g.Name) > 255 {
+		err["name"] = errors.New("name must not exceed 255 characters")
+	}
+	if len(g.Currency) != 3 {
+		err["currency"] = errors.New("currency must be a 3-letter ISO code")
+	}
+	if !g.TargetAmount.IsPositive() {
+		err["target_amount"] = errors.New("target_amount must be greater than 0")
+	}
+	if (len(g.AccountIDs) == 0) == (g.TagID == nil) {
+		err["account_ids"] = errors.New("either account_ids or tag_id is required")
+	} else if len(slices.Compact(slices.Sorted(slices.Values(g.AccountIDs)))) != len(g.AccountIDs) {
+		err["account_ids"] = errors.New("account_ids must not repeat an account")
+	}
+	if len(err) == 0 {
+		return true, nil
+	}
+	return false, err
+}
+
+// Progress computes the progress of the goal as of asOf, given the amount saved and its monthly
+// contributions. The average monthly contribution is taken over the goalAverageMonths complete
+// months before asOf, leaving out those before the first contribution.
+func (g *Goal) Progress(saved Money, contributions []GoalContribution, asOf time.Time) GoalProgress {
+	saved = saved.WithCurrency(g.Currency)
+	p := GoalProgress{
+		Goal:      *g,
+		AsOf:      asOf,
+		Saved:     saved,
+		Remaining: g.TargetAmount.Sub(saved),
+		Achieved:  saved.Cmp(g.TargetAmount) >= 0,
+	}
+	p.PercentComplete = math.Round(float64(saved.Cents)*10000/float64(g.TargetAmount.Cents)) / 100
+	if p.Achieved {
+		p.Remaining = NewMoney(0, g.Currency)
+	}
+
+	month := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if average, ok := averageContribution(contributions, month); ok {
+		avg := NewMoney(average, g.Currency)
+		p.AverageMonthlyContribution = &avg
+		if !p.Achieved && avg.IsPositive() {
+			months := ceilDiv(p.Remaining.Cents, avg.Cents)
+			projected := month.AddDate(0, int(months)+1, -1)
+			p.ProjectedDate = &projected
+		}
+	}
+
+	if g.TargetDate != nil {
+		onTrack := p.Achieved
+		target := time.Date(g.TargetDate.Year(), g.TargetDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if !p.Achieved && !target.Before(month) {
+			left := int64((target.Year()-month.Year())*12+int(target.Month()-month.Month())) + 1
+			required := NewMoney(ceilDiv(p.Remaining.Cents, left), g.Currency)
+			p.RequiredMonthlyContribution = &required
+			onTrack = p.ProjectedDate != nil && !p.ProjectedDate.After(target.AddDate(0, 1, -1))
+		}
+		p.OnTrack = &onTrack
+	}
+	return p
+}
+
+// averageContribution returns the average of the contributions over the complete months before
+// month, rounded to the cent, and false when there is no such month since the first contribution.
+func averageContribution(contributions []GoalContribution, month time.Time) (int64, bool) {
+	if len(contributions) == 0 {
+		return 0, false
+	}
+	from := month.AddDate(0, -goalAverageMonths, 0).Format("200601")
+	if first := contributions[0].Month; first > from {
+		from = first
+	}
+	to := month.AddDate(0, -1, 0).Format("200601")
+	if from > to {
+		return 0, false
+	}
+
+	var total int64
+	for _, c := range contributions {
+		if c.Month >= from && c.Month <= to {
+			total += c.Amount.Cents
+		}
+	}
+	start, _ := ParsePeriod(from)
+	months := (month.Year()-start.Year())*12 + int(month.Month()-start.Month())
+	return int64(math.Round(float64(total) / float64(months))), true
+}
+
+// ceilDiv divides a by the positive b, rounding up.
+func ceilDiv(a, b int64) int64 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGoal_IsValid(t *testing.T) {
+	tagID := "tag-1"
+	valid := func() Goal {
+		return Goal{TenantID: "tenant-1", Name: "Emergency fund", Currency: "BRL", TargetAmount: NewMoney(1000000, "BRL"), AccountIDs: []string{"acc-1", "acc-2"}}
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(g *Goal)
+		wantField string
+	}{
+		{name: "account goal", modify: func(g *Goal) {}},
+		{name: "tag goal", modify: func(g *Goal) { g.AccountIDs, g.TagID = nil, &tagID }},
+		{name: "accounts and tag", modify: func(g *Goal) { g.TagID = &tagID }, wantField: "account_ids"},
+		{name: "neither accounts nor tag", modify: func(g *Goal) { g.AccountIDs = nil }, wantField: "account_ids"},
+		{name: "repeated account", modify: func(g *Goal) { g.AccountIDs = []string{"acc-1", "acc-1"} }, wantField: "account_ids"},
+		{name: "missing name", modify: func(g *Goal) { g.Name = "" }, wantField: "name"},
+		{name: "zero target", modify: func(g *Goal) { g.TargetAmount = NewMoney(0, "BRL") }, wantField: "target_amount"},
+		{name: "missing currency", modify: func(g *Goal) { g.Currency = "" }, wantField: "currency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := valid()
+			tt.modify(&g)
+			ok, errs := g.IsValid()
+			if tt.wantField == "" {
+				if !ok {
+					t.Errorf("IsValid() = %v, want valid", errs)
+				}
+				return
+			}
+			if ok || errs[tt.wantField] == nil {
+				t.Errorf("IsValid() = %v, %v, want an error on %s", ok, errs, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestGoal_Progress(t *testing.T) {
+	asOf := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	date := func(y int, m time.Month, d int) *time.Time {
+		v := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	contributions := []GoalContribution{
+		{Month: "202403", Amount: NewMoney(100000, "BRL")},
+		{Month: "202404", Amount: NewMoney(50000, "BRL")},
+		{Month: "202405", Amount: NewMoney(150000, "BRL")},
+		{Month: "202406", Amount: NewMoney(900000, "BRL")}, // Incomplete month, left out of the average
+	}
+
+	t.Run("projected from the months since the first contribution", func(t *testing.T) {
+		g := Goal{Currency: "BRL", TargetAmount: NewMoney(1000000, "BRL"), TargetDate: date(2024, 12, 31)}
+		p := g.Progress(NewMoney(400000, "BRL"), contributions, asOf)
+		if p.Remaining != NewMoney(600000, "BRL") || p.PercentComplete != 40 || p.Achieved {
+			t.Errorf("Progress() = %+v, want 6000.00 remaining and 40%%", p)
+		}
+		if p.AverageMonthlyContribution == nil || *p.AverageMonthlyContribution != NewMoney(100000, "BRL") {
+			t.Fatalf("AverageMonthlyContribution = %v, want 1000.00", p.AverageMonthlyContribution)
+		}
+		if p.ProjectedDate == nil || !p.ProjectedDate.Equal(*date(2024, 12, 31)) {
+			t.Errorf("ProjectedDate = %v, want 2024-12-31", p.ProjectedDate)
+		}
+		if p.RequiredMonthlyContribution == nil || *p.RequiredMonthlyContribution != NewMoney(85715, "BRL") {
+			t.Errorf("RequiredMonthlyContribution = %v, want 857.15", p.RequiredMonthlyContribution)
+		}
+		if p.OnTrack == nil || !*p.OnTrack {
+			t.Errorf("OnTrack = %v, want true", p.OnTrack)
+		}
+	})
+
+	t.Run("behind the target date", func(t *testing.T) {
+		g := Goal{Currency: "BRL", TargetAmount: NewMoney(1000000, "BRL"), TargetDate: date(2024, 9, 1)}
+		p := g.Progress(NewMoney(400000, "BRL"), contributions, asOf)
+		if p.OnTrack == nil || *p.OnTrack {
+			t.Errorf("OnTrack = %v, want false", p.OnTrack)
+		}
+	})
+
+	t.Run("achieved", func(t *testing.T) {
+		g := Goal{Currency: "BRL", TargetAmount: NewMoney(300000, "BRL"), TargetDate: date(2024, 1, 1)}
+		p := g.Progress(NewMoney(400000, "BRL"), contributions, asOf)
+		if !p.Achieved || !p.Remaining.IsZero() || p.PercentComplete != 133.33 {
+			t.Errorf("Progress() = %+v, want achieved", p)
+		}
+		if p.ProjectedDate != nil || p.RequiredMonthlyContribution != nil || p.OnTrack == nil || !*p.OnTrack {
+			t.Errorf("Progress() = %+v, want no projection and on track", p)
+		}
+	})
+
+	t.Run("without history", func(t *testing.T) {
+		g := Goal{Currency: "BRL", TargetAmount: NewMoney(300000, "BRL")}
+		p := g.Progress(NewMoney(90000, "BRL"), contributions[3:], asOf)
+		if p.AverageMonthlyContribution != nil || p.ProjectedDate != nil || p.OnTrack != nil {
+			t.Errorf("Progress() = %+v, want no projection", p)
+		}
+	})
+
+	t.Run("withdrawals", func(t *testing.T) {
+		g := Goal{Currency: "BRL", TargetAmount: NewMoney(300000, "BRL")}
+		p := g.Progress(NewMoney(90000, "BRL"), []GoalContribution{{Month: "202405", Amount: NewMoney(-20000, "BRL")}}, asOf)
+		if p.AverageMonthlyContribution == nil || !p.AverageMonthlyContribution.IsNegative() || p.ProjectedDate != nil {
+			t.Errorf("Progress() = %+v, want a negative average and no projection", p)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyReused     = NewConflictError("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = NewConflictError("a request with this idempotency key is still in progress")
+)
+
+// IdempotencyKey records a request sent with an Idempotency-Key header and, once it completed,
+// the response replayed to repeats of the request. Keys are scoped to a tenant and user.
+type IdempotencyKey struct {
+	ID           string
+	TenantID     string // Empty for requests outside a tenant, e.g. creating one
+	UserID       string
+	Key          string
+	RequestHash  string // SHA-256 of the method, path and body
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+	CompletedAt  *time.Time // Nil while the request is in progress
+	ExpiresAt    time.Time
+}
+
+// Completed reports whether the response of the request was recorded.
+func (k *IdempotencyKey) Completed() bool {
+	return k.CompletedAt != nil
+}
+
+// IdempotencyKeyRepository defines the interface for idempotency key persistence.
+type IdempotencyKeyRepository interface {
+	// Reserve records key as in progress, as of key.CreatedAt. When the tenant and user already have a
+	// live record for the key it is returned instead and nothing is reserved. Expired records, and
+	// reservations still in progress since before staleBefore, are taken over.
+	Reserve(ctx context.Context, key *IdempotencyKey, staleBefore time.Time) (*IdempotencyKey, error)
+	// Complete records the response of a reserved key.
+	Complete(ctx context.Context, key *IdempotencyKey) error
+	// Release deletes a reservation so the request can be retried.
+	Release(ctx context.Context, key *IdempotencyKey) error
+	// DeleteExpired deletes the keys expired at now and returns how many were deleted.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
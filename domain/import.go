@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrImportNotFound        = NewNotFoundError("import not found")
+	ErrImportCommitted       = NewConflictError("the import is already committed")
+	ErrImportAlreadyImported = NewConflictError("every transaction in the file was already imported")
+	ErrImportRowImported     = NewConflictError("a selected row was already imported on the account")
+)
+
+// ImportFormat is the format of an imported file.
+type ImportFormat string
+
+const (
+	ImportFormatCSV ImportFormat = "csv" // Read with an ImportProfile
+	ImportFormatOFX ImportFormat = "ofx" // OFX 1.x (SGML) or 2.x (XML)
+)
+
+// ImportStatus represents the lifecycle state of an import.
+type ImportStatus string
+
+const (
+	ImportStatusStaged    ImportStatus = "staged"    // Parsed and waiting for review
+	ImportStatusCommitted ImportStatus = "committed" // The selected rows were turned into transactions
+)
+
+// Import is a statement of an account, parsed from a CSV file with a profile or from an OFX file.
+// Its rows are staged for review, with duplicate candidates and suggested categories, until the
+// selected ones are committed.
+type Import struct {
+	ID          string       `json:"id"`
+	TenantID    string       `json:"tenant_id"`
+	AccountID   string       `json:"account_id"`
+	Format      ImportFormat `json:"format"`
+	ProfileID   *string      `json:"profile_id,omitempty"` // CSV only
+	FileName    string       `json:"file_name"`
+	Currency    string       `json:"currency"` // Of the account
+	Status      ImportStatus `json:"status"`
+	CommittedAt *time.Time   `json:"committed_at,omitempty"`
+	Rows        []ImportRow  `json:"rows"`
+	// AlreadyImported counts the transactions of the file left out because their ExternalID was
+	// committed on the account before.
+	AlreadyImported int `json:"already_imported"`
+	// LedgerBalance is the balance the institution reports for the account on LedgerBalanceDate (OFX only).
+	LedgerBalance     *Money     `json:"ledger_balance,omitempty"`
+	LedgerBalanceDate *time.Time `json:"ledger_balance_date,omitempty"`
+	// ComputedBalance is the cleared balance of the account on LedgerBalanceDate, to compare with
+	// LedgerBalance. Attached by the service; not a column.
+	ComputedBalance *Money `json:"computed_balance,omitempty"`
+
+	CreatedAt     time.Time  `json:"created_at"`
+	CreatedBy     string     `json:"created_by"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	UpdatedBy     string     `json:"updated_by"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	DeactivatedBy *string    `json:"deactivated_by,omitempty"`
+}
+
+// ImportRow is a row of an imported file. Rows that could not be read only have their Line and Error.
+type ImportRow struct {
+	ID              string          `json:"id"`
+	ImportID        string          `json:"import_id"`
+	Line            int             `json:"line"`                  // In the file, from 1; for OFX, the position of the transaction
+	ExternalID      *string         `json:"external_id,omitempty"` // FITID of OFX transactions
+	Date            *time.Time      `json:"date,omitempty"`
+	Description     string          `json:"description"`
+	Amount          Money           `json:"amount"` // Always positive; TransactionType carries the sign
+	TransactionType TransactionType `json:"transaction_type,omitempty"`
+	Error           *string         `json:"error,omitempty"`
+	// DuplicateTransactionID is an existing transaction of the account with the same amount and
+	// direction, dated within ImportDuplicateDays of the row.
+	DuplicateTransactionID *string `json:"duplicate_transaction_id,omitempty"`
+	// SuggestedCategoryID is the category used most often for transactions with the same description.
+	SuggestedCategoryID *string `json:"suggested_category_id,omitempty"`
+	TransactionID       *string `json:"transaction_id,omitempty"` // Created from the row on commit
+}
+
+// ImportDuplicateDays is how many days apart a row and an existing transaction can be to be duplicates.
+const ImportDuplicateDays = 3
+
+// ImportSelection is a staged row to commit, optionally with the category to use instead of the suggested one.
+type ImportSelection struct {
+	RowID      string
+	CategoryID *string
+}
+
+// ImportRepository defines the interface for import persistence.
+type ImportRepository interface {
+	// GetByID returns the import with its rows, ordered by line.
+	GetByID(ctx context.Context, id, tenantID string) (*Import, error)
+	// Create saves the import with its rows.
+	Create(ctx context.Context, imp *Import) error
+	// MarkCommitted saves the transactions created from the rows of a staged import and marks it
+	// committed, or returns ErrImportCommitted when it no longer is staged and ErrImportRowImported
+	// when the external ID of a row is committed on the account already.
+	MarkCommitted(ctx context.Context, imp *Import) error
+	Delete(ctx context.Context, id, tenantID, userID string) error
+	// FindDuplicates returns, by line, the existing transaction of the account each parsed row may duplicate.
+	FindDuplicates(ctx context.Context, tenantID, accountID string, rows []ImportRow) (map[int]string, error)
+	// SuggestCategories returns, by line, the category most used by the transactions of the same
+	// type whose comments match the description of each parsed row (see NormalizeImportDescription).
+	SuggestCategories(ctx context.Context, tenantID string, rows []ImportRow) (map[int]string, error)
+	// ImportedExternalIDs returns, by external ID, the active transaction created from a committed
+	// row of the account with that external ID.
+	ImportedExternalIDs(ctx context.Context, tenantID, accountID string, externalIDs []string) (map[string]string, error)
+	// LockAccount locks the account until the end of the database transaction, so that imports into
+	// it are checked against ImportedExternalIDs and committed one at a time.
+	LockAccount(ctx context.Context, tenantID, accountID string) error
+}
+
+var (
+	descriptionDigits = regexp.MustCompile(`[0-9]+`)
+	descriptionSpaces = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeImportDescription lowercases a description and drops its digits (dates, document and
+// card numbers), so that "PIX 0412 MERCADO SAO JOSE" and "Pix 1207 Mercado Sao Jose" match.
+func NormalizeImportDescription(description string) string {
+	s := descriptionDigits.ReplaceAllString(strings.ToLower(description), "")
+	return strings.TrimSpace(descriptionSpaces.ReplaceAllString(s, " "))
+}
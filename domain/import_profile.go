@@ -0,0 +1,322 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxImportRows is the number of rows a file can have.
+const MaxImportRows = 5000
+
+var ErrImportProfileNotFound = NewNotFoundError("import profile not found")
+
+// ImportSignConvention tells credits from debits in a statement.
+type ImportSignConvention string
+
+const (
+	// ImportSignNegativeDebit reads negative amounts as debits, as in bank account statements.
+	ImportSignNegativeDebit ImportSignConvention = "negative_debit"
+	// ImportSignPositiveDebit reads positive amounts as debits, as in credit card statements.
+	ImportSignPositiveDebit ImportSignConvention = "positive_debit"
+	// ImportSignSplitColumns reads debits and credits from separate columns.
+	ImportSignSplitColumns ImportSignConvention = "split_columns"
+)
+
+// ImportField is a value read from each row of a statement.
+type ImportField string
+
+const (
+	ImportFieldDate        ImportField = "date"
+	ImportFieldDescription ImportField = "description"
+	ImportFieldAmount      ImportField = "amount" // Signed, except with ImportSignSplitColumns
+	ImportFieldDebit       ImportField = "debit"  // ImportSignSplitColumns only
+	ImportFieldCredit      ImportField = "credit" // ImportSignSplitColumns only
+)
+
+// ImportProfile describes how the CSV statements of a bank are read.
+type ImportProfile struct {
+	ID        string `json:"id"`
+	TenantID  string `json:"tenant_id"`
+	Name      string `json:"name"`
+	Delimiter string `json:"delimiter"` // One character; "\t" for tab-separated files
+	HasHeader bool   `json:"has_header"`
+	SkipRows  int    `json:"skip_rows"` // Lines skipped before the header, or the first row without one
+	// DateFormat is made of dd, mm, yy or yyyy (d and m when not zero-padded) and separators, e.g. dd/mm/yyyy.
+	DateFormat       string               `json:"date_format"`
+	DecimalSeparator string               `json:"decimal_separator"` // "," or "."; the other one separates thousands
+	SignConvention   ImportSignConvention `json:"sign_convention"`
+	// Columns maps each field to a header name (case-insensitive) or a 1-based column number.
+	Columns map[ImportField]string `json:"columns"`
+
+	CreatedAt     time.Time  `json:"created_at"`
+	CreatedBy     string     `json:"created_by"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	UpdatedBy     string     `json:"updated_by"`
+	Version       int        `json:"version"` // Incremented on every update
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	DeactivatedBy *string    `json:"deactivated_by,omitempty"`
+}
+
+// ImportProfileRepository defines the interface for import profile persistence.
+type ImportProfileRepository interface {
+	GetByID(ctx context.Context, id, tenantID string) (*ImportProfile, error)
+	List(ctx context.Context, tenantID string, page PageRequest) (*Page[ImportProfile], error)
+	Create(ctx context.Context, profile *ImportProfile) error
+	// Update only applies to profile.Version when it is set (ErrVersionMismatch otherwise) and sets it to the new version.
+	Update(ctx context.Context, profile *ImportProfile) error
+	Delete(ctx context.Context, id, tenantID, userID string) error
+}
+
+func (p *ImportProfile) IsValid() (bool, map[string]error) {
+	err := make(map[string]error)
+	if p.TenantID == "" {
+		err["tenant_id"] = errors.New("tenant_id is required")
+	}
+	if p.Name == "" {
+		err["name"] = errors.New("name is required")
+	} else if len(p.Name) > 255 {
+		err["name"] = errors.New("name must not exceed 255 characters")
+	}
+	if len(p.Delimiter) != 1 {
+		err["delimiter"] = errors.New("delimiter must be a single character")
+	}
+	if p.SkipRows < 0 {
+		err["skip_rows"] = errors.New("skip_rows must not be negative")
+	}
+	if _, lerr := p.dateLayout(); lerr != nil {
+		err["date_format"] = lerr
+	}
+	if p.DecimalSeparator != "," && p.DecimalSeparator != "." {
+		err["decimal_separator"] = errors.New("decimal_separator must be \",\" or \".\"")
+	}
+
+	required := []ImportField{ImportFieldDate, ImportFieldAmount}
+	switch p.SignConvention {
+	case ImportSignNegativeDebit, ImportSignPositiveDebit:
+	case ImportSignSplitColumns:
+		required = []ImportField{ImportFieldDate, ImportFieldDebit, ImportFieldCredit}
+	default:
+		err["sign_convention"] = errors.New("invalid sign convention")
+	}
+	known := []ImportField{ImportFieldDate, ImportFieldDescription, ImportFieldAmount, ImportFieldDebit, ImportFieldCredit}
+	for field, column := range p.Columns {
+		if !slices.Contains(known, field) {
+			err["columns"] = fmt.Errorf("unknown field %q", field)
+		} else if strings.TrimSpace(column) == "" {
+			err["columns"] = fmt.Errorf("the column of %s is required", field)
+		}
+	}
+	for _, field := range required {
+		if _, ok := p.Columns[field]; !ok && err["columns"] == nil {
+			err["columns"] = fmt.Errorf("the column of %s is required", field)
+		}
+	}
+	if len(err) == 0 {
+		return true, nil
+	}
+	return false, err
+}
+
+// dateLayout translates DateFormat into a time layout.
+func (p *ImportProfile) dateLayout() (string, error) {
+	tokens := []struct{ format, layout string }{
+		{"yyyy", "2006"}, {"yy", "06"}, {"mm", "01"}, {"dd", "02"}, {"m", "1"}, {"d", "2"},
+	}
+	var layout strings.Builder
+	var year, month, day bool
+	for rest := strings.ToLower(p.DateFormat); rest != ""; {
+		matched := false
+		for _, tok := range tokens {
+			if strings.HasPrefix(rest, tok.format) {
+				layout.WriteString(tok.layout)
+				year = year || tok.format[0] == 'y'
+				month = month || tok.format[0] == 'm'
+				day = day || tok.format[0] == 'd'
+				rest = rest[len(tok.format):]
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if !strings.ContainsRune("/-. ", rune(rest[0])) {
+			return "", errors.New("date_format must be made of dd, mm, yyyy and separators, e.g. dd/mm/yyyy")
+		}
+		layout.WriteByte(rest[0])
+		rest = rest[1:]
+	}
+	if !year || !month || !day {
+		return "", errors.New("date_format must have a day, a month and a year")
+	}
+	return layout.String(), nil
+}
+
+// Parse reads the rows of a CSV statement, with amounts in currency. A row that cannot be read keeps
+// its error and is returned with the others; a file that cannot be read at all, or lacks a mapped
+// column in its header, is an invalid "file" field. Blank lines are skipped.
+func (p *ImportProfile) Parse(data []byte, currency string) ([]ImportRow, error) {
+	layout, err := p.dateLayout()
+	if err != nil {
+		return nil, InvalidField("date_format", err.Error())
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff")) // UTF-8 byte order mark
+
+	// Skipped lines (bank name, period, balance) rarely follow the CSV layout, so they are cut before reading.
+	offset := 0
+	for ; offset < p.SkipRows && len(data) > 0; offset++ {
+		_, rest, found := bytes.Cut(data, []byte("\n"))
+		if !found {
+			rest = nil
+		}
+		data = rest
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = rune(p.Delimiter[0])
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	var header []string
+	if p.HasHeader {
+		if header, err = r.Read(); err != nil {
+			return nil, InvalidField("file", "the file has no header")
+		}
+	}
+	columns, err := p.resolveColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, InvalidField("file", fmt.Sprintf("the file is not a valid CSV: %v", err))
+		}
+		if len(rows) == MaxImportRows {
+			return nil, InvalidField("file", fmt.Sprintf("the file must not have more than %d rows", MaxImportRows))
+		}
+		line, _ := r.FieldPos(0)
+		row := ImportRow{Line: offset + line, Amount: NewMoney(0, currency)}
+		if perr := p.parseRow(&row, record, columns, layout, currency); perr != nil {
+			message := perr.Error()
+			row.Error = &message
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// resolveColumns returns the 0-based index of each mapped field.
+func (p *ImportProfile) resolveColumns(header []string) (map[ImportField]int, error) {
+	columns := make(map[ImportField]int, len(p.Columns))
+	for field, column := range p.Columns {
+		column = strings.TrimSpace(column)
+		index := slices.IndexFunc(header, func(h string) bool { return strings.EqualFold(strings.TrimSpace(h), column) })
+		if index < 0 {
+			n, err := strconv.Atoi(column)
+			if err != nil || n < 1 {
+				return nil, InvalidField("file", fmt.Sprintf("the header has no %q column for %s", column, field))
+			}
+			index = n - 1
+		}
+		columns[field] = index
+	}
+	return columns, nil
+}
+
+func (p *ImportProfile) parseRow(row *ImportRow, record []string, columns map[ImportField]int, layout, currency string) error {
+	value := func(field ImportField) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row.Description = value(ImportFieldDescription)
+
+	date, err := time.Parse(layout, value(ImportFieldDate))
+	if err != nil {
+		return fmt.Errorf("invalid date %q, expected %s", value(ImportFieldDate), p.DateFormat)
+	}
+	row.Date = &date
+
+	var amount Money
+	if p.SignConvention == ImportSignSplitColumns {
+		debit, credit := value(ImportFieldDebit), value(ImportFieldCredit)
+		if debit != "" {
+			if amount, err = p.parseAmount(debit, currency); err != nil {
+				return err
+			}
+			amount = amount.Abs().Neg()
+		}
+		if amount.IsZero() && credit != "" {
+			if amount, err = p.parseAmount(credit, currency); err != nil {
+				return err
+			}
+			amount = amount.Abs()
+		}
+	} else {
+		if amount, err = p.parseAmount(value(ImportFieldAmount), currency); err != nil {
+			return err
+		}
+		if p.SignConvention == ImportSignPositiveDebit {
+			amount = amount.Neg()
+		}
+	}
+	if amount.IsZero() {
+		return errors.New("amount is zero")
+	}
+
+	// Here a negative amount is money leaving the account
+	row.TransactionType = TransactionTypeCredit
+	if amount.IsNegative() {
+		row.TransactionType = TransactionTypeDebit
+	}
+	row.Amount = amount.Abs()
+	return nil
+}
+
+// parseAmount reads an amount written with the decimal separator of the profile, thousands
+// separators and a currency symbol, e.g. "R$ -1.234,56", "(1,234.56)" or "1.234,56-".
+func (p *ImportProfile) parseAmount(s, currency string) (Money, error) {
+	thousands := ","
+	if p.DecimalSeparator == "," {
+		thousands = "."
+	}
+
+	var b strings.Builder
+	negative := false
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case string(r) == p.DecimalSeparator:
+			b.WriteByte('.')
+		case string(r) == thousands:
+		case r == '-' || r == '(':
+			negative = true
+		}
+	}
+	m, err := ParseMoney(b.String(), currency)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	if negative {
+		m = m.Neg()
+	}
+	return m, nil
+}
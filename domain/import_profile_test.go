@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestImportProfile_IsValid(t *testing.T) {
+	valid := func() ImportProfile {
+		return ImportProfile{
+			TenantID: "tenant-1", Name: "Banco do Brasil", Delimiter: ";", HasHeader: true,
+			DateFormat: "dd/mm/yyyy", DecimalSeparator: ",", SignConvention: ImportSignNegativeDebit,
+			Columns: map[ImportField]string{ImportFieldDate: "Data", ImportFieldDescription: "Histórico", ImportFieldAmount: "Valor"},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(p *ImportProfile)
+		wantField string
+	}{
+		{name: "valid", modify: func(p *ImportProfile) {}},
+		{name: "split columns", modify: func(p *ImportProfile) {
+			p.SignConvention = ImportSignSplitColumns
+			p.Columns = map[ImportField]string{ImportFieldDate: "1", ImportFieldDebit: "3", ImportFieldCredit: "4"}
+		}},
+		{name: "iso date", modify: func(p *ImportProfile) { p.DateFormat = "yyyy-mm-dd" }},
+		{name: "date without year", modify: func(p *ImportProfile) { p.DateFormat = "dd/mm" }, wantField: "date_format"},
+		{name: "unknown date token", modify: func(p *ImportProfile) { p.DateFormat = "dd/MMM/yyyy hh" }, wantField: "date_format"},
+		{name: "long delimiter", modify: func(p *ImportProfile) { p.Delimiter = ";;" }, wantField: "delimiter"},
+		{name: "invalid decimal separator", modify: func(p *ImportProfile) { p.DecimalSeparator = "'" }, wantField: "decimal_separator"},
+		{name: "invalid sign convention", modify: func(p *ImportProfile) { p.SignConvention = "absolute" }, wantField: "sign_convention"},
+		{name: "missing amount column", modify: func(p *ImportProfile) { delete(p.Columns, ImportFieldAmount) }, wantField: "columns"},
+		{name: "split columns without credit", modify: func(p *ImportProfile) {
+			p.SignConvention = ImportSignSplitColumns
+			p.Columns = map[ImportField]string{ImportFieldDate: "1", ImportFieldDebit: "3"}
+		}, wantField: "columns"},
+		{name: "unknown field", modify: func(p *ImportProfile) { p.Columns["balance"] = "Saldo" }, wantField: "columns"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.modify(&p)
+			ok, errs := p.IsValid()
+			if tt.wantField == "" {
+				if !ok {
+					t.Errorf("IsValid() = %v, want valid", errs)
+				}
+				return
+			}
+			if ok || errs[tt.wantField] == nil {
+				t.Errorf("IsValid() = %v, %v, want an error on %s", ok, errs, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestImportProfile_Parse(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	type want struct {
+		line     int
+		date     time.Time
+		amount   int64
+		txType   TransactionType
+		desc     string
+		hasError bool
+	}
+
+	tests := []struct {
+		name    string
+		profile ImportProfile
+		file    string
+		want    []want
+	}{
+		{
+			name: "brazilian bank statement",
+			profile: ImportProfile{
+				Delimiter: ";", HasHeader: true, SkipRows: 2, DateFormat: "dd/mm/yyyy", DecimalSeparator: ",", SignConvention: ImportSignNegativeDebit,
+				Columns: map[ImportField]string{ImportFieldDate: "data", ImportFieldDescription: "Histórico", ImportFieldAmount: "Valor (R$)"},
+			},
+			file: "\ufeffExtrato conta corrente\nPeríodo: 01/03/2024 a 31/03/2024\n" +
+				"Data;Histórico;Valor (R$);Saldo\n" +
+				"05/03/2024;PIX RECEBIDO JOAO;1.234,56;1.234,56\n" +
+				"\n" +
+				"06/03/2024;\"COMPRA CARTAO; MERCADO\";-R$ 89,90;1.144,66\n" +
+				"31/02/2024;TARIFA;-12,00;1.132,66\n",
+			want: []want{
+				{line: 4, date: date(2024, 3, 5), amount: 123456, txType: TransactionTypeCredit, desc: "PIX RECEBIDO JOAO"},
+				{line: 6, date: date(2024, 3, 6), amount: 8990, txType: TransactionTypeDebit, desc: "COMPRA CARTAO; MERCADO"},
+				{line: 7, desc: "TARIFA", hasError: true},
+			},
+		},
+		{
+			name: "credit card statement without header",
+			profile: ImportProfile{
+				Delimiter: ",", DateFormat: "yyyy-mm-dd", DecimalSeparator: ".", SignConvention: ImportSignPositiveDebit,
+				Columns: map[ImportField]string{ImportFieldDate: "1", ImportFieldDescription: "2", ImportFieldAmount: "3"},
+			},
+			file: "2024-03-02,Bookstore,\"1,250.00\"\n2024-03-09,Refund,(30.5)\n2024-03-10,Nothing,0.00\n",
+			want: []want{
+				{line: 1, date: date(2024, 3, 2), amount: 125000, txType: TransactionTypeDebit, desc: "Bookstore"},
+				{line: 2, date: date(2024, 3, 9), amount: 3050, txType: TransactionTypeCredit, desc: "Refund"},
+				{line: 3, desc: "Nothing", hasError: true},
+			},
+		},
+		{
+			name: "split debit and credit columns",
+			profile: ImportProfile{
+				Delimiter: "\t", HasHeader: true, DateFormat: "d.m.yy", DecimalSeparator: ",", SignConvention: ImportSignSplitColumns,
+				Columns: map[ImportField]string{ImportFieldDate: "Data", ImportFieldDescription: "Descrição", ImportFieldDebit: "Débito", ImportFieldCredit: "Crédito"},
+			},
+			file: "Data\tDescrição\tDébito\tCrédito\n1.4.24\tAluguel\t2.500,00\t\n15.4.24\tSalário\t\t8.000,00\n16.4.24\tErro\tabc\t\n",
+			want: []want{
+				{line: 2, date: date(2024, 4, 1), amount: 250000, txType: TransactionTypeDebit, desc: "Aluguel"},
+				{line: 3, date: date(2024, 4, 15), amount: 800000, txType: TransactionTypeCredit, desc: "Salário"},
+				{line: 4, desc: "Erro", hasError: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := tt.profile.Parse([]byte(tt.file), "BRL")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("Parse() returned %d rows, want %d: %+v", len(rows), len(tt.want), rows)
+			}
+			for i, w := range tt.want {
+				row := rows[i]
+				if row.Line != w.line || row.Description != w.desc || (row.Error != nil) != w.hasError {
+					t.Errorf("row %d = %+v, want %+v", i, row, w)
+					continue
+				}
+				if w.hasError {
+					continue
+				}
+				if !row.Date.Equal(w.date) || row.Amount != NewMoney(w.amount, "BRL") || row.TransactionType != w.txType {
+					t.Errorf("row %d = %v %v %s, want %v %d %s", i, row.Date, row.Amount, row.TransactionType, w.date, w.amount, w.txType)
+				}
+			}
+		})
+	}
+}
+
+func TestImportProfile_Parse_InvalidFile(t *testing.T) {
+	profile := ImportProfile{
+		Delimiter: ";", HasHeader: true, DateFormat: "dd/mm/yyyy", DecimalSeparator: ",", SignConvention: ImportSignNegativeDebit,
+		Columns: map[ImportField]string{ImportFieldDate: "Data", ImportFieldAmount: "Valor"},
+	}
+
+	for name, file := range map[string]string{
+		"empty file":     "",
+		"missing column": "Data;Historico;Saldo\n05/03/2024;PIX;10,00\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := profile.Parse([]byte(file), "BRL")
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Fields["file"] == "" {
+				t.Errorf("Parse() error = %v, want a file validation error", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeImportDescription(t *testing.T) {
+	got := NormalizeImportDescription("  PIX 0412  MERCADO Sao Jose 12/03 ")
+	if want := "pix mercado sao jose /"; got != want {
+		t.Errorf("NormalizeImportDescription() = %q, want %q", got, want)
+	}
+}
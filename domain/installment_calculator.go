@@ -2,14 +2,13 @@ package domain
 
 import (
 	"errors"
-	"math"
 	"time"
 )
 
 // Installment represents a calculated installment with amount and due date.
 type Installment struct {
 	Number  int
-	Amount  float64
+	Amount  Money
 	DueDate time.Time
 }
 
@@ -18,62 +17,35 @@ type Installment struct {
 // count: Number of installments.
 // firstDueDate: The due date of the first installment.
 // isRecurring: If true, the full amount is repeated for each installment. If false, the amount is split.
-func CalculateInstallments(amount float64, count int, firstDueDate time.Time, isRecurring bool) ([]Installment, error) {
+func CalculateInstallments(amount Money, count int, firstDueDate time.Time, isRecurring bool) ([]Installment, error) {
 	if count < 1 {
 		return nil, errors.New("installments count must be at least 1")
 	}
-	if amount < 0 {
+	if amount.IsNegative() {
 		return nil, errors.New("amount must be non-negative")
 	}
 
-	installments := make([]Installment, count)
-
-	// Round amount to 2 decimal places to avoid float precision issues initially
-	amount = math.Round(amount*100) / 100
-
-	var partAmount float64
-	var remainder float64
-
+	var amounts []Money
 	if !isRecurring {
-		// Split logic
-		// Example: 100 / 3 = 33.33, Remainder 0.01
-		// 1st: 33.34, 2nd: 33.33, 3rd: 33.33
-
-		// Use integer math for cents to ensure precision
-		totalCents := int64(math.Round(amount * 100))
-		partCents := totalCents / int64(count)
-		remainderCents := totalCents % int64(count)
-
-		partAmount = float64(partCents) / 100.0
-		remainder = float64(remainderCents) / 100.0
+		// Split logic: the first installment absorbs the remainder.
+		// Example: 100 / 3 -> 33.34, 33.33, 33.33
+		amounts, _ = amount.Split(count)
 	} else {
-		// Recurring logic
-		partAmount = amount
-		remainder = 0
+		// Recurring logic: the full amount is repeated.
+		amounts = make([]Money, count)
+		for i := range amounts {
+			amounts[i] = amount
+		}
 	}
 
+	installments := make([]Installment, count)
 	for i := 0; i < count; i++ {
-		instAmount := partAmount
-		if !isRecurring && i == 0 {
-			instAmount += remainder
-			// Fix precision again just in case float math added noise (e.g. 0.33 + 0.01)
-			instAmount = math.Round(instAmount*100) / 100
-		}
-
-		// Calculate Due Date
-		// AddDate(0, i, 0) adds 'i' months.
-		// Go's AddDate handles rollover: Jan 31 + 1 month = March 3 (or Feb 28/29)?
-		// Actually Go's Time.AddDate normalizes: Jan 31 + 1 month -> Feb 31 -> March 3 (non-leap) or March 2 (leap).
-		// This is usually NOT what credit cards do. Credit cards typically snap to last day of month if overflow.
-		// However, standard simplistic implementation often accepts AddDate behavior or snaps.
-		// User requirement checks: "Handles month-end logic (31st -> 28th/29th)".
-		// So we need specific logic to snap to end of month if day doesn't exist.
-		dueDate := addMonths(firstDueDate, i)
-
+		// Credit cards snap to the last day of the month when the day doesn't exist
+		// (31st -> 28th/29th), instead of Go's AddDate normalization (Jan 31 + 1 month -> March 3).
 		installments[i] = Installment{
 			Number:  i + 1,
-			Amount:  instAmount,
-			DueDate: dueDate,
+			Amount:  amounts[i],
+			DueDate: addMonths(firstDueDate, i),
 		}
 	}
 
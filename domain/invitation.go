@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"time"
+)
+
+var (
+	ErrInvitationNotFound      = NewNotFoundError("invitation not found")
+	ErrInvitationNotPending    = NewConflictError("invitation is no longer pending")
+	ErrInvitationExpired       = NewConflictError("invitation has expired")
+	ErrInvitationEmailMismatch = NewForbiddenError("invitation was sent to a different email")
+	ErrInvitationAlreadyExists = NewConflictError("a pending invitation already exists for this email")
+	ErrAlreadyTenantMember     = NewConflictError("user is already a member of this tenant")
+)
+
+// InvitationTTL is how long an invitation can be accepted after it is created.
+const InvitationTTL = 7 * 24 * time.Hour
+
+// InvitationStatus represents the lifecycle state of an invitation.
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusRevoked  InvitationStatus = "revoked"
+	InvitationStatusExpired  InvitationStatus = "expired"
+)
+
+// Invitation represents an invitation for an email address to join a tenant with a given role.
+// Only the SHA-256 hash of the token is stored; the token itself is handed out once, when the invitation is created.
+type Invitation struct {
+	ID            string           `json:"id"`
+	TenantID      string           `json:"tenant_id"`
+	InviterUserID string           `json:"inviter_user_id"`
+	Email         string           `json:"email"`
+	Role          Role             `json:"role"`
+	TokenHash     string           `json:"-"`
+	Status        InvitationStatus `json:"status"`
+	ExpiresAt     time.Time        `json:"expires_at"`
+	AcceptedBy    *string          `json:"accepted_by,omitempty"`
+	AcceptedAt    *time.Time       `json:"accepted_at,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+// InvitationRepository defines the interface for invitation persistence.
+type InvitationRepository interface {
+	// Create stores a new pending invitation, or returns ErrInvitationAlreadyExists.
+	Create(ctx context.Context, inv *Invitation) error
+	GetByID(ctx context.Context, tenantID, id string) (*Invitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Invitation, error)
+	ListPending(ctx context.Context, tenantID string) ([]Invitation, error)
+	// Revoke marks a pending invitation as revoked, or returns ErrInvitationNotPending.
+	Revoke(ctx context.Context, tenantID, id string) error
+	// MarkAccepted marks a pending invitation as accepted by the user, or returns ErrInvitationNotPending.
+	MarkAccepted(ctx context.Context, inv *Invitation, userID string) error
+	// ExpirePending marks every pending invitation that expired before now as expired and returns how many were updated.
+	ExpirePending(ctx context.Context, now time.Time) (int64, error)
+}
+
+// NewInvitationToken returns a random URL-safe token and its hash.
+func NewInvitationToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashInvitationToken(token), nil
+}
+
+// HashInvitationToken returns the hex encoded SHA-256 hash under which a token is stored.
+func HashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsExpired reports whether the invitation can no longer be accepted at the given time.
+func (i *Invitation) IsExpired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+func (i *Invitation) IsValid() (bool, map[string]error) {
+	err := make(map[string]error)
+	if i.TenantID == "" {
+		err["tenant_id"] = errors.New("tenant_id is required")
+	}
+	if i.InviterUserID == "" {
+		err["inviter_user_id"] = errors.New("inviter_user_id is required")
+	}
+	if i.Email == "" {
+		err["email"] = errors.New("email is required")
+	} else {
+		const emailRegexPattern = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
+		if matched, _ := regexp.MatchString(emailRegexPattern, i.Email); !matched {
+			err["email"] = errors.New("invalid email format")
+		}
+	}
+	if !i.Role.IsValid() {
+		err["role"] = errors.New("invalid role")
+	}
+	if i.TokenHash == "" {
+		err["token_hash"] = errors.New("token_hash is required")
+	}
+	if len(err) == 0 {
+		return true, nil
+	}
+	return false, err
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// JobRunStatus represents the outcome of a background job run.
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning   JobRunStatus = "running"
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusFailed    JobRunStatus = "failed"
+)
+
+// JobRun is one execution of a background job for a scheduled slot.
+type JobRun struct {
+	ID          string       `json:"id"`
+	JobName     string       `json:"job_name"`
+	ScheduledAt time.Time    `json:"scheduled_at"`
+	StartedAt   time.Time    `json:"started_at"`
+	FinishedAt  *time.Time   `json:"finished_at,omitempty"`
+	Status      JobRunStatus `json:"status"`
+	Summary     *string      `json:"summary,omitempty"` // What the run did, e.g. "expired 3 invitations"
+	Error       *string      `json:"error,omitempty"`
+	Instance    string       `json:"instance"` // Host that ran the job
+}
+
+// JobRunRepository defines the interface for job coordination across replicas and run history.
+type JobRunRepository interface {
+	// TryLock takes the job's lock without waiting. When acquired, release must be called once the run is over.
+	TryLock(ctx context.Context, jobName string) (release func(), acquired bool, err error)
+	// StartRun records a run for its scheduled slot and returns false, without recording it,
+	// when the slot was already run by another instance.
+	StartRun(ctx context.Context, run *JobRun) (bool, error)
+	// FinishRun records the outcome of a run.
+	FinishRun(ctx context.Context, run *JobRun) error
+	// ListLatest returns the latest run of every job.
+	ListLatest(ctx context.Context) ([]JobRun, error)
+}
@@ -0,0 +1,257 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// moneyScale is the number of decimal places stored by the NUMERIC(…,2) money columns.
+const moneyScale = 2
+
+var ErrInvalidMoney = NewValidationError("invalid money amount")
+
+// Money represents an exact monetary amount as integer minor units (cents) plus an ISO currency code.
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+// MoneyEncoding selects how Money values are encoded in JSON.
+type MoneyEncoding int
+
+const (
+	// MoneyEncodingString encodes amounts as decimal strings (e.g. "1234.56").
+	MoneyEncodingString MoneyEncoding = iota
+	// MoneyEncodingCents encodes amounts as integer minor units (e.g. 123456).
+	MoneyEncodingCents
+)
+
+var moneyEncoding = MoneyEncodingString
+
+// SetMoneyEncoding sets the JSON encoding used for Money values.
+// It is meant to be called once during startup.
+func SetMoneyEncoding(e MoneyEncoding) {
+	moneyEncoding = e
+}
+
+// NewMoney creates a Money value from minor units.
+func NewMoney(cents int64, currency string) Money {
+	return Money{Cents: cents, Currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "1234.56" or "-0.5" into Money.
+func ParseMoney(s, currency string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Money{}, ErrInvalidMoney
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" && (!hasFrac || frac == "") {
+		return Money{}, ErrInvalidMoney
+	}
+	if len(frac) > moneyScale {
+		// Extra digits are only accepted when they don't carry value (e.g. "1.500").
+		if strings.Trim(frac[moneyScale:], "0") != "" {
+			return Money{}, fmt.Errorf("%w: more than %d decimal places", ErrInvalidMoney, moneyScale)
+		}
+		frac = frac[:moneyScale]
+	}
+	frac += strings.Repeat("0", moneyScale-len(frac))
+	if whole == "" {
+		whole = "0"
+	}
+
+	for _, r := range whole + frac {
+		if r < '0' || r > '9' {
+			return Money{}, ErrInvalidMoney
+		}
+	}
+
+	cents, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %v", ErrInvalidMoney, err)
+	}
+	if negative {
+		cents = -cents
+	}
+	return Money{Cents: cents, Currency: currency}, nil
+}
+
+// WithCurrency returns a copy of m in the given currency.
+func (m Money) WithCurrency(currency string) Money {
+	m.Currency = currency
+	return m
+}
+
+// Add returns m + o. The result keeps the currency of m, falling back to the one of o.
+func (m Money) Add(o Money) Money {
+	return Money{Cents: m.Cents + o.Cents, Currency: m.currencyWith(o)}
+}
+
+// Sub returns m - o. The result keeps the currency of m, falling back to the one of o.
+func (m Money) Sub(o Money) Money {
+	return Money{Cents: m.Cents - o.Cents, Currency: m.currencyWith(o)}
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{Cents: -m.Cents, Currency: m.Currency}
+}
+
+// Abs returns the absolute value of m.
+func (m Money) Abs() Money {
+	if m.Cents < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// IsZero reports whether m is zero.
+func (m Money) IsZero() bool { return m.Cents == 0 }
+
+// IsPositive reports whether m is greater than zero.
+func (m Money) IsPositive() bool { return m.Cents > 0 }
+
+// IsNegative reports whether m is less than zero.
+func (m Money) IsNegative() bool { return m.Cents < 0 }
+
+// Cmp compares the amounts of m and o, returning -1, 0 or +1.
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.Cents < o.Cents:
+		return -1
+	case m.Cents > o.Cents:
+		return 1
+	}
+	return 0
+}
+
+// SameCurrency reports whether m and o are in the same currency.
+// An empty currency is treated as compatible with any other.
+func (m Money) SameCurrency(o Money) bool {
+	return m.Currency == "" || o.Currency == "" || m.Currency == o.Currency
+}
+
+// Split divides m into n parts that add up exactly to m.
+// The first part absorbs the remainder (e.g. 10.00 / 3 -> 3.34, 3.33, 3.33).
+func (m Money) Split(n int) ([]Money, error) {
+	if n < 1 {
+		return nil, errors.New("split count must be at least 1")
+	}
+	part := m.Cents / int64(n)
+	remainder := m.Cents % int64(n)
+
+	parts := make([]Money, n)
+	for i := range parts {
+		parts[i] = Money{Cents: part, Currency: m.Currency}
+	}
+	parts[0].Cents += remainder
+	return parts, nil
+}
+
+// String formats m as a decimal string without currency (e.g. "-1234.56").
+func (m Money) String() string {
+	cents := m.Cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON encodes m according to the configured MoneyEncoding.
+func (m Money) MarshalJSON() ([]byte, error) {
+	if moneyEncoding == MoneyEncodingCents {
+		return []byte(strconv.FormatInt(m.Cents, 10)), nil
+	}
+	return []byte(strconv.Quote(m.String())), nil
+}
+
+// UnmarshalJSON accepts a decimal string ("12.34") or a JSON number.
+// Numbers are read as minor units in cents mode and as decimal amounts otherwise.
+// The currency of m is left untouched.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	raw := strings.TrimSpace(string(data))
+	if raw == "null" {
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(raw); err == nil {
+		parsed, err := ParseMoney(unquoted, m.Currency)
+		if err != nil {
+			return err
+		}
+		m.Cents = parsed.Cents
+		return nil
+	}
+
+	if moneyEncoding == MoneyEncodingCents {
+		cents, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: expected integer cents", ErrInvalidMoney)
+		}
+		m.Cents = cents
+		return nil
+	}
+
+	parsed, err := ParseMoney(raw, m.Currency)
+	if err != nil {
+		return err
+	}
+	m.Cents = parsed.Cents
+	return nil
+}
+
+// Scan implements sql.Scanner so NUMERIC columns can be read directly into Money.
+// Only the amount is set; the currency comes from its own column.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		m.Cents = 0
+		return nil
+	case string:
+		return m.scanString(v)
+	case []byte:
+		return m.scanString(string(v))
+	case int64:
+		m.Cents = v * 100
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
+
+// Value implements driver.Valuer so Money can be written to NUMERIC columns.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m *Money) scanString(s string) error {
+	parsed, err := ParseMoney(s, m.Currency)
+	if err != nil {
+		return err
+	}
+	m.Cents = parsed.Cents
+	return nil
+}
+
+func (m Money) currencyWith(o Money) string {
+	if m.Currency != "" {
+		return m.Currency
+	}
+	return o.Currency
+}
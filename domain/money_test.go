@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "1234.56", want: 123456},
+		{in: "-0.5", want: -50},
+		{in: "10", want: 1000},
+		{in: ".99", want: 99},
+		{in: "1.500", want: 150},
+		{in: "0.1", want: 10},
+		{in: "1.005", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseMoney(tt.in, "BRL")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Cents != tt.want {
+				t.Errorf("got %d cents, want %d", got.Cents, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneySplit(t *testing.T) {
+	parts, err := NewMoney(1000, "BRL").Split(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []int64{334, 333, 333}
+	var total Money
+	for i, p := range parts {
+		if p.Cents != want[i] {
+			t.Errorf("part %d: got %s, want %d cents", i, p, want[i])
+		}
+		total = total.Add(p)
+	}
+	if total.Cents != 1000 {
+		t.Errorf("parts add up to %s, want 10.00", total)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	defer SetMoneyEncoding(MoneyEncodingString)
+
+	m := NewMoney(-123456, "USD")
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `"-1234.56"` {
+		t.Errorf("string encoding: got %s", data)
+	}
+
+	var decoded Money
+	if err := json.Unmarshal([]byte(`12.3`), &decoded); err != nil || decoded.Cents != 1230 {
+		t.Errorf("decimal number decoding: got %d, err %v", decoded.Cents, err)
+	}
+
+	SetMoneyEncoding(MoneyEncodingCents)
+	data, _ = json.Marshal(m)
+	if string(data) != `-123456` {
+		t.Errorf("cents encoding: got %s", data)
+	}
+	if err := json.Unmarshal([]byte(`1230`), &decoded); err != nil || decoded.Cents != 1230 {
+		t.Errorf("cents decoding: got %d, err %v", decoded.Cents, err)
+	}
+	if err := json.Unmarshal([]byte(`"9.99"`), &decoded); err != nil || decoded.Cents != 999 {
+		t.Errorf("string decoding in cents mode: got %d, err %v", decoded.Cents, err)
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+var ErrInvalidCursor = NewValidationError("invalid cursor")
+
+const (
+	// DefaultPageLimit is the page size used by list endpoints when no limit is given.
+	DefaultPageLimit = 50
+	// MaxPageLimit is the largest page size list endpoints accept.
+	MaxPageLimit = 200
+)
+
+// PageRequest selects a page of a keyset-paginated list.
+// A zero Limit returns every row after the cursor; the API always sets a limit.
+type PageRequest struct {
+	Limit        int
+	Cursor       string
+	IncludeTotal bool
+}
+
+// Page is one page of a list. NextCursor is empty on the last page;
+// Total is only set when the request asked for it.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	Total      *int64
+}
+
+// EncodeCursor builds an opaque cursor from the sort key values of the last row of a page.
+func EncodeCursor(values ...string) string {
+	b, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor returns the n sort key values encoded in a cursor, or ErrInvalidCursor.
+func DecodeCursor(cursor string, n int) ([]string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var values []string
+	if err := json.Unmarshal(b, &values); err != nil || len(values) != n {
+		return nil, ErrInvalidCursor
+	}
+	return values, nil
+}
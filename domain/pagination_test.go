@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := EncodeCursor("Groceries, food & drinks", "7b0a5c1e-3f5e-4c3a-9d55-0f4a1c2b3d4e")
+	got, err := DecodeCursor(cursor, 2)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	want := []string{"Groceries, food & drinks", "7b0a5c1e-3f5e-4c3a-9d55-0f4a1c2b3d4e"}
+	if !slices.Equal(got, want) {
+		t.Errorf("DecodeCursor() = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+		n      int
+	}{
+		{name: "not base64", cursor: "%%%", n: 2},
+		{name: "not json", cursor: "bm90IGpzb24", n: 2},
+		{name: "wrong number of values", cursor: EncodeCursor("a"), n: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.cursor, tt.n); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor() error = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
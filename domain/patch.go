@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Field is a field of a partial update (JSON Merge Patch, RFC 7396). A field absent from the
+// document is left unchanged; a field set to null is cleared, which for nullable fields (pointer
+// types) means nil and for the others their zero value, rejected by validation where required.
+type Field[T any] struct {
+	Value T
+	Set   bool
+}
+
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	return json.Unmarshal(data, &f.Value)
+}
+
+// apply replaces *dst with the value of the field when it is set.
+func (f Field[T]) apply(dst *T) {
+	if f.Set {
+		*dst = f.Value
+	}
+}
+
+// TransactionPatch is a partial update of a transaction.
+type TransactionPatch struct {
+	FromAccountID   Field[string]          `json:"from_account_id"`
+	ToAccountID     Field[*string]         `json:"to_account_id"`
+	Amount          Field[Money]           `json:"amount"`
+	AccrualMonth    Field[string]          `json:"accrual_month"`
+	TransactionType Field[TransactionType] `json:"transaction_type"`
+	CategoryID      Field[string]          `json:"category_id"`
+	Comments        Field[*string]         `json:"comments"`
+	DueDate         Field[time.Time]       `json:"due_date"`
+	PaymentDate     Field[*time.Time]      `json:"payment_date"`
+	ExchangeRate    Field[ExchangeRate]    `json:"exchange_rate"`
+	Fee             Field[Money]           `json:"fee"`
+	TagIDs          Field[[]string]        `json:"tag_ids"` // Replaces the tags when set
+}
+
+// Apply sets the fields of the patch on t. Tags are not columns of t and are left to the caller.
+func (p *TransactionPatch) Apply(t *Transaction) {
+	p.FromAccountID.apply(&t.FromAccountID)
+	p.ToAccountID.apply(&t.ToAccountID)
+	p.Amount.apply(&t.Amount)
+	p.AccrualMonth.apply(&t.AccrualMonth)
+	p.TransactionType.apply(&t.TransactionType)
+	p.CategoryID.apply(&t.CategoryID)
+	p.Comments.apply(&t.Comments)
+	p.DueDate.apply(&t.DueDate)
+	p.PaymentDate.apply(&t.PaymentDate)
+	p.ExchangeRate.apply(&t.ExchangeRate)
+	p.Fee.apply(&t.Fee)
+	t.Amount.Currency = t.Currency
+	t.Fee.Currency = t.Currency
+}
+
+// AccountPatch is a partial update of an account. Currency and Type are immutable: they may only
+// be sent with their current value.
+type AccountPatch struct {
+	Name           Field[string]      `json:"name"`
+	InitialBalance Field[Money]       `json:"initial_balance"`
+	Color          Field[string]      `json:"color"`
+	Icon           Field[string]      `json:"icon"`
+	Currency       Field[string]      `json:"currency"`
+	Type           Field[AccountType] `json:"type"`
+}
+
+// Apply sets the mutable fields of the patch on a, returning a validation error when it changes an immutable one.
+func (p *AccountPatch) Apply(a *Account) error {
+	immutable := map[string]error{}
+	if p.Currency.Set && p.Currency.Value != a.Currency {
+		immutable["currency"] = errors.New("currency cannot be changed")
+	}
+	if p.Type.Set && p.Type.Value != a.Type {
+		immutable["type"] = errors.New("type cannot be changed")
+	}
+	if len(immutable) > 0 {
+		return InvalidFields(immutable)
+	}
+
+	p.Name.apply(&a.Name)
+	p.InitialBalance.apply(&a.InitialBalance)
+	p.Color.apply(&a.Color)
+	p.Icon.apply(&a.Icon)
+	a.InitialBalance.Currency = a.Currency
+	return nil
+}
+
+// CategoryPatch is a partial update of a category. Type is immutable: it may only be sent with its current value.
+type CategoryPatch struct {
+	Name             Field[string]       `json:"name"`
+	ParentCategoryID Field[*string]      `json:"parent_category_id"`
+	Color            Field[string]       `json:"color"`
+	Icon             Field[string]       `json:"icon"`
+	Type             Field[CategoryType] `json:"type"`
+}
+
+// Apply sets the mutable fields of the patch on c, returning a validation error when it changes an immutable one.
+func (p *CategoryPatch) Apply(c *Category) error {
+	if p.Type.Set && p.Type.Value != c.Type {
+		return InvalidField("type", "type cannot be changed")
+	}
+
+	p.Name.apply(&c.Name)
+	p.ParentCategoryID.apply(&c.ParentCategoryID)
+	p.Color.apply(&c.Color)
+	p.Icon.apply(&c.Icon)
+	return nil
+}
+
+// TagPatch is a partial update of a tag.
+type TagPatch struct {
+	Name Field[string] `json:"name"`
+}
+
+// Apply sets the fields of the patch on t.
+func (p *TagPatch) Apply(t *Tag) {
+	p.Name.apply(&t.Name)
+}
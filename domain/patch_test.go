@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestTransactionPatch_Apply(t *testing.T) {
+	comments := "Lunch"
+	toAccountID := "acc-2"
+	stored := func() *Transaction {
+		return &Transaction{
+			FromAccountID:   "acc-1",
+			ToAccountID:     &toAccountID,
+			Currency:        "BRL",
+			Amount:          NewMoney(1000, "BRL"),
+			AccrualMonth:    "202403",
+			TransactionType: TransactionTypeDebit,
+			CategoryID:      "cat-1",
+			Comments:        &comments,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		patch string
+		check func(t *testing.T, tx *Transaction)
+	}{
+		{
+			name:  "absent fields are left unchanged",
+			patch: `{"amount": "25.50"}`,
+			check: func(t *testing.T, tx *Transaction) {
+				if tx.Amount != NewMoney(2550, "BRL") {
+					t.Errorf("amount = %v, want 25.50 BRL", tx.Amount)
+				}
+				if tx.CategoryID != "cat-1" || tx.Comments == nil || tx.ToAccountID == nil {
+					t.Errorf("fields absent from the patch were changed: %+v", tx)
+				}
+			},
+		},
+		{
+			name:  "null clears nullable fields",
+			patch: `{"comments": null, "to_account_id": null}`,
+			check: func(t *testing.T, tx *Transaction) {
+				if tx.Comments != nil || tx.ToAccountID != nil {
+					t.Errorf("comments = %v, to_account_id = %v, want nil", tx.Comments, tx.ToAccountID)
+				}
+			},
+		},
+		{
+			name:  "null clears required fields, left to validation",
+			patch: `{"category_id": null}`,
+			check: func(t *testing.T, tx *Transaction) {
+				if tx.CategoryID != "" {
+					t.Errorf("category_id = %q, want empty", tx.CategoryID)
+				}
+				if valid, errs := tx.IsValid(); valid || errs["category_id"] == nil {
+					t.Errorf("IsValid() = %v, %v, want a category_id error", valid, errs)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch TransactionPatch
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			tx := stored()
+			patch.Apply(tx)
+			tt.check(t, tx)
+		})
+	}
+}
+
+func TestAccountPatch_Apply(t *testing.T) {
+	tests := []struct {
+		name      string
+		patch     string
+		wantField string
+	}{
+		{name: "mutable fields", patch: `{"name": "Savings", "color": "#00ff00"}`},
+		{name: "immutable fields with their current value", patch: `{"currency": "BRL", "type": "bank"}`},
+		{name: "currency change", patch: `{"currency": "USD"}`, wantField: "currency"},
+		{name: "type change", patch: `{"name": "Card", "type": "credit_card"}`, wantField: "type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch AccountPatch
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			acc := &Account{Name: "Checking", Currency: "BRL", Type: AccountTypeBank}
+			err := patch.Apply(acc)
+
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Apply() error = %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Fields[tt.wantField] == "" {
+				t.Fatalf("Apply() error = %v, want a validation error on %s", err, tt.wantField)
+			}
+			if acc.Name != "Checking" || acc.Currency != "BRL" || acc.Type != AccountTypeBank {
+				t.Errorf("rejected patch changed the account: %+v", acc)
+			}
+		})
+	}
+}
+
+func TestCategoryPatch_Apply(t *testing.T) {
+	parentID := "cat-parent"
+	category := &Category{Name: "Food", ParentCategoryID: &parentID, Type: CategoryTypeExpense}
+
+	var patch CategoryPatch
+	if err := json.Unmarshal([]byte(`{"parent_category_id": null, "type": "expense"}`), &patch); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if err := patch.Apply(category); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if category.ParentCategoryID != nil || category.Name != "Food" {
+		t.Errorf("category = %+v, want the parent cleared and the name unchanged", category)
+	}
+
+	patch = CategoryPatch{}
+	if err := json.Unmarshal([]byte(`{"type": "income"}`), &patch); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	var verr *ValidationError
+	if err := patch.Apply(category); !errors.As(err, &verr) || verr.Fields["type"] == "" {
+		t.Errorf("Apply() error = %v, want a validation error on type", err)
+	}
+}
@@ -0,0 +1,259 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	ErrRecurrenceRuleNotFound = NewNotFoundError("recurrence rule not found")
+	ErrNotAnOccurrence        = NewValidationError("date is not an occurrence of the recurrence rule")
+	ErrOccurrencePaid         = NewConflictError("occurrence is already paid")
+)
+
+// DefaultRecurrenceHorizon is how far ahead of today occurrences are materialized.
+const DefaultRecurrenceHorizon = 90 * 24 * time.Hour
+
+// maxRecurrenceSteps bounds the schedule walk of a single rule.
+const maxRecurrenceSteps = 10000
+
+// RecurrenceFrequency is the period a recurrence rule repeats on.
+type RecurrenceFrequency string
+
+const (
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"  // On the weekday of the start date
+	RecurrenceMonthly RecurrenceFrequency = "monthly" // On MonthDay (default: the day of the start date) or the last business day
+	RecurrenceYearly  RecurrenceFrequency = "yearly"  // On the month and day of the start date
+)
+
+// RecurrenceRule is a transaction template repeated on a schedule, such as rent, a salary or a subscription.
+// Its occurrences are materialized as regular transactions linked by RecurrenceRuleID.
+type RecurrenceRule struct {
+	ID              string          `json:"id"`
+	TenantID        string          `json:"tenant_id"`
+	FromAccountID   string          `json:"from_account_id"`
+	ToAccountID     *string         `json:"to_account_id,omitempty"`
+	CategoryID      string          `json:"category_id"`
+	TransactionType TransactionType `json:"transaction_type"`
+	Currency        string          `json:"currency"`
+	Amount          Money           `json:"amount"`
+	Comments        *string         `json:"comments,omitempty"`
+	TagIDs          []string        `json:"tag_ids"`
+
+	Frequency       RecurrenceFrequency `json:"frequency"`
+	Interval        int                 `json:"interval"`            // Every Interval periods, at least 1
+	MonthDay        *int                `json:"month_day,omitempty"` // Monthly only; snaps to the last day of shorter months
+	LastBusinessDay bool                `json:"last_business_day"`   // Monthly only: the last weekday of the month
+	StartDate       time.Time           `json:"start_date"`
+	EndDate         *time.Time          `json:"end_date,omitempty"`
+	Count           *int                `json:"count,omitempty"` // Number of occurrences, skipped ones included
+
+	MaterializedUntil *time.Time               `json:"materialized_until,omitempty"`
+	AmountChanges     []RecurrenceAmountChange `json:"amount_changes,omitempty"` // Ordered by EffectiveFrom
+	SkippedDates      []time.Time              `json:"skipped_dates,omitempty"`
+
+	CreatedAt     time.Time  `json:"created_at"`
+	CreatedBy     string     `json:"created_by"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	UpdatedBy     string     `json:"updated_by"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	DeactivatedBy *string    `json:"deactivated_by,omitempty"`
+}
+
+// RecurrenceAmountChange sets the amount of the occurrences from EffectiveFrom onward.
+type RecurrenceAmountChange struct {
+	EffectiveFrom time.Time `json:"effective_from"`
+	Amount        Money     `json:"amount"`
+}
+
+// RecurrenceRuleRepository defines the interface for recurrence rule persistence.
+type RecurrenceRuleRepository interface {
+	// GetByID returns the rule with its amount changes and skipped dates.
+	GetByID(ctx context.Context, tenantID, id string) (*RecurrenceRule, error)
+	List(ctx context.Context, tenantID string, page PageRequest) (*Page[RecurrenceRule], error)
+	// ListToMaterialize returns the active rules not materialized up to until, of every tenant when tenantID is empty.
+	ListToMaterialize(ctx context.Context, tenantID string, until time.Time) ([]RecurrenceRule, error)
+	Create(ctx context.Context, rule *RecurrenceRule) error
+	// Update saves the template and schedule, cancels the unpaid occurrences from the given date
+	// and rewinds materialized_until so they are materialized again under the new schedule.
+	Update(ctx context.Context, rule *RecurrenceRule, from time.Time) error
+	// Delete deactivates the rule and cancels its unpaid occurrences from the given date.
+	Delete(ctx context.Context, tenantID, id, userID string, from time.Time) error
+
+	// SkipOccurrence records a skipped date and cancels its materialized transaction, unless it is paid.
+	SkipOccurrence(ctx context.Context, tenantID, ruleID string, date time.Time, userID string) error
+	// ChangeAmount records an amount change and applies it to the unpaid materialized occurrences it covers.
+	ChangeAmount(ctx context.Context, tenantID, ruleID string, change RecurrenceAmountChange, userID string) error
+	// Materialize inserts the occurrences that do not exist yet, links the rule's tags and advances
+	// materialized_until, in a single database transaction. It returns the number of transactions created.
+	Materialize(ctx context.Context, rule *RecurrenceRule, occurrences []Transaction, until time.Time) (int, error)
+}
+
+func (r *RecurrenceRule) IsValid() (bool, map[string]error) {
+	err := make(map[string]error)
+	if r.TenantID == "" {
+		err["tenant_id"] = errors.New("tenant_id is required")
+	}
+	if r.FromAccountID == "" {
+		err["from_account_id"] = errors.New("from_account_id is required")
+	}
+	if r.CategoryID == "" {
+		err["category_id"] = errors.New("category_id is required")
+	}
+	if !r.Amount.IsPositive() {
+		err["amount"] = errors.New("amount must be greater than 0")
+	}
+	validTypes := []TransactionType{TransactionTypeCredit, TransactionTypeDebit, TransactionTypeTransfer, TransactionTypePayment}
+	if !slices.Contains(validTypes, r.TransactionType) {
+		err["transaction_type"] = errors.New("invalid transaction type")
+	}
+	switch r.Frequency {
+	case RecurrenceWeekly, RecurrenceYearly:
+		if r.MonthDay != nil || r.LastBusinessDay {
+			err["frequency"] = errors.New("month_day and last_business_day only apply to monthly rules")
+		}
+	case RecurrenceMonthly:
+		if r.MonthDay != nil && r.LastBusinessDay {
+			err["month_day"] = errors.New("month_day and last_business_day are mutually exclusive")
+		}
+		if r.MonthDay != nil && (*r.MonthDay < 1 || *r.MonthDay > 31) {
+			err["month_day"] = errors.New("month_day must be between 1 and 31")
+		}
+	default:
+		err["frequency"] = errors.New("frequency must be weekly, monthly or yearly")
+	}
+	if r.Interval < 1 {
+		err["interval"] = errors.New("interval must be at least 1")
+	}
+	if r.StartDate.IsZero() {
+		err["start_date"] = errors.New("start_date is required")
+	}
+	if r.EndDate != nil && r.EndDate.Before(r.StartDate) {
+		err["end_date"] = errors.New("end_date must not be before start_date")
+	}
+	if r.Count != nil && *r.Count < 1 {
+		err["count"] = errors.New("count must be at least 1")
+	}
+	if len(err) == 0 {
+		return true, nil
+	}
+	return false, err
+}
+
+// Occurrences returns the scheduled dates between from and to, inclusive, skipped ones included.
+func (r *RecurrenceRule) Occurrences(from, to time.Time) []time.Time {
+	from, to = truncateToDate(from), truncateToDate(to)
+	start := truncateToDate(r.StartDate)
+
+	var dates []time.Time
+	seen := 0
+	for n := 0; n < maxRecurrenceSteps; n++ {
+		date := r.nth(start, n)
+		if date.Before(start) {
+			continue // A month day before the start date: the schedule begins the next period
+		}
+		if date.After(to) || (r.EndDate != nil && date.After(truncateToDate(*r.EndDate))) {
+			break
+		}
+		if r.Count != nil && seen >= *r.Count {
+			break
+		}
+		seen++
+		if !date.Before(from) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// IsOccurrence reports whether the rule is scheduled on date.
+func (r *RecurrenceRule) IsOccurrence(date time.Time) bool {
+	date = truncateToDate(date)
+	return slices.ContainsFunc(r.Occurrences(date, date), date.Equal)
+}
+
+// IsSkipped reports whether the occurrence on date was skipped.
+func (r *RecurrenceRule) IsSkipped(date time.Time) bool {
+	date = truncateToDate(date)
+	return slices.ContainsFunc(r.SkippedDates, func(d time.Time) bool { return truncateToDate(d).Equal(date) })
+}
+
+// AmountOn returns the amount of the occurrence on date, after the amount changes in effect.
+func (r *RecurrenceRule) AmountOn(date time.Time) Money {
+	amount := r.Amount
+	for _, c := range r.AmountChanges {
+		if !truncateToDate(c.EffectiveFrom).After(truncateToDate(date)) {
+			amount = c.Amount
+		}
+	}
+	return amount.WithCurrency(r.Currency)
+}
+
+// MaterializeFrom returns the first date not materialized yet.
+func (r *RecurrenceRule) MaterializeFrom() time.Time {
+	if r.MaterializedUntil != nil {
+		return truncateToDate(*r.MaterializedUntil).AddDate(0, 0, 1)
+	}
+	return truncateToDate(r.StartDate)
+}
+
+// Occurrence returns the transaction materialized for the occurrence on date.
+func (r *RecurrenceRule) Occurrence(date time.Time) Transaction {
+	ruleID := r.ID
+	recurrenceDate := truncateToDate(date)
+	t := Transaction{
+		TenantID:         r.TenantID,
+		FromAccountID:    r.FromAccountID,
+		ToAccountID:      r.ToAccountID,
+		Currency:         r.Currency,
+		Amount:           r.AmountOn(date),
+		AccrualMonth:     recurrenceDate.Format("200601"),
+		TransactionType:  r.TransactionType,
+		CategoryID:       r.CategoryID,
+		Comments:         r.Comments,
+		DueDate:          recurrenceDate,
+		RecurrenceRuleID: &ruleID,
+		RecurrenceDate:   &recurrenceDate,
+		CreatedBy:        r.UpdatedBy,
+		UpdatedBy:        r.UpdatedBy,
+	}
+	// Recurring transfers are between accounts of the same currency: the occurrence is the outgoing leg.
+	if t.TransactionType == TransactionTypeTransfer {
+		out := TransferDirectionOut
+		t.TransferDirection = &out
+		t.ExchangeRate = ExchangeRateOne
+	}
+	return t
+}
+
+// nth returns the date of the nth period of the schedule, which may fall before the start date.
+func (r *RecurrenceRule) nth(start time.Time, n int) time.Time {
+	switch r.Frequency {
+	case RecurrenceWeekly:
+		return start.AddDate(0, 0, 7*r.Interval*n)
+	case RecurrenceMonthly:
+		month := time.Date(start.Year(), start.Month()+time.Month(r.Interval*n), 1, 0, 0, 0, 0, time.UTC)
+		if r.LastBusinessDay {
+			return lastBusinessDay(month)
+		}
+		day := start.Day()
+		if r.MonthDay != nil {
+			day = *r.MonthDay
+		}
+		return withDay(month, day)
+	default: // RecurrenceYearly
+		month := time.Date(start.Year()+r.Interval*n, start.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return withDay(month, start.Day())
+	}
+}
+
+// lastBusinessDay returns the last weekday of the month. Holidays are not taken into account.
+func lastBusinessDay(month time.Time) time.Time {
+	day := time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRecurrenceRule_Occurrences(t *testing.T) {
+	day31 := 31
+	count := 3
+	end := date(2024, 2, 5)
+	tests := []struct {
+		name string
+		rule RecurrenceRule
+		from time.Time
+		to   time.Time
+		want []time.Time
+	}{
+		{
+			name: "Monthly Day 31 Snaps To Month End",
+			rule: RecurrenceRule{Frequency: RecurrenceMonthly, Interval: 1, MonthDay: &day31, StartDate: date(2024, 1, 15)},
+			from: date(2024, 1, 1),
+			to:   date(2024, 4, 30),
+			want: []time.Time{date(2024, 1, 31), date(2024, 2, 29), date(2024, 3, 31), date(2024, 4, 30)},
+		},
+		{
+			name: "Monthly Day Before Start Begins Next Month",
+			rule: RecurrenceRule{Frequency: RecurrenceMonthly, Interval: 1, MonthDay: &day31, StartDate: date(2024, 2, 1)},
+			from: date(2024, 1, 1),
+			to:   date(2024, 3, 1),
+			want: []time.Time{date(2024, 2, 29)},
+		},
+		{
+			name: "Last Business Day",
+			rule: RecurrenceRule{Frequency: RecurrenceMonthly, Interval: 1, LastBusinessDay: true, StartDate: date(2024, 3, 1)},
+			from: date(2024, 3, 1),
+			to:   date(2024, 6, 30),
+			want: []time.Time{date(2024, 3, 29), date(2024, 4, 30), date(2024, 5, 31), date(2024, 6, 28)},
+		},
+		{
+			name: "Every Two Weeks With End Date",
+			rule: RecurrenceRule{Frequency: RecurrenceWeekly, Interval: 2, StartDate: date(2024, 1, 1), EndDate: &end},
+			from: date(2024, 1, 1),
+			to:   date(2024, 12, 31),
+			want: []time.Time{date(2024, 1, 1), date(2024, 1, 15), date(2024, 1, 29)},
+		},
+		{
+			name: "Yearly With Count Counts Occurrences Before From",
+			rule: RecurrenceRule{Frequency: RecurrenceYearly, Interval: 1, StartDate: date(2024, 2, 29), Count: &count},
+			from: date(2025, 1, 1),
+			to:   date(2030, 12, 31),
+			want: []time.Time{date(2025, 2, 28), date(2026, 2, 28)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rule.Occurrences(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Occurrences()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceRule_AmountOn(t *testing.T) {
+	rule := RecurrenceRule{
+		Currency: "BRL",
+		Amount:   NewMoney(100000, "BRL"),
+		AmountChanges: []RecurrenceAmountChange{
+			{EffectiveFrom: date(2024, 3, 1), Amount: NewMoney(110000, "BRL")},
+			{EffectiveFrom: date(2024, 6, 1), Amount: NewMoney(120000, "BRL")},
+		},
+	}
+	tests := []struct {
+		on   time.Time
+		want int64
+	}{
+		{on: date(2024, 2, 29), want: 100000},
+		{on: date(2024, 3, 1), want: 110000},
+		{on: date(2024, 5, 31), want: 110000},
+		{on: date(2024, 7, 1), want: 120000},
+	}
+
+	for _, tt := range tests {
+		if got := rule.AmountOn(tt.on); got.Cents != tt.want {
+			t.Errorf("AmountOn(%s) = %d, want %d", tt.on.Format(time.DateOnly), got.Cents, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxReportMonths is the longest month range a report covers.
+const maxReportMonths = 60
+
+// Default and largest number of tags in a top tags report.
+const (
+	DefaultTopTagsLimit = 10
+	MaxTopTagsLimit     = 50
+)
+
+var ErrInvalidReportFilter = NewValidationError("invalid report filter")
+
+// ReportFilter scopes a report to an inclusive range of months (YYYYMM) and optionally to one
+// account and one currency. Months are accrual months, except in the cash flow report where they
+// are the months money actually moved.
+type ReportFilter struct {
+	MonthFrom string `json:"month_from"`
+	MonthTo   string `json:"month_to"`
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
+}
+
+// Normalize defaults the range to the twelve months up to today's and validates it.
+func (f *ReportFilter) Normalize(today time.Time) error {
+	if f.MonthTo == "" {
+		f.MonthTo = today.Format("200601")
+	}
+	if f.MonthFrom == "" {
+		from, err := AddPeriodMonths(f.MonthTo, -11)
+		if err != nil {
+			return fmt.Errorf("%w: month_to %q must be YYYYMM", ErrInvalidReportFilter, f.MonthTo)
+		}
+		f.MonthFrom = from
+	}
+
+	from, err := ParsePeriod(f.MonthFrom)
+	if err != nil {
+		return fmt.Errorf("%w: month_from %q must be YYYYMM", ErrInvalidReportFilter, f.MonthFrom)
+	}
+	to, err := ParsePeriod(f.MonthTo)
+	if err != nil {
+		return fmt.Errorf("%w: month_to %q must be YYYYMM", ErrInvalidReportFilter, f.MonthTo)
+	}
+	if from.After(to) {
+		return fmt.Errorf("%w: month_from is after month_to", ErrInvalidReportFilter)
+	}
+	if len(f.Months()) > maxReportMonths {
+		return fmt.Errorf("%w: the range spans more than %d months", ErrInvalidReportFilter, maxReportMonths)
+	}
+	return nil
+}
+
+// Months returns the months of a normalized filter, in order.
+func (f *ReportFilter) Months() []string {
+	var months []string
+	for month := f.MonthFrom; month <= f.MonthTo && len(months) <= maxReportMonths; {
+		months = append(months, month)
+		next, err := AddPeriodMonths(month, 1)
+		if err != nil {
+			break
+		}
+		month = next
+	}
+	return months
+}
+
+// CategorySpend is what was spent in an expense category in a month: Amount on the category itself,
+// Total on it and all its subcategories. Refunds (credits) are deducted.
+type CategorySpend struct {
+	Month            string  `json:"month"` // YYYYMM
+	CategoryID       string  `json:"category_id"`
+	ParentCategoryID *string `json:"parent_category_id,omitempty"`
+	Name             string  `json:"name"`
+	Currency         string  `json:"currency"`
+	Amount           Money   `json:"amount"`
+	Total            Money   `json:"total"`
+}
+
+// IncomeExpense is the income and expense of a month in one currency, by category type.
+type IncomeExpense struct {
+	Month    string `json:"month"` // YYYYMM
+	Currency string `json:"currency"`
+	Income   Money  `json:"income"`
+	Expense  Money  `json:"expense"`
+	Net      Money  `json:"net"` // Income - Expense
+}
+
+// CashFlow is the money that entered and left the accounts in a month, in one currency.
+type CashFlow struct {
+	Month    string `json:"month"` // YYYYMM
+	Currency string `json:"currency"`
+	Inflow   Money  `json:"inflow"`
+	Outflow  Money  `json:"outflow"`
+	Net      Money  `json:"net"` // Inflow - Outflow
+}
+
+// TagSpend is what was spent under a tag over a report's range, in one currency.
+type TagSpend struct {
+	TagID            string `json:"tag_id"`
+	Name             string `json:"name"`
+	Currency         string `json:"currency"`
+	Amount           Money  `json:"amount"`
+	TransactionCount int    `json:"transaction_count"`
+}
+
+// ReportRepository aggregates the transactions of a tenant, one row per currency since amounts in
+// different currencies are never added up. Category spend, income and expense and top tags count
+// credit and debit transactions by accrual month; the cash flow follows the account postings by
+// payment date, transfers and card payments included.
+type ReportRepository interface {
+	SpendByCategory(ctx context.Context, tenantID string, filter ReportFilter) ([]CategorySpend, error)
+	IncomeExpense(ctx context.Context, tenantID string, filter ReportFilter) ([]IncomeExpense, error)
+	CashFlow(ctx context.Context, tenantID string, filter ReportFilter) ([]CashFlow, error)
+	TopTags(ctx context.Context, tenantID string, filter ReportFilter, limit int) ([]TagSpend, error)
+}
+
+// FillIncomeExpense returns one row per month of months and currency of rows (or currency, when
+// rows is empty), with the months without activity at zero, and computes the net of each.
+func FillIncomeExpense(rows []IncomeExpense, months []string, currency string) []IncomeExpense {
+	return fillMonths(rows, months, currency,
+		func(r IncomeExpense) (string, string) { return r.Month, r.Currency },
+		func(month, currency string) IncomeExpense {
+			return IncomeExpense{Month: month, Currency: currency, Income: NewMoney(0, currency), Expense: NewMoney(0, currency)}
+		},
+		func(r *IncomeExpense) { r.Net = r.Income.Sub(r.Expense) },
+	)
+}
+
+// FillCashFlow is FillIncomeExpense for cash flow rows.
+func FillCashFlow(rows []CashFlow, months []string, currency string) []CashFlow {
+	return fillMonths(rows, months, currency,
+		func(r CashFlow) (string, string) { return r.Month, r.Currency },
+		func(month, currency string) CashFlow {
+			return CashFlow{Month: month, Currency: currency, Inflow: NewMoney(0, currency), Outflow: NewMoney(0, currency)}
+		},
+		func(r *CashFlow) { r.Net = r.Inflow.Sub(r.Outflow) },
+	)
+}
+
+// fillMonths completes rows keyed by (month, currency) so every currency has a row for every month,
+// ordered by currency and month, and applies finish to each.
+func fillMonths[T any](rows []T, months []string, currency string, key func(T) (string, string), zero func(month, currency string) T, finish func(*T)) []T {
+	byKey := make(map[[2]string]T, len(rows))
+	currencies := map[string]bool{}
+	for _, r := range rows {
+		month, ccy := key(r)
+		byKey[[2]string{month, ccy}] = r
+		currencies[ccy] = true
+	}
+	if len(currencies) == 0 && currency != "" {
+		currencies[currency] = true
+	}
+	ordered := make([]string, 0, len(currencies))
+	for ccy := range currencies {
+		ordered = append(ordered, ccy)
+	}
+	sort.Strings(ordered)
+
+	filled := make([]T, 0, len(ordered)*len(months))
+	for _, ccy := range ordered {
+		for _, month := range months {
+			r, ok := byKey[[2]string{month, ccy}]
+			if !ok {
+				r = zero(month, ccy)
+			}
+			finish(&r)
+			filled = append(filled, r)
+		}
+	}
+	return filled
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReportFilter_Normalize(t *testing.T) {
+	today := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		filter   ReportFilter
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{name: "defaults to the last twelve months", wantFrom: "202304", wantTo: "202403"},
+		{name: "from defaults relative to to", filter: ReportFilter{MonthTo: "202312"}, wantFrom: "202301", wantTo: "202312"},
+		{name: "explicit range", filter: ReportFilter{MonthFrom: "202401", MonthTo: "202401"}, wantFrom: "202401", wantTo: "202401"},
+		{name: "from after to", filter: ReportFilter{MonthFrom: "202402", MonthTo: "202401"}, wantErr: true},
+		{name: "invalid month", filter: ReportFilter{MonthFrom: "202413", MonthTo: "202501"}, wantErr: true},
+		{name: "invalid to", filter: ReportFilter{MonthTo: "2024-1"}, wantErr: true},
+		{name: "sixty months", filter: ReportFilter{MonthFrom: "201901", MonthTo: "202312"}, wantFrom: "201901", wantTo: "202312"},
+		{name: "more than sixty months", filter: ReportFilter{MonthFrom: "201812", MonthTo: "202312"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.filter
+			err := f.Normalize(today)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidReportFilter) {
+					t.Errorf("Normalize() error = %v, want ErrInvalidReportFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize() error = %v", err)
+			}
+			if f.MonthFrom != tt.wantFrom || f.MonthTo != tt.wantTo {
+				t.Errorf("Normalize() = %s..%s, want %s..%s", f.MonthFrom, f.MonthTo, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestReportFilter_Months(t *testing.T) {
+	f := ReportFilter{MonthFrom: "202311", MonthTo: "202402"}
+	got := f.Months()
+	want := []string{"202311", "202312", "202401", "202402"}
+	if len(got) != len(want) {
+		t.Fatalf("Months() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Months() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestFillIncomeExpense(t *testing.T) {
+	months := []string{"202401", "202402", "202403"}
+
+	t.Run("fills missing months per currency", func(t *testing.T) {
+		rows := []IncomeExpense{
+			{Month: "202402", Currency: "USD", Income: NewMoney(1000, "USD"), Expense: NewMoney(300, "USD")},
+			{Month: "202401", Currency: "BRL", Income: NewMoney(5000, "BRL"), Expense: NewMoney(7000, "BRL")},
+		}
+		got := FillIncomeExpense(rows, months, "")
+		if len(got) != 6 {
+			t.Fatalf("FillIncomeExpense() returned %d rows, want 6", len(got))
+		}
+		if got[0].Currency != "BRL" || got[0].Month != "202401" || got[0].Net != NewMoney(-2000, "BRL") {
+			t.Errorf("first row = %+v, want BRL 202401 with a net of -20.00", got[0])
+		}
+		if got[1].Month != "202402" || !got[1].Income.IsZero() || got[1].Net != NewMoney(0, "BRL") {
+			t.Errorf("second row = %+v, want an empty BRL 202402", got[1])
+		}
+		if got[4].Currency != "USD" || got[4].Month != "202402" || got[4].Net != NewMoney(700, "USD") {
+			t.Errorf("fifth row = %+v, want USD 202402 with a net of 7.00", got[4])
+		}
+	})
+
+	t.Run("no activity in the filtered currency", func(t *testing.T) {
+		got := FillIncomeExpense(nil, months, "EUR")
+		if len(got) != 3 || got[2].Currency != "EUR" || got[2].Expense != NewMoney(0, "EUR") {
+			t.Errorf("FillIncomeExpense() = %+v, want three empty EUR months", got)
+		}
+	})
+
+	t.Run("no activity at all", func(t *testing.T) {
+		if got := FillIncomeExpense(nil, months, ""); len(got) != 0 {
+			t.Errorf("FillIncomeExpense() = %+v, want no rows", got)
+		}
+	})
+}
+
+func TestFillCashFlow(t *testing.T) {
+	rows := []CashFlow{{Month: "202402", Currency: "BRL", Inflow: NewMoney(10000, "BRL"), Outflow: NewMoney(2500, "BRL")}}
+	got := FillCashFlow(rows, []string{"202401", "202402"}, "BRL")
+	if len(got) != 2 || !got[0].Net.IsZero() || got[1].Net != NewMoney(7500, "BRL") {
+		t.Errorf("FillCashFlow() = %+v, want an empty 202401 and a net of 75.00 in 202402", got)
+	}
+}
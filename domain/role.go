@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"slices"
+)
+
+var ErrForbidden = NewForbiddenError("insufficient permissions")
+
+// Role represents the role of a user within a tenant.
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+// Permission represents an action guarded by the tenant role.
+type Permission string
+
+const (
+	// PermissionRead allows reading every tenant resource.
+	PermissionRead Permission = "read"
+	// PermissionWrite allows creating, updating and deleting transactions, categories and tags,
+	// and creating and updating accounts.
+	PermissionWrite Permission = "write"
+	// PermissionDeleteAccounts allows deleting accounts.
+	PermissionDeleteAccounts Permission = "delete_accounts"
+	// PermissionManageMembers allows changing member roles and removing members.
+	PermissionManageMembers Permission = "manage_members"
+	// PermissionManageOwners allows granting, changing and removing the owner role.
+	PermissionManageOwners Permission = "manage_owners"
+)
+
+// rolePermissions is the permission matrix. Each role includes the permissions of the roles below it.
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: {PermissionRead},
+	RoleEditor: {PermissionRead, PermissionWrite},
+	RoleAdmin:  {PermissionRead, PermissionWrite, PermissionDeleteAccounts, PermissionManageMembers},
+	RoleOwner:  {PermissionRead, PermissionWrite, PermissionDeleteAccounts, PermissionManageMembers, PermissionManageOwners},
+}
+
+// Can reports whether the role grants the given permission.
+func (r Role) Can(p Permission) bool {
+	return slices.Contains(rolePermissions[r], p)
+}
+
+// IsValid reports whether r is a known role.
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+const roleKey contextKey = "role"
+
+// WithRole returns a new context with the role of the current user in the current tenant.
+func WithRole(ctx context.Context, role Role) context.Context {
+	return context.WithValue(ctx, roleKey, role)
+}
+
+// GetRole retrieves the role of the current user in the current tenant, or an empty role if it is not set.
+func GetRole(ctx context.Context) Role {
+	val, _ := ctx.Value(roleKey).(Role)
+	return val
+}
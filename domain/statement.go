@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var ErrInvalidStatementPeriod = NewValidationError("statement period must be in YYYYMM format")
+
+// StatementMinimumDuePercent is the share of a statement total that must be paid by its due date.
+const StatementMinimumDuePercent = 15
+
+// StatementStatus represents the lifecycle of a credit card statement.
+type StatementStatus string
+
+const (
+	StatementStatusOpen    StatementStatus = "open"
+	StatementStatusClosed  StatementStatus = "closed"
+	StatementStatusPaid    StatementStatus = "paid"
+	StatementStatusOverdue StatementStatus = "overdue"
+)
+
+// Statement represents a credit card statement (invoice).
+// Period is the YYYYMM of the statement's due date, which is also the accrual month
+// of every purchase billed on it and of the payments that settle it.
+type Statement struct {
+	AccountID   string          `json:"account_id"`
+	Period      string          `json:"period"` // YYYYMM
+	OpeningDate time.Time       `json:"opening_date"`
+	ClosingDate time.Time       `json:"closing_date"`
+	DueDate     time.Time       `json:"due_date"`
+	Charges     Money           `json:"charges"`
+	Credits     Money           `json:"credits"`
+	Total       Money           `json:"total"`
+	Paid        Money           `json:"paid"`
+	Balance     Money           `json:"balance"`
+	MinimumDue  Money           `json:"minimum_due"`
+	Status      StatementStatus `json:"status"`
+	Items       []Transaction   `json:"items,omitempty"`
+}
+
+// StatementRepository defines the interface for reading statement data.
+// Statements are not stored; they are derived from the card's transactions.
+type StatementRepository interface {
+	// ListTotals returns the charges, credits and payments of every period with activity,
+	// newest first. Only Period, Charges, Credits and Paid are filled.
+	ListTotals(ctx context.Context, tenantID, accountID string) ([]Statement, error)
+	GetTotals(ctx context.Context, tenantID, accountID, period string) (*Statement, error)
+	ListItems(ctx context.Context, tenantID, accountID, period string) ([]Transaction, error)
+}
+
+// StatementPeriod returns the period of the statement a purchase made on the given date is billed on.
+// Purchases made up to the closing day fall on the statement closing that month;
+// purchases made after it roll over to the next one.
+func (cci *CreditCardInfo) StatementPeriod(purchaseDate time.Time) string {
+	year, month, day := purchaseDate.Date()
+	closingMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	if day > clampDay(closingMonth, cci.ClosingDay()) {
+		closingMonth = closingMonth.AddDate(0, 1, 0)
+	}
+	return cci.dueMonth(closingMonth).Format("200601")
+}
+
+// StatementDates returns the opening, closing and due dates of the statement for the given period.
+func (cci *CreditCardInfo) StatementDates(period string) (opening, closing, due time.Time, err error) {
+	dueMonth, err := ParsePeriod(period)
+	if err != nil {
+		return time.Time{}, time.Time{}, time.Time{}, err
+	}
+
+	closingMonth := dueMonth
+	if !cci.dueInClosingMonth() {
+		closingMonth = dueMonth.AddDate(0, -1, 0)
+	}
+	previousClosingMonth := closingMonth.AddDate(0, -1, 0)
+
+	closing = withDay(closingMonth, cci.ClosingDay())
+	opening = withDay(previousClosingMonth, cci.ClosingDay()).AddDate(0, 0, 1)
+	due = withDay(dueMonth, cci.DueDay())
+	return opening, closing, due, nil
+}
+
+// dueInClosingMonth reports whether the due day comes after the closing day in the same month.
+// Otherwise the statement is due the month after it closes.
+func (cci *CreditCardInfo) dueInClosingMonth() bool {
+	return cci.DueDay() > cci.ClosingDay()
+}
+
+func (cci *CreditCardInfo) dueMonth(closingMonth time.Time) time.Time {
+	if cci.dueInClosingMonth() {
+		return closingMonth
+	}
+	return closingMonth.AddDate(0, 1, 0)
+}
+
+// CreditCardVersionAt returns the version of the card details a statement is dated by: the one in effect
+// when it closed, so later changes to the card only move the statements still open. versions must be
+// ordered oldest first, and not be empty.
+func CreditCardVersionAt(versions []CreditCardInfo, period string) (*CreditCardInfo, error) {
+	card := &versions[0]
+	for i := 1; i < len(versions); i++ {
+		_, closing, _, err := card.StatementDates(period)
+		if err != nil {
+			return nil, err
+		}
+		if !versions[i].CreatedAt.Before(closing.AddDate(0, 0, 1)) {
+			break // Closed before the change
+		}
+		card = &versions[i]
+	}
+	return card, nil
+}
+
+// Finalize derives the statement dates, totals, minimum due and status from its raw totals.
+// Charges, Credits and Paid must already be set; asOf decides whether the statement is still open or overdue.
+func (s *Statement) Finalize(cci *CreditCardInfo, currency string, asOf time.Time) error {
+	opening, closing, due, err := cci.StatementDates(s.Period)
+	if err != nil {
+		return err
+	}
+	s.OpeningDate, s.ClosingDate, s.DueDate = opening, closing, due
+
+	s.Charges = s.Charges.WithCurrency(currency)
+	s.Credits = s.Credits.WithCurrency(currency)
+	s.Paid = s.Paid.WithCurrency(currency)
+	s.Total = s.Charges.Sub(s.Credits)
+	s.Balance = s.Total.Sub(s.Paid)
+
+	// Minimum due is a share of the total, rounded up to the cent, minus what was already paid.
+	minimum := NewMoney((s.Total.Cents*StatementMinimumDuePercent+99)/100, currency).Sub(s.Paid)
+	if minimum.Cmp(s.Balance) > 0 {
+		minimum = s.Balance
+	}
+	if minimum.IsNegative() {
+		minimum = NewMoney(0, currency)
+	}
+	s.MinimumDue = minimum
+
+	today := truncateToDate(asOf)
+	switch {
+	case !today.After(s.ClosingDate):
+		s.Status = StatementStatusOpen
+	case !s.Balance.IsPositive():
+		s.Status = StatementStatusPaid
+	case today.After(s.DueDate):
+		s.Status = StatementStatusOverdue
+	default:
+		s.Status = StatementStatusClosed
+	}
+	return nil
+}
+
+// ParsePeriod parses a YYYYMM period into the first day of that month (UTC).
+func ParsePeriod(period string) (time.Time, error) {
+	if len(period) != 6 {
+		return time.Time{}, ErrInvalidStatementPeriod
+	}
+	t, err := time.Parse("200601", period)
+	if err != nil {
+		return time.Time{}, ErrInvalidStatementPeriod
+	}
+	return t, nil
+}
+
+// AddPeriodMonths shifts a YYYYMM period by the given number of months.
+func AddPeriodMonths(period string, months int) (string, error) {
+	t, err := ParsePeriod(period)
+	if err != nil {
+		return "", err
+	}
+	return t.AddDate(0, months, 0).Format("200601"), nil
+}
+
+// withDay returns the given day of month, snapping to the last day when the month is shorter.
+func withDay(month time.Time, day int) time.Time {
+	return time.Date(month.Year(), month.Month(), clampDay(month, day), 0, 0, 0, 0, time.UTC)
+}
+
+func clampDay(month time.Time, day int) int {
+	lastDay := time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > lastDay {
+		return lastDay
+	}
+	return day
+}
+
+func truncateToDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCreditCardInfo_StatementPeriod(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		closing  int
+		due      int
+		purchase time.Time
+		want     string
+		wantDue  time.Time
+	}{
+		{name: "on closing day, due next month", closing: 25, due: 5, purchase: date(2024, 1, 25), want: "202402", wantDue: date(2024, 2, 5)},
+		{name: "after closing day, due next month", closing: 25, due: 5, purchase: date(2024, 1, 26), want: "202403", wantDue: date(2024, 3, 5)},
+		{name: "due in closing month", closing: 3, due: 10, purchase: date(2024, 1, 2), want: "202401", wantDue: date(2024, 1, 10)},
+		{name: "closing day snaps to month end", closing: 31, due: 10, purchase: date(2024, 2, 29), want: "202403", wantDue: date(2024, 3, 10)},
+		{name: "year rollover", closing: 20, due: 1, purchase: date(2024, 12, 21), want: "202502", wantDue: date(2025, 2, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := &CreditCardInfo{
+				ClosingDate: date(2024, 1, tt.closing),
+				DueDate:     date(2024, 1, tt.due),
+			}
+			got := card.StatementPeriod(tt.purchase)
+			if got != tt.want {
+				t.Fatalf("period: got %s, want %s", got, tt.want)
+			}
+			_, closing, due, err := card.StatementDates(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !due.Equal(tt.wantDue) {
+				t.Errorf("due date: got %v, want %v", due, tt.wantDue)
+			}
+			if tt.purchase.After(closing) {
+				t.Errorf("purchase %v falls after the statement closing date %v", tt.purchase, closing)
+			}
+		})
+	}
+}
+
+func TestStatement_Finalize(t *testing.T) {
+	card := &CreditCardInfo{
+		ClosingDate: time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC),
+		DueDate:     time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name        string
+		paid        int64
+		asOf        time.Time
+		wantStatus  StatementStatus
+		wantMinimum int64
+	}{
+		{name: "open", asOf: time.Date(2024, 1, 25, 12, 0, 0, 0, time.UTC), wantStatus: StatementStatusOpen, wantMinimum: 15000},
+		{name: "closed", asOf: time.Date(2024, 1, 26, 0, 0, 0, 0, time.UTC), wantStatus: StatementStatusClosed, wantMinimum: 15000},
+		{name: "partially paid and overdue", paid: 10000, asOf: time.Date(2024, 2, 6, 0, 0, 0, 0, time.UTC), wantStatus: StatementStatusOverdue, wantMinimum: 5000},
+		{name: "paid", paid: 100000, asOf: time.Date(2024, 2, 6, 0, 0, 0, 0, time.UTC), wantStatus: StatementStatusPaid, wantMinimum: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Statement{Period: "202402", Charges: NewMoney(110000, ""), Credits: NewMoney(10000, ""), Paid: NewMoney(tt.paid, "")}
+			if err := s.Finalize(card, "BRL", tt.asOf); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s.Status != tt.wantStatus {
+				t.Errorf("status: got %s, want %s", s.Status, tt.wantStatus)
+			}
+			if s.MinimumDue.Cents != tt.wantMinimum {
+				t.Errorf("minimum due: got %s, want %d cents", s.MinimumDue, tt.wantMinimum)
+			}
+			if s.Total.Cents != 100000 || s.Total.Currency != "BRL" {
+				t.Errorf("total: got %s %s, want 1000.00 BRL", s.Total, s.Total.Currency)
+			}
+		})
+	}
+}
+
+func TestCreditCardVersionAt(t *testing.T) {
+	date := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC) }
+	versions := []CreditCardInfo{
+		// Closes on the 25th, due on the 5th of the next month
+		{ID: "v1", ClosingDate: date(1, 25), DueDate: date(1, 5), CreatedAt: date(1, 1)},
+		// Changed on March 10th to close on the 10th and be due on the 20th
+		{ID: "v2", ClosingDate: date(1, 10), DueDate: date(1, 20), CreatedAt: date(3, 10).Add(15 * time.Hour)},
+	}
+
+	tests := []struct {
+		period      string
+		wantVersion string
+		wantClosing time.Time
+	}{
+		{period: "202312", wantVersion: "v1", wantClosing: time.Date(2023, 11, 25, 0, 0, 0, 0, time.UTC)},
+		{period: "202403", wantVersion: "v1", wantClosing: date(2, 25)},
+		// Still open under v1 when the card changed
+		{period: "202404", wantVersion: "v2", wantClosing: date(4, 10)},
+		{period: "202405", wantVersion: "v2", wantClosing: date(5, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			card, err := CreditCardVersionAt(versions, tt.period)
+			if err != nil {
+				t.Fatalf("CreditCardVersionAt() error = %v", err)
+			}
+			_, closing, _, _ := card.StatementDates(tt.period)
+			if card.ID != tt.wantVersion || !closing.Equal(tt.wantClosing) {
+				t.Errorf("CreditCardVersionAt() = %s closing %v, want %s closing %v", card.ID, closing, tt.wantVersion, tt.wantClosing)
+			}
+		})
+	}
+}
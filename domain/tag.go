@@ -6,6 +6,8 @@ import (
 	"time"
 )
 
+var ErrTagNotFound = NewNotFoundError("tag not found")
+
 // Tag represents a label for transactions.
 type Tag struct {
 	ID            string     `json:"id"`
@@ -15,6 +17,7 @@ type Tag struct {
 	CreatedBy     string     `json:"created_by"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 	UpdatedBy     string     `json:"updated_by"`
+	Version       int        `json:"version"` // Incremented on every update
 	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
 	DeactivatedBy *string    `json:"deactivated_by,omitempty"`
 }
@@ -22,8 +25,9 @@ type Tag struct {
 // TagRepository defines the interface for tag persistence.
 type TagRepository interface {
 	GetByID(ctx context.Context, id, tenantID string) (*Tag, error)
-	List(ctx context.Context, tenantID string) ([]Tag, error)
+	List(ctx context.Context, tenantID string, page PageRequest) (*Page[Tag], error)
 	Create(ctx context.Context, tag *Tag) error
+	// Update only applies to tag.Version when it is set (ErrVersionMismatch otherwise) and sets it to the new version.
 	Update(ctx context.Context, tag *Tag) error
 	Delete(ctx context.Context, id, tenantID, userID string) error
 	ValidateTags(ctx context.Context, tenantID string, tagIDs []string) (bool, error)
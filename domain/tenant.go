@@ -6,6 +6,8 @@ import (
 	"time"
 )
 
+var ErrTenantNotFound = NewNotFoundError("tenant not found")
+
 // Tenant represents a tenant in the system.
 type Tenant struct {
 	ID            string     `json:"id"`
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 	"time"
 )
@@ -19,25 +20,45 @@ const (
 
 // Transaction represents a financial movement.
 type Transaction struct {
-	ID                  string          `json:"id"`
-	ParentTransactionID *string         `json:"parent_transaction_id,omitempty"`
-	TenantID            string          `json:"tenant_id"`
-	FromAccountID       string          `json:"from_account_id"`
-	ToAccountID         *string         `json:"to_account_id,omitempty"`
-	Currency            string          `json:"currency"`
-	Amount              float64         `json:"amount"`
-	AccrualMonth        string          `json:"accrual_month"` // YYYYMM
-	TransactionType     TransactionType `json:"transaction_type"`
-	CategoryID          string          `json:"category_id"`
-	Comments            *string         `json:"comments,omitempty"`
-	DueDate             time.Time       `json:"due_date"`
-	PaymentDate         *time.Time      `json:"payment_date,omitempty"`
-	CreatedAt           time.Time       `json:"created_at"`
-	CreatedBy           string          `json:"created_by"`
-	UpdatedAt           time.Time       `json:"updated_at"`
-	UpdatedBy           string          `json:"updated_by"`
-	DeactivatedAt       *time.Time      `json:"deactivated_at,omitempty"`
-	DeactivatedBy       *string         `json:"deactivated_by,omitempty"`
+	ID                  string             `json:"id"`
+	ParentTransactionID *string            `json:"parent_transaction_id,omitempty"`
+	InstallmentNumber   *int               `json:"installment_number,omitempty"` // Position in the installment series, from 1
+	InstallmentCount    *int               `json:"installment_count,omitempty"`  // Size of the installment series
+	RecurrenceRuleID    *string            `json:"recurrence_rule_id,omitempty"` // Set on occurrences materialized from a recurrence rule
+	RecurrenceDate      *time.Time         `json:"recurrence_date,omitempty"`    // Scheduled date of the occurrence
+	TenantID            string             `json:"tenant_id"`
+	FromAccountID       string             `json:"from_account_id"`
+	ToAccountID         *string            `json:"to_account_id,omitempty"`
+	Currency            string             `json:"currency"`
+	Amount              Money              `json:"amount"`
+	AccrualMonth        string             `json:"accrual_month"` // YYYYMM
+	TransactionType     TransactionType    `json:"transaction_type"`
+	CategoryID          string             `json:"category_id"`
+	Comments            *string            `json:"comments,omitempty"`
+	DueDate             time.Time          `json:"due_date"`
+	PaymentDate         *time.Time         `json:"payment_date,omitempty"`          // Set once the transaction is paid in full
+	SettledAmount       Money              `json:"settled_amount"`                  // Sum of the partial payments recorded so far
+	LinkedTransactionID *string            `json:"linked_transaction_id,omitempty"` // Transfers: the other leg
+	TransferDirection   *TransferDirection `json:"transfer_direction,omitempty"`    // Transfers: which leg this is
+	ExchangeRate        ExchangeRate       `json:"exchange_rate,omitempty"`         // Transfers: destination units per source unit
+	Fee                 Money              `json:"fee"`                             // Transfers: charged to the source account on top of the amount
+	CreatedAt           time.Time          `json:"created_at"`
+	CreatedBy           string             `json:"created_by"`
+	UpdatedAt           time.Time          `json:"updated_at"`
+	UpdatedBy           string             `json:"updated_by"`
+	Version             int                `json:"version"` // Incremented on every update
+	DeactivatedAt       *time.Time         `json:"deactivated_at,omitempty"`
+	DeactivatedBy       *string            `json:"deactivated_by,omitempty"`
+	Tags                []Tag              `json:"tags,omitempty"` // Attached by the service; not a column
+}
+
+// TagIDs returns the IDs of the tags attached to the transaction.
+func (t *Transaction) TagIDs() []string {
+	ids := make([]string, len(t.Tags))
+	for i := range t.Tags {
+		ids[i] = t.Tags[i].ID
+	}
+	return ids
 }
 
 // TransactionTag represents the association between a transaction and a tag.
@@ -60,27 +81,125 @@ type TransactionAttachment struct {
 	DeactivatedBy *string    `json:"deactivated_by,omitempty"`
 }
 
-// TransactionFilter defines optional filters for listing transactions.
+// TransactionStatus is the payment status of a transaction, which a transaction list can be filtered by.
+type TransactionStatus string
+
+const (
+	TransactionStatusPaid          TransactionStatus = "paid"           // payment_date is set
+	TransactionStatusUnpaid        TransactionStatus = "unpaid"         // payment_date is not set (filter only: pending, partially paid or overdue)
+	TransactionStatusOverdue       TransactionStatus = "overdue"        // unpaid and due before today
+	TransactionStatusPending       TransactionStatus = "pending"        // unpaid, not overdue, nothing settled
+	TransactionStatusPartiallyPaid TransactionStatus = "partially_paid" // unpaid, not overdue, partly settled
+)
+
+// TagMatch selects whether a transaction must carry any or all of the filtered tags.
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
+var ErrInvalidTransactionFilter = NewValidationError("invalid transaction filter")
+
+// TransactionFilter defines optional filters for listing transactions. Zero values mean "no filter";
+// date and amount ranges are inclusive and may be open on either side.
 type TransactionFilter struct {
-	AccrualMonth    string          `json:"accrual_month"`
-	AccountID       string          `json:"account_id"`
-	TransactionType TransactionType `json:"transaction_type"`
+	AccrualMonth     string          `json:"accrual_month"`
+	AccrualMonthFrom string          `json:"accrual_month_from"`
+	AccrualMonthTo   string          `json:"accrual_month_to"`
+	AccountID        string          `json:"account_id"`
+	TransactionType  TransactionType `json:"transaction_type"`
+	DueDateFrom      *time.Time      `json:"due_date_from"`
+	DueDateTo        *time.Time      `json:"due_date_to"`
+	PaymentDateFrom  *time.Time      `json:"payment_date_from"`
+	PaymentDateTo    *time.Time      `json:"payment_date_to"`
+	MinAmount        *Money          `json:"min_amount"`
+	MaxAmount        *Money          `json:"max_amount"`
+	// CategoryIDs matches any of the categories; with IncludeSubcategories, their descendants as well.
+	CategoryIDs          []string          `json:"category_ids"`
+	IncludeSubcategories bool              `json:"include_subcategories"`
+	TagIDs               []string          `json:"tag_ids"`
+	TagMatch             TagMatch          `json:"tag_match"` // Defaults to any
+	Status               TransactionStatus `json:"status"`
+	// ParentTransactionID selects an installment series: the parent and its children.
+	ParentTransactionID string `json:"parent_transaction_id"`
+	Currency            string `json:"currency"`
+	// Search matches comments case-insensitively.
+	Search string `json:"search"`
+}
+
+// Validate checks that the ranges and enums of the filter are consistent.
+func (f *TransactionFilter) Validate() error {
+	for _, period := range []string{f.AccrualMonth, f.AccrualMonthFrom, f.AccrualMonthTo} {
+		if period == "" {
+			continue
+		}
+		if _, err := ParsePeriod(period); err != nil {
+			return fmt.Errorf("%w: accrual month %q must be YYYYMM", ErrInvalidTransactionFilter, period)
+		}
+	}
+	if f.AccrualMonthFrom != "" && f.AccrualMonthTo != "" && f.AccrualMonthFrom > f.AccrualMonthTo {
+		return fmt.Errorf("%w: accrual_month_from is after accrual_month_to", ErrInvalidTransactionFilter)
+	}
+	if f.DueDateFrom != nil && f.DueDateTo != nil && f.DueDateFrom.After(*f.DueDateTo) {
+		return fmt.Errorf("%w: due_date_from is after due_date_to", ErrInvalidTransactionFilter)
+	}
+	if f.PaymentDateFrom != nil && f.PaymentDateTo != nil && f.PaymentDateFrom.After(*f.PaymentDateTo) {
+		return fmt.Errorf("%w: payment_date_from is after payment_date_to", ErrInvalidTransactionFilter)
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && f.MinAmount.Cents > f.MaxAmount.Cents {
+		return fmt.Errorf("%w: min_amount is greater than max_amount", ErrInvalidTransactionFilter)
+	}
+	switch f.TagMatch {
+	case "", TagMatchAny, TagMatchAll:
+	default:
+		return fmt.Errorf("%w: tag_match must be any or all", ErrInvalidTransactionFilter)
+	}
+	switch f.Status {
+	case "", TransactionStatusPaid, TransactionStatusUnpaid, TransactionStatusOverdue, TransactionStatusPending, TransactionStatusPartiallyPaid:
+	default:
+		return fmt.Errorf("%w: status must be paid, unpaid, overdue, pending or partially_paid", ErrInvalidTransactionFilter)
+	}
+	if f.IncludeSubcategories && len(f.CategoryIDs) == 0 {
+		return fmt.Errorf("%w: include_subcategories requires category_id", ErrInvalidTransactionFilter)
+	}
+	return nil
 }
 
 // TransactionRepository defines the interface for transaction persistence.
 type TransactionRepository interface {
 	GetByID(ctx context.Context, tenantID, id string) (*Transaction, error)
-	List(ctx context.Context, tenantID string, filter TransactionFilter) ([]Transaction, error)
+	List(ctx context.Context, tenantID string, filter TransactionFilter, page PageRequest) (*Page[Transaction], error)
 	Create(ctx context.Context, tx *Transaction) error
 	CreateWithInstallments(ctx context.Context, parent *Transaction, children []Transaction, tagIDs []string) error
+	// Update only applies to tx.Version when it is set (ErrVersionMismatch otherwise) and sets it to the new version.
 	Update(ctx context.Context, tx *Transaction) error
+	// Delete soft-deletes the transaction and, for a transfer, its other leg.
 	Delete(ctx context.Context, tenantID, id, userID string) error
 
+	// Transfers. Both legs are written in a single database transaction; UpdateTransfer saves each at its Version.
+	CreateTransfer(ctx context.Context, out, in *Transaction, tagIDs []string) error
+	UpdateTransfer(ctx context.Context, out, in *Transaction) error
+
+	// Installment series
+	ListSeries(ctx context.Context, tenantID, parentID string) ([]Transaction, error)
+	ApplySeriesChange(ctx context.Context, tenantID, userID string, change SeriesChange) error
+
 	// Tag associations
 	AddTagsToTransaction(ctx context.Context, transactionID string, tagIDs []string) error
 	ReplaceTags(ctx context.Context, transactionID string, tagIDs []string) error
 	RemoveTagFromTransaction(ctx context.Context, transactionID, tagID string) error
-	ListTransactionTags(ctx context.Context, transactionID string) ([]Tag, error)
+	// ListTagsForTransactions returns the active tags of each transaction, keyed by transaction ID.
+	ListTagsForTransactions(ctx context.Context, transactionIDs []string) (map[string][]Tag, error)
+
+	// Payment ledger. The settlement state of the transaction (SettledAmount and PaymentDate) is saved with
+	// its payments, at its Version (ErrVersionMismatch otherwise), which is then set to the new version.
+	// AddPayments records the payments and the settlement state of the transactions they settle, atomically.
+	AddPayments(ctx context.Context, txs []*Transaction, payments []TransactionPayment) error
+	// ClearPayments deactivates the payments of t and saves its settlement state.
+	ClearPayments(ctx context.Context, t *Transaction, userID string) error
+	ListPayments(ctx context.Context, tenantID, transactionID string) ([]TransactionPayment, error)
 
 	// Attachment associations
 	AddAttachment(ctx context.Context, attachment *TransactionAttachment) error
@@ -96,7 +215,7 @@ func (t *Transaction) IsValid() (bool, map[string]error) {
 	if t.FromAccountID == "" {
 		err["from_account_id"] = errors.New("from_account_id is required")
 	}
-	if t.Amount <= 0 {
+	if !t.Amount.IsPositive() {
 		err["amount"] = errors.New("amount must be greater than 0")
 	}
 	if t.TransactionType == "" {
@@ -108,8 +227,7 @@ func (t *Transaction) IsValid() (bool, map[string]error) {
 		} else if t.TransactionType == TransactionTypeTransfer {
 			if t.ToAccountID == nil || *t.ToAccountID == "" {
 				err["to_account_id"] = errors.New("to_account_id is required for transfers")
-			}
-			if t.FromAccountID == *t.ToAccountID {
+			} else if t.FromAccountID == *t.ToAccountID {
 				err["to_account_id"] = errors.New("to_account_id must be different from from_account_id")
 			}
 		}
@@ -124,6 +242,10 @@ func (t *Transaction) IsValid() (bool, map[string]error) {
 	if t.DueDate.IsZero() {
 		err["due_date"] = errors.New("due_date is required")
 	}
+	if t.Amount.Cmp(t.SettledAmount) < 0 {
+		err["amount"] = errors.New("amount must not be less than the settled amount")
+	}
+	t.validateTransferFields(err)
 	if len(err) == 0 {
 		return true, nil
 	}
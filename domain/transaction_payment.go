@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrTransactionAlreadyPaid = NewConflictError("transaction is already paid")
+	ErrPaymentExceedsAmount   = InvalidField("amount", "amount exceeds the outstanding amount of the transaction")
+)
+
+// TransactionPayment is a settlement of part (or all) of a transaction, made from an account on a date.
+// The payments of a transaction form its ledger; their sum is the transaction's SettledAmount.
+type TransactionPayment struct {
+	ID            string     `json:"id"`
+	TenantID      string     `json:"tenant_id"`
+	TransactionID string     `json:"transaction_id"`
+	AccountID     string     `json:"account_id"` // Account the payment is made from (received into, for credits)
+	Amount        Money      `json:"amount"`
+	PaymentDate   time.Time  `json:"payment_date"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CreatedBy     string     `json:"created_by"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	DeactivatedBy *string    `json:"deactivated_by,omitempty"`
+}
+
+func (p *TransactionPayment) IsValid() (bool, map[string]error) {
+	err := make(map[string]error)
+	if p.TransactionID == "" {
+		err["transaction_id"] = errors.New("transaction_id is required")
+	}
+	if p.AccountID == "" {
+		err["account_id"] = errors.New("account_id is required")
+	}
+	if !p.Amount.IsPositive() {
+		err["amount"] = errors.New("amount must be greater than 0")
+	}
+	if p.PaymentDate.IsZero() {
+		err["payment_date"] = errors.New("payment_date is required")
+	}
+	if len(err) == 0 {
+		return true, nil
+	}
+	return false, err
+}
+
+// Outstanding returns the amount of the transaction still to be settled: none once it is paid.
+func (t *Transaction) Outstanding() Money {
+	if t.PaymentDate != nil {
+		return NewMoney(0, t.Currency)
+	}
+	if outstanding := t.Amount.Sub(t.SettledAmount); outstanding.IsPositive() {
+		return outstanding.WithCurrency(t.Currency)
+	}
+	return NewMoney(0, t.Currency)
+}
+
+// Settle records p against t. A zero p.Amount settles the outstanding amount. The transaction is paid,
+// with p.PaymentDate as its payment date, once its payments add up to its amount.
+func (t *Transaction) Settle(p *TransactionPayment) error {
+	if t.PaymentDate != nil {
+		return ErrTransactionAlreadyPaid
+	}
+	outstanding := t.Outstanding()
+	if p.Amount.IsZero() {
+		p.Amount = outstanding
+	}
+	p.Amount.Currency = t.Currency
+	p.TransactionID = t.ID
+	p.TenantID = t.TenantID
+	if valid, errs := p.IsValid(); !valid {
+		return InvalidFields(errs)
+	}
+	if p.Amount.Cmp(outstanding) > 0 {
+		return ErrPaymentExceedsAmount
+	}
+
+	t.SettledAmount = t.SettledAmount.Add(p.Amount).WithCurrency(t.Currency)
+	if t.SettledAmount.Cmp(t.Amount) == 0 {
+		paymentDate := p.PaymentDate
+		t.PaymentDate = &paymentDate
+	}
+	return nil
+}
+
+// Unsettle reverts t to unpaid, discarding its settled amount. The payments themselves are
+// deactivated by the repository.
+func (t *Transaction) Unsettle() {
+	t.PaymentDate = nil
+	t.SettledAmount = NewMoney(0, t.Currency)
+}
+
+// Status derives the payment status of t as of today: paid once its payment date is set, overdue
+// when unpaid past its due date, and otherwise partially paid or pending depending on its payments.
+func (t *Transaction) Status(today time.Time) TransactionStatus {
+	switch {
+	case t.PaymentDate != nil:
+		return TransactionStatusPaid
+	case t.DueDate.Before(startOfDay(today)):
+		return TransactionStatusOverdue
+	case t.SettledAmount.IsPositive():
+		return TransactionStatusPartiallyPaid
+	}
+	return TransactionStatusPending
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
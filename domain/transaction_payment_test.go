@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransaction_Settle(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	bill := func() *Transaction {
+		return &Transaction{ID: "tx-1", TenantID: "tenant-1", Currency: "BRL", Amount: NewMoney(30000, "BRL"), DueDate: day(10)}
+	}
+
+	t.Run("partial payments until paid", func(t *testing.T) {
+		tx := bill()
+		first := TransactionPayment{AccountID: "acc-1", Amount: NewMoney(10000, ""), PaymentDate: day(1)}
+		if err := tx.Settle(&first); err != nil {
+			t.Fatalf("Settle() error = %v", err)
+		}
+		if tx.PaymentDate != nil || tx.SettledAmount != NewMoney(10000, "BRL") {
+			t.Fatalf("after first payment: payment_date = %v, settled = %v", tx.PaymentDate, tx.SettledAmount)
+		}
+		if first.TransactionID != "tx-1" || first.Amount.Currency != "BRL" {
+			t.Errorf("payment = %+v, want it bound to tx-1 in BRL", first)
+		}
+
+		// A zero amount settles the rest
+		rest := TransactionPayment{AccountID: "acc-1", PaymentDate: day(5)}
+		if err := tx.Settle(&rest); err != nil {
+			t.Fatalf("Settle() error = %v", err)
+		}
+		if rest.Amount != NewMoney(20000, "BRL") {
+			t.Errorf("rest amount = %v, want 200.00", rest.Amount)
+		}
+		if tx.PaymentDate == nil || !tx.PaymentDate.Equal(day(5)) {
+			t.Errorf("payment_date = %v, want %v", tx.PaymentDate, day(5))
+		}
+		if !tx.Outstanding().IsZero() {
+			t.Errorf("outstanding = %v, want 0", tx.Outstanding())
+		}
+
+		if err := tx.Settle(&TransactionPayment{AccountID: "acc-1", PaymentDate: day(6)}); !errors.Is(err, ErrTransactionAlreadyPaid) {
+			t.Errorf("Settle() on a paid transaction error = %v, want ErrTransactionAlreadyPaid", err)
+		}
+	})
+
+	t.Run("overpayment", func(t *testing.T) {
+		tx := bill()
+		err := tx.Settle(&TransactionPayment{AccountID: "acc-1", Amount: NewMoney(30001, ""), PaymentDate: day(1)})
+		if !errors.Is(err, ErrPaymentExceedsAmount) {
+			t.Errorf("Settle() error = %v, want ErrPaymentExceedsAmount", err)
+		}
+		if !tx.SettledAmount.IsZero() {
+			t.Errorf("settled = %v, want 0", tx.SettledAmount)
+		}
+	})
+
+	t.Run("missing payment date", func(t *testing.T) {
+		var verr *ValidationError
+		if err := bill().Settle(&TransactionPayment{AccountID: "acc-1"}); !errors.As(err, &verr) || verr.Fields["payment_date"] == "" {
+			t.Errorf("Settle() error = %v, want a payment_date validation error", err)
+		}
+	})
+
+	t.Run("unsettle", func(t *testing.T) {
+		tx := bill()
+		if err := tx.Settle(&TransactionPayment{AccountID: "acc-1", PaymentDate: day(1)}); err != nil {
+			t.Fatalf("Settle() error = %v", err)
+		}
+		tx.Unsettle()
+		if tx.PaymentDate != nil || !tx.SettledAmount.IsZero() || tx.Outstanding() != tx.Amount {
+			t.Errorf("after Unsettle: payment_date = %v, settled = %v", tx.PaymentDate, tx.SettledAmount)
+		}
+	})
+}
+
+func TestTransaction_Status(t *testing.T) {
+	today := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	paid := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	dueToday := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	dueYesterday := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		tx   Transaction
+		want TransactionStatus
+	}{
+		{name: "pending", tx: Transaction{DueDate: dueToday}, want: TransactionStatusPending},
+		{name: "partially paid", tx: Transaction{DueDate: dueToday, SettledAmount: NewMoney(100, "BRL")}, want: TransactionStatusPartiallyPaid},
+		{name: "overdue", tx: Transaction{DueDate: dueYesterday}, want: TransactionStatusOverdue},
+		{name: "overdue after a partial payment", tx: Transaction{DueDate: dueYesterday, SettledAmount: NewMoney(100, "BRL")}, want: TransactionStatusOverdue},
+		{name: "paid", tx: Transaction{DueDate: dueYesterday, PaymentDate: &paid}, want: TransactionStatusPaid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tx.Status(today); got != tt.want {
+				t.Errorf("Status() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package domain
+
+import "time"
+
+var (
+	ErrTransactionNotFound       = NewNotFoundError("transaction not found")
+	ErrNotInstallmentSeries      = NewNotFoundError("transaction is not part of an installment series")
+	ErrInvalidSeriesScope        = NewValidationError("scope must be this, following or all")
+	ErrNoUnpaidInstallments      = NewConflictError("series has no unpaid installments")
+	ErrInvalidPayOffDiscount     = NewValidationError("discount must be zero or positive and less than the remaining amount")
+	ErrPartiallyPaidInstallments = NewConflictError("series has partially paid installments; clear their payments before cancelling")
+)
+
+// SeriesScope selects which installments of a series a change applies to.
+type SeriesScope string
+
+const (
+	SeriesScopeThis      SeriesScope = "this"      // Only the given installment
+	SeriesScopeFollowing SeriesScope = "following" // The given installment and the ones after it
+	SeriesScopeAll       SeriesScope = "all"       // Every installment of the series
+)
+
+// TransactionSeries is an installment series: the parent transaction and its installments,
+// ordered by installment number. Payment status is evaluated as of AsOf.
+type TransactionSeries struct {
+	ParentID     string
+	Installments []Transaction
+	AsOf         time.Time
+}
+
+// SeriesChange is a change to the installments of a series, applied atomically.
+type SeriesChange struct {
+	Updated   []Transaction // Installments to rewrite
+	Cancelled []string      // IDs of installments to soft-delete
+	// PaymentsTo, when set, is the ID of the installment the ledger payments of the Cancelled ones move to
+	PaymentsTo string
+	TagIDs     []string // When not nil, replaces the tags of the Updated installments
+}
+
+// IsPaid reports whether the transaction was paid by now.
+// A future payment date, such as a card installment not yet posted, does not count as paid.
+func (t *Transaction) IsPaid(now time.Time) bool {
+	return t.PaymentDate != nil && !t.PaymentDate.After(now)
+}
+
+// SeriesParentID returns the ID of the parent of the installment series t belongs to.
+// It returns ErrNotInstallmentSeries for transactions that are not installments.
+func (t *Transaction) SeriesParentID() (string, error) {
+	if t.InstallmentNumber == nil {
+		return "", ErrNotInstallmentSeries
+	}
+	if t.ParentTransactionID != nil {
+		return *t.ParentTransactionID, nil
+	}
+	return t.ID, nil
+}
+
+// Totals returns the sum of all installments and how much of it is paid and remaining.
+func (s *TransactionSeries) Totals() (total, paid, remaining Money) {
+	for i := range s.Installments {
+		inst := &s.Installments[i]
+		total = total.Add(inst.Amount)
+		if inst.IsPaid(s.AsOf) {
+			paid = paid.Add(inst.Amount)
+		} else {
+			// Partial payments of an unpaid installment count as paid
+			paid = paid.Add(inst.SettledAmount)
+			remaining = remaining.Add(inst.Amount.Sub(inst.SettledAmount))
+		}
+	}
+	return total, paid, remaining
+}
+
+// Select returns copies of the installments a scope covers, relative to the installment with the given ID.
+func (s *TransactionSeries) Select(id string, scope SeriesScope) ([]Transaction, error) {
+	idx := -1
+	for i := range s.Installments {
+		if s.Installments[i].ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, ErrTransactionNotFound
+	}
+
+	var selected []Transaction
+	switch scope {
+	case SeriesScopeThis:
+		selected = s.Installments[idx : idx+1]
+	case SeriesScopeFollowing:
+		selected = s.Installments[idx:]
+	case SeriesScopeAll:
+		selected = s.Installments
+	default:
+		return nil, ErrInvalidSeriesScope
+	}
+	return append([]Transaction(nil), selected...), nil
+}
+
+// Unpaid returns copies of the installments that are not paid yet.
+func (s *TransactionSeries) Unpaid() []Transaction {
+	var unpaid []Transaction
+	for i := range s.Installments {
+		if !s.Installments[i].IsPaid(s.AsOf) {
+			unpaid = append(unpaid, s.Installments[i])
+		}
+	}
+	return unpaid
+}
+
+// Cancel returns the change that soft-deletes the unpaid installments. Installments with ledger payments
+// cannot be cancelled, as their payments would drop out of the balances.
+func (s *TransactionSeries) Cancel() (SeriesChange, error) {
+	unpaid := s.Unpaid()
+	if len(unpaid) == 0 {
+		return SeriesChange{}, ErrNoUnpaidInstallments
+	}
+
+	var change SeriesChange
+	for i := range unpaid {
+		if unpaid[i].SettledAmount.IsPositive() {
+			return SeriesChange{}, ErrPartiallyPaidInstallments
+		}
+		change.Cancelled = append(change.Cancelled, unpaid[i].ID)
+	}
+	return change, nil
+}
+
+// Reschedule returns the change that moves the unpaid installments to monthly due dates starting at firstDueDate.
+// Accrual months move by as many months as the due dates, and a future payment date by as many days,
+// so card installments stay aligned with their statements.
+func (s *TransactionSeries) Reschedule(firstDueDate time.Time) (SeriesChange, error) {
+	unpaid := s.Unpaid()
+	if len(unpaid) == 0 {
+		return SeriesChange{}, ErrNoUnpaidInstallments
+	}
+
+	var change SeriesChange
+	for i := range unpaid {
+		inst := unpaid[i]
+		dueDate := addMonths(firstDueDate, i)
+
+		accrualMonth, err := AddPeriodMonths(inst.AccrualMonth, monthsBetween(inst.DueDate, dueDate))
+		if err != nil {
+			return SeriesChange{}, err
+		}
+		if inst.PaymentDate != nil {
+			paymentDate := inst.PaymentDate.Add(dueDate.Sub(inst.DueDate))
+			inst.PaymentDate = &paymentDate
+		}
+		inst.DueDate = dueDate
+		inst.AccrualMonth = accrualMonth
+		change.Updated = append(change.Updated, inst)
+	}
+	return change, nil
+}
+
+// PayOff returns the change that settles the series early: the first unpaid installment is paid on paymentDate
+// with the remaining amount minus the discount, and the other unpaid installments are cancelled. Partial
+// payments of the unpaid installments move to the first one, which adds them to its amount.
+func (s *TransactionSeries) PayOff(paymentDate time.Time, discount Money) (SeriesChange, error) {
+	unpaid := s.Unpaid()
+	if len(unpaid) == 0 {
+		return SeriesChange{}, ErrNoUnpaidInstallments
+	}
+
+	var remaining, settled Money
+	for i := range unpaid {
+		remaining = remaining.Add(unpaid[i].Amount.Sub(unpaid[i].SettledAmount))
+		settled = settled.Add(unpaid[i].SettledAmount)
+	}
+	if discount.IsNegative() || discount.Cmp(remaining) >= 0 {
+		return SeriesChange{}, ErrInvalidPayOffDiscount
+	}
+
+	payoff := unpaid[0]
+	payoff.Amount = settled.Add(remaining.Sub(discount)).WithCurrency(payoff.Amount.Currency)
+	payoff.SettledAmount = settled.WithCurrency(payoff.Amount.Currency)
+	payoff.DueDate = paymentDate
+	payoff.PaymentDate = &paymentDate
+	payoff.AccrualMonth = paymentDate.Format("200601")
+
+	change := SeriesChange{Updated: []Transaction{payoff}, PaymentsTo: payoff.ID}
+	for i := range unpaid[1:] {
+		change.Cancelled = append(change.Cancelled, unpaid[i+1].ID)
+	}
+	return change, nil
+}
+
+// monthsBetween returns the number of calendar months from a to b.
+func monthsBetween(a, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+}
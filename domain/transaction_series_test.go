@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func testSeries(paid int) *TransactionSeries {
+	asOf := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	count := 4
+	s := &TransactionSeries{ParentID: "tx-1", AsOf: asOf}
+	for i := range count {
+		number := i + 1
+		t := Transaction{
+			ID:                string(rune('1' + i)),
+			InstallmentNumber: &number,
+			InstallmentCount:  &count,
+			Amount:            NewMoney(2500, "BRL"),
+			DueDate:           time.Date(2024, time.Month(1+i), 10, 0, 0, 0, 0, time.UTC),
+			AccrualMonth:      time.Date(2024, time.Month(1+i), 1, 0, 0, 0, 0, time.UTC).Format("200601"),
+		}
+		if i < paid {
+			p := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+			t.PaymentDate = &p
+		}
+		s.Installments = append(s.Installments, t)
+	}
+	return s
+}
+
+func TestTransactionSeries_Select(t *testing.T) {
+	s := testSeries(0)
+	tests := []struct {
+		scope   SeriesScope
+		want    int
+		wantErr error
+	}{
+		{scope: SeriesScopeThis, want: 1},
+		{scope: SeriesScopeFollowing, want: 3},
+		{scope: SeriesScopeAll, want: 4},
+		{scope: "some", wantErr: ErrInvalidSeriesScope},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.scope), func(t *testing.T) {
+			got, err := s.Select("2", tt.scope)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Select() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Select() selected %d installments, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionSeries_PayOff(t *testing.T) {
+	s := testSeries(2)
+	paymentDate := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	change, err := s.PayOff(paymentDate, NewMoney(500, "BRL"))
+	if err != nil {
+		t.Fatalf("PayOff() error = %v", err)
+	}
+	if len(change.Updated) != 1 || change.Updated[0].ID != "3" {
+		t.Fatalf("expected installment 3 to carry the payment, got %+v", change.Updated)
+	}
+	payoff := change.Updated[0]
+	if payoff.Amount.Cents != 4500 || !payoff.IsPaid(s.AsOf) || payoff.AccrualMonth != "202403" {
+		t.Errorf("payoff = %s paid %v in %s, want 45.00 paid in 202403", payoff.Amount, payoff.IsPaid(s.AsOf), payoff.AccrualMonth)
+	}
+	if len(change.Cancelled) != 1 || change.Cancelled[0] != "4" {
+		t.Errorf("expected installment 4 to be cancelled, got %v", change.Cancelled)
+	}
+
+	if _, err := s.PayOff(paymentDate, NewMoney(5000, "BRL")); !errors.Is(err, ErrInvalidPayOffDiscount) {
+		t.Errorf("discount of the whole remaining amount: error = %v, want %v", err, ErrInvalidPayOffDiscount)
+	}
+	if _, err := testSeries(4).PayOff(paymentDate, Money{}); !errors.Is(err, ErrNoUnpaidInstallments) {
+		t.Errorf("fully paid series: error = %v, want %v", err, ErrNoUnpaidInstallments)
+	}
+}
+
+func TestTransactionSeries_PartiallySettled(t *testing.T) {
+	s := testSeries(2)
+	s.Installments[2].SettledAmount = NewMoney(1000, "BRL")
+	s.Installments[3].SettledAmount = NewMoney(500, "BRL")
+	paymentDate := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	change, err := s.PayOff(paymentDate, NewMoney(500, "BRL"))
+	if err != nil {
+		t.Fatalf("PayOff() error = %v", err)
+	}
+	// 15.00 and 20.00 still owed, minus the discount, on top of the 15.00 already settled
+	payoff := change.Updated[0]
+	if payoff.Amount != NewMoney(4500, "BRL") || payoff.SettledAmount != NewMoney(1500, "BRL") {
+		t.Errorf("payoff = %s with %s settled, want 45.00 with 15.00 settled", payoff.Amount, payoff.SettledAmount)
+	}
+	if change.PaymentsTo != "3" || len(change.Cancelled) != 1 || change.Cancelled[0] != "4" {
+		t.Errorf("PayOff() moves the payments of %v to %q, want those of [4] to 3", change.Cancelled, change.PaymentsTo)
+	}
+	if _, err := s.PayOff(paymentDate, NewMoney(3500, "BRL")); !errors.Is(err, ErrInvalidPayOffDiscount) {
+		t.Errorf("discount of the whole outstanding amount: error = %v, want %v", err, ErrInvalidPayOffDiscount)
+	}
+
+	if _, err := s.Cancel(); !errors.Is(err, ErrPartiallyPaidInstallments) {
+		t.Errorf("Cancel() error = %v, want %v", err, ErrPartiallyPaidInstallments)
+	}
+}
+
+func TestTransactionSeries_Reschedule(t *testing.T) {
+	s := testSeries(1)
+
+	change, err := s.Reschedule(time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Reschedule() error = %v", err)
+	}
+	want := []struct{ due, accrual string }{
+		{"2024-05-31", "202405"},
+		{"2024-06-30", "202406"},
+		{"2024-07-31", "202407"},
+	}
+	if len(change.Updated) != len(want) {
+		t.Fatalf("expected %d rescheduled installments, got %d", len(want), len(change.Updated))
+	}
+	for i, w := range want {
+		got := change.Updated[i]
+		if got.DueDate.Format(time.DateOnly) != w.due || got.AccrualMonth != w.accrual {
+			t.Errorf("installment %d: due %s accrual %s, want %s %s", i+2, got.DueDate.Format(time.DateOnly), got.AccrualMonth, w.due, w.accrual)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransactionFilter_Validate(t *testing.T) {
+	day := func(d int) *time.Time {
+		v := time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	amount := func(cents int64) *Money {
+		m := NewMoney(cents, "BRL")
+		return &m
+	}
+
+	tests := []struct {
+		name    string
+		filter  TransactionFilter
+		wantErr bool
+	}{
+		{name: "empty", filter: TransactionFilter{}},
+		{
+			name: "full",
+			filter: TransactionFilter{
+				AccrualMonthFrom: "202401", AccrualMonthTo: "202403",
+				DueDateFrom: day(1), DueDateTo: day(31),
+				MinAmount: amount(100), MaxAmount: amount(100),
+				CategoryIDs: []string{"c1"}, IncludeSubcategories: true,
+				TagIDs: []string{"t1", "t2"}, TagMatch: TagMatchAll,
+				Status: TransactionStatusOverdue,
+			},
+		},
+		{name: "open ranges", filter: TransactionFilter{AccrualMonthFrom: "202401", PaymentDateTo: day(5), MinAmount: amount(0)}},
+		{name: "malformed accrual month", filter: TransactionFilter{AccrualMonthTo: "202413"}, wantErr: true},
+		{name: "accrual months reversed", filter: TransactionFilter{AccrualMonthFrom: "202402", AccrualMonthTo: "202401"}, wantErr: true},
+		{name: "due dates reversed", filter: TransactionFilter{DueDateFrom: day(2), DueDateTo: day(1)}, wantErr: true},
+		{name: "payment dates reversed", filter: TransactionFilter{PaymentDateFrom: day(2), PaymentDateTo: day(1)}, wantErr: true},
+		{name: "amounts reversed", filter: TransactionFilter{MinAmount: amount(101), MaxAmount: amount(100)}, wantErr: true},
+		{name: "unknown tag match", filter: TransactionFilter{TagMatch: "some"}, wantErr: true},
+		{name: "unknown status", filter: TransactionFilter{Status: "late"}, wantErr: true},
+		{name: "subcategories without categories", filter: TransactionFilter{IncludeSubcategories: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTransactionFilter) {
+				t.Errorf("Validate() error = %v, want %v", err, ErrInvalidTransactionFilter)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// Transactor runs fn in a database transaction, committed when fn returns nil and rolled back
+// otherwise. The repositories called with the context passed to fn take part in it.
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,251 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// TransferDirection tells the two legs of a transfer apart. Both legs are transactions of type
+// transfer, linked to each other: the outgoing leg debits the source account (its from_account_id)
+// and the incoming leg credits the destination account (its own from_account_id).
+type TransferDirection string
+
+const (
+	TransferDirectionOut TransferDirection = "out"
+	TransferDirectionIn  TransferDirection = "in"
+)
+
+var (
+	ErrTransferLegLocked      = InvalidField("amount", "the accounts, amount, exchange rate and fee of a transfer are changed on its outgoing leg")
+	ErrTransferTypeChange     = InvalidField("transaction_type", "a transaction cannot be changed to or from a transfer")
+	ErrTransferInstallments   = InvalidField("installments", "transfers cannot be split into installments")
+	ErrExchangeRateRequired   = InvalidField("exchange_rate", "exchange_rate is required between accounts of different currencies")
+	ErrExchangeRateUnneeded   = InvalidField("exchange_rate", "exchange_rate must be 1 between accounts of the same currency")
+	ErrTransferAmountTooSmall = InvalidField("exchange_rate", "the converted amount must be greater than 0")
+)
+
+// exchangeRateScale is the number of decimal places stored by the NUMERIC(18,8) exchange_rate column.
+const exchangeRateScale = 8
+
+var exchangeRateUnit = big.NewInt(100_000_000)
+
+var ErrInvalidExchangeRate = NewValidationError("invalid exchange rate")
+
+// ExchangeRate is the amount of the destination currency bought by one unit of the source currency,
+// stored exactly as an integer number of 1e-8 units. The zero value means no rate.
+type ExchangeRate int64
+
+// ExchangeRateOne is the rate between accounts of the same currency.
+const ExchangeRateOne ExchangeRate = 100_000_000
+
+// ParseExchangeRate parses a positive decimal string with up to 8 decimal places, such as "5.4321".
+func ParseExchangeRate(s string) (ExchangeRate, error) {
+	s = strings.TrimSpace(s)
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" {
+		whole = "0"
+	}
+	if len(frac) > exchangeRateScale {
+		if strings.Trim(frac[exchangeRateScale:], "0") != "" {
+			return 0, fmt.Errorf("%w: more than %d decimal places", ErrInvalidExchangeRate, exchangeRateScale)
+		}
+		frac = frac[:exchangeRateScale]
+	}
+	frac += strings.Repeat("0", exchangeRateScale-len(frac))
+	for _, r := range whole + frac {
+		if r < '0' || r > '9' {
+			return 0, ErrInvalidExchangeRate
+		}
+	}
+	units, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidExchangeRate, err)
+	}
+	if units == 0 {
+		return 0, fmt.Errorf("%w: must be greater than 0", ErrInvalidExchangeRate)
+	}
+	return ExchangeRate(units), nil
+}
+
+// IsZero reports whether no rate is set.
+func (r ExchangeRate) IsZero() bool { return r == 0 }
+
+// Convert returns m converted at r into currency, rounded half away from zero to the cent.
+func (r ExchangeRate) Convert(m Money, currency string) Money {
+	n := new(big.Int).Mul(big.NewInt(m.Cents), big.NewInt(int64(r)))
+	q, rem := new(big.Int).QuoRem(n, exchangeRateUnit, new(big.Int))
+	if twice := new(big.Int).Abs(rem); twice.Lsh(twice, 1).Cmp(exchangeRateUnit) >= 0 {
+		q.Add(q, big.NewInt(int64(n.Sign())))
+	}
+	return NewMoney(q.Int64(), currency)
+}
+
+// String formats r as a decimal string without trailing zeros (e.g. "5.4321").
+func (r ExchangeRate) String() string {
+	s := fmt.Sprintf("%d.%08d", int64(r)/int64(ExchangeRateOne), int64(r)%int64(ExchangeRateOne))
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// MarshalJSON encodes r as a decimal string.
+func (r ExchangeRate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(r.String())), nil
+}
+
+// UnmarshalJSON accepts a decimal string ("5.4321") or a JSON number.
+func (r *ExchangeRate) UnmarshalJSON(data []byte) error {
+	raw := strings.TrimSpace(string(data))
+	if raw == "null" {
+		*r = 0
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(raw); err == nil {
+		raw = unquoted
+	}
+	parsed, err := ParseExchangeRate(raw)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner so the NUMERIC exchange_rate column can be read into ExchangeRate; NULL is no rate.
+func (r *ExchangeRate) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*r = 0
+		return nil
+	case string:
+		parsed, err := ParseExchangeRate(v)
+		*r = parsed
+		return err
+	case []byte:
+		parsed, err := ParseExchangeRate(string(v))
+		*r = parsed
+		return err
+	default:
+		return fmt.Errorf("cannot scan %T into ExchangeRate", src)
+	}
+}
+
+// Value implements driver.Valuer, writing no rate as NULL.
+func (r ExchangeRate) Value() (driver.Value, error) {
+	if r.IsZero() {
+		return nil, nil
+	}
+	return r.String(), nil
+}
+
+// IsTransferLeg reports whether t is one of the two linked legs of a transfer.
+func (t *Transaction) IsTransferLeg() bool {
+	return t.TransferDirection != nil
+}
+
+// IsIncomingLeg reports whether t is the incoming leg of a transfer.
+func (t *Transaction) IsIncomingLeg() bool {
+	return t.TransferDirection != nil && *t.TransferDirection == TransferDirectionIn
+}
+
+// PrepareTransfer completes the outgoing leg t of a transfer between accounts in fromCurrency and
+// toCurrency, and returns its incoming leg. The exchange rate defaults to 1 between accounts of the
+// same currency and is required between accounts of different ones.
+func (t *Transaction) PrepareTransfer(fromCurrency, toCurrency string) (Transaction, error) {
+	if fromCurrency == toCurrency {
+		if t.ExchangeRate.IsZero() {
+			t.ExchangeRate = ExchangeRateOne
+		}
+		if t.ExchangeRate != ExchangeRateOne {
+			return Transaction{}, ErrExchangeRateUnneeded
+		}
+	} else if t.ExchangeRate.IsZero() {
+		return Transaction{}, ErrExchangeRateRequired
+	}
+
+	out := TransferDirectionOut
+	t.TransferDirection = &out
+	t.Currency = fromCurrency
+	t.Amount.Currency = fromCurrency
+	t.Fee.Currency = fromCurrency
+
+	in := t.IncomingLeg(toCurrency)
+	if !in.Amount.IsPositive() {
+		return Transaction{}, ErrTransferAmountTooSmall
+	}
+	return in, nil
+}
+
+// IncomingLeg returns the incoming leg of the outgoing leg t: the amount of t converted into currency,
+// credited to the destination account. It carries no fee and starts unsettled.
+func (t *Transaction) IncomingLeg(currency string) Transaction {
+	in := TransferDirectionIn
+	source := t.FromAccountID
+	leg := Transaction{
+		TenantID:          t.TenantID,
+		FromAccountID:     *t.ToAccountID,
+		ToAccountID:       &source,
+		Currency:          currency,
+		Amount:            t.ExchangeRate.Convert(t.Amount, currency),
+		TransactionType:   TransactionTypeTransfer,
+		PaymentDate:       t.PaymentDate,
+		SettledAmount:     NewMoney(0, currency),
+		ExchangeRate:      t.ExchangeRate,
+		Fee:               NewMoney(0, currency),
+		TransferDirection: &in,
+		CreatedBy:         t.CreatedBy,
+		UpdatedBy:         t.UpdatedBy,
+	}
+	if t.ID != "" {
+		outID := t.ID
+		leg.LinkedTransactionID = &outID
+	}
+	t.SyncLeg(&leg)
+	return leg
+}
+
+// SyncLeg copies the fields both legs of a transfer share from t onto its other leg. Payment dates
+// and settlements are kept per leg.
+func (t *Transaction) SyncLeg(leg *Transaction) {
+	leg.AccrualMonth = t.AccrualMonth
+	leg.CategoryID = t.CategoryID
+	leg.Comments = t.Comments
+	leg.DueDate = t.DueDate
+	leg.RecurrenceRuleID = t.RecurrenceRuleID
+	leg.RecurrenceDate = t.RecurrenceDate
+}
+
+// SameTransferTerms reports whether t and o move the same amount between the same accounts, at the
+// same exchange rate and fee.
+func (t *Transaction) SameTransferTerms(o *Transaction) bool {
+	return t.FromAccountID == o.FromAccountID &&
+		ptrEqual(t.ToAccountID, o.ToAccountID) &&
+		t.Amount.Cmp(o.Amount) == 0 &&
+		t.ExchangeRate == o.ExchangeRate &&
+		t.Fee.Cmp(o.Fee) == 0
+}
+
+func ptrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// validateTransferFields reports the transfer-only fields set on a transaction of another type.
+func (t *Transaction) validateTransferFields(err map[string]error) {
+	if t.Fee.IsNegative() {
+		err["fee"] = errors.New("fee must not be negative")
+	}
+	if t.TransactionType == TransactionTypeTransfer {
+		return
+	}
+	if !t.ExchangeRate.IsZero() {
+		err["exchange_rate"] = errors.New("exchange_rate only applies to transfers")
+	}
+	if !t.Fee.IsZero() {
+		err["fee"] = errors.New("fee only applies to transfers")
+	}
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExchangeRate_Convert(t *testing.T) {
+	tests := []struct {
+		rate string
+		in   int64
+		want int64
+	}{
+		{rate: "1", in: 12345, want: 12345},
+		{rate: "5.4321", in: 10000, want: 54321},
+		{rate: "0.18409", in: 10000, want: 1841}, // 18.409 rounds up
+		{rate: "0.18404", in: 10000, want: 1840}, // 18.404 rounds down
+		{rate: "0.5", in: 1, want: 1},            // half a cent rounds away from zero
+		{rate: "0.00000001", in: 100, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rate, func(t *testing.T) {
+			rate, err := ParseExchangeRate(tt.rate)
+			if err != nil {
+				t.Fatalf("ParseExchangeRate() error = %v", err)
+			}
+			got := rate.Convert(NewMoney(tt.in, "BRL"), "USD")
+			if got != NewMoney(tt.want, "USD") {
+				t.Errorf("Convert(%d) = %+v, want %d USD", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExchangeRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "5.4321", want: "5.4321"},
+		{in: "1.000000000", want: "1"},
+		{in: ".25", want: "0.25"},
+		{in: "0", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: "1.123456789", wantErr: true},
+		{in: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseExchangeRate(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidExchangeRate) {
+					t.Errorf("ParseExchangeRate(%q) error = %v, want ErrInvalidExchangeRate", tt.in, err)
+				}
+				return
+			}
+			if err != nil || got.String() != tt.want {
+				t.Errorf("ParseExchangeRate(%q) = %s, %v, want %s", tt.in, got, err, tt.want)
+			}
+		})
+	}
+
+	var r ExchangeRate
+	if err := json.Unmarshal([]byte(`5.25`), &r); err != nil || r.String() != "5.25" {
+		t.Errorf("Unmarshal(number) = %s, %v, want 5.25", r, err)
+	}
+	if data, _ := json.Marshal(r); string(data) != `"5.25"` {
+		t.Errorf("Marshal() = %s, want \"5.25\"", data)
+	}
+}
+
+func TestTransaction_PrepareTransfer(t *testing.T) {
+	outgoing := func() *Transaction {
+		to := "acc-usd"
+		return &Transaction{
+			ID:              "out-1",
+			TenantID:        "tenant-1",
+			FromAccountID:   "acc-brl",
+			ToAccountID:     &to,
+			Amount:          NewMoney(50000, ""),
+			Fee:             NewMoney(250, ""),
+			AccrualMonth:    "202403",
+			TransactionType: TransactionTypeTransfer,
+			CategoryID:      "cat-1",
+			DueDate:         time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	t.Run("between currencies", func(t *testing.T) {
+		out := outgoing()
+		out.ExchangeRate, _ = ParseExchangeRate("0.2")
+		in, err := out.PrepareTransfer("BRL", "USD")
+		if err != nil {
+			t.Fatalf("PrepareTransfer() error = %v", err)
+		}
+		if !out.IsTransferLeg() || out.IsIncomingLeg() || out.Currency != "BRL" || out.Fee != NewMoney(250, "BRL") {
+			t.Errorf("outgoing leg = %+v", out)
+		}
+		if !in.IsIncomingLeg() || in.FromAccountID != "acc-usd" || *in.ToAccountID != "acc-brl" {
+			t.Errorf("incoming leg accounts = %s -> %v", in.FromAccountID, in.ToAccountID)
+		}
+		if in.Amount != NewMoney(10000, "USD") || !in.Fee.IsZero() {
+			t.Errorf("incoming leg amount = %v, fee = %v, want 100.00 USD and no fee", in.Amount, in.Fee)
+		}
+		if in.LinkedTransactionID == nil || *in.LinkedTransactionID != "out-1" || in.CategoryID != "cat-1" || !in.DueDate.Equal(out.DueDate) {
+			t.Errorf("incoming leg = %+v, want it linked to out-1 with the shared fields", in)
+		}
+		if valid, errs := in.IsValid(); !valid {
+			t.Errorf("incoming leg is invalid: %v", errs)
+		}
+	})
+
+	t.Run("same currency defaults the rate", func(t *testing.T) {
+		out := outgoing()
+		in, err := out.PrepareTransfer("BRL", "BRL")
+		if err != nil {
+			t.Fatalf("PrepareTransfer() error = %v", err)
+		}
+		if out.ExchangeRate != ExchangeRateOne || in.Amount != NewMoney(50000, "BRL") {
+			t.Errorf("rate = %s, incoming amount = %v", out.ExchangeRate, in.Amount)
+		}
+	})
+
+	t.Run("rate required between currencies", func(t *testing.T) {
+		if _, err := outgoing().PrepareTransfer("BRL", "USD"); !errors.Is(err, ErrExchangeRateRequired) {
+			t.Errorf("PrepareTransfer() error = %v, want ErrExchangeRateRequired", err)
+		}
+	})
+
+	t.Run("rate other than 1 in the same currency", func(t *testing.T) {
+		out := outgoing()
+		out.ExchangeRate, _ = ParseExchangeRate("2")
+		if _, err := out.PrepareTransfer("BRL", "BRL"); !errors.Is(err, ErrExchangeRateUnneeded) {
+			t.Errorf("PrepareTransfer() error = %v, want ErrExchangeRateUnneeded", err)
+		}
+	})
+}
+
+func TestTransaction_IsValid_TransferFields(t *testing.T) {
+	debit := Transaction{
+		TenantID:        "tenant-1",
+		FromAccountID:   "acc-1",
+		Amount:          NewMoney(100, "BRL"),
+		TransactionType: TransactionTypeDebit,
+		CategoryID:      "cat-1",
+		AccrualMonth:    "202403",
+		DueDate:         time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		ExchangeRate:    ExchangeRateOne,
+		Fee:             NewMoney(10, "BRL"),
+	}
+	valid, errs := debit.IsValid()
+	if valid || errs["exchange_rate"] == nil || errs["fee"] == nil {
+		t.Errorf("IsValid() = %v, %v, want exchange_rate and fee errors", valid, errs)
+	}
+}
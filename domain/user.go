@@ -7,6 +7,12 @@ import (
 	"time"
 )
 
+var (
+	ErrUserNotFound    = NewNotFoundError("user not found")
+	ErrNotTenantMember = NewNotFoundError("user is not a member of this tenant")
+	ErrLastOwner       = NewConflictError("a tenant must keep at least one owner")
+)
+
 // User represents a user in the system.
 type User struct {
 	ID            string     `json:"id"`
@@ -22,11 +28,21 @@ type User struct {
 type UserTenant struct {
 	UserID        string     `json:"user_id"`
 	TenantID      string     `json:"tenant_id"`
+	Role          Role       `json:"role"`
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
 }
 
+// TenantMember represents a user's membership in a tenant along with the user's details.
+type TenantMember struct {
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // UserRepository defines the interface for user persistence.
 type UserRepository interface {
 	GetByID(ctx context.Context, id string) (*User, error)
@@ -37,9 +53,16 @@ type UserRepository interface {
 	Delete(ctx context.Context, id string) error
 
 	// Tenant associations
-	AddUserToTenant(ctx context.Context, userID, tenantID string) error
+	// AddUserToTenant adds a user to a tenant with the given role, reactivating a previous membership if any.
+	AddUserToTenant(ctx context.Context, userID, tenantID string, role Role) error
+	// RemoveUserFromTenant deactivates a membership, or returns ErrLastOwner if it is the only owner left.
 	RemoveUserFromTenant(ctx context.Context, userID, tenantID string) error
 	ListUserTenants(ctx context.Context, userID string) ([]Tenant, error)
+	ListTenantMembers(ctx context.Context, tenantID string) ([]TenantMember, error)
+	// UpdateUserTenantRole changes the role of a member, or returns ErrLastOwner if it would demote the only owner left.
+	UpdateUserTenantRole(ctx context.Context, userID, tenantID string, role Role) error
+	// GetUserTenant returns the active membership of a user in an active tenant, or ErrNotTenantMember.
+	GetUserTenant(ctx context.Context, userID, tenantID string) (*UserTenant, error)
 }
 
 const userIdKey contextKey = "userID"
@@ -49,16 +72,20 @@ func WithUserID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, userIdKey, userID)
 }
 
+// GetUserID retrieves the user ID from the context, or an empty string if it is not set.
 func GetUserID(ctx context.Context) string {
-	return ctx.Value(userIdKey).(string)
+	val, _ := ctx.Value(userIdKey).(string)
+	return val
 }
 
 func WithToken(ctx context.Context, token string) context.Context {
 	return context.WithValue(ctx, tokenKey, token)
 }
 
+// GetToken retrieves the access token from the context, or an empty string if it is not set.
 func GetToken(ctx context.Context) string {
-	return ctx.Value(tokenKey).(string)
+	val, _ := ctx.Value(tokenKey).(string)
+	return val
 }
 
 func (u *User) IsValid() (bool, map[string]error) {
@@ -7,16 +7,55 @@ import (
 )
 
 type AccountResponse struct {
-	ID             string             `json:"id"`
-	TenantID       string             `json:"tenant_id"`
-	Name           string             `json:"name"`
-	InitialBalance float64            `json:"initial_balance"`
-	Color          string             `json:"color"`
-	Currency       string             `json:"currency"`
-	Icon           string             `json:"icon"`
-	Type           domain.AccountType `json:"type"`
-	CreatedAt      time.Time          `json:"created_at"`
-	UpdatedAt      time.Time          `json:"updated_at"`
+	ID             string              `json:"id"`
+	TenantID       string              `json:"tenant_id"`
+	Name           string              `json:"name"`
+	InitialBalance domain.Money        `json:"initial_balance" swaggertype:"string" example:"1500.00"`
+	Color          string              `json:"color"`
+	Currency       string              `json:"currency"`
+	Icon           string              `json:"icon"`
+	Type           domain.AccountType  `json:"type"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	Version        int                 `json:"version"`
+	Balance        *BalanceResponse    `json:"balance,omitempty"`
+	CreditCard     *CreditCardResponse `json:"credit_card,omitempty"`
+}
+
+type BalanceResponse struct {
+	AccountID      string       `json:"account_id"`
+	Currency       string       `json:"currency"`
+	InitialBalance domain.Money `json:"initial_balance" swaggertype:"string" example:"1500.00"`
+	Current        domain.Money `json:"current" swaggertype:"string" example:"1234.56"`
+	Cleared        domain.Money `json:"cleared" swaggertype:"string" example:"1000.00"`
+	Projected      domain.Money `json:"projected" swaggertype:"string" example:"980.10"`
+	AsOf           string       `json:"as_of"` // YYYY-MM-DD
+}
+
+// BalanceQuery defines the query parameters accepted by balance endpoints.
+type BalanceQuery struct {
+	AsOf string `form:"as_of" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// AsOfDate returns the requested as-of date, defaulting to today.
+func (q *BalanceQuery) AsOfDate() time.Time {
+	if q.AsOf == "" {
+		return time.Now()
+	}
+	asOf, _ := time.Parse(time.DateOnly, q.AsOf)
+	return asOf
+}
+
+func MapBalanceToResponse(b *domain.AccountBalance) *BalanceResponse {
+	return &BalanceResponse{
+		AccountID:      b.AccountID,
+		Currency:       b.Currency,
+		InitialBalance: b.InitialBalance,
+		Current:        b.Current,
+		Cleared:        b.Cleared,
+		Projected:      b.Projected,
+		AsOf:           b.AsOf.Format(time.DateOnly),
+	}
 }
 
 func MapAccountToResponse(acc *domain.Account) *AccountResponse {
@@ -31,12 +70,14 @@ func MapAccountToResponse(acc *domain.Account) *AccountResponse {
 		Type:           acc.Type,
 		CreatedAt:      acc.CreatedAt,
 		UpdatedAt:      acc.UpdatedAt,
+		Version:        acc.Version,
+		CreditCard:     MapCreditCardToResponse(acc.CreditCard),
 	}
 }
 
 type CreateAccountRequest struct {
 	Name           string             `json:"name" validate:"required"`
-	InitialBalance float64            `json:"initial_balance"`
+	InitialBalance domain.Money       `json:"initial_balance" swaggertype:"string" example:"1500.00"`
 	Color          string             `json:"color"`
 	Currency       string             `json:"currency"`
 	Icon           string             `json:"icon"`
@@ -48,7 +89,7 @@ func (r *CreateAccountRequest) ToEntity(creatorID string, tenantID string) *doma
 	return &domain.Account{
 		TenantID:       tenantID,
 		Name:           r.Name,
-		InitialBalance: r.InitialBalance,
+		InitialBalance: r.InitialBalance.WithCurrency(r.Currency),
 		Color:          r.Color,
 		Currency:       r.Currency,
 		Icon:           r.Icon,
@@ -61,10 +102,10 @@ func (r *CreateAccountRequest) ToEntity(creatorID string, tenantID string) *doma
 }
 
 type UpdateAccountRequest struct {
-	Name           string  `json:"name" validate:"required"`
-	InitialBalance float64 `json:"initial_balance"`
-	Color          string  `json:"color"`
-	Icon           string  `json:"icon"`
+	Name           string       `json:"name" validate:"required"`
+	InitialBalance domain.Money `json:"initial_balance" swaggertype:"string" example:"1500.00"`
+	Color          string       `json:"color"`
+	Icon           string       `json:"icon"`
 }
 
 func (r *UpdateAccountRequest) ToEntity(id string, userID string) *domain.Account {
@@ -80,3 +121,74 @@ func (r *UpdateAccountRequest) ToEntity(id string, userID string) *domain.Accoun
 		UpdatedBy:      userID,
 	}
 }
+
+// PatchAccountRequest is a JSON Merge Patch of an account: only the fields sent are changed.
+// currency and type cannot be changed and are only accepted with their current value.
+type PatchAccountRequest struct {
+	Name           domain.Field[string]             `json:"name" swaggertype:"string"`
+	InitialBalance domain.Field[domain.Money]       `json:"initial_balance" swaggertype:"string" example:"1500.00"`
+	Color          domain.Field[string]             `json:"color" swaggertype:"string"`
+	Icon           domain.Field[string]             `json:"icon" swaggertype:"string"`
+	Currency       domain.Field[string]             `json:"currency" swaggertype:"string"`
+	Type           domain.Field[domain.AccountType] `json:"type" swaggertype:"string"`
+}
+
+func (r *PatchAccountRequest) ToDomain() domain.AccountPatch {
+	return domain.AccountPatch{
+		Name:           r.Name,
+		InitialBalance: r.InitialBalance,
+		Color:          r.Color,
+		Icon:           r.Icon,
+		Currency:       r.Currency,
+		Type:           r.Type,
+	}
+}
+
+// CreditCardRequest represents the payload for setting the card details of a credit card account.
+type CreditCardRequest struct {
+	LastFour   string                 `json:"last_four" binding:"required,len=4,numeric"`
+	Name       string                 `json:"name" binding:"required"`
+	Brand      domain.CreditCardBrand `json:"brand" binding:"required,oneof=visa mastercard amex discover jcb unionpay diners_club maestro unknown"`
+	ClosingDay int                    `json:"closing_day" binding:"required,min=1,max=31"`
+	DueDay     int                    `json:"due_day" binding:"required,min=1,max=31"`
+}
+
+func (r *CreditCardRequest) ToEntity(accountID string) *domain.CreditCardInfo {
+	return &domain.CreditCardInfo{
+		AccountID:   accountID,
+		LastFour:    r.LastFour,
+		Name:        r.Name,
+		Brand:       r.Brand,
+		ClosingDate: domain.NewCreditCardDay(r.ClosingDay),
+		DueDate:     domain.NewCreditCardDay(r.DueDay),
+	}
+}
+
+// CreditCardResponse represents the card details of a credit card account.
+type CreditCardResponse struct {
+	ID         string                 `json:"id"`
+	AccountID  string                 `json:"account_id"`
+	LastFour   string                 `json:"last_four"`
+	Name       string                 `json:"name"`
+	Brand      domain.CreditCardBrand `json:"brand"`
+	ClosingDay int                    `json:"closing_day"`
+	DueDay     int                    `json:"due_day"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+// MapCreditCardToResponse maps domain.CreditCardInfo to CreditCardResponse. A nil card maps to nil.
+func MapCreditCardToResponse(card *domain.CreditCardInfo) *CreditCardResponse {
+	if card == nil {
+		return nil
+	}
+	return &CreditCardResponse{
+		ID:         card.ID,
+		AccountID:  card.AccountID,
+		LastFour:   card.LastFour,
+		Name:       card.Name,
+		Brand:      card.Brand,
+		ClosingDay: card.ClosingDay(),
+		DueDay:     card.DueDay(),
+		UpdatedAt:  card.UpdatedAt,
+	}
+}
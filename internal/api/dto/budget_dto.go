@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// BudgetRequest represents the payload for creating or replacing a budget.
+// Exactly one of category_id and tag_id is required.
+type BudgetRequest struct {
+	CategoryID *string      `json:"category_id,omitempty" binding:"omitempty,uuid"` // An expense category; its subcategories count too
+	TagID      *string      `json:"tag_id,omitempty" binding:"omitempty,uuid"`
+	Month      string       `json:"month" binding:"required,len=6"`                // YYYYMM; the first month of a recurring budget
+	EndMonth   *string      `json:"end_month,omitempty" binding:"omitempty,len=6"` // Recurring only: the last month
+	Recurring  bool         `json:"recurring,omitempty"`                           // Repeat every month from month on
+	Currency   string       `json:"currency" binding:"required,len=3"`
+	Amount     domain.Money `json:"amount" swaggertype:"string" example:"800.00"`
+	Rollover   bool         `json:"rollover,omitempty"` // Recurring only: carry the unspent or overspent amount into the next month
+}
+
+// ToDomain maps BudgetRequest to domain.Budget.
+func (req *BudgetRequest) ToDomain() *domain.Budget {
+	currency := strings.ToUpper(req.Currency)
+	return &domain.Budget{
+		CategoryID: req.CategoryID,
+		TagID:      req.TagID,
+		Month:      req.Month,
+		EndMonth:   req.EndMonth,
+		Recurring:  req.Recurring,
+		Currency:   currency,
+		Amount:     req.Amount.WithCurrency(currency),
+		Rollover:   req.Rollover,
+	}
+}
+
+// BudgetStatusQuery defines the query parameters of the budget status.
+type BudgetStatusQuery struct {
+	Month string `form:"month" binding:"omitempty,len=6"` // YYYYMM, defaults to the current month
+}
+
+// BudgetResponse represents the API response for a budget.
+type BudgetResponse struct {
+	ID         string       `json:"id"`
+	TenantID   string       `json:"tenant_id"`
+	CategoryID *string      `json:"category_id,omitempty"`
+	TagID      *string      `json:"tag_id,omitempty"`
+	Month      string       `json:"month"`
+	EndMonth   *string      `json:"end_month,omitempty"`
+	Recurring  bool         `json:"recurring"`
+	Currency   string       `json:"currency"`
+	Amount     domain.Money `json:"amount" swaggertype:"string" example:"800.00"`
+	Rollover   bool         `json:"rollover"`
+	CreatedAt  time.Time    `json:"created_at"`
+	CreatedBy  string       `json:"created_by"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+	UpdatedBy  string       `json:"updated_by"`
+	Version    int          `json:"version"`
+}
+
+// MapBudgetToResponse maps domain.Budget to BudgetResponse.
+func MapBudgetToResponse(b *domain.Budget) BudgetResponse {
+	return BudgetResponse{
+		ID:         b.ID,
+		TenantID:   b.TenantID,
+		CategoryID: b.CategoryID,
+		TagID:      b.TagID,
+		Month:      b.Month,
+		EndMonth:   b.EndMonth,
+		Recurring:  b.Recurring,
+		Currency:   b.Currency,
+		Amount:     b.Amount,
+		Rollover:   b.Rollover,
+		CreatedAt:  b.CreatedAt,
+		CreatedBy:  b.CreatedBy,
+		UpdatedAt:  b.UpdatedAt,
+		UpdatedBy:  b.UpdatedBy,
+		Version:    b.Version,
+	}
+}
+
+// BudgetStatusResponse represents the progress of a budget in a month.
+type BudgetStatusResponse struct {
+	Budget      BudgetResponse `json:"budget"`
+	Month       string         `json:"month"`
+	Currency    string         `json:"currency"`
+	Planned     domain.Money   `json:"planned" swaggertype:"string" example:"800.00"`
+	CarriedOver domain.Money   `json:"carried_over" swaggertype:"string" example:"50.00"` // From earlier months, with rollover
+	Available   domain.Money   `json:"available" swaggertype:"string" example:"850.00"`
+	Actual      domain.Money   `json:"actual" swaggertype:"string" example:"612.40"`
+	Remaining   domain.Money   `json:"remaining" swaggertype:"string" example:"237.60"`
+	PercentUsed *float64       `json:"percent_used,omitempty" example:"72.05"` // Omitted when nothing is available
+}
+
+// MapBudgetStatusToResponse maps domain.BudgetStatus to BudgetStatusResponse.
+func MapBudgetStatusToResponse(s *domain.BudgetStatus) BudgetStatusResponse {
+	return BudgetStatusResponse{
+		Budget:      MapBudgetToResponse(&s.Budget),
+		Month:       s.Month,
+		Currency:    s.Budget.Currency,
+		Planned:     s.Planned,
+		CarriedOver: s.CarriedOver,
+		Available:   s.Available,
+		Actual:      s.Actual,
+		Remaining:   s.Remaining,
+		PercentUsed: s.PercentUsed,
+	}
+}
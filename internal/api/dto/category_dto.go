@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
 
 type CreateCategoryRequest struct {
 	Name             string  `json:"name" binding:"required"`
@@ -17,6 +21,26 @@ type UpdateCategoryRequest struct {
 	Icon             string  `json:"icon"`
 }
 
+// PatchCategoryRequest is a JSON Merge Patch of a category: only the fields sent are changed, and null
+// clears parent_category_id. type cannot be changed and is only accepted with its current value.
+type PatchCategoryRequest struct {
+	Name             domain.Field[string]              `json:"name" swaggertype:"string"`
+	ParentCategoryID domain.Field[*string]             `json:"parent_category_id" swaggertype:"string"`
+	Color            domain.Field[string]              `json:"color" swaggertype:"string"`
+	Icon             domain.Field[string]              `json:"icon" swaggertype:"string"`
+	Type             domain.Field[domain.CategoryType] `json:"type" swaggertype:"string"`
+}
+
+func (r *PatchCategoryRequest) ToDomain() domain.CategoryPatch {
+	return domain.CategoryPatch{
+		Name:             r.Name,
+		ParentCategoryID: r.ParentCategoryID,
+		Color:            r.Color,
+		Icon:             r.Icon,
+		Type:             r.Type,
+	}
+}
+
 type CategoryResponse struct {
 	ID               string    `json:"id"`
 	ParentCategoryID *string   `json:"parent_category_id,omitempty"`
@@ -29,4 +53,5 @@ type CategoryResponse struct {
 	CreatedBy        string    `json:"created_by"`
 	UpdatedAt        time.Time `json:"updated_at"`
 	UpdatedBy        string    `json:"updated_by"`
+	Version          int       `json:"version"`
 }
@@ -0,0 +1,21 @@
+package dto
+
+import "strings"
+
+// ExpandTags embeds full tag objects in transaction responses.
+const ExpandTags = "tags"
+
+// ExpandQuery defines the expand query parameter: a comma-separated list of related objects to embed.
+type ExpandQuery struct {
+	Expand string `form:"expand"`
+}
+
+// Has reports whether the given relation was requested.
+func (q *ExpandQuery) Has(relation string) bool {
+	for _, r := range strings.Split(q.Expand, ",") {
+		if strings.TrimSpace(r) == relation {
+			return true
+		}
+	}
+	return false
+}
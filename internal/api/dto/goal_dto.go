@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// GoalRequest represents the payload for creating or replacing a savings goal.
+// Exactly one of account_ids and tag_id is required.
+type GoalRequest struct {
+	Name         string       `json:"name" binding:"required,max=255"`
+	Currency     string       `json:"currency" binding:"required,len=3"`
+	TargetAmount domain.Money `json:"target_amount" swaggertype:"string" example:"10000.00"`
+	TargetDate   *time.Time   `json:"target_date,omitempty"`
+	AccountIDs   []string     `json:"account_ids,omitempty" binding:"omitempty,dive,uuid"` // Accounts in the goal currency; their balance is the amount saved
+	TagID        *string      `json:"tag_id,omitempty" binding:"omitempty,uuid"`           // The tagged transactions are the contributions
+}
+
+// ToDomain maps GoalRequest to domain.Goal.
+func (req *GoalRequest) ToDomain() *domain.Goal {
+	currency := strings.ToUpper(req.Currency)
+	return &domain.Goal{
+		Name:         req.Name,
+		Currency:     currency,
+		TargetAmount: req.TargetAmount.WithCurrency(currency),
+		TargetDate:   req.TargetDate,
+		AccountIDs:   req.AccountIDs,
+		TagID:        req.TagID,
+	}
+}
+
+// GoalResponse represents the API response for a savings goal.
+type GoalResponse struct {
+	ID           string       `json:"id"`
+	TenantID     string       `json:"tenant_id"`
+	Name         string       `json:"name"`
+	Currency     string       `json:"currency"`
+	TargetAmount domain.Money `json:"target_amount" swaggertype:"string" example:"10000.00"`
+	TargetDate   *time.Time   `json:"target_date,omitempty"`
+	AccountIDs   []string     `json:"account_ids"`
+	TagID        *string      `json:"tag_id,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	CreatedBy    string       `json:"created_by"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	UpdatedBy    string       `json:"updated_by"`
+	Version      int          `json:"version"`
+}
+
+// MapGoalToResponse maps domain.Goal to GoalResponse.
+func MapGoalToResponse(g *domain.Goal) GoalResponse {
+	resp := GoalResponse{
+		ID:           g.ID,
+		TenantID:     g.TenantID,
+		Name:         g.Name,
+		Currency:     g.Currency,
+		TargetAmount: g.TargetAmount,
+		TargetDate:   g.TargetDate,
+		AccountIDs:   g.AccountIDs,
+		TagID:        g.TagID,
+		CreatedAt:    g.CreatedAt,
+		CreatedBy:    g.CreatedBy,
+		UpdatedAt:    g.UpdatedAt,
+		UpdatedBy:    g.UpdatedBy,
+		Version:      g.Version,
+	}
+	if resp.AccountIDs == nil {
+		resp.AccountIDs = []string{}
+	}
+	return resp
+}
+
+// GoalProgressResponse represents the progress of a savings goal.
+type GoalProgressResponse struct {
+	Goal                        GoalResponse  `json:"goal"`
+	AsOf                        time.Time     `json:"as_of"`
+	Currency                    string        `json:"currency"`
+	Saved                       domain.Money  `json:"saved" swaggertype:"string" example:"4000.00"`
+	Remaining                   domain.Money  `json:"remaining" swaggertype:"string" example:"6000.00"` // Zero once the target is reached
+	PercentComplete             float64       `json:"percent_complete" example:"40"`
+	Achieved                    bool          `json:"achieved"`
+	AverageMonthlyContribution  *domain.Money `json:"average_monthly_contribution,omitempty" swaggertype:"string" example:"1000.00"` // Over the last 6 complete months; omitted without history
+	ProjectedDate               *time.Time    `json:"projected_date,omitempty"`                                                      // End of the month the target is reached in at that average
+	RequiredMonthlyContribution *domain.Money `json:"required_monthly_contribution,omitempty" swaggertype:"string" example:"857.15"` // To reach the target by target_date
+	OnTrack                     *bool         `json:"on_track,omitempty"`                                                            // Omitted without a target date
+}
+
+// MapGoalProgressToResponse maps domain.GoalProgress to GoalProgressResponse.
+func MapGoalProgressToResponse(p *domain.GoalProgress) GoalProgressResponse {
+	return GoalProgressResponse{
+		Goal:                        MapGoalToResponse(&p.Goal),
+		AsOf:                        p.AsOf,
+		Currency:                    p.Goal.Currency,
+		Saved:                       p.Saved,
+		Remaining:                   p.Remaining,
+		PercentComplete:             p.PercentComplete,
+		Achieved:                    p.Achieved,
+		AverageMonthlyContribution:  p.AverageMonthlyContribution,
+		ProjectedDate:               p.ProjectedDate,
+		RequiredMonthlyContribution: p.RequiredMonthlyContribution,
+		OnTrack:                     p.OnTrack,
+	}
+}
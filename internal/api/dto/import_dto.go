@@ -0,0 +1,190 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// ImportProfileRequest represents the payload for creating or replacing an import profile.
+type ImportProfileRequest struct {
+	Name             string                        `json:"name" binding:"required,max=255"`
+	Delimiter        string                        `json:"delimiter" binding:"required,len=1" example:";"`
+	HasHeader        bool                          `json:"has_header"`
+	SkipRows         int                           `json:"skip_rows" binding:"min=0"`                                  // Lines before the header, or before the first row without one
+	DateFormat       string                        `json:"date_format" binding:"required,max=32" example:"dd/mm/yyyy"` // dd, mm, yy or yyyy, d and m when not zero-padded, and separators
+	DecimalSeparator string                        `json:"decimal_separator" binding:"required,len=1" example:","`     // "," or "."; the other one separates thousands
+	SignConvention   domain.ImportSignConvention   `json:"sign_convention" binding:"required,oneof=negative_debit positive_debit split_columns"`
+	Columns          map[domain.ImportField]string `json:"columns" binding:"required"` // Field (date, description, amount, debit, credit) to header name or 1-based column number
+}
+
+// ToDomain maps ImportProfileRequest to domain.ImportProfile.
+func (req *ImportProfileRequest) ToDomain() *domain.ImportProfile {
+	return &domain.ImportProfile{
+		Name:             req.Name,
+		Delimiter:        req.Delimiter,
+		HasHeader:        req.HasHeader,
+		SkipRows:         req.SkipRows,
+		DateFormat:       req.DateFormat,
+		DecimalSeparator: req.DecimalSeparator,
+		SignConvention:   req.SignConvention,
+		Columns:          req.Columns,
+	}
+}
+
+// ImportProfileResponse represents the API response for an import profile.
+type ImportProfileResponse struct {
+	ID               string                        `json:"id"`
+	TenantID         string                        `json:"tenant_id"`
+	Name             string                        `json:"name"`
+	Delimiter        string                        `json:"delimiter"`
+	HasHeader        bool                          `json:"has_header"`
+	SkipRows         int                           `json:"skip_rows"`
+	DateFormat       string                        `json:"date_format"`
+	DecimalSeparator string                        `json:"decimal_separator"`
+	SignConvention   domain.ImportSignConvention   `json:"sign_convention"`
+	Columns          map[domain.ImportField]string `json:"columns"`
+	CreatedAt        time.Time                     `json:"created_at"`
+	CreatedBy        string                        `json:"created_by"`
+	UpdatedAt        time.Time                     `json:"updated_at"`
+	UpdatedBy        string                        `json:"updated_by"`
+	Version          int                           `json:"version"`
+}
+
+// MapImportProfileToResponse maps domain.ImportProfile to ImportProfileResponse.
+func MapImportProfileToResponse(p *domain.ImportProfile) ImportProfileResponse {
+	return ImportProfileResponse{
+		ID:               p.ID,
+		TenantID:         p.TenantID,
+		Name:             p.Name,
+		Delimiter:        p.Delimiter,
+		HasHeader:        p.HasHeader,
+		SkipRows:         p.SkipRows,
+		DateFormat:       p.DateFormat,
+		DecimalSeparator: p.DecimalSeparator,
+		SignConvention:   p.SignConvention,
+		Columns:          p.Columns,
+		CreatedAt:        p.CreatedAt,
+		CreatedBy:        p.CreatedBy,
+		UpdatedAt:        p.UpdatedAt,
+		UpdatedBy:        p.UpdatedBy,
+		Version:          p.Version,
+	}
+}
+
+// ImportRequest represents the multipart form uploading a CSV or OFX statement.
+type ImportRequest struct {
+	AccountID string                `form:"account_id" binding:"required,uuid"`
+	ProfileID string                `form:"profile_id" binding:"omitempty,uuid"` // Required for CSV files
+	File      *multipart.FileHeader `form:"file" binding:"required"`
+}
+
+// CommitImportRequest represents the rows of a staged import to turn into transactions.
+type CommitImportRequest struct {
+	Rows []CommitImportRowRequest `json:"rows" binding:"required,min=1,dive"`
+}
+
+// CommitImportRowRequest selects a staged row.
+type CommitImportRowRequest struct {
+	ID         string  `json:"id" binding:"required,uuid"`
+	CategoryID *string `json:"category_id,omitempty" binding:"omitempty,uuid"` // Required when the row has no suggested category
+}
+
+// ToDomain maps CommitImportRequest to the selected rows.
+func (req *CommitImportRequest) ToDomain() []domain.ImportSelection {
+	selections := make([]domain.ImportSelection, len(req.Rows))
+	for i, row := range req.Rows {
+		selections[i] = domain.ImportSelection{RowID: row.ID, CategoryID: row.CategoryID}
+	}
+	return selections
+}
+
+// ImportRowResponse represents a row of an imported file.
+type ImportRowResponse struct {
+	ID                     string                 `json:"id"`
+	Line                   int                    `json:"line"`
+	Date                   *time.Time             `json:"date,omitempty"`
+	Description            string                 `json:"description"`
+	Amount                 domain.Money           `json:"amount" swaggertype:"string" example:"89.90"`
+	ExternalID             *string                `json:"external_id,omitempty"` // FITID of OFX transactions
+	TransactionType        domain.TransactionType `json:"transaction_type,omitempty"`
+	Error                  *string                `json:"error,omitempty"`                    // Why the row could not be read; such rows cannot be committed
+	DuplicateTransactionID *string                `json:"duplicate_transaction_id,omitempty"` // Existing transaction with the same amount and direction within 3 days
+	SuggestedCategoryID    *string                `json:"suggested_category_id,omitempty"`
+	TransactionID          *string                `json:"transaction_id,omitempty"` // Created on commit
+}
+
+// ImportResponse represents the API response for an import.
+type ImportResponse struct {
+	ID              string              `json:"id"`
+	TenantID        string              `json:"tenant_id"`
+	AccountID       string              `json:"account_id"`
+	ProfileID       *string             `json:"profile_id,omitempty"` // CSV only
+	Format          domain.ImportFormat `json:"format"`
+	FileName        string              `json:"file_name"`
+	Currency        string              `json:"currency"`
+	Status          domain.ImportStatus `json:"status"`
+	CommittedAt     *time.Time          `json:"committed_at,omitempty"`
+	RowCount        int                 `json:"row_count"`
+	ErrorCount      int                 `json:"error_count"`      // Rows that could not be read
+	DuplicateCount  int                 `json:"duplicate_count"`  // Rows with a duplicate candidate
+	AlreadyImported int                 `json:"already_imported"` // OFX transactions left out because their FITID was imported before
+	// LedgerBalance is the balance of the account the OFX statement reports on LedgerBalanceDate, and
+	// ComputedBalance the cleared balance of the account on that date, to reconcile them.
+	LedgerBalance     *domain.Money       `json:"ledger_balance,omitempty" swaggertype:"string" example:"2160.10"`
+	LedgerBalanceDate *time.Time          `json:"ledger_balance_date,omitempty"`
+	ComputedBalance   *domain.Money       `json:"computed_balance,omitempty" swaggertype:"string" example:"2160.10"`
+	Rows              []ImportRowResponse `json:"rows"`
+	CreatedAt         time.Time           `json:"created_at"`
+	CreatedBy         string              `json:"created_by"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+	UpdatedBy         string              `json:"updated_by"`
+}
+
+// MapImportToResponse maps domain.Import to ImportResponse.
+func MapImportToResponse(imp *domain.Import) ImportResponse {
+	resp := ImportResponse{
+		ID:                imp.ID,
+		TenantID:          imp.TenantID,
+		AccountID:         imp.AccountID,
+		ProfileID:         imp.ProfileID,
+		Format:            imp.Format,
+		FileName:          imp.FileName,
+		Currency:          imp.Currency,
+		Status:            imp.Status,
+		CommittedAt:       imp.CommittedAt,
+		RowCount:          len(imp.Rows),
+		AlreadyImported:   imp.AlreadyImported,
+		LedgerBalance:     imp.LedgerBalance,
+		LedgerBalanceDate: imp.LedgerBalanceDate,
+		ComputedBalance:   imp.ComputedBalance,
+		Rows:              make([]ImportRowResponse, len(imp.Rows)),
+		CreatedAt:         imp.CreatedAt,
+		CreatedBy:         imp.CreatedBy,
+		UpdatedAt:         imp.UpdatedAt,
+		UpdatedBy:         imp.UpdatedBy,
+	}
+	for i, row := range imp.Rows {
+		if row.Error != nil {
+			resp.ErrorCount++
+		}
+		if row.DuplicateTransactionID != nil {
+			resp.DuplicateCount++
+		}
+		resp.Rows[i] = ImportRowResponse{
+			ID:                     row.ID,
+			Line:                   row.Line,
+			Date:                   row.Date,
+			Description:            row.Description,
+			Amount:                 row.Amount,
+			ExternalID:             row.ExternalID,
+			TransactionType:        row.TransactionType,
+			Error:                  row.Error,
+			DuplicateTransactionID: row.DuplicateTransactionID,
+			SuggestedCategoryID:    row.SuggestedCategoryID,
+			TransactionID:          row.TransactionID,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// CreateInvitationRequest represents the payload for inviting someone to a tenant.
+type CreateInvitationRequest struct {
+	Email string      `json:"email" binding:"required,email"`
+	Role  domain.Role `json:"role" binding:"required,oneof=owner admin editor viewer"`
+}
+
+// InvitationResponse represents an invitation in API responses.
+// Token is only returned when the invitation is created.
+type InvitationResponse struct {
+	ID            string                  `json:"id"`
+	TenantID      string                  `json:"tenant_id"`
+	InviterUserID string                  `json:"inviter_user_id"`
+	Email         string                  `json:"email"`
+	Role          domain.Role             `json:"role"`
+	Status        domain.InvitationStatus `json:"status"`
+	Token         string                  `json:"token,omitempty"`
+	ExpiresAt     time.Time               `json:"expires_at"`
+	AcceptedAt    *time.Time              `json:"accepted_at,omitempty"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
+
+func MapInvitationToResponse(inv *domain.Invitation) InvitationResponse {
+	return InvitationResponse{
+		ID:            inv.ID,
+		TenantID:      inv.TenantID,
+		InviterUserID: inv.InviterUserID,
+		Email:         inv.Email,
+		Role:          inv.Role,
+		Status:        inv.Status,
+		ExpiresAt:     inv.ExpiresAt,
+		AcceptedAt:    inv.AcceptedAt,
+		CreatedAt:     inv.CreatedAt,
+	}
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/igoventura/fintrack-api/internal/jobs"
+)
+
+// JobRunResponse represents a background job run.
+type JobRunResponse struct {
+	ID          string              `json:"id"`
+	ScheduledAt time.Time           `json:"scheduled_at"`
+	StartedAt   time.Time           `json:"started_at"`
+	FinishedAt  *time.Time          `json:"finished_at,omitempty"`
+	Status      domain.JobRunStatus `json:"status"`
+	Summary     *string             `json:"summary,omitempty"`
+	Error       *string             `json:"error,omitempty"`
+	Instance    string              `json:"instance"`
+}
+
+// JobStatusResponse represents the API response for a registered background job.
+type JobStatusResponse struct {
+	Name     string          `json:"name"`
+	Schedule string          `json:"schedule"`           // Cron expression, in UTC
+	NextRun  *time.Time      `json:"next_run,omitempty"` // On the instance serving the request; omitted when jobs do not run there
+	LastRun  *JobRunResponse `json:"last_run,omitempty"` // Latest run on any instance
+}
+
+// MapJobStatusToResponse maps jobs.Status to JobStatusResponse.
+func MapJobStatusToResponse(s *jobs.Status) JobStatusResponse {
+	resp := JobStatusResponse{
+		Name:     s.Name,
+		Schedule: s.Schedule,
+	}
+	if !s.NextRun.IsZero() {
+		resp.NextRun = &s.NextRun
+	}
+	if r := s.LastRun; r != nil {
+		resp.LastRun = &JobRunResponse{
+			ID:          r.ID,
+			ScheduledAt: r.ScheduledAt,
+			StartedAt:   r.StartedAt,
+			FinishedAt:  r.FinishedAt,
+			Status:      r.Status,
+			Summary:     r.Summary,
+			Error:       r.Error,
+			Instance:    r.Instance,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,45 @@
+package dto
+
+import "github.com/igoventura/fintrack-api/domain"
+
+// PageQuery defines the pagination query parameters of list endpoints.
+type PageQuery struct {
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor       string `form:"cursor"`
+	IncludeTotal bool   `form:"include_total"`
+}
+
+// ToDomain maps PageQuery to domain.PageRequest, applying the default page size.
+func (q *PageQuery) ToDomain() domain.PageRequest {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = domain.DefaultPageLimit
+	}
+	return domain.PageRequest{
+		Limit:        limit,
+		Cursor:       q.Cursor,
+		IncludeTotal: q.IncludeTotal,
+	}
+}
+
+// PageResponse is the envelope of paginated list responses.
+// Pass next_cursor as the cursor query parameter to fetch the next page; it is omitted on the last page.
+// Items is always a slice; handlers document its element type as dto.PageResponse{items=[]dto.X}.
+type PageResponse struct {
+	Items      any    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// NewPageResponse maps a domain page to its response envelope.
+func NewPageResponse[T, R any](page *domain.Page[T], mapItem func(*T) R) PageResponse {
+	items := make([]R, 0, len(page.Items))
+	for i := range page.Items {
+		items = append(items, mapItem(&page.Items[i]))
+	}
+	return PageResponse{
+		Items:      items,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
+}
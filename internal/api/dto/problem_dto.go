@@ -0,0 +1,11 @@
+package dto
+
+// ProblemResponse is the body of error responses: RFC 7807 problem details, served as application/problem+json.
+type ProblemResponse struct {
+	Type     string            `json:"type" example:"about:blank"`
+	Title    string            `json:"title" example:"Bad Request"`
+	Status   int               `json:"status" example:"400"`
+	Detail   string            `json:"detail,omitempty" example:"validation failed"`
+	Instance string            `json:"instance,omitempty" example:"/transactions"`
+	Errors   map[string]string `json:"errors,omitempty"` // Message per invalid field, keyed by its JSON name
+}
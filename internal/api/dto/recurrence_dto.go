@@ -0,0 +1,138 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// RecurrenceRuleRequest represents the payload for creating or updating a recurrence rule.
+// Updating replaces the unpaid occurrences from today on; past and paid occurrences are kept.
+type RecurrenceRuleRequest struct {
+	FromAccountID   string                     `json:"from_account_id" binding:"required,uuid"`
+	ToAccountID     *string                    `json:"to_account_id,omitempty" binding:"omitempty,uuid"`
+	CategoryID      string                     `json:"category_id" binding:"required,uuid"`
+	TransactionType domain.TransactionType     `json:"transaction_type" binding:"required,oneof=credit debit transfer payment"`
+	Currency        string                     `json:"currency,omitempty" binding:"omitempty,len=3"` // Defaults to the account currency
+	Amount          domain.Money               `json:"amount" swaggertype:"string" example:"1500.00"`
+	Comments        *string                    `json:"comments,omitempty"`
+	TagIDs          []string                   `json:"tag_ids,omitempty" binding:"omitempty,dive,uuid"`
+	Frequency       domain.RecurrenceFrequency `json:"frequency" binding:"required,oneof=weekly monthly yearly"`
+	Interval        int                        `json:"interval,omitempty" binding:"omitempty,min=1"`         // Every N periods, defaults to 1
+	MonthDay        *int                       `json:"month_day,omitempty" binding:"omitempty,min=1,max=31"` // Monthly only, defaults to the day of start_date
+	LastBusinessDay bool                       `json:"last_business_day,omitempty"`                          // Monthly only
+	StartDate       time.Time                  `json:"start_date" binding:"required"`                        // First occurrence (or the start of the schedule)
+	EndDate         *time.Time                 `json:"end_date,omitempty"`                                   // Last possible occurrence
+	Count           *int                       `json:"count,omitempty" binding:"omitempty,min=1"`            // Number of occurrences
+}
+
+// SkipOccurrenceRequest represents the payload for skipping a single occurrence.
+type SkipOccurrenceRequest struct {
+	Date time.Time `json:"date" binding:"required"`
+}
+
+// ChangeAmountRequest represents the payload for changing the amount of a rule from a date onward.
+type ChangeAmountRequest struct {
+	EffectiveFrom time.Time    `json:"effective_from" binding:"required"`
+	Amount        domain.Money `json:"amount" swaggertype:"string" example:"1650.00"`
+}
+
+// RecurrenceAmountChangeResponse represents an amount change of a recurrence rule.
+type RecurrenceAmountChangeResponse struct {
+	EffectiveFrom time.Time    `json:"effective_from"`
+	Amount        domain.Money `json:"amount" swaggertype:"string" example:"1650.00"`
+}
+
+// RecurrenceRuleResponse represents the API response for a recurrence rule.
+type RecurrenceRuleResponse struct {
+	ID                string                           `json:"id"`
+	TenantID          string                           `json:"tenant_id"`
+	FromAccountID     string                           `json:"from_account_id"`
+	ToAccountID       *string                          `json:"to_account_id,omitempty"`
+	CategoryID        string                           `json:"category_id"`
+	TransactionType   domain.TransactionType           `json:"transaction_type"`
+	Currency          string                           `json:"currency"`
+	Amount            domain.Money                     `json:"amount" swaggertype:"string" example:"1500.00"`
+	Comments          *string                          `json:"comments,omitempty"`
+	TagIDs            []string                         `json:"tag_ids"`
+	Frequency         domain.RecurrenceFrequency       `json:"frequency"`
+	Interval          int                              `json:"interval"`
+	MonthDay          *int                             `json:"month_day,omitempty"`
+	LastBusinessDay   bool                             `json:"last_business_day"`
+	StartDate         time.Time                        `json:"start_date"`
+	EndDate           *time.Time                       `json:"end_date,omitempty"`
+	Count             *int                             `json:"count,omitempty"`
+	MaterializedUntil *time.Time                       `json:"materialized_until,omitempty"`
+	AmountChanges     []RecurrenceAmountChangeResponse `json:"amount_changes"`
+	SkippedDates      []time.Time                      `json:"skipped_dates"`
+	CreatedAt         time.Time                        `json:"created_at"`
+	CreatedBy         string                           `json:"created_by"`
+	UpdatedAt         time.Time                        `json:"updated_at"`
+	UpdatedBy         string                           `json:"updated_by"`
+}
+
+// MaterializeResponse represents the result of materializing recurrence rules.
+type MaterializeResponse struct {
+	Created int `json:"created"` // Number of transactions created
+}
+
+// ToDomain maps RecurrenceRuleRequest to domain.RecurrenceRule.
+func (req *RecurrenceRuleRequest) ToDomain() *domain.RecurrenceRule {
+	return &domain.RecurrenceRule{
+		FromAccountID:   req.FromAccountID,
+		ToAccountID:     req.ToAccountID,
+		CategoryID:      req.CategoryID,
+		TransactionType: req.TransactionType,
+		Currency:        req.Currency,
+		Amount:          req.Amount,
+		Comments:        req.Comments,
+		TagIDs:          req.TagIDs,
+		Frequency:       req.Frequency,
+		Interval:        req.Interval,
+		MonthDay:        req.MonthDay,
+		LastBusinessDay: req.LastBusinessDay,
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+		Count:           req.Count,
+	}
+}
+
+// MapRecurrenceRuleToResponse maps domain.RecurrenceRule to RecurrenceRuleResponse.
+func MapRecurrenceRuleToResponse(r *domain.RecurrenceRule) RecurrenceRuleResponse {
+	resp := RecurrenceRuleResponse{
+		ID:                r.ID,
+		TenantID:          r.TenantID,
+		FromAccountID:     r.FromAccountID,
+		ToAccountID:       r.ToAccountID,
+		CategoryID:        r.CategoryID,
+		TransactionType:   r.TransactionType,
+		Currency:          r.Currency,
+		Amount:            r.Amount,
+		Comments:          r.Comments,
+		TagIDs:            r.TagIDs,
+		Frequency:         r.Frequency,
+		Interval:          r.Interval,
+		MonthDay:          r.MonthDay,
+		LastBusinessDay:   r.LastBusinessDay,
+		StartDate:         r.StartDate,
+		EndDate:           r.EndDate,
+		Count:             r.Count,
+		MaterializedUntil: r.MaterializedUntil,
+		AmountChanges:     make([]RecurrenceAmountChangeResponse, 0, len(r.AmountChanges)),
+		SkippedDates:      r.SkippedDates,
+		CreatedAt:         r.CreatedAt,
+		CreatedBy:         r.CreatedBy,
+		UpdatedAt:         r.UpdatedAt,
+		UpdatedBy:         r.UpdatedBy,
+	}
+	if resp.TagIDs == nil {
+		resp.TagIDs = []string{}
+	}
+	if resp.SkippedDates == nil {
+		resp.SkippedDates = []time.Time{}
+	}
+	for _, c := range r.AmountChanges {
+		resp.AmountChanges = append(resp.AmountChanges, RecurrenceAmountChangeResponse{EffectiveFrom: c.EffectiveFrom, Amount: c.Amount})
+	}
+	return resp
+}
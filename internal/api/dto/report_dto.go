@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// ReportFilterRequest defines the query parameters shared by the reports.
+// Months are YYYYMM and the range is inclusive; it defaults to the last twelve months.
+type ReportFilterRequest struct {
+	MonthFrom string `form:"month_from" binding:"omitempty,len=6"`
+	MonthTo   string `form:"month_to" binding:"omitempty,len=6"`
+	AccountID string `form:"account_id" binding:"omitempty,uuid"`
+	Currency  string `form:"currency" binding:"omitempty,len=3"`
+}
+
+// ToDomain maps ReportFilterRequest to domain.ReportFilter.
+func (f *ReportFilterRequest) ToDomain() domain.ReportFilter {
+	return domain.ReportFilter{
+		MonthFrom: f.MonthFrom,
+		MonthTo:   f.MonthTo,
+		AccountID: f.AccountID,
+		Currency:  strings.ToUpper(f.Currency),
+	}
+}
+
+// TopTagsRequest defines the query parameters of the top tags report besides the filter.
+type TopTagsRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// CategorySpendResponse represents the spend of an expense category in a month.
+type CategorySpendResponse struct {
+	Month            string       `json:"month"` // YYYYMM
+	CategoryID       string       `json:"category_id"`
+	ParentCategoryID *string      `json:"parent_category_id,omitempty"`
+	Name             string       `json:"name"`
+	Currency         string       `json:"currency"`
+	Amount           domain.Money `json:"amount" swaggertype:"string" example:"320.00"` // Spent on the category itself
+	Total            domain.Money `json:"total" swaggertype:"string" example:"450.00"`  // Including its subcategories
+}
+
+// FromCategorySpendDomain maps domain.CategorySpend to CategorySpendResponse.
+func FromCategorySpendDomain(s *domain.CategorySpend) CategorySpendResponse {
+	return CategorySpendResponse{
+		Month:            s.Month,
+		CategoryID:       s.CategoryID,
+		ParentCategoryID: s.ParentCategoryID,
+		Name:             s.Name,
+		Currency:         s.Currency,
+		Amount:           s.Amount,
+		Total:            s.Total,
+	}
+}
+
+// IncomeExpenseResponse represents the income and expense of a month.
+type IncomeExpenseResponse struct {
+	Month    string       `json:"month"` // YYYYMM
+	Currency string       `json:"currency"`
+	Income   domain.Money `json:"income" swaggertype:"string" example:"5000.00"`
+	Expense  domain.Money `json:"expense" swaggertype:"string" example:"3200.00"`
+	Net      domain.Money `json:"net" swaggertype:"string" example:"1800.00"`
+}
+
+// FromIncomeExpenseDomain maps domain.IncomeExpense to IncomeExpenseResponse.
+func FromIncomeExpenseDomain(m *domain.IncomeExpense) IncomeExpenseResponse {
+	return IncomeExpenseResponse{
+		Month:    m.Month,
+		Currency: m.Currency,
+		Income:   m.Income,
+		Expense:  m.Expense,
+		Net:      m.Net,
+	}
+}
+
+// CashFlowResponse represents the money that entered and left the accounts in a month.
+type CashFlowResponse struct {
+	Month    string       `json:"month"` // YYYYMM
+	Currency string       `json:"currency"`
+	Inflow   domain.Money `json:"inflow" swaggertype:"string" example:"5200.00"`
+	Outflow  domain.Money `json:"outflow" swaggertype:"string" example:"4100.00"`
+	Net      domain.Money `json:"net" swaggertype:"string" example:"1100.00"`
+}
+
+// FromCashFlowDomain maps domain.CashFlow to CashFlowResponse.
+func FromCashFlowDomain(m *domain.CashFlow) CashFlowResponse {
+	return CashFlowResponse{
+		Month:    m.Month,
+		Currency: m.Currency,
+		Inflow:   m.Inflow,
+		Outflow:  m.Outflow,
+		Net:      m.Net,
+	}
+}
+
+// TagSpendResponse represents the spend under a tag over a report's range.
+type TagSpendResponse struct {
+	TagID            string       `json:"tag_id"`
+	Name             string       `json:"name"`
+	Currency         string       `json:"currency"`
+	Amount           domain.Money `json:"amount" swaggertype:"string" example:"780.00"`
+	TransactionCount int          `json:"transaction_count"`
+}
+
+// FromTagSpendDomain maps domain.TagSpend to TagSpendResponse.
+func FromTagSpendDomain(s *domain.TagSpend) TagSpendResponse {
+	return TagSpendResponse{
+		TagID:            s.TagID,
+		Name:             s.Name,
+		Currency:         s.Currency,
+		Amount:           s.Amount,
+		TransactionCount: s.TransactionCount,
+	}
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// StatementResponse represents the API response for a credit card statement.
+type StatementResponse struct {
+	AccountID   string                 `json:"account_id"`
+	Period      string                 `json:"period"`       // YYYYMM of the due date
+	OpeningDate string                 `json:"opening_date"` // YYYY-MM-DD
+	ClosingDate string                 `json:"closing_date"` // YYYY-MM-DD
+	DueDate     string                 `json:"due_date"`     // YYYY-MM-DD
+	Currency    string                 `json:"currency"`
+	Charges     domain.Money           `json:"charges" swaggertype:"string" example:"1200.00"`
+	Credits     domain.Money           `json:"credits" swaggertype:"string" example:"50.00"`
+	Total       domain.Money           `json:"total" swaggertype:"string" example:"1150.00"`
+	Paid        domain.Money           `json:"paid" swaggertype:"string" example:"0.00"`
+	Balance     domain.Money           `json:"balance" swaggertype:"string" example:"1150.00"`
+	MinimumDue  domain.Money           `json:"minimum_due" swaggertype:"string" example:"172.50"`
+	Status      domain.StatementStatus `json:"status"`
+	Items       []TransactionResponse  `json:"items,omitempty"`
+}
+
+// MapStatementToResponse maps domain.Statement to StatementResponse.
+func MapStatementToResponse(s *domain.Statement) *StatementResponse {
+	resp := &StatementResponse{
+		AccountID:   s.AccountID,
+		Period:      s.Period,
+		OpeningDate: s.OpeningDate.Format(time.DateOnly),
+		ClosingDate: s.ClosingDate.Format(time.DateOnly),
+		DueDate:     s.DueDate.Format(time.DateOnly),
+		Currency:    s.Total.Currency,
+		Charges:     s.Charges,
+		Credits:     s.Credits,
+		Total:       s.Total,
+		Paid:        s.Paid,
+		Balance:     s.Balance,
+		MinimumDue:  s.MinimumDue,
+		Status:      s.Status,
+	}
+	for i := range s.Items {
+		resp.Items = append(resp.Items, FromTransactionDomain(&s.Items[i], false))
+	}
+	return resp
+}
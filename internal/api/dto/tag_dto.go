@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
 
 type CreateTagRequest struct {
 	Name string `json:"name" binding:"required"`
@@ -10,6 +14,15 @@ type UpdateTagRequest struct {
 	Name string `json:"name" binding:"required"`
 }
 
+// PatchTagRequest is a JSON Merge Patch of a tag: only the fields sent are changed.
+type PatchTagRequest struct {
+	Name domain.Field[string] `json:"name" swaggertype:"string"`
+}
+
+func (r *PatchTagRequest) ToDomain() domain.TagPatch {
+	return domain.TagPatch{Name: r.Name}
+}
+
 type TagResponse struct {
 	ID            string     `json:"id"`
 	TenantID      string     `json:"tenant_id"`
@@ -18,5 +31,21 @@ type TagResponse struct {
 	CreatedBy     string     `json:"created_by"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 	UpdatedBy     string     `json:"updated_by"`
+	Version       int        `json:"version"`
 	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
 }
+
+// MapTagToResponse maps domain.Tag to TagResponse.
+func MapTagToResponse(tag *domain.Tag) TagResponse {
+	return TagResponse{
+		ID:            tag.ID,
+		TenantID:      tag.TenantID,
+		Name:          tag.Name,
+		CreatedAt:     tag.CreatedAt,
+		CreatedBy:     tag.CreatedBy,
+		UpdatedAt:     tag.UpdatedAt,
+		UpdatedBy:     tag.UpdatedBy,
+		Version:       tag.Version,
+		DeactivatedAt: tag.DeactivatedAt,
+	}
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
 
 // CreateTenantRequest represents the payload for creating a new tenant.
 type CreateTenantRequest struct {
@@ -14,3 +18,27 @@ type TenantResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// TenantMemberResponse represents a member of a tenant in API responses.
+type TenantMemberResponse struct {
+	UserID    string      `json:"user_id"`
+	Name      string      `json:"name"`
+	Email     string      `json:"email"`
+	Role      domain.Role `json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// UpdateMemberRoleRequest represents the payload for changing the role of a tenant member.
+type UpdateMemberRoleRequest struct {
+	Role domain.Role `json:"role" binding:"required,oneof=owner admin editor viewer"`
+}
+
+func MapTenantMemberToResponse(m *domain.TenantMember) TenantMemberResponse {
+	return TenantMemberResponse{
+		UserID:    m.UserID,
+		Name:      m.Name,
+		Email:     m.Email,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+	}
+}
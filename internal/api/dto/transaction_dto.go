@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/igoventura/fintrack-api/domain"
@@ -10,13 +12,15 @@ import (
 type CreateTransactionRequest struct {
 	FromAccountID   string                 `json:"from_account_id" binding:"required,uuid"`
 	ToAccountID     *string                `json:"to_account_id,omitempty" binding:"omitempty,uuid"`
-	Amount          float64                `json:"amount" binding:"required,gt=0"`
-	AccrualMonth    string                 `json:"accrual_month" binding:"required,len=6"` // YYYYMM
+	Amount          domain.Money           `json:"amount" swaggertype:"string" example:"123.45"`
+	AccrualMonth    string                 `json:"accrual_month" binding:"omitempty,len=6"` // YYYYMM, defaults to the due date month (statement period for credit cards)
 	TransactionType domain.TransactionType `json:"transaction_type" binding:"required,oneof=credit debit transfer payment"`
 	CategoryID      string                 `json:"category_id" binding:"required,uuid"`
 	Comments        *string                `json:"comments,omitempty"`
 	DueDate         time.Time              `json:"due_date" binding:"required"`
 	PaymentDate     *time.Time             `json:"payment_date,omitempty"`
+	ExchangeRate    domain.ExchangeRate    `json:"exchange_rate,omitempty" swaggertype:"string" example:"5.4321"` // Transfers between currencies: destination units per source unit
+	Fee             domain.Money           `json:"fee" swaggertype:"string" example:"1.50"`                       // Transfers: charged to the source account
 	TagIDs          []string               `json:"tag_ids,omitempty" binding:"omitempty,dive,uuid"`
 	Installments    int                    `json:"installments,omitempty" binding:"omitempty,min=1"`
 	IsRecurring     bool                   `json:"is_recurring,omitempty"`
@@ -27,38 +31,86 @@ type CreateTransactionRequest struct {
 type UpdateTransactionRequest struct {
 	FromAccountID   string                 `json:"from_account_id" binding:"required,uuid"`
 	ToAccountID     *string                `json:"to_account_id,omitempty" binding:"omitempty,uuid"`
-	Amount          float64                `json:"amount" binding:"required,gt=0"`
+	Amount          domain.Money           `json:"amount" swaggertype:"string" example:"123.45"`
 	AccrualMonth    string                 `json:"accrual_month" binding:"required,len=6"` // YYYYMM
 	TransactionType domain.TransactionType `json:"transaction_type" binding:"required,oneof=credit debit transfer payment"`
 	CategoryID      string                 `json:"category_id" binding:"required,uuid"`
 	Comments        *string                `json:"comments,omitempty"`
 	DueDate         time.Time              `json:"due_date" binding:"required"`
 	PaymentDate     *time.Time             `json:"payment_date,omitempty"`
+	ExchangeRate    domain.ExchangeRate    `json:"exchange_rate,omitempty" swaggertype:"string" example:"5.4321"` // Transfers between currencies; kept when omitted on the incoming leg
+	Fee             domain.Money           `json:"fee" swaggertype:"string" example:"1.50"`                       // Transfers: charged to the source account
 	TagIDs          []string               `json:"tag_ids,omitempty" binding:"omitempty,dive,uuid"`
 }
 
+// PatchTransactionRequest is a JSON Merge Patch of a transaction: only the fields sent are changed,
+// and null clears the nullable ones (to_account_id, comments, payment_date, tag_ids).
+type PatchTransactionRequest struct {
+	FromAccountID   domain.Field[string]                 `json:"from_account_id" swaggertype:"string"`
+	ToAccountID     domain.Field[*string]                `json:"to_account_id" swaggertype:"string"`
+	Amount          domain.Field[domain.Money]           `json:"amount" swaggertype:"string" example:"123.45"`
+	AccrualMonth    domain.Field[string]                 `json:"accrual_month" swaggertype:"string"` // YYYYMM
+	TransactionType domain.Field[domain.TransactionType] `json:"transaction_type" swaggertype:"string" enums:"credit,debit,transfer,payment"`
+	CategoryID      domain.Field[string]                 `json:"category_id" swaggertype:"string"`
+	Comments        domain.Field[*string]                `json:"comments" swaggertype:"string"`
+	DueDate         domain.Field[time.Time]              `json:"due_date" swaggertype:"string" format:"date-time"`
+	PaymentDate     domain.Field[*time.Time]             `json:"payment_date" swaggertype:"string" format:"date-time"`
+	ExchangeRate    domain.Field[domain.ExchangeRate]    `json:"exchange_rate" swaggertype:"string" example:"5.4321"`
+	Fee             domain.Field[domain.Money]           `json:"fee" swaggertype:"string" example:"1.50"`
+	TagIDs          domain.Field[[]string]               `json:"tag_ids" swaggertype:"array,string"`
+}
+
+func (r *PatchTransactionRequest) ToDomain() domain.TransactionPatch {
+	return domain.TransactionPatch{
+		FromAccountID:   r.FromAccountID,
+		ToAccountID:     r.ToAccountID,
+		Amount:          r.Amount,
+		AccrualMonth:    r.AccrualMonth,
+		TransactionType: r.TransactionType,
+		CategoryID:      r.CategoryID,
+		Comments:        r.Comments,
+		DueDate:         r.DueDate,
+		PaymentDate:     r.PaymentDate,
+		ExchangeRate:    r.ExchangeRate,
+		Fee:             r.Fee,
+		TagIDs:          r.TagIDs,
+	}
+}
+
 // TransactionResponse represents the API response for a transaction.
 type TransactionResponse struct {
 	ID                  string                 `json:"id"`
 	ParentTransactionID *string                `json:"parent_transaction_id,omitempty"`
+	InstallmentNumber   *int                   `json:"installment_number,omitempty"`
+	InstallmentCount    *int                   `json:"installment_count,omitempty"`
+	RecurrenceRuleID    *string                `json:"recurrence_rule_id,omitempty"`
+	RecurrenceDate      *time.Time             `json:"recurrence_date,omitempty"`
 	TenantID            string                 `json:"tenant_id"`
 	FromAccountID       string                 `json:"from_account_id"`
 	ToAccountID         *string                `json:"to_account_id,omitempty"`
 	Currency            string                 `json:"currency"`
-	Amount              float64                `json:"amount"`
+	Amount              domain.Money           `json:"amount" swaggertype:"string" example:"123.45"`
 	AccrualMonth        string                 `json:"accrual_month"`
 	TransactionType     domain.TransactionType `json:"transaction_type"`
 	CategoryID          string                 `json:"category_id"`
 	Comments            *string                `json:"comments,omitempty"`
 	DueDate             time.Time              `json:"due_date"`
 	PaymentDate         *time.Time             `json:"payment_date,omitempty"`
+	SettledAmount       domain.Money           `json:"settled_amount" swaggertype:"string" example:"50.00"` // Sum of the partial payments
+	Status              string                 `json:"status" enums:"pending,partially_paid,paid,overdue"`
+	LinkedTransactionID *string                `json:"linked_transaction_id,omitempty"` // Transfers: the other leg
+	TransferDirection   *string                `json:"transfer_direction,omitempty" enums:"out,in"`
+	ExchangeRate        domain.ExchangeRate    `json:"exchange_rate,omitempty" swaggertype:"string" example:"5.4321"`
+	Fee                 domain.Money           `json:"fee" swaggertype:"string" example:"0.00"`
 	CreatedAt           time.Time              `json:"created_at"`
 	CreatedBy           string                 `json:"created_by"`
 	UpdatedAt           time.Time              `json:"updated_at"`
 	UpdatedBy           string                 `json:"updated_by"`
+	Version             int                    `json:"version"`
 	DeactivatedAt       *time.Time             `json:"deactivated_at,omitempty"`
 	DeactivatedBy       *string                `json:"deactivated_by,omitempty"`
 	TagIDs              []string               `json:"tag_ids,omitempty"`
+	Tags                []TagResponse          `json:"tags,omitempty"` // Only with expand=tags
 }
 
 // ToDomain maps CreateTransactionRequest to domain.Transaction.
@@ -73,6 +125,8 @@ func (req *CreateTransactionRequest) ToDomain() *domain.Transaction {
 		Comments:        req.Comments,
 		DueDate:         req.DueDate,
 		PaymentDate:     req.PaymentDate,
+		ExchangeRate:    req.ExchangeRate,
+		Fee:             req.Fee,
 	}
 }
 
@@ -88,14 +142,21 @@ func (req *UpdateTransactionRequest) ToDomain() *domain.Transaction {
 		Comments:        req.Comments,
 		DueDate:         req.DueDate,
 		PaymentDate:     req.PaymentDate,
+		ExchangeRate:    req.ExchangeRate,
+		Fee:             req.Fee,
 	}
 }
 
 // FromTransactionDomain maps domain.Transaction to TransactionResponse.
-func FromTransactionDomain(t *domain.Transaction, tagIDs []string) TransactionResponse {
-	return TransactionResponse{
+// Tag IDs are always included; expandTags also embeds the full tag objects.
+func FromTransactionDomain(t *domain.Transaction, expandTags bool) TransactionResponse {
+	resp := TransactionResponse{
 		ID:                  t.ID,
 		ParentTransactionID: t.ParentTransactionID,
+		InstallmentNumber:   t.InstallmentNumber,
+		InstallmentCount:    t.InstallmentCount,
+		RecurrenceRuleID:    t.RecurrenceRuleID,
+		RecurrenceDate:      t.RecurrenceDate,
 		TenantID:            t.TenantID,
 		FromAccountID:       t.FromAccountID,
 		ToAccountID:         t.ToAccountID,
@@ -107,28 +168,155 @@ func FromTransactionDomain(t *domain.Transaction, tagIDs []string) TransactionRe
 		Comments:            t.Comments,
 		DueDate:             t.DueDate,
 		PaymentDate:         t.PaymentDate,
+		SettledAmount:       t.SettledAmount,
+		Status:              string(t.Status(time.Now())),
+		LinkedTransactionID: t.LinkedTransactionID,
+		ExchangeRate:        t.ExchangeRate,
+		Fee:                 t.Fee,
 		CreatedAt:           t.CreatedAt,
 		CreatedBy:           t.CreatedBy,
 		UpdatedAt:           t.UpdatedAt,
 		UpdatedBy:           t.UpdatedBy,
+		Version:             t.Version,
 		DeactivatedAt:       t.DeactivatedAt,
 		DeactivatedBy:       t.DeactivatedBy,
-		TagIDs:              tagIDs,
+		TagIDs:              t.TagIDs(),
+	}
+	if t.TransferDirection != nil {
+		direction := string(*t.TransferDirection)
+		resp.TransferDirection = &direction
 	}
+	if expandTags {
+		resp.Tags = make([]TagResponse, len(t.Tags))
+		for i := range t.Tags {
+			resp.Tags[i] = MapTagToResponse(&t.Tags[i])
+		}
+	}
+	return resp
 }
 
 // TransactionFilterRequest defines query parameters for listing transactions.
+// Dates are YYYY-MM-DD and ranges are inclusive; category_id and tag_id can be repeated.
 type TransactionFilterRequest struct {
-	AccrualMonth    string                 `form:"accrual_month" binding:"omitempty,len=6"`
-	AccountID       string                 `form:"account_id" binding:"omitempty,uuid"`
-	TransactionType domain.TransactionType `form:"transaction_type" binding:"omitempty,oneof=credit debit transfer payment"`
+	AccrualMonth         string                   `form:"accrual_month" binding:"omitempty,len=6"`
+	AccrualMonthFrom     string                   `form:"accrual_month_from" binding:"omitempty,len=6"`
+	AccrualMonthTo       string                   `form:"accrual_month_to" binding:"omitempty,len=6"`
+	AccountID            string                   `form:"account_id" binding:"omitempty,uuid"`
+	TransactionType      domain.TransactionType   `form:"transaction_type" binding:"omitempty,oneof=credit debit transfer payment"`
+	DueDateFrom          string                   `form:"due_date_from" binding:"omitempty,datetime=2006-01-02"`
+	DueDateTo            string                   `form:"due_date_to" binding:"omitempty,datetime=2006-01-02"`
+	PaymentDateFrom      string                   `form:"payment_date_from" binding:"omitempty,datetime=2006-01-02"`
+	PaymentDateTo        string                   `form:"payment_date_to" binding:"omitempty,datetime=2006-01-02"`
+	MinAmount            string                   `form:"min_amount"`
+	MaxAmount            string                   `form:"max_amount"`
+	CategoryIDs          []string                 `form:"category_id" binding:"omitempty,dive,uuid"`
+	IncludeSubcategories bool                     `form:"include_subcategories"`
+	TagIDs               []string                 `form:"tag_id" binding:"omitempty,dive,uuid"`
+	TagMatch             domain.TagMatch          `form:"tag_match" binding:"omitempty,oneof=any all"`
+	Status               domain.TransactionStatus `form:"status" binding:"omitempty,oneof=paid unpaid overdue pending partially_paid"`
+	ParentTransactionID  string                   `form:"parent_transaction_id" binding:"omitempty,uuid"`
+	Currency             string                   `form:"currency" binding:"omitempty,len=3"`
+	Search               string                   `form:"q" binding:"omitempty,max=200"`
 }
 
 // ToDomain maps TransactionFilterRequest to domain.TransactionFilter.
-func (f *TransactionFilterRequest) ToDomain() domain.TransactionFilter {
-	return domain.TransactionFilter{
-		AccrualMonth:    f.AccrualMonth,
-		AccountID:       f.AccountID,
-		TransactionType: f.TransactionType,
+// It returns domain.ErrInvalidTransactionFilter for malformed amounts.
+func (f *TransactionFilterRequest) ToDomain() (domain.TransactionFilter, error) {
+	filter := domain.TransactionFilter{
+		AccrualMonth:         f.AccrualMonth,
+		AccrualMonthFrom:     f.AccrualMonthFrom,
+		AccrualMonthTo:       f.AccrualMonthTo,
+		AccountID:            f.AccountID,
+		TransactionType:      f.TransactionType,
+		DueDateFrom:          parseFilterDate(f.DueDateFrom),
+		DueDateTo:            parseFilterDate(f.DueDateTo),
+		PaymentDateFrom:      parseFilterDate(f.PaymentDateFrom),
+		PaymentDateTo:        parseFilterDate(f.PaymentDateTo),
+		CategoryIDs:          f.CategoryIDs,
+		IncludeSubcategories: f.IncludeSubcategories,
+		TagIDs:               f.TagIDs,
+		TagMatch:             f.TagMatch,
+		Status:               f.Status,
+		ParentTransactionID:  f.ParentTransactionID,
+		Currency:             strings.ToUpper(f.Currency),
+		Search:               strings.TrimSpace(f.Search),
+	}
+
+	for _, bound := range []struct {
+		value string
+		dst   **domain.Money
+		name  string
+	}{
+		{f.MinAmount, &filter.MinAmount, "min_amount"},
+		{f.MaxAmount, &filter.MaxAmount, "max_amount"},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		amount, err := domain.ParseMoney(bound.value, filter.Currency)
+		if err != nil {
+			return domain.TransactionFilter{}, fmt.Errorf("%w: %s must be a decimal amount", domain.ErrInvalidTransactionFilter, bound.name)
+		}
+		*bound.dst = &amount
+	}
+	return filter, nil
+}
+
+// parseFilterDate parses a YYYY-MM-DD date already checked by the binding, or returns nil when empty.
+func parseFilterDate(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return nil
+	}
+	return &d
+}
+
+// SettleTransactionRequest represents the payload for recording a payment against a transaction.
+type SettleTransactionRequest struct {
+	PaymentDate time.Time    `json:"payment_date" binding:"required"`
+	Amount      domain.Money `json:"amount" swaggertype:"string" example:"50.00"`   // Defaults to the outstanding amount
+	AccountID   string       `json:"account_id,omitempty" binding:"omitempty,uuid"` // Defaults to the transaction's from_account_id
+}
+
+// ToDomain maps SettleTransactionRequest to domain.TransactionPayment.
+func (req *SettleTransactionRequest) ToDomain() domain.TransactionPayment {
+	return domain.TransactionPayment{
+		AccountID:   req.AccountID,
+		Amount:      req.Amount,
+		PaymentDate: req.PaymentDate,
+	}
+}
+
+// BulkSettleTransactionsRequest represents the payload for settling several transactions in full.
+type BulkSettleTransactionsRequest struct {
+	TransactionIDs []string  `json:"transaction_ids" binding:"required,min=1,max=100,dive,uuid"`
+	PaymentDate    time.Time `json:"payment_date" binding:"required"`
+	AccountID      string    `json:"account_id,omitempty" binding:"omitempty,uuid"` // Defaults to each transaction's from_account_id
+}
+
+// TransactionPaymentResponse represents the API response for a payment of a transaction.
+type TransactionPaymentResponse struct {
+	ID            string       `json:"id"`
+	TransactionID string       `json:"transaction_id"`
+	AccountID     string       `json:"account_id"`
+	Amount        domain.Money `json:"amount" swaggertype:"string" example:"50.00"`
+	PaymentDate   time.Time    `json:"payment_date"`
+	CreatedAt     time.Time    `json:"created_at"`
+	CreatedBy     string       `json:"created_by"`
+}
+
+// MapTransactionPaymentToResponse maps domain.TransactionPayment to TransactionPaymentResponse.
+func MapTransactionPaymentToResponse(p *domain.TransactionPayment) TransactionPaymentResponse {
+	return TransactionPaymentResponse{
+		ID:            p.ID,
+		TransactionID: p.TransactionID,
+		AccountID:     p.AccountID,
+		Amount:        p.Amount,
+		PaymentDate:   p.PaymentDate,
+		CreatedAt:     p.CreatedAt,
+		CreatedBy:     p.CreatedBy,
 	}
 }
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// SeriesScopeQuery selects the installments a series change applies to. It defaults to this.
+type SeriesScopeQuery struct {
+	Scope domain.SeriesScope `form:"scope" binding:"omitempty,oneof=this following all"`
+}
+
+// ToDomain returns the selected scope, applying the default.
+func (q *SeriesScopeQuery) ToDomain() domain.SeriesScope {
+	if q.Scope == "" {
+		return domain.SeriesScopeThis
+	}
+	return q.Scope
+}
+
+// UpdateSeriesRequest represents the payload for changing the category and tags of installments.
+// Omitted fields are left unchanged; an empty tag_ids removes all tags.
+type UpdateSeriesRequest struct {
+	CategoryID *string  `json:"category_id,omitempty" binding:"omitempty,uuid"`
+	TagIDs     []string `json:"tag_ids,omitempty" binding:"omitempty,dive,uuid"`
+}
+
+// RescheduleSeriesRequest represents the payload for moving the unpaid installments of a series.
+type RescheduleSeriesRequest struct {
+	FirstDueDate time.Time `json:"first_due_date" binding:"required"` // Due date of the first unpaid installment; the others follow monthly
+}
+
+// PayOffSeriesRequest represents the payload for settling a series early.
+type PayOffSeriesRequest struct {
+	PaymentDate time.Time    `json:"payment_date" binding:"required"`
+	Discount    domain.Money `json:"discount" swaggertype:"string" example:"10.00"` // Optional early payment discount
+}
+
+// TransactionSeriesResponse represents the API response for an installment series.
+type TransactionSeriesResponse struct {
+	ParentTransactionID string                `json:"parent_transaction_id"`
+	Total               domain.Money          `json:"total" swaggertype:"string" example:"300.00"`
+	Paid                domain.Money          `json:"paid" swaggertype:"string" example:"100.00"`
+	Remaining           domain.Money          `json:"remaining" swaggertype:"string" example:"200.00"`
+	Installments        []TransactionResponse `json:"installments"`
+}
+
+// MapTransactionSeriesToResponse maps domain.TransactionSeries to TransactionSeriesResponse.
+func MapTransactionSeriesToResponse(s *domain.TransactionSeries, expandTags bool) TransactionSeriesResponse {
+	total, paid, remaining := s.Totals()
+	resp := TransactionSeriesResponse{
+		ParentTransactionID: s.ParentID,
+		Total:               total,
+		Paid:                paid,
+		Remaining:           remaining,
+		Installments:        make([]TransactionResponse, 0, len(s.Installments)),
+	}
+	for i := range s.Installments {
+		resp.Installments = append(resp.Installments, FromTransactionDomain(&s.Installments[i], expandTags))
+	}
+	return resp
+}
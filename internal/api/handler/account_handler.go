@@ -24,9 +24,11 @@ func NewAccountHandler(s *service.AccountService) *AccountHandler {
 // @Accept  json
 // @Produce  json
 // @Param id path string true "Account ID"
+// @Param If-None-Match header string false "ETag of the cached version"
 // @Security AuthPassword
 // @Success 200 {object} dto.AccountResponse
-// @Failure 404 {object} handler.ErrorResponse
+// @Success 304 "Not Modified"
+// @Failure 404 {object} dto.ProblemResponse
 // @Router /accounts/{id} [get]
 func (h *AccountHandler) Get(c *gin.Context) {
 	id := c.Param("id")
@@ -37,36 +39,101 @@ func (h *AccountHandler) Get(c *gin.Context) {
 
 	acc, err := h.service.GetAccount(c.Request.Context(), id)
 	if err != nil {
-		ErrorJSON(c, http.StatusNotFound, "Account not found")
+		abortWithError(c, err, "Failed to get account")
+		return
+	}
+	if notModified(c, acc.Version) {
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.MapAccountToResponse(acc))
 }
 
+// GetBalance godoc
+// @Summary Get an account balance
+// @Description get the current, cleared and projected balance of an account, optionally as of a past date
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Account ID"
+// @Param as_of query string false "As-of date (YYYY-MM-DD), defaults to today"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Security AuthPassword
+// @Success 200 {object} dto.BalanceResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Router /accounts/{id}/balance [get]
+func (h *AccountHandler) GetBalance(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		ErrorJSON(c, http.StatusBadRequest, "Account ID is required")
+		return
+	}
+
+	var query dto.BalanceQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	balance, err := h.service.GetBalance(c.Request.Context(), id, query.AsOfDate())
+	if err != nil {
+		abortWithError(c, err, "Failed to get account balance")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapBalanceToResponse(balance))
+}
+
 // List godoc
 // @Summary List accounts
-// @Description list accounts by tenant ID
+// @Description list accounts by tenant ID, including their balances
 // @Tags accounts
 // @Accept  json
 // @Produce  json
+// @Param as_of query string false "As-of date for balances (YYYY-MM-DD), defaults to today"
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param include_total query bool false "Include the total number of items"
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Security AuthPassword
-// @Success 200 {array} dto.AccountResponse
-// @Failure 400 {object} handler.ErrorResponse
+// @Success 200 {object} dto.PageResponse{items=[]dto.AccountResponse}
+// @Failure 400 {object} dto.ProblemResponse
 // @Router /accounts [get]
 func (h *AccountHandler) List(c *gin.Context) {
-	accounts, err := h.service.ListAccounts(c.Request.Context())
+	var query dto.BalanceQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.service.ListAccounts(c.Request.Context(), page)
 	if err != nil {
-		ErrorJSON(c, http.StatusInternalServerError, "Failed to list accounts")
+		abortWithError(c, err, "Failed to list accounts")
 		return
 	}
 
-	var resp []*dto.AccountResponse
-	for _, a := range accounts {
-		resp = append(resp, dto.MapAccountToResponse(&a))
+	ids := make([]string, len(accounts.Items))
+	for i := range accounts.Items {
+		ids[i] = accounts.Items[i].ID
+	}
+	balances, err := h.service.ListBalances(c.Request.Context(), ids, query.AsOfDate())
+	if err != nil {
+		abortWithError(c, err, "Failed to compute account balances")
+		return
 	}
 
+	resp := dto.NewPageResponse(accounts, func(a *domain.Account) *dto.AccountResponse {
+		accResp := dto.MapAccountToResponse(a)
+		if b, ok := balances[a.ID]; ok {
+			accResp.Balance = dto.MapBalanceToResponse(&b)
+		}
+		return accResp
+	})
+
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -78,14 +145,15 @@ func (h *AccountHandler) List(c *gin.Context) {
 // @Produce  json
 // @Param account body dto.CreateAccountRequest true "Create account"
 // @Param X-Tenant-ID header string true "Tenant ID"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
 // @Security AuthPassword
 // @Success 201 {object} dto.AccountResponse
-// @Failure 400 {object} handler.ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
 // @Router /accounts [post]
 func (h *AccountHandler) Create(c *gin.Context) {
 	var req dto.CreateAccountRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		ErrorJSON(c, http.StatusBadRequest, "Invalid request payload")
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -99,7 +167,7 @@ func (h *AccountHandler) Create(c *gin.Context) {
 	acc := req.ToEntity(userId, tenantID)
 
 	if err := h.service.CreateAccount(c.Request.Context(), acc); err != nil {
-		ErrorJSON(c, http.StatusInternalServerError, "Failed to create account")
+		abortWithError(c, err, "Failed to create account")
 		return
 	}
 
@@ -115,10 +183,12 @@ func (h *AccountHandler) Create(c *gin.Context) {
 // @Param id path string true "Account ID"
 // @Param account body dto.UpdateAccountRequest true "Update account"
 // @Param X-Tenant-ID header string true "Tenant ID"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Security AuthPassword
 // @Success 200 {object} dto.AccountResponse
-// @Failure 400 {object} handler.ErrorResponse
-// @Failure 404 {object} handler.ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
 // @Router /accounts/{id} [put]
 func (h *AccountHandler) Update(c *gin.Context) {
 	id := c.Param("id")
@@ -128,8 +198,7 @@ func (h *AccountHandler) Update(c *gin.Context) {
 	}
 
 	var req dto.UpdateAccountRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		ErrorJSON(c, http.StatusBadRequest, "Invalid request payload")
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -139,13 +208,63 @@ func (h *AccountHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	acc := req.ToEntity(id, userId)
+	acc.Version = version
 
 	if err := h.service.UpdateAccount(c.Request.Context(), acc); err != nil {
-		ErrorJSON(c, http.StatusInternalServerError, "Failed to update account")
+		abortWithError(c, err, "Failed to update account")
 		return
 	}
 
+	setETag(c, acc.Version)
+	c.JSON(http.StatusOK, dto.MapAccountToResponse(acc))
+}
+
+// Patch godoc
+// @Summary Patch an account
+// @Description apply a JSON Merge Patch (RFC 7396) to an account: only the fields sent are changed. Currency and type cannot be changed.
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Account ID"
+// @Param account body dto.PatchAccountRequest true "Fields to change"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Security AuthPassword
+// @Success 200 {object} dto.AccountResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Router /accounts/{id} [patch]
+func (h *AccountHandler) Patch(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		ErrorJSON(c, http.StatusBadRequest, "Account ID is required")
+		return
+	}
+
+	var req dto.PatchAccountRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	acc, err := h.service.PatchAccount(c.Request.Context(), id, req.ToDomain(), version)
+	if err != nil {
+		abortWithError(c, err, "Failed to update account")
+		return
+	}
+
+	setETag(c, acc.Version)
 	c.JSON(http.StatusOK, dto.MapAccountToResponse(acc))
 }
 
@@ -159,8 +278,8 @@ func (h *AccountHandler) Update(c *gin.Context) {
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Security AuthPassword
 // @Success 204 "No Content"
-// @Failure 400 {object} handler.ErrorResponse
-// @Failure 404 {object} handler.ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
 // @Router /accounts/{id} [delete]
 func (h *AccountHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
@@ -172,7 +291,99 @@ func (h *AccountHandler) Delete(c *gin.Context) {
 	userId := domain.GetUserID(c.Request.Context())
 
 	if err := h.service.DeleteAccount(c.Request.Context(), id, userId); err != nil {
-		ErrorJSON(c, http.StatusInternalServerError, "Failed to delete account")
+		abortWithError(c, err, "Failed to delete account")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetCreditCard godoc
+// @Summary Get credit card details
+// @Description get the current card details (closing/due days, brand, last four digits) of a credit card account
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Account ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Security AuthPassword
+// @Success 200 {object} dto.CreditCardResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Router /accounts/{id}/credit-card [get]
+func (h *AccountHandler) GetCreditCard(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		ErrorJSON(c, http.StatusBadRequest, "Account ID is required")
+		return
+	}
+
+	card, err := h.service.GetCreditCard(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err, "Failed to get credit card details")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapCreditCardToResponse(card))
+}
+
+// PutCreditCard godoc
+// @Summary Set credit card details
+// @Description set the card details of a credit card account; previous details are kept as history
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Account ID"
+// @Param card body dto.CreditCardRequest true "Credit card details"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Security AuthPassword
+// @Success 200 {object} dto.CreditCardResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Router /accounts/{id}/credit-card [put]
+func (h *AccountHandler) PutCreditCard(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		ErrorJSON(c, http.StatusBadRequest, "Account ID is required")
+		return
+	}
+
+	var req dto.CreditCardRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	card := req.ToEntity(id)
+	if err := h.service.SetCreditCard(c.Request.Context(), card); err != nil {
+		abortWithError(c, err, "Failed to set credit card details")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapCreditCardToResponse(card))
+}
+
+// DeleteCreditCard godoc
+// @Summary Delete credit card details
+// @Description deactivate the current card details of a credit card account
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Account ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Security AuthPassword
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Router /accounts/{id}/credit-card [delete]
+func (h *AccountHandler) DeleteCreditCard(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		ErrorJSON(c, http.StatusBadRequest, "Account ID is required")
+		return
+	}
+
+	if err := h.service.DeleteCreditCard(c.Request.Context(), id); err != nil {
+		abortWithError(c, err, "Failed to delete credit card details")
 		return
 	}
 
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+	"github.com/igoventura/fintrack-api/internal/jobs"
+)
+
+type AdminHandler struct {
+	scheduler *jobs.Scheduler
+}
+
+func NewAdminHandler(scheduler *jobs.Scheduler) *AdminHandler {
+	return &AdminHandler{scheduler: scheduler}
+}
+
+// ListJobs returns the status of the background jobs.
+// @Summary List background jobs
+// @Description Lists the background jobs with their schedule, next run on this instance and latest run on any instance. Restricted to the operators listed in ADMIN_USER_IDS.
+// @Tags admin
+// @Produce json
+// @Security AuthPassword
+// @Success 200 {array} dto.JobStatusResponse
+// @Failure 403 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /admin/jobs [get]
+func (h *AdminHandler) ListJobs(c *gin.Context) {
+	statuses, err := h.scheduler.Status(c.Request.Context())
+	if err != nil {
+		abortWithError(c, err, "Failed to list jobs")
+		return
+	}
+
+	resp := make([]dto.JobStatusResponse, 0, len(statuses))
+	for i := range statuses {
+		resp = append(resp, dto.MapJobStatusToResponse(&statuses[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -23,14 +23,12 @@ func NewAuthHandler(service service.AuthService) *AuthHandler {
 // @Accept  json
 // @Produce  json
 // @Param request body dto.RegisterRequest true "Register User"
-// @Param X-Tenant-ID header string false "Tenant ID"
 // @Success 201 {object} dto.AuthResponse
-// @Failure 400 {object} handler.ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req dto.RegisterRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		ErrorJSON(c, http.StatusBadRequest, "Invalid request payload")
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -52,7 +50,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Param username formData string true "Username"
 // @Param password formData string true "Password"
 // @Success 200 {object} dto.AuthResponse
-// @Failure 400 {object} handler.ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	username := c.Request.FormValue("username")
@@ -80,13 +78,12 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Produce  json
 // @Param request body dto.RefreshTokenRequest true "Refresh Token Request"
 // @Success 200 {object} dto.AuthResponse
-// @Failure 400 {object} handler.ErrorResponse
-// @Failure 401 {object} handler.ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 401 {object} dto.ProblemResponse
 // @Router /auth/refresh-token [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req dto.RefreshTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		ErrorJSON(c, http.StatusBadRequest, "Invalid request payload")
+	if !bindJSON(c, &req) {
 		return
 	}
 
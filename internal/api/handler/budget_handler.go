@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+	"github.com/igoventura/fintrack-api/internal/service"
+)
+
+type BudgetHandler struct {
+	service *service.BudgetService
+}
+
+func NewBudgetHandler(service *service.BudgetService) *BudgetHandler {
+	return &BudgetHandler{service: service}
+}
+
+// Create handles the creation of a new budget.
+// @Summary Create a budget
+// @Description Creates a budget for an expense category (subcategories included) or a tag, for one accrual month or every month from it on.
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param budget body dto.BudgetRequest true "Budget data"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
+// @Success 201 {object} dto.BudgetResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /budgets [post]
+func (h *BudgetHandler) Create(c *gin.Context) {
+	var req dto.BudgetRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	budget := req.ToDomain()
+	if err := h.service.CreateBudget(c.Request.Context(), budget); err != nil {
+		abortWithError(c, err, "Failed to create budget")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapBudgetToResponse(budget))
+}
+
+// List returns the budgets of the tenant.
+// @Summary List budgets
+// @Description Lists the active budgets of the tenant, ordered by (first) month.
+// @Tags budgets
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param include_total query bool false "Include the total number of items"
+// @Success 200 {object} dto.PageResponse{items=[]dto.BudgetResponse}
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /budgets [get]
+func (h *BudgetHandler) List(c *gin.Context) {
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	budgets, err := h.service.ListBudgets(c.Request.Context(), page)
+	if err != nil {
+		abortWithError(c, err, "Failed to list budgets")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPageResponse(budgets, dto.MapBudgetToResponse))
+}
+
+// Status returns the progress of the budgets of a month.
+// @Summary Get budget status
+// @Description Planned amount, actual spend, remaining amount and percentage used of every budget covering the month, from the transactions of that accrual month. Spend is debits minus refunds in expense categories, in the budget currency; with rollover, the balance of earlier months is carried over.
+// @Tags budgets
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param month query string false "Accrual month (YYYYMM), defaults to the current month"
+// @Success 200 {array} dto.BudgetStatusResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /budgets/status [get]
+func (h *BudgetHandler) Status(c *gin.Context) {
+	var query dto.BudgetStatusQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	statuses, err := h.service.Status(c.Request.Context(), query.Month)
+	if err != nil {
+		abortWithError(c, err, "Failed to get budget status")
+		return
+	}
+
+	resp := make([]dto.BudgetStatusResponse, 0, len(statuses))
+	for i := range statuses {
+		resp = append(resp, dto.MapBudgetStatusToResponse(&statuses[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetByID returns a budget by ID.
+// @Summary Get budget by ID
+// @Description Retrieves a budget.
+// @Tags budgets
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Budget ID"
+// @Param If-None-Match header string false "ETag of the cached version"
+// @Success 200 {object} dto.BudgetResponse
+// @Success 304 "Not Modified"
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /budgets/{id} [get]
+func (h *BudgetHandler) GetByID(c *gin.Context) {
+	budget, err := h.service.GetBudget(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, err, "Failed to get budget")
+		return
+	}
+	if notModified(c, budget.Version) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapBudgetToResponse(budget))
+}
+
+// Update replaces a budget.
+// @Summary Update a budget
+// @Description Replaces a budget. Its status is always computed from the current budget, earlier months included.
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Budget ID"
+// @Param budget body dto.BudgetRequest true "Budget data"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 200 {object} dto.BudgetResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /budgets/{id} [put]
+func (h *BudgetHandler) Update(c *gin.Context) {
+	var req dto.BudgetRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	budget := req.ToDomain()
+	budget.ID = c.Param("id")
+	budget.Version = version
+	updated, err := h.service.UpdateBudget(c.Request.Context(), budget)
+	if err != nil {
+		abortWithError(c, err, "Failed to update budget")
+		return
+	}
+
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, dto.MapBudgetToResponse(updated))
+}
+
+// Delete deactivates a budget.
+// @Summary Delete a budget
+// @Description Soft-deletes a budget.
+// @Tags budgets
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Budget ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /budgets/{id} [delete]
+func (h *BudgetHandler) Delete(c *gin.Context) {
+	if err := h.service.DeleteBudget(c.Request.Context(), c.Param("id")); err != nil {
+		abortWithError(c, err, "Failed to delete budget")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
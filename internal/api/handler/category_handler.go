@@ -26,14 +26,15 @@ func NewCategoryHandler(service *service.CategoryService) *CategoryHandler {
 // @Security AuthPassword
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param request body dto.CreateCategoryRequest true "Create Category Request"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
 // @Success 201 {object} dto.CategoryResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /categories [post]
 func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	var req dto.CreateCategoryRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -49,7 +50,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	}
 
 	if err := h.service.CreateCategory(c.Request.Context(), category); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to create category")
 		return
 	}
 
@@ -65,6 +66,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 		CreatedBy:        category.CreatedBy,
 		UpdatedAt:        category.UpdatedAt,
 		UpdatedBy:        category.UpdatedBy,
+		Version:          category.Version,
 	})
 }
 
@@ -76,14 +78,20 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 // @Security AuthPassword
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param id path string true "Category ID"
+// @Param If-None-Match header string false "ETag of the cached version"
 // @Success 200 {object} dto.CategoryResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 304 "Not Modified"
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /categories/{id} [get]
 func (h *CategoryHandler) GetCategory(c *gin.Context) {
 	id := c.Param("id")
 	category, err := h.service.GetCategory(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to get category")
+		return
+	}
+	if notModified(c, category.Version) {
 		return
 	}
 
@@ -99,6 +107,7 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 		CreatedBy:        category.CreatedBy,
 		UpdatedAt:        category.UpdatedAt,
 		UpdatedBy:        category.UpdatedBy,
+		Version:          category.Version,
 	})
 }
 
@@ -108,20 +117,28 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 // @Tags categories
 // @Produce json
 // @Security AuthPassword
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param include_total query bool false "Include the total number of items"
 // @Param X-Tenant-ID header string true "Tenant ID"
-// @Success 200 {object} []dto.CategoryResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 200 {object} dto.PageResponse{items=[]dto.CategoryResponse}
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /categories [get]
 func (h *CategoryHandler) ListCategories(c *gin.Context) {
-	categories, err := h.service.ListCategories(c.Request.Context())
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	categories, err := h.service.ListCategories(c.Request.Context(), page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to list categories")
 		return
 	}
 
-	var resp []*dto.CategoryResponse
-	for _, category := range categories {
-		resp = append(resp, &dto.CategoryResponse{
+	resp := dto.NewPageResponse(categories, func(category *domain.Category) *dto.CategoryResponse {
+		return &dto.CategoryResponse{
 			ID:               category.ID,
 			ParentCategoryID: category.ParentCategoryID,
 			TenantID:         category.TenantID,
@@ -133,12 +150,9 @@ func (h *CategoryHandler) ListCategories(c *gin.Context) {
 			CreatedBy:        category.CreatedBy,
 			UpdatedAt:        category.UpdatedAt,
 			UpdatedBy:        category.UpdatedBy,
-		})
-	}
-
-	if resp == nil {
-		resp = []*dto.CategoryResponse{}
-	}
+			Version:          category.Version,
+		}
+	})
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -152,15 +166,22 @@ func (h *CategoryHandler) ListCategories(c *gin.Context) {
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param id path string true "Category ID"
 // @Param request body dto.UpdateCategoryRequest true "Update Category Request"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Success 200 {object} dto.CategoryResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /categories/{id} [put]
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	id := c.Param("id")
 	var req dto.UpdateCategoryRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
@@ -172,19 +193,21 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		Color:            req.Color,
 		Icon:             req.Icon,
 		UpdatedBy:        userID,
+		Version:          version,
 	}
 
 	if err := h.service.UpdateCategory(c.Request.Context(), category); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to update category")
 		return
 	}
 
 	updatedCategory, err := h.service.GetCategory(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to get category")
 		return
 	}
 
+	setETag(c, updatedCategory.Version)
 	c.JSON(http.StatusOK, dto.CategoryResponse{
 		ID:               updatedCategory.ID,
 		ParentCategoryID: updatedCategory.ParentCategoryID,
@@ -197,6 +220,59 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		CreatedBy:        updatedCategory.CreatedBy,
 		UpdatedAt:        updatedCategory.UpdatedAt,
 		UpdatedBy:        updatedCategory.UpdatedBy,
+		Version:          updatedCategory.Version,
+	})
+}
+
+// PatchCategory partially updates a category
+// @Summary Patch category
+// @Description Apply a JSON Merge Patch (RFC 7396) to a category: only the fields sent are changed, and null clears the parent. The type cannot be changed.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Category ID"
+// @Param request body dto.PatchCategoryRequest true "Fields to change"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /categories/{id} [patch]
+func (h *CategoryHandler) PatchCategory(c *gin.Context) {
+	id := c.Param("id")
+	var req dto.PatchCategoryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.service.PatchCategory(c.Request.Context(), id, req.ToDomain(), version)
+	if err != nil {
+		abortWithError(c, err, "Failed to update category")
+		return
+	}
+
+	setETag(c, category.Version)
+	c.JSON(http.StatusOK, dto.CategoryResponse{
+		ID:               category.ID,
+		ParentCategoryID: category.ParentCategoryID,
+		TenantID:         category.TenantID,
+		Name:             category.Name,
+		Type:             string(category.Type),
+		Color:            category.Color,
+		Icon:             category.Icon,
+		CreatedAt:        category.CreatedAt,
+		CreatedBy:        category.CreatedBy,
+		UpdatedAt:        category.UpdatedAt,
+		UpdatedBy:        category.UpdatedBy,
+		Version:          category.Version,
 	})
 }
 
@@ -209,14 +285,15 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param id path string true "Category ID"
 // @Success 204 "No Content"
-// @Failure 500 {object} ErrorResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /categories/{id} [delete]
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	id := c.Param("id")
 	userID := domain.GetUserID(c.Request.Context())
 
 	if err := h.service.DeleteCategory(c.Request.Context(), id, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to delete category")
 		return
 	}
 
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The ETag of a versioned resource (account, category, tag, transaction) is its version, e.g. "3".
+// GET answers If-None-Match with 304 Not Modified, and PUT only applies when If-Match holds the
+// current version (412 Precondition Failed otherwise).
+
+func formatETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// setETag sets the ETag header of a resource version.
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", formatETag(version))
+}
+
+// notModified sets the ETag header and reports whether If-None-Match matches it, in which case
+// a 304 Not Modified has been sent.
+func notModified(c *gin.Context, version int) bool {
+	setETag(c, version)
+	etag := formatETag(version)
+	for _, tag := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		// If-None-Match uses the weak comparison: W/"3" matches "3"
+		if tag = strings.TrimSpace(tag); tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion returns the version required by the If-Match header, or 0 when the header is absent
+// or "*". It aborts with 412 when the header does not hold a single version, which cannot match.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	value, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		value, ok = strings.CutSuffix(value, `"`)
+	}
+	version, err := strconv.Atoi(value)
+	if !ok || err != nil || version < 1 {
+		ErrorJSON(c, http.StatusPreconditionFailed, "If-Match must hold the ETag of the resource")
+		return 0, false
+	}
+	return version, true
+}
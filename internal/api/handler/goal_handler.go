@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+	"github.com/igoventura/fintrack-api/internal/service"
+)
+
+type GoalHandler struct {
+	service *service.GoalService
+}
+
+func NewGoalHandler(service *service.GoalService) *GoalHandler {
+	return &GoalHandler{service: service}
+}
+
+// Create handles the creation of a new savings goal.
+// @Summary Create a goal
+// @Description Creates a savings goal tracked through linked accounts, all in the goal currency, or through a tag.
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param goal body dto.GoalRequest true "Goal data"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
+// @Success 201 {object} dto.GoalResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /goals [post]
+func (h *GoalHandler) Create(c *gin.Context) {
+	var req dto.GoalRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	goal := req.ToDomain()
+	if err := h.service.CreateGoal(c.Request.Context(), goal); err != nil {
+		abortWithError(c, err, "Failed to create goal")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapGoalToResponse(goal))
+}
+
+// List returns the savings goals of the tenant.
+// @Summary List goals
+// @Description Lists the active savings goals of the tenant, ordered by name.
+// @Tags goals
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param include_total query bool false "Include the total number of items"
+// @Success 200 {object} dto.PageResponse{items=[]dto.GoalResponse}
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /goals [get]
+func (h *GoalHandler) List(c *gin.Context) {
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	goals, err := h.service.ListGoals(c.Request.Context(), page)
+	if err != nil {
+		abortWithError(c, err, "Failed to list goals")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPageResponse(goals, dto.MapGoalToResponse))
+}
+
+// GetByID returns a savings goal by ID.
+// @Summary Get goal by ID
+// @Description Retrieves a savings goal.
+// @Tags goals
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Goal ID"
+// @Param If-None-Match header string false "ETag of the cached version"
+// @Success 200 {object} dto.GoalResponse
+// @Success 304 "Not Modified"
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /goals/{id} [get]
+func (h *GoalHandler) GetByID(c *gin.Context) {
+	goal, err := h.service.GetGoal(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, err, "Failed to get goal")
+		return
+	}
+	if notModified(c, goal.Version) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapGoalToResponse(goal))
+}
+
+// Progress returns the progress of a savings goal.
+// @Summary Get goal progress
+// @Description Amount saved, remaining amount and percentage of the target reached today, with the average monthly contribution of the last 6 complete months and the projected completion date. The amount saved is the current balance of the linked accounts (transfers into them count automatically) or the net of the paid transactions with the goal tag.
+// @Tags goals
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Goal ID"
+// @Success 200 {object} dto.GoalProgressResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /goals/{id}/progress [get]
+func (h *GoalHandler) Progress(c *gin.Context) {
+	progress, err := h.service.Progress(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, err, "Failed to get goal progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapGoalProgressToResponse(progress))
+}
+
+// Update replaces a savings goal.
+// @Summary Update a goal
+// @Description Replaces a savings goal and its linked accounts.
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Goal ID"
+// @Param goal body dto.GoalRequest true "Goal data"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 200 {object} dto.GoalResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /goals/{id} [put]
+func (h *GoalHandler) Update(c *gin.Context) {
+	var req dto.GoalRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	goal := req.ToDomain()
+	goal.ID = c.Param("id")
+	goal.Version = version
+	updated, err := h.service.UpdateGoal(c.Request.Context(), goal)
+	if err != nil {
+		abortWithError(c, err, "Failed to update goal")
+		return
+	}
+
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, dto.MapGoalToResponse(updated))
+}
+
+// Delete deactivates a savings goal.
+// @Summary Delete a goal
+// @Description Soft-deletes a savings goal. Its accounts and transactions are kept.
+// @Tags goals
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Goal ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /goals/{id} [delete]
+func (h *GoalHandler) Delete(c *gin.Context) {
+	if err := h.service.DeleteGoal(c.Request.Context(), c.Param("id")); err != nil {
+		abortWithError(c, err, "Failed to delete goal")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessPingTimeout bounds the database check of the readiness probe.
+const readinessPingTimeout = 2 * time.Second
+
+// Pinger checks a dependency the API cannot serve without, such as the database.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthHandler serves the liveness and readiness probes.
+type HealthHandler struct {
+	db       Pinger
+	draining atomic.Bool
+}
+
+func NewHealthHandler(db Pinger) *HealthHandler {
+	return &HealthHandler{db: db}
+}
+
+// SetDraining makes the readiness probe fail, so the load balancer stops routing new requests
+// while the ones in flight complete.
+func (h *HealthHandler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Live reports that the process is up. It does not check dependencies, so a database outage
+// does not get the instance restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.String(http.StatusOK, "ok")
+}
+
+// Ready reports whether the instance can take traffic: it is not shutting down and the database answers.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		c.String(http.StatusServiceUnavailable, "draining")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessPingTimeout)
+	defer cancel()
+	if err := h.db.Ping(ctx); err != nil {
+		c.String(http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	c.String(http.StatusOK, "ok")
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+	"github.com/igoventura/fintrack-api/internal/service"
+)
+
+// maxImportFileSize is the size of the largest statement that can be uploaded.
+const maxImportFileSize = 5 << 20
+
+type ImportHandler struct {
+	service *service.ImportService
+}
+
+func NewImportHandler(service *service.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// CreateProfile handles the creation of a new import profile.
+// @Summary Create an import profile
+// @Description Creates a profile describing how the CSV statements of a bank are read: delimiter, header, lines to skip, date format, decimal separator, sign convention and the column of each field.
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param profile body dto.ImportProfileRequest true "Import profile data"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
+// @Success 201 {object} dto.ImportProfileResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /imports/profiles [post]
+func (h *ImportHandler) CreateProfile(c *gin.Context) {
+	var req dto.ImportProfileRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	profile := req.ToDomain()
+	if err := h.service.CreateProfile(c.Request.Context(), profile); err != nil {
+		abortWithError(c, err, "Failed to create import profile")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapImportProfileToResponse(profile))
+}
+
+// ListProfiles returns the import profiles of the tenant.
+// @Summary List import profiles
+// @Description Lists the active import profiles of the tenant, ordered by name.
+// @Tags imports
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param include_total query bool false "Include the total number of items"
+// @Success 200 {object} dto.PageResponse{items=[]dto.ImportProfileResponse}
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /imports/profiles [get]
+func (h *ImportHandler) ListProfiles(c *gin.Context) {
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	profiles, err := h.service.ListProfiles(c.Request.Context(), page)
+	if err != nil {
+		abortWithError(c, err, "Failed to list import profiles")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPageResponse(profiles, dto.MapImportProfileToResponse))
+}
+
+// GetProfile returns an import profile by ID.
+// @Summary Get import profile by ID
+// @Description Retrieves an import profile.
+// @Tags imports
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Import profile ID"
+// @Param If-None-Match header string false "ETag of the cached version"
+// @Success 200 {object} dto.ImportProfileResponse
+// @Success 304 "Not Modified"
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /imports/profiles/{id} [get]
+func (h *ImportHandler) GetProfile(c *gin.Context) {
+	profile, err := h.service.GetProfile(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, err, "Failed to get import profile")
+		return
+	}
+	if notModified(c, profile.Version) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapImportProfileToResponse(profile))
+}
+
+// UpdateProfile replaces an import profile.
+// @Summary Update an import profile
+// @Description Replaces an import profile. Staged imports keep the rows already parsed.
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Import profile ID"
+// @Param profile body dto.ImportProfileRequest true "Import profile data"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 200 {object} dto.ImportProfileResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /imports/profiles/{id} [put]
+func (h *ImportHandler) UpdateProfile(c *gin.Context) {
+	var req dto.ImportProfileRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	profile := req.ToDomain()
+	profile.ID = c.Param("id")
+	profile.Version = version
+	updated, err := h.service.UpdateProfile(c.Request.Context(), profile)
+	if err != nil {
+		abortWithError(c, err, "Failed to update import profile")
+		return
+	}
+
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, dto.MapImportProfileToResponse(updated))
+}
+
+// DeleteProfile deactivates an import profile.
+// @Summary Delete an import profile
+// @Description Soft-deletes an import profile. The imports made with it are kept.
+// @Tags imports
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Import profile ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /imports/profiles/{id} [delete]
+func (h *ImportHandler) DeleteProfile(c *gin.Context) {
+	if err := h.service.DeleteProfile(c.Request.Context(), c.Param("id")); err != nil {
+		abortWithError(c, err, "Failed to delete import profile")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Create stages a CSV or OFX statement for review.
+// @Summary Import a CSV or OFX statement
+// @Description Parses a statement (up to 5 MB and 5000 rows) of an account and stages its rows for review. CSV files are read with an import profile; OFX 1.x (SGML) and 2.x (XML) files are recognized by their content and need none. Each row has its parsed date, description, amount and type, or the error that kept it from being read, along with an existing transaction of the account it may duplicate (same amount and direction within 3 days) and the category most used for the same description. OFX transactions whose FITID was already imported into the account are left out and counted as already_imported; a file with nothing new is rejected with 409. The ledger balance of an OFX statement is reported next to the cleared balance of the account on the same date. Nothing is created until the import is committed.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param account_id formData string true "Account the statement belongs to"
+// @Param profile_id formData string false "Import profile to read the file with (CSV only)"
+// @Param file formData file true "CSV or OFX statement"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
+// @Success 201 {object} dto.ImportResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /imports [post]
+func (h *ImportHandler) Create(c *gin.Context) {
+	var req dto.ImportRequest
+	if !bindForm(c, &req) {
+		return
+	}
+	if req.File.Size > maxImportFileSize {
+		abortWithError(c, domain.InvalidField("file", fmt.Sprintf("file must not exceed %d MB", maxImportFileSize>>20)), "")
+		return
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		abortWithError(c, err, "Failed to read the uploaded file")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		abortWithError(c, err, "Failed to read the uploaded file")
+		return
+	}
+
+	imp, err := h.service.Stage(c.Request.Context(), req.AccountID, req.ProfileID, req.File.Filename, data)
+	if err != nil {
+		abortWithError(c, err, "Failed to import statement")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapImportToResponse(imp))
+}
+
+// GetByID returns an import with its rows.
+// @Summary Get import by ID
+// @Description Retrieves an import with its rows, ordered by line, and for OFX files the cleared balance of the account on the date of the statement's ledger balance.
+// @Tags imports
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Import ID"
+// @Success 200 {object} dto.ImportResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /imports/{id} [get]
+func (h *ImportHandler) GetByID(c *gin.Context) {
+	imp, err := h.service.GetImport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, err, "Failed to get import")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapImportToResponse(imp))
+}
+
+// Commit turns the selected rows of a staged import into transactions.
+// @Summary Commit an import
+// @Description Creates a paid transaction on the import account for each selected row, dated and typed as parsed, with the row description as comments and the given category or else the suggested one. The transactions are created like POST /transactions, all in one database transaction: if any fails, none is created and the import stays staged. Rows whose FITID was imported into the account since the import was staged are rejected. Rows left out are discarded.
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Import ID"
+// @Param rows body dto.CommitImportRequest true "Rows to commit"
+// @Success 200 {object} dto.ImportResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /imports/{id}/commit [post]
+func (h *ImportHandler) Commit(c *gin.Context) {
+	var req dto.CommitImportRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	imp, err := h.service.Commit(c.Request.Context(), c.Param("id"), req.ToDomain())
+	if err != nil {
+		abortWithError(c, err, "Failed to commit import")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapImportToResponse(imp))
+}
+
+// Delete discards a staged import.
+// @Summary Discard an import
+// @Description Soft-deletes a staged import. Committed imports cannot be discarded.
+// @Tags imports
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Import ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /imports/{id} [delete]
+func (h *ImportHandler) Delete(c *gin.Context) {
+	if err := h.service.DiscardImport(c.Request.Context(), c.Param("id")); err != nil {
+		abortWithError(c, err, "Failed to discard import")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
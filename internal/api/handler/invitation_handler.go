@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+	"github.com/igoventura/fintrack-api/internal/service"
+)
+
+type InvitationHandler struct {
+	service *service.InvitationService
+}
+
+func NewInvitationHandler(service *service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{service: service}
+}
+
+// Create handles inviting an email address to a tenant.
+// @Summary Invite someone to a tenant
+// @Description Create an invitation for an email address to join a tenant with a role. Requires the admin role; only owners can invite owners.
+// @Description The token is only returned in this response and expires after 7 days.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body dto.CreateInvitationRequest true "Invitation"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
+// @Security AuthPassword
+// @Success 201 {object} dto.InvitationResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 403 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /tenants/{id}/invitations [post]
+func (h *InvitationHandler) Create(c *gin.Context) {
+	var req dto.CreateInvitationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	inv, token, err := h.service.Create(c.Request.Context(), req.Email, req.Role)
+	if err != nil {
+		abortWithError(c, err, "Failed to create invitation")
+		return
+	}
+
+	resp := dto.MapInvitationToResponse(inv)
+	resp.Token = token
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListPending handles listing the pending invitations of a tenant.
+// @Summary List pending invitations
+// @Description List the invitations of a tenant that can still be accepted. Requires the admin role.
+// @Tags invitations
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Security AuthPassword
+// @Success 200 {array} dto.InvitationResponse
+// @Failure 403 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /tenants/{id}/invitations [get]
+func (h *InvitationHandler) ListPending(c *gin.Context) {
+	invitations, err := h.service.ListPending(c.Request.Context())
+	if err != nil {
+		abortWithError(c, err, "Failed to list invitations")
+		return
+	}
+
+	resp := make([]dto.InvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		resp = append(resp, dto.MapInvitationToResponse(&invitations[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Revoke handles revoking a pending invitation.
+// @Summary Revoke an invitation
+// @Description Revoke a pending invitation of a tenant. Requires the admin role; only owners can revoke invitations for the owner role.
+// @Tags invitations
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param invitationId path string true "Invitation ID"
+// @Security AuthPassword
+// @Success 204 "No Content"
+// @Failure 403 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Router /tenants/{id}/invitations/{invitationId} [delete]
+func (h *InvitationHandler) Revoke(c *gin.Context) {
+	if err := h.service.Revoke(c.Request.Context(), c.Param("invitationId")); err != nil {
+		abortWithError(c, err, "Failed to revoke invitation")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Accept handles accepting an invitation.
+// @Summary Accept an invitation
+// @Description Join the invitation's tenant with the invitation's role. The invitation must be addressed to the authenticated user's email.
+// @Tags invitations
+// @Produce json
+// @Param token path string true "Invitation token"
+// @Security AuthPassword
+// @Success 200 {object} dto.InvitationResponse
+// @Failure 403 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Router /invitations/{token}/accept [post]
+func (h *InvitationHandler) Accept(c *gin.Context) {
+	inv, err := h.service.Accept(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		abortWithError(c, err, "Failed to accept invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapInvitationToResponse(inv))
+}
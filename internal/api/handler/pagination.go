@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+)
+
+// bindPage binds the limit, cursor and include_total query parameters.
+// It aborts with a validation error and returns false when they are invalid.
+func bindPage(c *gin.Context) (domain.PageRequest, bool) {
+	var query dto.PageQuery
+	if !bindQuery(c, &query) {
+		return domain.PageRequest{}, false
+	}
+	return query.ToDomain(), true
+}
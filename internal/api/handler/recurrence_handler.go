@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+	"github.com/igoventura/fintrack-api/internal/service"
+)
+
+type RecurrenceHandler struct {
+	service *service.RecurrenceService
+}
+
+func NewRecurrenceHandler(service *service.RecurrenceService) *RecurrenceHandler {
+	return &RecurrenceHandler{service: service}
+}
+
+// Create handles the creation of a new recurrence rule.
+// @Summary Create a recurrence rule
+// @Description Creates a recurring transaction template and materializes its occurrences up to the rolling horizon.
+// @Tags recurrences
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param rule body dto.RecurrenceRuleRequest true "Recurrence rule data"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
+// @Success 201 {object} dto.RecurrenceRuleResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /recurrences [post]
+func (h *RecurrenceHandler) Create(c *gin.Context) {
+	var req dto.RecurrenceRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule := req.ToDomain()
+	if err := h.service.CreateRule(c.Request.Context(), rule); err != nil {
+		abortWithError(c, err, "Failed to create recurrence rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapRecurrenceRuleToResponse(rule))
+}
+
+// List returns the recurrence rules of the tenant.
+// @Summary List recurrence rules
+// @Description Lists the active recurrence rules of the tenant, ordered by start date.
+// @Tags recurrences
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param include_total query bool false "Include the total number of items"
+// @Success 200 {object} dto.PageResponse{items=[]dto.RecurrenceRuleResponse}
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /recurrences [get]
+func (h *RecurrenceHandler) List(c *gin.Context) {
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	rules, err := h.service.ListRules(c.Request.Context(), page)
+	if err != nil {
+		abortWithError(c, err, "Failed to list recurrence rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPageResponse(rules, dto.MapRecurrenceRuleToResponse))
+}
+
+// GetByID returns a recurrence rule by ID.
+// @Summary Get recurrence rule by ID
+// @Description Retrieves a recurrence rule with its amount changes and skipped dates.
+// @Tags recurrences
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Recurrence rule ID"
+// @Success 200 {object} dto.RecurrenceRuleResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /recurrences/{id} [get]
+func (h *RecurrenceHandler) GetByID(c *gin.Context) {
+	rule, err := h.service.GetRule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, err, "Failed to get recurrence rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapRecurrenceRuleToResponse(rule))
+}
+
+// Update replaces the template and schedule of a recurrence rule.
+// @Summary Update a recurrence rule
+// @Description Updates a recurrence rule. Unpaid occurrences from today on are replaced by ones following the new rule; past and paid occurrences are kept.
+// @Tags recurrences
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Recurrence rule ID"
+// @Param rule body dto.RecurrenceRuleRequest true "Recurrence rule data"
+// @Success 200 {object} dto.RecurrenceRuleResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /recurrences/{id} [put]
+func (h *RecurrenceHandler) Update(c *gin.Context) {
+	var req dto.RecurrenceRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule := req.ToDomain()
+	rule.ID = c.Param("id")
+	updated, err := h.service.UpdateRule(c.Request.Context(), rule)
+	if err != nil {
+		abortWithError(c, err, "Failed to update recurrence rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapRecurrenceRuleToResponse(updated))
+}
+
+// Delete deactivates a recurrence rule.
+// @Summary Delete a recurrence rule
+// @Description Soft-deletes a recurrence rule and cancels its unpaid occurrences from today on.
+// @Tags recurrences
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Recurrence rule ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /recurrences/{id} [delete]
+func (h *RecurrenceHandler) Delete(c *gin.Context) {
+	if err := h.service.DeleteRule(c.Request.Context(), c.Param("id")); err != nil {
+		abortWithError(c, err, "Failed to delete recurrence rule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SkipOccurrence skips a single occurrence of a recurrence rule.
+// @Summary Skip an occurrence
+// @Description Skips the occurrence on the given date, cancelling its transaction if it was already materialized and is not paid.
+// @Tags recurrences
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Recurrence rule ID"
+// @Param request body dto.SkipOccurrenceRequest true "Occurrence to skip"
+// @Success 200 {object} dto.RecurrenceRuleResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /recurrences/{id}/skip [post]
+func (h *RecurrenceHandler) SkipOccurrence(c *gin.Context) {
+	var req dto.SkipOccurrenceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule, err := h.service.SkipOccurrence(c.Request.Context(), c.Param("id"), req.Date)
+	if err != nil {
+		abortWithError(c, err, "Failed to skip occurrence")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapRecurrenceRuleToResponse(rule))
+}
+
+// ChangeAmount changes the amount of a recurrence rule from a date onward.
+// @Summary Change the amount from a date
+// @Description Sets the amount of the occurrences from effective_from onward, updating the unpaid ones already materialized.
+// @Tags recurrences
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Recurrence rule ID"
+// @Param request body dto.ChangeAmountRequest true "Amount change"
+// @Success 200 {object} dto.RecurrenceRuleResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /recurrences/{id}/amount-changes [post]
+func (h *RecurrenceHandler) ChangeAmount(c *gin.Context) {
+	var req dto.ChangeAmountRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule, err := h.service.ChangeAmount(c.Request.Context(), c.Param("id"), req.EffectiveFrom, req.Amount)
+	if err != nil {
+		abortWithError(c, err, "Failed to change amount")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapRecurrenceRuleToResponse(rule))
+}
+
+// Materialize creates the missing occurrences of the tenant's rules.
+// @Summary Materialize occurrences
+// @Description Creates the occurrences of every recurrence rule of the tenant up to the rolling horizon. Existing occurrences are left untouched.
+// @Tags recurrences
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {object} dto.MaterializeResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /recurrences/materialize [post]
+func (h *RecurrenceHandler) Materialize(c *gin.Context) {
+	created, err := h.service.Materialize(c.Request.Context())
+	if err != nil {
+		abortWithError(c, err, "Failed to materialize occurrences")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MaterializeResponse{Created: created})
+}
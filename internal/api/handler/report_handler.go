@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+	"github.com/igoventura/fintrack-api/internal/service"
+)
+
+type ReportHandler struct {
+	service *service.ReportService
+}
+
+func NewReportHandler(s *service.ReportService) *ReportHandler {
+	return &ReportHandler{service: s}
+}
+
+// SpendByCategory godoc
+// @Summary Report spend by category
+// @Description net spend (debits minus refunds) of each expense category per accrual month, ordered by currency, month and total. amount is the category's own spend and total includes its subcategories; parent categories are listed even without spend of their own. Transfers and card payments are not spend.
+// @Tags reports
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param month_from query string false "First accrual month (YYYYMM), defaults to 11 months before month_to"
+// @Param month_to query string false "Last accrual month (YYYYMM), defaults to the current month"
+// @Param account_id query string false "Account ID"
+// @Param currency query string false "Currency (ISO 4217), defaults to the account's when account_id is given"
+// @Success 200 {array} dto.CategorySpendResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /reports/spend-by-category [get]
+func (h *ReportHandler) SpendByCategory(c *gin.Context) {
+	var req dto.ReportFilterRequest
+	if !bindQuery(c, &req) {
+		return
+	}
+
+	spend, err := h.service.SpendByCategory(c.Request.Context(), req.ToDomain())
+	if err != nil {
+		abortWithError(c, err, "Failed to report spend by category")
+		return
+	}
+
+	resp := make([]dto.CategorySpendResponse, 0, len(spend))
+	for i := range spend {
+		resp = append(resp, dto.FromCategorySpendDomain(&spend[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// IncomeExpense godoc
+// @Summary Report income vs expense
+// @Description income (income categories) and expense (expense categories) of every accrual month of the range, with their net, one series per currency. Months without activity are zero.
+// @Tags reports
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param month_from query string false "First accrual month (YYYYMM), defaults to 11 months before month_to"
+// @Param month_to query string false "Last accrual month (YYYYMM), defaults to the current month"
+// @Param account_id query string false "Account ID"
+// @Param currency query string false "Currency (ISO 4217), defaults to the account's when account_id is given"
+// @Success 200 {array} dto.IncomeExpenseResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /reports/income-expense [get]
+func (h *ReportHandler) IncomeExpense(c *gin.Context) {
+	var req dto.ReportFilterRequest
+	if !bindQuery(c, &req) {
+		return
+	}
+
+	months, err := h.service.IncomeExpense(c.Request.Context(), req.ToDomain())
+	if err != nil {
+		abortWithError(c, err, "Failed to report income and expense")
+		return
+	}
+
+	resp := make([]dto.IncomeExpenseResponse, 0, len(months))
+	for i := range months {
+		resp = append(resp, dto.FromIncomeExpenseDomain(&months[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CashFlow godoc
+// @Summary Report cash flow
+// @Description money that entered and left the accounts in every month of the range, by payment date (partial payments included), with the net, one series per account currency. Transfers and card payments count on both of their accounts. Months without activity are zero.
+// @Tags reports
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param month_from query string false "First payment month (YYYYMM), defaults to 11 months before month_to"
+// @Param month_to query string false "Last payment month (YYYYMM), defaults to the current month"
+// @Param account_id query string false "Account ID"
+// @Param currency query string false "Currency (ISO 4217), defaults to the account's when account_id is given"
+// @Success 200 {array} dto.CashFlowResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /reports/cash-flow [get]
+func (h *ReportHandler) CashFlow(c *gin.Context) {
+	var req dto.ReportFilterRequest
+	if !bindQuery(c, &req) {
+		return
+	}
+
+	months, err := h.service.CashFlow(c.Request.Context(), req.ToDomain())
+	if err != nil {
+		abortWithError(c, err, "Failed to report cash flow")
+		return
+	}
+
+	resp := make([]dto.CashFlowResponse, 0, len(months))
+	for i := range months {
+		resp = append(resp, dto.FromCashFlowDomain(&months[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// TopTags godoc
+// @Summary Report top tags
+// @Description tags with the highest net spend in expense categories over the accrual months of the range, per currency
+// @Tags reports
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param month_from query string false "First accrual month (YYYYMM), defaults to 11 months before month_to"
+// @Param month_to query string false "Last accrual month (YYYYMM), defaults to the current month"
+// @Param account_id query string false "Account ID"
+// @Param currency query string false "Currency (ISO 4217), defaults to the account's when account_id is given"
+// @Param limit query int false "Tags per currency (1-50, default 10)"
+// @Success 200 {array} dto.TagSpendResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /reports/top-tags [get]
+func (h *ReportHandler) TopTags(c *gin.Context) {
+	var req dto.ReportFilterRequest
+	var top dto.TopTagsRequest
+	if !bindQuery(c, &req) || !bindQuery(c, &top) {
+		return
+	}
+
+	tags, err := h.service.TopTags(c.Request.Context(), req.ToDomain(), top.Limit)
+	if err != nil {
+		abortWithError(c, err, "Failed to report top tags")
+		return
+	}
+
+	resp := make([]dto.TagSpendResponse, 0, len(tags))
+	for i := range tags {
+		resp = append(resp, dto.FromTagSpendDomain(&tags[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
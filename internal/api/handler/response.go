@@ -1,13 +1,122 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/igoventura/fintrack-api/internal/api/middleware"
 )
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+// ErrorJSON aborts with a problem details response of the given status, for failures the handler
+// detects itself. Errors returned by the services go through abortWithError.
+func ErrorJSON(c *gin.Context, code int, message string) {
+	middleware.AbortWithProblem(c, code, message)
 }
 
-func ErrorJSON(c *gin.Context, code int, message string) {
-	c.JSON(code, ErrorResponse{Error: message})
+// abortWithError hands err to the error middleware, which derives the status from its kind.
+// message is the detail of the 500 returned when err is not a domain error.
+func abortWithError(c *gin.Context, err error, message string) {
+	c.Error(err).SetMeta(message)
+	c.Abort()
+}
+
+// bindJSON binds the request body, aborting with a validation error when it is invalid.
+func bindJSON(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		abortWithError(c, bindingError(err, "invalid request payload"), "")
+		return false
+	}
+	return true
+}
+
+// bindQuery binds the query parameters, aborting with a validation error when they are invalid.
+func bindQuery(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindQuery(obj); err != nil {
+		abortWithError(c, bindingError(err, "invalid query parameters"), "")
+		return false
+	}
+	return true
+}
+
+// bindForm binds a multipart form, aborting with a validation error when it is invalid.
+func bindForm(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindWith(obj, binding.FormMultipart); err != nil {
+		abortWithError(c, bindingError(err, "invalid form"), "")
+		return false
+	}
+	return true
+}
+
+// bindingError converts a binding error into a validation error, with a message per field when
+// the failing fields are known.
+func bindingError(err error, message string) error {
+	var (
+		fieldErrs  validator.ValidationErrors
+		typeErr    *json.UnmarshalTypeError
+		syntaxErr  *json.SyntaxError
+		validation *domain.ValidationError
+	)
+	switch {
+	case errors.As(err, &fieldErrs):
+		fields := make(map[string]string, len(fieldErrs))
+		for _, fe := range fieldErrs {
+			fields[fe.Field()] = fieldMessage(fe)
+		}
+		return &domain.ValidationError{Message: message, Fields: fields}
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return domain.InvalidField(typeErr.Field, fmt.Sprintf("%s must be a %s", typeErr.Field, typeErr.Type))
+	case errors.As(err, &syntaxErr):
+		return domain.NewValidationError(message + ": malformed JSON")
+	case errors.As(err, &validation):
+		return validation
+	}
+	return domain.NewValidationError(message)
+}
+
+// fieldMessage describes a failed validation rule.
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fe.Field() + " is required"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", fe.Field(), strings.Join(strings.Fields(fe.Param()), ", "))
+	case "min", "gte":
+		return fmt.Sprintf("%s must be at least %s", fe.Field(), fe.Param())
+	case "max", "lte":
+		return fmt.Sprintf("%s must be at most %s", fe.Field(), fe.Param())
+	case "len":
+		return fmt.Sprintf("%s must have length %s", fe.Field(), fe.Param())
+	case "email":
+		return fe.Field() + " must be a valid email"
+	case "uuid", "uuid4":
+		return fe.Field() + " must be a valid UUID"
+	}
+	return fe.Field() + " is invalid"
+}
+
+// RegisterFieldNames makes validation errors name fields by their JSON (or query) name.
+func RegisterFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
 }
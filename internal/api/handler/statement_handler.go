@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+	"github.com/igoventura/fintrack-api/internal/service"
+)
+
+type StatementHandler struct {
+	service *service.StatementService
+}
+
+func NewStatementHandler(s *service.StatementService) *StatementHandler {
+	return &StatementHandler{service: s}
+}
+
+// List godoc
+// @Summary List credit card statements
+// @Description list the statements of a credit card account with activity, newest first
+// @Tags statements
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Account ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Security AuthPassword
+// @Success 200 {array} dto.StatementResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /accounts/{id}/statements [get]
+func (h *StatementHandler) List(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		ErrorJSON(c, http.StatusBadRequest, "Account ID is required")
+		return
+	}
+
+	statements, err := h.service.List(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err, "Failed to list statements")
+		return
+	}
+
+	resp := make([]*dto.StatementResponse, 0, len(statements))
+	for i := range statements {
+		resp = append(resp, dto.MapStatementToResponse(&statements[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get godoc
+// @Summary Get a credit card statement
+// @Description get the statement of a credit card account for a period (YYYYMM of its due date), including line items
+// @Tags statements
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Account ID"
+// @Param period path string true "Statement period (YYYYMM)"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Security AuthPassword
+// @Success 200 {object} dto.StatementResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /accounts/{id}/statements/{period} [get]
+func (h *StatementHandler) Get(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		ErrorJSON(c, http.StatusBadRequest, "Account ID is required")
+		return
+	}
+
+	statement, err := h.service.Get(c.Request.Context(), id, c.Param("period"))
+	if err != nil {
+		abortWithError(c, err, "Failed to get statement")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapStatementToResponse(statement))
+}
@@ -26,14 +26,15 @@ func NewTagHandler(service *service.TagService) *TagHandler {
 // @Security AuthPassword
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param request body dto.CreateTagRequest true "Create Tag Request"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
 // @Success 201 {object} dto.TagResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /tags [post]
 func (h *TagHandler) CreateTag(c *gin.Context) {
 	var req dto.CreateTagRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -45,20 +46,11 @@ func (h *TagHandler) CreateTag(c *gin.Context) {
 	}
 
 	if err := h.service.CreateTag(c.Request.Context(), tag); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to create tag")
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.TagResponse{
-		ID:            tag.ID,
-		TenantID:      tag.TenantID,
-		Name:          tag.Name,
-		CreatedAt:     tag.CreatedAt,
-		CreatedBy:     tag.CreatedBy,
-		UpdatedAt:     tag.UpdatedAt,
-		UpdatedBy:     tag.UpdatedBy,
-		DeactivatedAt: tag.DeactivatedAt,
-	})
+	c.JSON(http.StatusCreated, dto.MapTagToResponse(tag))
 }
 
 // GetTag returns a tag by ID
@@ -69,27 +61,24 @@ func (h *TagHandler) CreateTag(c *gin.Context) {
 // @Security AuthPassword
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param id path string true "Tag ID"
+// @Param If-None-Match header string false "ETag of the cached version"
 // @Success 200 {object} dto.TagResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 304 "Not Modified"
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /tags/{id} [get]
 func (h *TagHandler) GetTag(c *gin.Context) {
 	id := c.Param("id")
 	tag, err := h.service.GetTag(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to get tag")
+		return
+	}
+	if notModified(c, tag.Version) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.TagResponse{
-		ID:            tag.ID,
-		TenantID:      tag.TenantID,
-		Name:          tag.Name,
-		CreatedAt:     tag.CreatedAt,
-		CreatedBy:     tag.CreatedBy,
-		UpdatedAt:     tag.UpdatedAt,
-		UpdatedBy:     tag.UpdatedBy,
-		DeactivatedAt: tag.DeactivatedAt,
-	})
+	c.JSON(http.StatusOK, dto.MapTagToResponse(tag))
 }
 
 // ListTags returns all tags for the tenant
@@ -98,35 +87,27 @@ func (h *TagHandler) GetTag(c *gin.Context) {
 // @Tags tags
 // @Produce json
 // @Security AuthPassword
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param include_total query bool false "Include the total number of items"
 // @Param X-Tenant-ID header string true "Tenant ID"
-// @Success 200 {object} []dto.TagResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 200 {object} dto.PageResponse{items=[]dto.TagResponse}
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /tags [get]
 func (h *TagHandler) ListTags(c *gin.Context) {
-	tags, err := h.service.ListTags(c.Request.Context())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	page, ok := bindPage(c)
+	if !ok {
 		return
 	}
 
-	var resp []*dto.TagResponse
-	for _, tag := range tags {
-		resp = append(resp, &dto.TagResponse{
-			ID:            tag.ID,
-			TenantID:      tag.TenantID,
-			Name:          tag.Name,
-			CreatedAt:     tag.CreatedAt,
-			CreatedBy:     tag.CreatedBy,
-			UpdatedAt:     tag.UpdatedAt,
-			UpdatedBy:     tag.UpdatedBy,
-			DeactivatedAt: tag.DeactivatedAt,
-		})
+	tags, err := h.service.ListTags(c.Request.Context(), page)
+	if err != nil {
+		abortWithError(c, err, "Failed to list tags")
+		return
 	}
 
-	if resp == nil {
-		resp = []*dto.TagResponse{}
-	}
-	c.JSON(http.StatusOK, resp)
+	c.JSON(http.StatusOK, dto.NewPageResponse(tags, dto.MapTagToResponse))
 }
 
 // UpdateTag updates a tag
@@ -139,15 +120,22 @@ func (h *TagHandler) ListTags(c *gin.Context) {
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param id path string true "Tag ID"
 // @Param request body dto.UpdateTagRequest true "Update Tag Request"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Success 200 {object} dto.TagResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /tags/{id} [put]
 func (h *TagHandler) UpdateTag(c *gin.Context) {
 	id := c.Param("id")
 	var req dto.UpdateTagRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
@@ -156,29 +144,61 @@ func (h *TagHandler) UpdateTag(c *gin.Context) {
 		ID:        id,
 		Name:      req.Name,
 		UpdatedBy: userID,
+		Version:   version,
 	}
 
 	if err := h.service.UpdateTag(c.Request.Context(), tag); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to update tag")
 		return
 	}
 
 	updatedTag, err := h.service.GetTag(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to get tag")
+		return
+	}
+
+	setETag(c, updatedTag.Version)
+	c.JSON(http.StatusOK, dto.MapTagToResponse(updatedTag))
+}
+
+// PatchTag partially updates a tag
+// @Summary Patch tag
+// @Description Apply a JSON Merge Patch (RFC 7396) to a tag: only the fields sent are changed.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Tag ID"
+// @Param request body dto.PatchTagRequest true "Fields to change"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 200 {object} dto.TagResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /tags/{id} [patch]
+func (h *TagHandler) PatchTag(c *gin.Context) {
+	id := c.Param("id")
+	var req dto.PatchTagRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	tag, err := h.service.PatchTag(c.Request.Context(), id, req.ToDomain(), version)
+	if err != nil {
+		abortWithError(c, err, "Failed to update tag")
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.TagResponse{
-		ID:            updatedTag.ID,
-		TenantID:      updatedTag.TenantID,
-		Name:          updatedTag.Name,
-		CreatedAt:     updatedTag.CreatedAt,
-		CreatedBy:     updatedTag.CreatedBy,
-		UpdatedAt:     updatedTag.UpdatedAt,
-		UpdatedBy:     updatedTag.UpdatedBy,
-		DeactivatedAt: updatedTag.DeactivatedAt,
-	})
+	setETag(c, tag.Version)
+	c.JSON(http.StatusOK, dto.MapTagToResponse(tag))
 }
 
 // DeleteTag deletes a tag
@@ -190,14 +210,14 @@ func (h *TagHandler) UpdateTag(c *gin.Context) {
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param id path string true "Tag ID"
 // @Success 204 "No Content"
-// @Failure 500 {object} ErrorResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /tags/{id} [delete]
 func (h *TagHandler) DeleteTag(c *gin.Context) {
 	id := c.Param("id")
 	userID := domain.GetUserID(c.Request.Context())
 
 	if err := h.service.DeleteTag(c.Request.Context(), id, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to delete tag")
 		return
 	}
 
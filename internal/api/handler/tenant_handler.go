@@ -24,22 +24,23 @@ func NewTenantHandler(service *service.TenantService) *TenantHandler {
 // @Accept json
 // @Produce json
 // @Param tenant body dto.CreateTenantRequest true "Create tenant"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
 // @Security AuthPassword
 // @Success 201 {object} dto.TenantResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /tenants [post]
 func (h *TenantHandler) Create(c *gin.Context) {
 	var req dto.CreateTenantRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	userID := domain.GetUserID(c.Request.Context())
 	tenant, err := h.service.CreateTenant(c.Request.Context(), req.Name, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to create tenant")
 		return
 	}
 
@@ -50,3 +51,80 @@ func (h *TenantHandler) Create(c *gin.Context) {
 		UpdatedAt: tenant.UpdatedAt,
 	})
 }
+
+// ListMembers handles listing the members of a tenant.
+// @Summary List tenant members
+// @Description List the active members of a tenant and their roles.
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Security AuthPassword
+// @Success 200 {array} dto.TenantMemberResponse
+// @Failure 403 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /tenants/{id}/members [get]
+func (h *TenantHandler) ListMembers(c *gin.Context) {
+	members, err := h.service.ListMembers(c.Request.Context())
+	if err != nil {
+		abortWithError(c, err, "Failed to list members")
+		return
+	}
+
+	resp := make([]dto.TenantMemberResponse, 0, len(members))
+	for i := range members {
+		resp = append(resp, dto.MapTenantMemberToResponse(&members[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateMemberRole handles changing the role of a tenant member.
+// @Summary Update a tenant member role
+// @Description Change the role of a tenant member. Requires the admin role; only owners can grant or revoke the owner role.
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param userId path string true "User ID"
+// @Param request body dto.UpdateMemberRoleRequest true "New role"
+// @Security AuthPassword
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 403 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Router /tenants/{id}/members/{userId} [put]
+func (h *TenantHandler) UpdateMemberRole(c *gin.Context) {
+	var req dto.UpdateMemberRoleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.service.UpdateMemberRole(c.Request.Context(), c.Param("userId"), req.Role); err != nil {
+		abortWithError(c, err, "Failed to update member role")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveMember handles removing a member from a tenant.
+// @Summary Remove a tenant member
+// @Description Remove a member from a tenant. Requires the admin role; only owners can remove another owner.
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param userId path string true "User ID"
+// @Security AuthPassword
+// @Success 204 "No Content"
+// @Failure 403 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Router /tenants/{id}/members/{userId} [delete]
+func (h *TenantHandler) RemoveMember(c *gin.Context) {
+	if err := h.service.RemoveMember(c.Request.Context(), c.Param("userId")); err != nil {
+		abortWithError(c, err, "Failed to remove member")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/domain"
 	"github.com/igoventura/fintrack-api/internal/api/dto"
 	"github.com/igoventura/fintrack-api/internal/service"
 )
@@ -18,31 +19,32 @@ func NewTransactionHandler(service *service.TransactionService) *TransactionHand
 
 // Create handles the creation of a new transaction.
 // @Summary Create a new transaction
-// @Description Creates a new transaction for the authenticated user's tenant.
+// @Description Creates a new transaction for the authenticated user's tenant. A transfer is created as two linked legs: an outgoing one on from_account_id and an incoming one on to_account_id, converted at exchange_rate when the accounts use different currencies.
 // @Tags transactions
 // @Accept json
 // @Produce json
 // @Security AuthPassword
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param transaction body dto.CreateTransactionRequest true "Transaction data"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
 // @Success 201 {object} dto.TransactionResponse
-// @Failure 400 {object} handler.ErrorResponse
-// @Failure 500 {object} handler.ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /transactions [post]
 func (h *TransactionHandler) Create(c *gin.Context) {
 	var req dto.CreateTransactionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		ErrorJSON(c, http.StatusBadRequest, "Invalid request payload")
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	tx := req.ToDomain()
 	if err := h.service.Create(c.Request.Context(), tx, req.TagIDs, req.Installments, req.IsRecurring); err != nil {
-		ErrorJSON(c, http.StatusInternalServerError, "Failed to create transaction")
+		abortWithError(c, err, "Failed to create transaction")
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.FromTransactionDomain(tx, req.TagIDs))
+	c.JSON(http.StatusCreated, dto.FromTransactionDomain(tx, false))
 }
 
 // GetByID returns a transaction by ID.
@@ -53,29 +55,35 @@ func (h *TransactionHandler) Create(c *gin.Context) {
 // @Security AuthPassword
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param id path string true "Transaction ID"
+// @Param expand query string false "Embed related objects" Enums(tags)
+// @Param If-None-Match header string false "ETag of the cached version"
 // @Success 200 {object} dto.TransactionResponse
-// @Failure 404 {object} handler.ErrorResponse
-// @Failure 500 {object} handler.ErrorResponse
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /transactions/{id} [get]
 func (h *TransactionHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
+	var expand dto.ExpandQuery
+	if !bindQuery(c, &expand) {
+		return
+	}
+
 	tx, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		ErrorJSON(c, http.StatusInternalServerError, "Failed to get transaction")
+		abortWithError(c, err, "Failed to get transaction")
 		return
 	}
 	if tx == nil {
 		ErrorJSON(c, http.StatusNotFound, "Transaction not found")
 		return
 	}
-
-	tagIDs, err := h.service.GetTagIDsForTransaction(c.Request.Context(), id)
-	if err != nil {
-		ErrorJSON(c, http.StatusInternalServerError, "Failed to get transaction tags")
+	if notModified(c, tx.Version) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.FromTransactionDomain(tx, tagIDs))
+	c.JSON(http.StatusOK, dto.FromTransactionDomain(tx, expand.Has(dto.ExpandTags)))
 }
 
 // List returns a list of transactions with optional filtering.
@@ -86,45 +94,68 @@ func (h *TransactionHandler) GetByID(c *gin.Context) {
 // @Security AuthPassword
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param accrual_month query string false "Accrual Month (YYYYMM)"
+// @Param accrual_month_from query string false "Accrual months from (YYYYMM, inclusive)"
+// @Param accrual_month_to query string false "Accrual months until (YYYYMM, inclusive)"
 // @Param account_id query string false "Account ID"
 // @Param transaction_type query string false "Transaction Type"
-// @Success 200 {array} dto.TransactionResponse
-// @Failure 400 {object} handler.ErrorResponse
-// @Failure 500 {object} handler.ErrorResponse
+// @Param due_date_from query string false "Due date from (YYYY-MM-DD, inclusive)"
+// @Param due_date_to query string false "Due date until (YYYY-MM-DD, inclusive)"
+// @Param payment_date_from query string false "Payment date from (YYYY-MM-DD, inclusive)"
+// @Param payment_date_to query string false "Payment date until (YYYY-MM-DD, inclusive)"
+// @Param min_amount query string false "Minimum amount (inclusive)"
+// @Param max_amount query string false "Maximum amount (inclusive)"
+// @Param category_id query []string false "Category IDs (repeatable)" collectionFormat(multi)
+// @Param include_subcategories query bool false "Also match the descendants of the given categories"
+// @Param tag_id query []string false "Tag IDs (repeatable)" collectionFormat(multi)
+// @Param tag_match query string false "Match any (default) or all of the given tags" Enums(any, all)
+// @Param status query string false "Payment status" Enums(paid, unpaid, overdue, pending, partially_paid)
+// @Param parent_transaction_id query string false "Installment series: the parent transaction and its installments"
+// @Param currency query string false "Currency (ISO 4217)"
+// @Param q query string false "Case-insensitive search in comments"
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param include_total query bool false "Include the total number of items"
+// @Param expand query string false "Embed related objects" Enums(tags)
+// @Success 200 {object} dto.PageResponse{items=[]dto.TransactionResponse}
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /transactions [get]
 func (h *TransactionHandler) List(c *gin.Context) {
 	var filterReq dto.TransactionFilterRequest
-	if err := c.ShouldBindQuery(&filterReq); err != nil {
-		ErrorJSON(c, http.StatusBadRequest, "Invalid query parameters")
+	var expand dto.ExpandQuery
+	if !bindQuery(c, &filterReq) {
+		return
+	}
+	if !bindQuery(c, &expand) {
 		return
 	}
 
-	txs, err := h.service.List(c.Request.Context(), filterReq.ToDomain())
-	if err != nil {
-		ErrorJSON(c, http.StatusInternalServerError, "Failed to list transactions")
+	page, ok := bindPage(c)
+	if !ok {
 		return
 	}
 
-	var response []dto.TransactionResponse
-	for _, tx := range txs {
-		tagIDs, err := h.service.GetTagIDsForTransaction(c.Request.Context(), tx.ID)
-		if err != nil {
-			ErrorJSON(c, http.StatusInternalServerError, "Failed to get transaction tags")
-			return
-		}
-		response = append(response, dto.FromTransactionDomain(&tx, tagIDs))
+	filter, err := filterReq.ToDomain()
+	if err != nil {
+		abortWithError(c, err, "Invalid query parameters")
+		return
 	}
-	// Return empty array instead of null if no results
-	if response == nil {
-		response = []dto.TransactionResponse{}
+
+	txs, err := h.service.List(c.Request.Context(), filter, page)
+	if err != nil {
+		abortWithError(c, err, "Failed to list transactions")
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	expandTags := expand.Has(dto.ExpandTags)
+	c.JSON(http.StatusOK, dto.NewPageResponse(txs, func(tx *domain.Transaction) dto.TransactionResponse {
+		return dto.FromTransactionDomain(tx, expandTags)
+	}))
 }
 
 // Update updates an existing transaction.
 // @Summary Update a transaction
-// @Description Updates a transaction by ID.
+// @Description Updates a transaction by ID. Updating a leg of a transfer updates the other leg too; the accounts, amount, exchange rate and fee are changed on the outgoing leg.
 // @Tags transactions
 // @Accept json
 // @Produce json
@@ -132,45 +163,93 @@ func (h *TransactionHandler) List(c *gin.Context) {
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param id path string true "Transaction ID"
 // @Param transaction body dto.UpdateTransactionRequest true "Transaction data"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Success 200 {object} dto.TransactionResponse
-// @Failure 400 {object} handler.ErrorResponse
-// @Failure 500 {object} handler.ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /transactions/{id} [put]
 func (h *TransactionHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 	var req dto.UpdateTransactionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		ErrorJSON(c, http.StatusBadRequest, "Invalid request payload")
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
 	tx := req.ToDomain()
 	tx.ID = id
+	tx.Version = version
 
 	// Note: Update logic in Service handles tag replacement.
 	if err := h.service.Update(c.Request.Context(), tx, req.TagIDs); err != nil {
-		ErrorJSON(c, http.StatusInternalServerError, "Failed to update transaction")
+		abortWithError(c, err, "Failed to update transaction")
+		return
+	}
+
+	setETag(c, tx.Version)
+	c.JSON(http.StatusOK, dto.FromTransactionDomain(tx, false))
+}
+
+// Patch partially updates a transaction.
+// @Summary Patch a transaction
+// @Description Applies a JSON Merge Patch (RFC 7396) to a transaction: only the fields sent are changed, and null clears a nullable field. The result is validated like a full update.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Transaction ID"
+// @Param transaction body dto.PatchTransactionRequest true "Fields to change"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 200 {object} dto.TransactionResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /transactions/{id} [patch]
+func (h *TransactionHandler) Patch(c *gin.Context) {
+	id := c.Param("id")
+	var req dto.PatchTransactionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	tx, err := h.service.Patch(c.Request.Context(), id, req.ToDomain(), version)
+	if err != nil {
+		abortWithError(c, err, "Failed to update transaction")
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.FromTransactionDomain(tx, req.TagIDs))
+	setETag(c, tx.Version)
+	c.JSON(http.StatusOK, dto.FromTransactionDomain(tx, false))
 }
 
 // Delete removes a transaction.
 // @Summary Delete a transaction
-// @Description Soft-deletes a transaction by ID.
+// @Description Soft-deletes a transaction by ID. Deleting either leg of a transfer deletes both.
 // @Tags transactions
 // @Produce json
 // @Security AuthPassword
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param id path string true "Transaction ID"
 // @Success 204 "No Content"
-// @Failure 500 {object} handler.ErrorResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /transactions/{id} [delete]
 func (h *TransactionHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		ErrorJSON(c, http.StatusInternalServerError, "Failed to delete transaction")
+		abortWithError(c, err, "Failed to delete transaction")
 		return
 	}
 
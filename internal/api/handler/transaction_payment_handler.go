@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+)
+
+// Settle records a payment against a transaction.
+// @Summary Settle a transaction
+// @Description Records a full or partial payment. Without amount the outstanding amount is paid, and without account_id it is paid from the transaction's from_account_id. The transaction becomes paid, with payment_date set, once its payments add up to its amount.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Transaction ID"
+// @Param request body dto.SettleTransactionRequest true "Payment"
+// @Param If-Match header string false "ETag of the version being settled"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
+// @Success 200 {object} dto.TransactionResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /transactions/{id}/settle [post]
+func (h *TransactionHandler) Settle(c *gin.Context) {
+	var req dto.SettleTransactionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	tx, err := h.service.Settle(c.Request.Context(), c.Param("id"), req.ToDomain(), version)
+	if err != nil {
+		abortWithError(c, err, "Failed to settle transaction")
+		return
+	}
+
+	setETag(c, tx.Version)
+	c.JSON(http.StatusOK, dto.FromTransactionDomain(tx, false))
+}
+
+// Unsettle reverts a transaction to unpaid.
+// @Summary Unsettle a transaction
+// @Description Deactivates the payments of a transaction and clears its payment date.
+// @Tags transactions
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Transaction ID"
+// @Param If-Match header string false "ETag of the version being unsettled"
+// @Success 200 {object} dto.TransactionResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /transactions/{id}/unsettle [post]
+func (h *TransactionHandler) Unsettle(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	tx, err := h.service.Unsettle(c.Request.Context(), c.Param("id"), version)
+	if err != nil {
+		abortWithError(c, err, "Failed to unsettle transaction")
+		return
+	}
+
+	setETag(c, tx.Version)
+	c.JSON(http.StatusOK, dto.FromTransactionDomain(tx, false))
+}
+
+// BulkSettle settles several transactions in full.
+// @Summary Settle transactions in bulk
+// @Description Pays the outstanding amount of each transaction on the same date, all or nothing. Transactions already paid are returned unchanged.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param request body dto.BulkSettleTransactionsRequest true "Transactions to settle"
+// @Param Idempotency-Key header string false "Replays the response of a previous request with the same key"
+// @Success 200 {array} dto.TransactionResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 412 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /transactions/settle [post]
+func (h *TransactionHandler) BulkSettle(c *gin.Context) {
+	var req dto.BulkSettleTransactionsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	txs, err := h.service.SettleMany(c.Request.Context(), req.TransactionIDs, req.PaymentDate, req.AccountID)
+	if err != nil {
+		abortWithError(c, err, "Failed to settle transactions")
+		return
+	}
+
+	resp := make([]dto.TransactionResponse, 0, len(txs))
+	for i := range txs {
+		resp = append(resp, dto.FromTransactionDomain(&txs[i], false))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListPayments lists the payments of a transaction.
+// @Summary List transaction payments
+// @Description Returns the payments recorded against a transaction, oldest first.
+// @Tags transactions
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Transaction ID"
+// @Success 200 {array} dto.TransactionPaymentResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /transactions/{id}/payments [get]
+func (h *TransactionHandler) ListPayments(c *gin.Context) {
+	payments, err := h.service.ListPayments(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, err, "Failed to list payments")
+		return
+	}
+
+	resp := make([]dto.TransactionPaymentResponse, 0, len(payments))
+	for i := range payments {
+		resp = append(resp, dto.MapTransactionPaymentToResponse(&payments[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
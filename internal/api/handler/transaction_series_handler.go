@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+)
+
+// GetSeries returns the installment series of a transaction.
+// @Summary Get installment series
+// @Description Returns the installments of the series the transaction belongs to, with paid and remaining totals.
+// @Tags transactions
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Transaction ID (any installment of the series)"
+// @Param expand query string false "Embed related objects" Enums(tags)
+// @Success 200 {object} dto.TransactionSeriesResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /transactions/{id}/series [get]
+func (h *TransactionHandler) GetSeries(c *gin.Context) {
+	var expand dto.ExpandQuery
+	if !bindQuery(c, &expand) {
+		return
+	}
+
+	series, err := h.service.GetSeries(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, err, "Failed to get installment series")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapTransactionSeriesToResponse(series, expand.Has(dto.ExpandTags)))
+}
+
+// UpdateSeries changes the category and tags of installments of a series.
+// @Summary Update installment series
+// @Description Sets the category and/or replaces the tags of this installment, the following ones or all of them.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Transaction ID (any installment of the series)"
+// @Param scope query string false "Installments to change (default this)" Enums(this, following, all)
+// @Param request body dto.UpdateSeriesRequest true "Series changes"
+// @Success 200 {object} dto.TransactionSeriesResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /transactions/{id}/series [patch]
+func (h *TransactionHandler) UpdateSeries(c *gin.Context) {
+	var query dto.SeriesScopeQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	var req dto.UpdateSeriesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	series, err := h.service.UpdateSeries(c.Request.Context(), c.Param("id"), query.ToDomain(), req.CategoryID, req.TagIDs)
+	if err != nil {
+		abortWithError(c, err, "Failed to update installment series")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapTransactionSeriesToResponse(series, false))
+}
+
+// CancelSeries cancels the unpaid installments of a series.
+// @Summary Cancel remaining installments
+// @Description Soft-deletes the installments of the series that are not paid yet. Fails with 409 when one of them is partially paid, as its payments would be lost.
+// @Tags transactions
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Transaction ID (any installment of the series)"
+// @Success 200 {object} dto.TransactionSeriesResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /transactions/{id}/series/cancel [post]
+func (h *TransactionHandler) CancelSeries(c *gin.Context) {
+	series, err := h.service.CancelSeries(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, err, "Failed to cancel installments")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapTransactionSeriesToResponse(series, false))
+}
+
+// RescheduleSeries moves the unpaid installments of a series.
+// @Summary Reschedule installments
+// @Description Moves the unpaid installments to monthly due dates starting at first_due_date. Paid installments keep their dates.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Transaction ID (any installment of the series)"
+// @Param request body dto.RescheduleSeriesRequest true "New schedule"
+// @Success 200 {object} dto.TransactionSeriesResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /transactions/{id}/series/reschedule [post]
+func (h *TransactionHandler) RescheduleSeries(c *gin.Context) {
+	var req dto.RescheduleSeriesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	series, err := h.service.RescheduleSeries(c.Request.Context(), c.Param("id"), req.FirstDueDate)
+	if err != nil {
+		abortWithError(c, err, "Failed to reschedule installments")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapTransactionSeriesToResponse(series, false))
+}
+
+// PayOffSeries settles a series early.
+// @Summary Pay off installments early
+// @Description Collapses the unpaid installments into one payment of the outstanding amount minus the optional discount, and cancels the others. Partial payments of the unpaid installments move to the one carrying the payment.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security AuthPassword
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param id path string true "Transaction ID (any installment of the series)"
+// @Param request body dto.PayOffSeriesRequest true "Early payment"
+// @Success 200 {object} dto.TransactionSeriesResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 409 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
+// @Router /transactions/{id}/series/payoff [post]
+func (h *TransactionHandler) PayOffSeries(c *gin.Context) {
+	var req dto.PayOffSeriesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	series, err := h.service.PayOffSeries(c.Request.Context(), c.Param("id"), req.PaymentDate, req.Discount)
+	if err != nil {
+		abortWithError(c, err, "Failed to pay off installments")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapTransactionSeriesToResponse(series, false))
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -26,13 +25,14 @@ func NewUserHandler(userService *service.UserService, supabaseAuthService *servi
 // @Produce json
 // @Security AuthPassword
 // @Success 200 {object} dto.UserResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /users/profile [get]
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID := domain.GetUserID(c.Request.Context())
 	user, err := h.userService.GetUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to get user")
 		return
 	}
 
@@ -54,15 +54,14 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 // @Security AuthPassword
 // @Param request body dto.UpdateUserRequest true "Update User Request"
 // @Success 200 {object} dto.UserResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} dto.ProblemResponse
+// @Failure 404 {object} dto.ProblemResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /users/profile [put]
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	// get req
 	var req dto.UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -71,7 +70,7 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	// get original user
 	originalUser, err := h.userService.GetUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to get user")
 		return
 	}
 
@@ -84,23 +83,21 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	// validate user
-	isValid, validationError := user.IsValid()
-	if !isValid {
-		jsonString, _ := json.Marshal(validationError)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: string(jsonString)})
+	if isValid, validationErrors := user.IsValid(); !isValid {
+		abortWithError(c, domain.InvalidFields(validationErrors), "")
 		return
 	}
 
 	// update user
 	if err := h.userService.UpdateUser(c.Request.Context(), user); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to update user")
 		return
 	}
 
 	// update supabase user
 	if originalUser.Email != req.Email || originalUser.Name != req.Name {
 		if err := h.supabaseAuthService.UpdateUser(c.Request.Context(), user); err != nil {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			abortWithError(c, err, "Failed to update user")
 			return
 		}
 	}
@@ -121,13 +118,13 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 // @Produce json
 // @Security AuthPassword
 // @Success 200 {object} []dto.UserTenantResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 500 {object} dto.ProblemResponse
 // @Router /users/tenants [get]
 func (h *UserHandler) ListUserTenants(c *gin.Context) {
 	userID := domain.GetUserID(c.Request.Context())
 	tenants, err := h.userService.ListUserTenants(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		abortWithError(c, err, "Failed to list user tenants")
 		return
 	}
 
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// RequireAdmin aborts with 403 unless the authenticated user is one of the given operators.
+// It must run after the auth middleware. With no admin IDs configured, every request is rejected.
+func RequireAdmin(adminUserIDs []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(adminUserIDs, domain.GetUserID(c.Request.Context())) {
+			AbortWithProblem(c, http.StatusForbidden, "insufficient permissions")
+			return
+		}
+		c.Next()
+	}
+}
@@ -29,26 +29,26 @@ func (m *AuthMiddleware) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			AbortWithProblem(c, http.StatusUnauthorized, "Authorization header required")
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			AbortWithProblem(c, http.StatusUnauthorized, "Invalid authorization header format")
 			return
 		}
 
 		tokenString := parts[1]
 		claims, err := m.validator.ValidateToken(tokenString)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			AbortWithProblem(c, http.StatusUnauthorized, "Invalid token")
 			return
 		}
 
 		user, err := m.userRepo.GetBySupabaseID(c.Request.Context(), claims.Subject)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			AbortWithProblem(c, http.StatusUnauthorized, "User not found")
 			return
 		}
 
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// ErrorHandler renders the last error a handler recorded with c.Error as problem details.
+// The status follows the kind of the domain error: 400 for validation errors (with a message
+// per invalid field), 403 forbidden, 404 not found, 409 conflict and 412 precondition failed. Any other error is logged
+// and becomes a 500 whose detail is the message set as the error's meta, so internals do not leak.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		last := c.Errors.Last()
+		status, detail, fields := classifyError(last.Err)
+		if status == http.StatusInternalServerError {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, last.Err)
+			detail, _ = last.Meta.(string)
+		}
+		writeProblem(c, status, detail, fields)
+	}
+}
+
+// AbortWithProblem aborts the request with a problem details response.
+func AbortWithProblem(c *gin.Context, status int, detail string) {
+	writeProblem(c, status, detail, nil)
+	c.Abort()
+}
+
+func writeProblem(c *gin.Context, status int, detail string, fields map[string]string) {
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(status, dto.ProblemResponse{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Errors:   fields,
+	})
+}
+
+// classifyError returns the status, detail and field messages of a domain error, or a 500 for other errors.
+func classifyError(err error) (int, string, map[string]string) {
+	var (
+		validation *domain.ValidationError
+		notFound   *domain.NotFoundError
+		conflict   *domain.ConflictError
+		forbidden  *domain.ForbiddenError
+		failed     *domain.PreconditionFailedError
+	)
+	switch {
+	case errors.As(err, &validation):
+		if len(validation.Fields) > 0 {
+			return http.StatusBadRequest, validation.Message, validation.Fields
+		}
+		return http.StatusBadRequest, errorDetail(err, validation), nil
+	case errors.As(err, &notFound):
+		return http.StatusNotFound, errorDetail(err, notFound), nil
+	case errors.As(err, &conflict):
+		return http.StatusConflict, errorDetail(err, conflict), nil
+	case errors.As(err, &forbidden):
+		return http.StatusForbidden, errorDetail(err, forbidden), nil
+	case errors.As(err, &failed):
+		return http.StatusPreconditionFailed, errorDetail(err, failed), nil
+	}
+	return http.StatusInternalServerError, "", nil
+}
+
+// errorDetail returns the message of the domain error target found in err, along with the context
+// added after it by the errors wrapping it (fmt.Errorf("%w: ...")), but without the
+// "failed to ..." prefixes of the layers it went through.
+func errorDetail(err, target error) string {
+	message := target.Error()
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if s := e.Error(); strings.HasPrefix(s, message) {
+			return s
+		}
+	}
+	return message
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/igoventura/fintrack-api/internal/api/dto"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantDetail string
+		wantFields map[string]string
+	}{
+		{
+			name:       "not found",
+			err:        fmt.Errorf("service failed to get account: %w", domain.ErrAccountNotFound),
+			wantStatus: http.StatusNotFound,
+			wantDetail: "account not found",
+		},
+		{
+			name:       "validation with fields",
+			err:        domain.InvalidField("category_id", "category not found"),
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "validation failed",
+			wantFields: map[string]string{"category_id": "category not found"},
+		},
+		{
+			name:       "validation with context",
+			err:        fmt.Errorf("service failed: %w", fmt.Errorf("%w: min_amount is greater than max_amount", domain.ErrInvalidTransactionFilter)),
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "invalid transaction filter: min_amount is greater than max_amount",
+		},
+		{
+			name:       "conflict",
+			err:        domain.ErrOccurrencePaid,
+			wantStatus: http.StatusConflict,
+			wantDetail: "occurrence is already paid",
+		},
+		{
+			name:       "forbidden",
+			err:        domain.ErrForbidden,
+			wantStatus: http.StatusForbidden,
+			wantDetail: "insufficient permissions",
+		},
+		{
+			name:       "precondition failed",
+			err:        fmt.Errorf("service failed to update account: %w", domain.ErrVersionMismatch),
+			wantStatus: http.StatusPreconditionFailed,
+			wantDetail: "resource was modified since it was read",
+		},
+		{
+			name:       "unexpected",
+			err:        errors.New("connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "Failed to get account",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(ErrorHandler())
+			r.GET("/accounts/:id", func(c *gin.Context) {
+				c.Error(tt.err).SetMeta("Failed to get account")
+			})
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/accounts/1", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status: got %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+				t.Errorf("content type: got %q, want %q", ct, ProblemContentType)
+			}
+			var problem dto.ProblemResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("invalid body %q: %v", rec.Body.String(), err)
+			}
+			if problem.Status != tt.wantStatus || problem.Title != http.StatusText(tt.wantStatus) || problem.Instance != "/accounts/1" {
+				t.Errorf("problem: got %+v", problem)
+			}
+			if problem.Detail != tt.wantDetail {
+				t.Errorf("detail: got %q, want %q", problem.Detail, tt.wantDetail)
+			}
+			if len(problem.Errors) != len(tt.wantFields) {
+				t.Errorf("errors: got %v, want %v", problem.Errors, tt.wantFields)
+			}
+			for field, msg := range tt.wantFields {
+				if problem.Errors[field] != msg {
+					t.Errorf("errors[%s]: got %q, want %q", field, problem.Errors[field], msg)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a previous request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultIdempotencyKeyTTL is how long a key and its response are kept.
+	DefaultIdempotencyKeyTTL = 24 * time.Hour
+
+	// idempotencyLockTimeout is how long a request holds its key before the reservation is considered
+	// abandoned (e.g. the server died mid-request) and a retry may take it over.
+	idempotencyLockTimeout = time.Minute
+
+	maxIdempotencyKeyLength = 255
+)
+
+type IdempotencyMiddleware struct {
+	repo domain.IdempotencyKeyRepository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewIdempotencyMiddleware creates the idempotency middleware. Keys and their responses are kept for ttl.
+func NewIdempotencyMiddleware(repo domain.IdempotencyKeyRepository, ttl time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{repo: repo, ttl: ttl, now: time.Now}
+}
+
+// Handle makes a request sent with an Idempotency-Key header run at most once per tenant and user.
+// A repeat with the same method, path and body gets the stored response back (with Idempotent-Replayed: true);
+// reusing the key for a different request, or while the first one is in progress, returns 409.
+// Only responses below 500 written by the handler are stored: when the request fails, the key is
+// released so that the client can retry. It must run after the auth and tenant middleware.
+func (m *IdempotencyMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			AbortWithProblem(c, http.StatusBadRequest, "idempotency key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			AbortWithProblem(c, http.StatusBadRequest, "failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		now := m.now()
+		record := &domain.IdempotencyKey{
+			TenantID:    domain.GetTenantID(ctx),
+			UserID:      domain.GetUserID(ctx),
+			Key:         key,
+			RequestHash: requestHash(c.Request.Method, c.Request.URL.Path, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(m.ttl),
+		}
+		existing, err := m.repo.Reserve(ctx, record, now.Add(-idempotencyLockTimeout))
+		if err != nil {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+			AbortWithProblem(c, http.StatusInternalServerError, "failed to check idempotency key")
+			return
+		}
+		if existing != nil {
+			replay(c, record, existing)
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		// The outcome is recorded even if the client went away, so a retry finds it.
+		ctx = context.WithoutCancel(ctx)
+		if !writer.Written() || len(c.Errors) > 0 || writer.Status() >= http.StatusInternalServerError {
+			err = m.repo.Release(ctx, record)
+		} else {
+			completedAt := m.now()
+			record.StatusCode = writer.Status()
+			record.ContentType = writer.Header().Get("Content-Type")
+			record.ResponseBody = writer.body.Bytes()
+			record.CompletedAt = &completedAt
+			err = m.repo.Complete(ctx, record)
+		}
+		if err != nil {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		}
+	}
+}
+
+// replay answers a request whose key is already held by existing.
+func replay(c *gin.Context, record, existing *domain.IdempotencyKey) {
+	switch {
+	case existing.RequestHash != record.RequestHash:
+		AbortWithProblem(c, http.StatusConflict, domain.ErrIdempotencyKeyReused.Error())
+	case !existing.Completed():
+		AbortWithProblem(c, http.StatusConflict, domain.ErrIdempotencyKeyInProgress.Error())
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(existing.StatusCode, existing.ContentType, existing.ResponseBody)
+		c.Abort()
+	}
+}
+
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter keeps a copy of the response body.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
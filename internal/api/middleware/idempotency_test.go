@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// mockIdempotencyRepo keeps keys in memory, ignoring expiry.
+type mockIdempotencyRepo struct {
+	domain.IdempotencyKeyRepository
+	keys map[string]*domain.IdempotencyKey
+}
+
+func (m *mockIdempotencyRepo) Reserve(ctx context.Context, key *domain.IdempotencyKey, staleBefore time.Time) (*domain.IdempotencyKey, error) {
+	id := key.TenantID + "/" + key.UserID + "/" + key.Key
+	if existing, ok := m.keys[id]; ok {
+		return existing, nil
+	}
+	key.ID = id
+	stored := *key
+	m.keys[id] = &stored
+	return nil, nil
+}
+
+func (m *mockIdempotencyRepo) Complete(ctx context.Context, key *domain.IdempotencyKey) error {
+	stored := *key
+	m.keys[key.ID] = &stored
+	return nil
+}
+
+func (m *mockIdempotencyRepo) Release(ctx context.Context, key *domain.IdempotencyKey) error {
+	delete(m.keys, key.ID)
+	return nil
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockIdempotencyRepo{keys: make(map[string]*domain.IdempotencyKey)}
+	m := NewIdempotencyMiddleware(repo, time.Hour)
+	created := 0
+	r := gin.New()
+	r.Use(ErrorHandler(), func(c *gin.Context) {
+		ctx := domain.WithUserID(c.Request.Context(), "user-1")
+		c.Request = c.Request.WithContext(domain.WithTenantID(ctx, "tenant-1"))
+	})
+	r.POST("/transactions", m.Handle(), func(c *gin.Context) {
+		if c.Query("fail") != "" {
+			c.Error(domain.NewValidationError("invalid request payload"))
+			return
+		}
+		created++
+		c.JSON(http.StatusCreated, gin.H{"n": created})
+	})
+
+	send := func(key, body, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/transactions"+query, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name         string
+		key, body    string
+		query        string
+		wantStatus   int
+		wantBody     string
+		wantReplayed bool
+	}{
+		{name: "first request", key: "k1", body: `{"amount":"10"}`, wantStatus: http.StatusCreated, wantBody: `{"n":1}`},
+		{name: "repeat is replayed", key: "k1", body: `{"amount":"10"}`, wantStatus: http.StatusCreated, wantBody: `{"n":1}`, wantReplayed: true},
+		{name: "same key with a different body", key: "k1", body: `{"amount":"20"}`, wantStatus: http.StatusConflict},
+		{name: "without a key", body: `{"amount":"10"}`, wantStatus: http.StatusCreated, wantBody: `{"n":2}`},
+		{name: "failed request", key: "k2", body: `{}`, query: "?fail=1", wantStatus: http.StatusBadRequest},
+		{name: "failed request releases the key", key: "k2", body: `{}`, wantStatus: http.StatusCreated, wantBody: `{"n":3}`},
+		{name: "key too long", key: strings.Repeat("k", 256), body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := send(tt.key, tt.body, tt.query)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status: got %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body: got %s, want %s", rec.Body.String(), tt.wantBody)
+			}
+			if replayed := rec.Header().Get(IdempotentReplayedHeader) == "true"; replayed != tt.wantReplayed {
+				t.Errorf("replayed: got %v, want %v", replayed, tt.wantReplayed)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_InProgress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockIdempotencyRepo{keys: map[string]*domain.IdempotencyKey{
+		"/user-1/k1": {Key: "k1", UserID: "user-1", RequestHash: requestHash(http.MethodPost, "/tenants", []byte(`{}`))},
+	}}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(domain.WithUserID(c.Request.Context(), "user-1"))
+	})
+	r.POST("/tenants", NewIdempotencyMiddleware(repo, time.Hour).Handle(), func(c *gin.Context) {
+		t.Error("handler ran while the key was in progress")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/tenants", strings.NewReader(`{}`))
+	req.Header.Set(IdempotencyKeyHeader, "k1")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status: got %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
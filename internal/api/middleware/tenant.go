@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/igoventura/fintrack-api/domain"
@@ -9,30 +12,179 @@ import (
 
 const (
 	TenantIDHeader = "X-Tenant-ID"
+
+	// DefaultMembershipCacheTTL is how long a verified tenant membership is trusted before it is checked again.
+	DefaultMembershipCacheTTL = 30 * time.Second
 )
 
 type TenantMiddleware struct {
 	tenantRepo domain.TenantRepository
+	userRepo   domain.UserRepository
+	cache      *membershipCache
 }
 
-func NewTenantMiddleware(tenantRepo domain.TenantRepository) *TenantMiddleware {
-	return &TenantMiddleware{tenantRepo: tenantRepo}
+// NewTenantMiddleware creates the tenant middleware.
+// Verified memberships are cached for cacheTTL; a zero TTL disables the cache.
+func NewTenantMiddleware(tenantRepo domain.TenantRepository, userRepo domain.UserRepository, cacheTTL time.Duration) *TenantMiddleware {
+	return &TenantMiddleware{
+		tenantRepo: tenantRepo,
+		userRepo:   userRepo,
+		cache:      newMembershipCache(cacheTTL),
+	}
 }
 
+// Handle resolves the X-Tenant-ID header into the request context.
+// For authenticated requests the user must be an active member of the tenant (403 otherwise),
+// and the user's role is added to the context; unauthenticated routes only check that the tenant exists.
 func (m *TenantMiddleware) Handle(skipValidation bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tenantID := c.GetHeader(TenantIDHeader)
-		if tenantID != "" {
-			if _, err := m.tenantRepo.GetByID(c.Request.Context(), tenantID); err != nil {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "tenant ID is not valid"})
+		if tenantID == "" {
+			if !skipValidation {
+				AbortWithProblem(c, http.StatusUnauthorized, "tenant ID is required")
+				return
+			}
+			c.Next()
+			return
+		}
+		m.resolve(c, tenantID)
+	}
+}
+
+// HandlePath works like Handle but takes the tenant ID from a path parameter (e.g. /tenants/:id/members).
+// A X-Tenant-ID header, if sent, must match it.
+func (m *TenantMiddleware) HandlePath(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.Param(param)
+		if header := c.GetHeader(TenantIDHeader); header != "" && header != tenantID {
+			AbortWithProblem(c, http.StatusBadRequest, "tenant ID header does not match the path")
+			return
+		}
+		m.resolve(c, tenantID)
+	}
+}
+
+func (m *TenantMiddleware) resolve(c *gin.Context, tenantID string) {
+	ctx := c.Request.Context()
+	if userID := domain.GetUserID(ctx); userID != "" {
+		role, err := m.membershipRole(c, userID, tenantID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotTenantMember) {
+				AbortWithProblem(c, http.StatusForbidden, "user is not a member of this tenant")
 				return
 			}
-			ctx := domain.WithTenantID(c.Request.Context(), tenantID)
-			c.Request = c.Request.WithContext(ctx)
-		} else if !skipValidation {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "tenant ID is required"})
+			AbortWithProblem(c, http.StatusInternalServerError, "failed to verify tenant membership")
+			return
+		}
+		ctx = domain.WithRole(ctx, role)
+	} else if _, err := m.tenantRepo.GetByID(ctx, tenantID); err != nil {
+		AbortWithProblem(c, http.StatusUnauthorized, "tenant ID is not valid")
+		return
+	}
+	ctx = domain.WithTenantID(ctx, tenantID)
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
+
+func (m *TenantMiddleware) membershipRole(c *gin.Context, userID, tenantID string) (domain.Role, error) {
+	if role, ok := m.cache.get(userID, tenantID); ok {
+		return role, nil
+	}
+	ut, err := m.userRepo.GetUserTenant(c.Request.Context(), userID, tenantID)
+	if err != nil {
+		return "", err
+	}
+	m.cache.set(userID, tenantID, ut.Role)
+	return ut.Role, nil
+}
+
+// InvalidateMembership drops the cached membership of a user in a tenant, e.g. after a role change.
+// Only the cache of this process is cleared; other instances keep theirs until it expires.
+func (m *TenantMiddleware) InvalidateMembership(userID, tenantID string) {
+	m.cache.delete(userID, tenantID)
+}
+
+// RequirePermission aborts with 403 unless the role of the user in the current tenant grants p.
+// It must run after the tenant middleware.
+func RequirePermission(p domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !domain.GetRole(c.Request.Context()).Can(p) {
+			AbortWithProblem(c, http.StatusForbidden, "insufficient permissions")
 			return
 		}
 		c.Next()
 	}
 }
+
+// membershipCache remembers verified (user, tenant) memberships and their roles for a short time.
+// Only positive results are cached, so a newly added member is never rejected,
+// and a removed member or a role change takes at most one TTL to apply.
+type membershipCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[membershipKey]membershipEntry
+}
+
+type membershipEntry struct {
+	role      domain.Role
+	expiresAt time.Time
+}
+
+type membershipKey struct {
+	userID   string
+	tenantID string
+}
+
+func newMembershipCache(ttl time.Duration) *membershipCache {
+	return &membershipCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[membershipKey]membershipEntry),
+	}
+}
+
+func (c *membershipCache) get(userID, tenantID string) (domain.Role, bool) {
+	if c.ttl <= 0 {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := membershipKey{userID: userID, tenantID: tenantID}
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.role, true
+}
+
+func (c *membershipCache) set(userID, tenantID string, role domain.Role) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	// Drop expired entries now and then so the map doesn't grow unbounded.
+	if len(c.entries) >= 1024 {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[membershipKey{userID: userID, tenantID: tenantID}] = membershipEntry{role: role, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *membershipCache) delete(userID, tenantID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, membershipKey{userID: userID, tenantID: tenantID})
+}
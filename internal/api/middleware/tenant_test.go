@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type mockUserRepo struct {
+	domain.UserRepository
+	GetUserTenantFn func(ctx context.Context, userID, tenantID string) (*domain.UserTenant, error)
+	calls           int
+}
+
+func (m *mockUserRepo) GetUserTenant(ctx context.Context, userID, tenantID string) (*domain.UserTenant, error) {
+	m.calls++
+	return m.GetUserTenantFn(ctx, userID, tenantID)
+}
+
+func TestTenantMiddleware_Membership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	members := map[string]bool{"user-1/tenant-1": true}
+	newRepo := func() *mockUserRepo {
+		return &mockUserRepo{
+			GetUserTenantFn: func(ctx context.Context, userID, tenantID string) (*domain.UserTenant, error) {
+				if userID == "user-db-error" {
+					return nil, errors.New("connection refused")
+				}
+				if !members[userID+"/"+tenantID] {
+					return nil, domain.ErrNotTenantMember
+				}
+				return &domain.UserTenant{UserID: userID, TenantID: tenantID, Role: domain.RoleEditor}, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		userID     string
+		tenantID   string
+		wantStatus int
+	}{
+		{name: "member", userID: "user-1", tenantID: "tenant-1", wantStatus: http.StatusOK},
+		{name: "non-member", userID: "user-2", tenantID: "tenant-1", wantStatus: http.StatusForbidden},
+		{name: "missing tenant header", userID: "user-1", wantStatus: http.StatusUnauthorized},
+		{name: "lookup failure", userID: "user-db-error", tenantID: "tenant-1", wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewTenantMiddleware(nil, newRepo(), time.Minute)
+			rec := serveWithUser(m, tt.userID, tt.tenantID)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status: got %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestTenantMiddleware_MembershipCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockUserRepo{
+		GetUserTenantFn: func(ctx context.Context, userID, tenantID string) (*domain.UserTenant, error) {
+			return &domain.UserTenant{UserID: userID, TenantID: tenantID}, nil
+		},
+	}
+	m := NewTenantMiddleware(nil, repo, time.Minute)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m.cache.now = func() time.Time { return now }
+
+	serveWithUser(m, "user-1", "tenant-1")
+	serveWithUser(m, "user-1", "tenant-1")
+	if repo.calls != 1 {
+		t.Errorf("expected membership to be cached, got %d lookups", repo.calls)
+	}
+
+	now = now.Add(time.Minute)
+	serveWithUser(m, "user-1", "tenant-1")
+	if repo.calls != 2 {
+		t.Errorf("expected membership to be checked again after the TTL, got %d lookups", repo.calls)
+	}
+
+	m.InvalidateMembership("user-1", "tenant-1")
+	serveWithUser(m, "user-1", "tenant-1")
+	if repo.calls != 3 {
+		t.Errorf("expected membership to be checked again after invalidation, got %d lookups", repo.calls)
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		role       domain.Role
+		permission domain.Permission
+		wantStatus int
+	}{
+		{role: domain.RoleViewer, permission: domain.PermissionRead, wantStatus: http.StatusOK},
+		{role: domain.RoleViewer, permission: domain.PermissionWrite, wantStatus: http.StatusForbidden},
+		{role: domain.RoleEditor, permission: domain.PermissionWrite, wantStatus: http.StatusOK},
+		{role: domain.RoleEditor, permission: domain.PermissionDeleteAccounts, wantStatus: http.StatusForbidden},
+		{role: domain.RoleAdmin, permission: domain.PermissionManageMembers, wantStatus: http.StatusOK},
+		{role: domain.RoleAdmin, permission: domain.PermissionManageOwners, wantStatus: http.StatusForbidden},
+		{role: domain.RoleOwner, permission: domain.PermissionManageOwners, wantStatus: http.StatusOK},
+		{role: "", permission: domain.PermissionRead, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"/"+string(tt.permission), func(t *testing.T) {
+			repo := &mockUserRepo{
+				GetUserTenantFn: func(ctx context.Context, userID, tenantID string) (*domain.UserTenant, error) {
+					return &domain.UserTenant{UserID: userID, TenantID: tenantID, Role: tt.role}, nil
+				},
+			}
+			m := NewTenantMiddleware(nil, repo, 0)
+			rec := serveWithUser(m, "user-1", "tenant-1", RequirePermission(tt.permission))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status: got %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func serveWithUser(m *TenantMiddleware, userID, tenantID string, guards ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(domain.WithUserID(c.Request.Context(), userID))
+	})
+	handlers := append([]gin.HandlerFunc{m.Handle(false)}, guards...)
+	handlers = append(handlers, func(c *gin.Context) {
+		if domain.GetTenantID(c.Request.Context()) != tenantID {
+			c.Status(http.StatusTeapot)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	r.GET("/", handlers...)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if tenantID != "" {
+		req.Header.Set(TenantIDHeader, tenantID)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
@@ -7,82 +7,196 @@ import (
 	"github.com/MarceloPetrucio/go-scalar-api-reference"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/igoventura/fintrack-api/domain"
 	"github.com/igoventura/fintrack-api/internal/api/handler"
 	"github.com/igoventura/fintrack-api/internal/api/middleware"
 )
 
-func NewRouter(accountHandler *handler.AccountHandler, authHandler *handler.AuthHandler, categoryHandler *handler.CategoryHandler, tagHandler *handler.TagHandler, tenantHandler *handler.TenantHandler, transactionHandler *handler.TransactionHandler, authMiddleware *middleware.AuthMiddleware, tenantMiddleware *middleware.TenantMiddleware, userHandler *handler.UserHandler) *gin.Engine {
+func NewRouter(accountHandler *handler.AccountHandler, adminHandler *handler.AdminHandler, authHandler *handler.AuthHandler, budgetHandler *handler.BudgetHandler, categoryHandler *handler.CategoryHandler, goalHandler *handler.GoalHandler, healthHandler *handler.HealthHandler, importHandler *handler.ImportHandler, invitationHandler *handler.InvitationHandler, recurrenceHandler *handler.RecurrenceHandler, reportHandler *handler.ReportHandler, statementHandler *handler.StatementHandler, tagHandler *handler.TagHandler, tenantHandler *handler.TenantHandler, transactionHandler *handler.TransactionHandler, authMiddleware *middleware.AuthMiddleware, tenantMiddleware *middleware.TenantMiddleware, idempotencyMiddleware *middleware.IdempotencyMiddleware, userHandler *handler.UserHandler, adminUserIDs []string) *gin.Engine {
 	r := gin.Default()
 
 	// CORS configuration
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH", "HEAD"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "Accept", "Cache-Control", "X-Requested-With", "X-Tenant-ID", "DNT", "Keep-Alive", "User-Agent", "If-Modified-Since", "sec-ch-ua", "sec-ch-ua-mobile", "sec-ch-ua-platform"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "Accept", "Cache-Control", "X-Requested-With", "X-Tenant-ID", "Idempotency-Key", "DNT", "Keep-Alive", "User-Agent", "If-Modified-Since", "If-Match", "If-None-Match", "sec-ch-ua", "sec-ch-ua-mobile", "sec-ch-ua-platform"},
+		ExposeHeaders:    []string{"Content-Length", "ETag", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.String(http.StatusOK, "ok")
-	})
+	// Errors recorded by the handlers are rendered as RFC 7807 problem details
+	r.Use(middleware.ErrorHandler())
+	handler.RegisterFieldNames()
+
+	// Role-based guards, evaluated after the tenant middleware
+	canRead := middleware.RequirePermission(domain.PermissionRead)
+	canWrite := middleware.RequirePermission(domain.PermissionWrite)
+	canDeleteAccounts := middleware.RequirePermission(domain.PermissionDeleteAccounts)
+	canManageMembers := middleware.RequirePermission(domain.PermissionManageMembers)
+
+	// Create endpoints replay the response of a request retried with the same Idempotency-Key
+	idempotent := idempotencyMiddleware.Handle()
+
+	// Health checks: liveness (process up) and readiness (database reachable, not shutting down)
+	r.GET("/health", healthHandler.Live)
+	r.GET("/health/live", healthHandler.Live)
+	r.GET("/health/ready", healthHandler.Ready)
 
 	// Tenant routes
 	tenants := r.Group("/tenants")
 	tenants.Use(authMiddleware.Handle())
 	{
-		tenants.POST("", tenantHandler.Create)
+		tenants.POST("", idempotent, tenantHandler.Create)
+
+		members := tenants.Group("/:id/members", tenantMiddleware.HandlePath("id"))
+		members.GET("", canRead, tenantHandler.ListMembers)
+		members.PUT("/:userId", canManageMembers, tenantHandler.UpdateMemberRole)
+		members.DELETE("/:userId", canManageMembers, tenantHandler.RemoveMember)
+
+		invitations := tenants.Group("/:id/invitations", tenantMiddleware.HandlePath("id"))
+		invitations.GET("", canManageMembers, invitationHandler.ListPending)
+		invitations.POST("", canManageMembers, idempotent, invitationHandler.Create)
+		invitations.DELETE("/:invitationId", canManageMembers, invitationHandler.Revoke)
+	}
+
+	// Invitation routes (no tenant required: the invitation determines the tenant)
+	invitations := r.Group("/invitations")
+	invitations.Use(authMiddleware.Handle(), tenantMiddleware.Handle(true))
+	{
+		invitations.POST("/:token/accept", invitationHandler.Accept)
 	}
 
 	// Account routes
 	accounts := r.Group("/accounts")
 	accounts.Use(authMiddleware.Handle(), tenantMiddleware.Handle(false))
 	{
-		accounts.GET("", accountHandler.List)
-		accounts.POST("", accountHandler.Create)
-		accounts.GET("/:id", accountHandler.Get)
-		accounts.PUT("/:id", accountHandler.Update)
-		accounts.DELETE("/:id", accountHandler.Delete)
+		accounts.GET("", canRead, accountHandler.List)
+		accounts.POST("", canWrite, idempotent, accountHandler.Create)
+		accounts.GET("/:id", canRead, accountHandler.Get)
+		accounts.GET("/:id/balance", canRead, accountHandler.GetBalance)
+		accounts.GET("/:id/credit-card", canRead, accountHandler.GetCreditCard)
+		accounts.PUT("/:id/credit-card", canWrite, accountHandler.PutCreditCard)
+		accounts.DELETE("/:id/credit-card", canWrite, accountHandler.DeleteCreditCard)
+		accounts.GET("/:id/statements", canRead, statementHandler.List)
+		accounts.GET("/:id/statements/:period", canRead, statementHandler.Get)
+		accounts.PUT("/:id", canWrite, accountHandler.Update)
+		accounts.PATCH("/:id", canWrite, accountHandler.Patch)
+		accounts.DELETE("/:id", canDeleteAccounts, accountHandler.Delete)
 	}
 
 	// Category routes
 	categories := r.Group("/categories")
 	categories.Use(authMiddleware.Handle(), tenantMiddleware.Handle(false))
 	{
-		categories.GET("", categoryHandler.ListCategories)
-		categories.POST("", categoryHandler.CreateCategory)
-		categories.GET("/:id", categoryHandler.GetCategory)
-		categories.PUT("/:id", categoryHandler.UpdateCategory)
-		categories.DELETE("/:id", categoryHandler.DeleteCategory)
+		categories.GET("", canRead, categoryHandler.ListCategories)
+		categories.POST("", canWrite, idempotent, categoryHandler.CreateCategory)
+		categories.GET("/:id", canRead, categoryHandler.GetCategory)
+		categories.PUT("/:id", canWrite, categoryHandler.UpdateCategory)
+		categories.PATCH("/:id", canWrite, categoryHandler.PatchCategory)
+		categories.DELETE("/:id", canWrite, categoryHandler.DeleteCategory)
 	}
 
 	// Tag routes
 	tags := r.Group("/tags")
 	tags.Use(authMiddleware.Handle(), tenantMiddleware.Handle(false))
 	{
-		tags.GET("", tagHandler.ListTags)
-		tags.POST("", tagHandler.CreateTag)
-		tags.GET("/:id", tagHandler.GetTag)
-		tags.PUT("/:id", tagHandler.UpdateTag)
-		tags.DELETE("/:id", tagHandler.DeleteTag)
+		tags.GET("", canRead, tagHandler.ListTags)
+		tags.POST("", canWrite, idempotent, tagHandler.CreateTag)
+		tags.GET("/:id", canRead, tagHandler.GetTag)
+		tags.PUT("/:id", canWrite, tagHandler.UpdateTag)
+		tags.PATCH("/:id", canWrite, tagHandler.PatchTag)
+		tags.DELETE("/:id", canWrite, tagHandler.DeleteTag)
 	}
 
 	// Transaction routes
 	transactions := r.Group("/transactions")
 	transactions.Use(authMiddleware.Handle(), tenantMiddleware.Handle(false))
 	{
-		transactions.GET("", transactionHandler.List)
-		transactions.POST("", transactionHandler.Create)
-		transactions.GET("/:id", transactionHandler.GetByID)
-		transactions.PUT("/:id", transactionHandler.Update)
-		transactions.DELETE("/:id", transactionHandler.Delete)
+		transactions.GET("", canRead, transactionHandler.List)
+		transactions.POST("", canWrite, idempotent, transactionHandler.Create)
+		transactions.POST("/settle", canWrite, idempotent, transactionHandler.BulkSettle)
+		transactions.GET("/:id", canRead, transactionHandler.GetByID)
+		transactions.PUT("/:id", canWrite, transactionHandler.Update)
+		transactions.PATCH("/:id", canWrite, transactionHandler.Patch)
+		transactions.DELETE("/:id", canWrite, transactionHandler.Delete)
+		transactions.GET("/:id/payments", canRead, transactionHandler.ListPayments)
+		transactions.POST("/:id/settle", canWrite, idempotent, transactionHandler.Settle)
+		transactions.POST("/:id/unsettle", canWrite, transactionHandler.Unsettle)
+		transactions.GET("/:id/series", canRead, transactionHandler.GetSeries)
+		transactions.PATCH("/:id/series", canWrite, transactionHandler.UpdateSeries)
+		transactions.POST("/:id/series/cancel", canWrite, transactionHandler.CancelSeries)
+		transactions.POST("/:id/series/reschedule", canWrite, transactionHandler.RescheduleSeries)
+		transactions.POST("/:id/series/payoff", canWrite, transactionHandler.PayOffSeries)
+	}
+
+	// Budget routes
+	budgets := r.Group("/budgets")
+	budgets.Use(authMiddleware.Handle(), tenantMiddleware.Handle(false))
+	{
+		budgets.GET("", canRead, budgetHandler.List)
+		budgets.POST("", canWrite, idempotent, budgetHandler.Create)
+		budgets.GET("/status", canRead, budgetHandler.Status)
+		budgets.GET("/:id", canRead, budgetHandler.GetByID)
+		budgets.PUT("/:id", canWrite, budgetHandler.Update)
+		budgets.DELETE("/:id", canWrite, budgetHandler.Delete)
+	}
+
+	// Goal routes
+	goals := r.Group("/goals")
+	goals.Use(authMiddleware.Handle(), tenantMiddleware.Handle(false))
+	{
+		goals.GET("", canRead, goalHandler.List)
+		goals.POST("", canWrite, idempotent, goalHandler.Create)
+		goals.GET("/:id", canRead, goalHandler.GetByID)
+		goals.GET("/:id/progress", canRead, goalHandler.Progress)
+		goals.PUT("/:id", canWrite, goalHandler.Update)
+		goals.DELETE("/:id", canWrite, goalHandler.Delete)
+	}
+
+	// Import routes
+	imports := r.Group("/imports")
+	imports.Use(authMiddleware.Handle(), tenantMiddleware.Handle(false))
+	{
+		imports.POST("", canWrite, idempotent, importHandler.Create)
+		imports.GET("/:id", canRead, importHandler.GetByID)
+		imports.POST("/:id/commit", canWrite, importHandler.Commit)
+		imports.DELETE("/:id", canWrite, importHandler.Delete)
+
+		profiles := imports.Group("/profiles")
+		profiles.GET("", canRead, importHandler.ListProfiles)
+		profiles.POST("", canWrite, idempotent, importHandler.CreateProfile)
+		profiles.GET("/:id", canRead, importHandler.GetProfile)
+		profiles.PUT("/:id", canWrite, importHandler.UpdateProfile)
+		profiles.DELETE("/:id", canWrite, importHandler.DeleteProfile)
+	}
+
+	// Recurrence routes
+	recurrences := r.Group("/recurrences")
+	recurrences.Use(authMiddleware.Handle(), tenantMiddleware.Handle(false))
+	{
+		recurrences.GET("", canRead, recurrenceHandler.List)
+		recurrences.POST("", canWrite, idempotent, recurrenceHandler.Create)
+		recurrences.POST("/materialize", canWrite, recurrenceHandler.Materialize)
+		recurrences.GET("/:id", canRead, recurrenceHandler.GetByID)
+		recurrences.PUT("/:id", canWrite, recurrenceHandler.Update)
+		recurrences.DELETE("/:id", canWrite, recurrenceHandler.Delete)
+		recurrences.POST("/:id/skip", canWrite, recurrenceHandler.SkipOccurrence)
+		recurrences.POST("/:id/amount-changes", canWrite, recurrenceHandler.ChangeAmount)
+	}
+
+	// Report routes
+	reports := r.Group("/reports")
+	reports.Use(authMiddleware.Handle(), tenantMiddleware.Handle(false))
+	{
+		reports.GET("/spend-by-category", canRead, reportHandler.SpendByCategory)
+		reports.GET("/income-expense", canRead, reportHandler.IncomeExpense)
+		reports.GET("/cash-flow", canRead, reportHandler.CashFlow)
+		reports.GET("/top-tags", canRead, reportHandler.TopTags)
 	}
 
 	// Auth routes
 	auth := r.Group("/auth")
-	auth.Use(tenantMiddleware.Handle(true))
 	{
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
@@ -98,6 +212,13 @@ func NewRouter(accountHandler *handler.AccountHandler, authHandler *handler.Auth
 		users.GET("/tenants", userHandler.ListUserTenants)
 	}
 
+	// Admin routes (operators listed in ADMIN_USER_IDS, not tenant-scoped)
+	admin := r.Group("/admin")
+	admin.Use(authMiddleware.Handle(), middleware.RequireAdmin(adminUserIDs))
+	{
+		admin.GET("/jobs", adminHandler.ListJobs)
+	}
+
 	// Documentation
 	r.GET("/docs", func(c *gin.Context) {
 		htmlContent, err := scalar.ApiReferenceHTML(&scalar.Options{
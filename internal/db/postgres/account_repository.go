@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/igoventura/fintrack-api/domain"
 )
@@ -16,20 +17,28 @@ func NewAccountRepository(db *DB) *AccountRepository {
 }
 
 func (r *AccountRepository) GetByID(ctx context.Context, id, tenantID string) (*domain.Account, error) {
-	query := `SELECT id, tenant_id, name, initial_balance, color, currency, icon, type, created_at, created_by, updated_at, updated_by, deactivated_at, deactivated_by FROM accounts WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	query := `SELECT id, tenant_id, name, initial_balance, color, currency, icon, type, created_at, created_by, updated_at, updated_by, version, deactivated_at, deactivated_by FROM accounts WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
 	var a domain.Account
-	err := r.db.Pool.QueryRow(ctx, query, id, tenantID).Scan(
-		&a.ID, &a.TenantID, &a.Name, &a.InitialBalance, &a.Color, &a.Currency, &a.Icon, &a.Type, &a.CreatedAt, &a.CreatedBy, &a.UpdatedAt, &a.UpdatedBy, &a.DeactivatedAt, &a.DeactivatedBy,
+	err := r.db.conn(ctx).QueryRow(ctx, query, id, tenantID).Scan(
+		&a.ID, &a.TenantID, &a.Name, &a.InitialBalance, &a.Color, &a.Currency, &a.Icon, &a.Type, &a.CreatedAt, &a.CreatedBy, &a.UpdatedAt, &a.UpdatedBy, &a.Version, &a.DeactivatedAt, &a.DeactivatedBy,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get account by id: %w", err)
+		return nil, translateError(err, domain.ErrAccountNotFound, "failed to get account by id")
 	}
+	a.InitialBalance.Currency = a.Currency
 	return &a, nil
 }
 
-func (r *AccountRepository) List(ctx context.Context, tenantID string) ([]domain.Account, error) {
-	query := `SELECT id, tenant_id, name, initial_balance, color, currency, icon, type, created_at, created_by, updated_at, updated_by, deactivated_at, deactivated_by FROM accounts WHERE tenant_id = $1 AND deactivated_at IS NULL`
-	rows, err := r.db.Pool.Query(ctx, query, tenantID)
+func (r *AccountRepository) List(ctx context.Context, tenantID string, page domain.PageRequest) (*domain.Page[domain.Account], error) {
+	from := `FROM accounts WHERE tenant_id = $1 AND deactivated_at IS NULL`
+	args := []any{tenantID}
+
+	after, err := afterName(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	query, queryArgs := nameKeyset.apply(`SELECT id, tenant_id, name, initial_balance, color, currency, icon, type, created_at, created_by, updated_at, updated_by, version, deactivated_at, deactivated_by `+from, args, page, after)
+	rows, err := r.db.conn(ctx).Query(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list accounts: %w", err)
 	}
@@ -38,70 +47,251 @@ func (r *AccountRepository) List(ctx context.Context, tenantID string) ([]domain
 	var accounts []domain.Account
 	for rows.Next() {
 		var a domain.Account
-		if err := rows.Scan(&a.ID, &a.TenantID, &a.Name, &a.InitialBalance, &a.Color, &a.Currency, &a.Icon, &a.Type, &a.CreatedAt, &a.CreatedBy, &a.UpdatedAt, &a.UpdatedBy, &a.DeactivatedAt, &a.DeactivatedBy); err != nil {
+		if err := rows.Scan(&a.ID, &a.TenantID, &a.Name, &a.InitialBalance, &a.Color, &a.Currency, &a.Icon, &a.Type, &a.CreatedAt, &a.CreatedBy, &a.UpdatedAt, &a.UpdatedBy, &a.Version, &a.DeactivatedAt, &a.DeactivatedBy); err != nil {
 			return nil, fmt.Errorf("failed to scan account: %w", err)
 		}
+		a.InitialBalance.Currency = a.Currency
 		accounts = append(accounts, a)
 	}
-	return accounts, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	result := pageOf(accounts, page, func(a *domain.Account) string { return nameCursor(a.Name, a.ID) })
+	if page.IncludeTotal {
+		if result.Total, err = r.db.countRows(ctx, from, args); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 func (r *AccountRepository) Create(ctx context.Context, a *domain.Account) error {
 	query := `INSERT INTO accounts (tenant_id, name, initial_balance, color, currency, icon, type, created_by, updated_by)
 			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-			  RETURNING id, created_at, updated_at`
-	row := r.db.Pool.QueryRow(ctx, query, a.TenantID, a.Name, a.InitialBalance, a.Color, a.Currency, a.Icon, a.Type, a.CreatedBy, a.UpdatedBy)
-	if err := row.Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to create account: %w", err)
+			  RETURNING id, created_at, updated_at, version`
+	row := r.db.conn(ctx).QueryRow(ctx, query, a.TenantID, a.Name, a.InitialBalance, a.Color, a.Currency, a.Icon, a.Type, a.CreatedBy, a.UpdatedBy)
+	if err := row.Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt, &a.Version); err != nil {
+		return translateError(err, nil, "failed to create account")
 	}
 	return nil
 }
 
 func (r *AccountRepository) Update(ctx context.Context, a *domain.Account) error {
-	query := `UPDATE accounts SET name = $2, initial_balance = $3, color = $4, icon = $5, updated_at = CURRENT_TIMESTAMP, updated_by = $6 WHERE id = $1 AND tenant_id = $7 RETURNING updated_at`
-	row := r.db.Pool.QueryRow(ctx, query, a.ID, a.Name, a.InitialBalance, a.Color, a.Icon, a.UpdatedBy, a.TenantID)
-	if err := row.Scan(&a.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to update account: %w", err)
+	query := `UPDATE accounts SET name = $2, initial_balance = $3, color = $4, icon = $5, updated_at = CURRENT_TIMESTAMP, updated_by = $6, version = version + 1
+			  WHERE id = $1 AND tenant_id = $7 AND ($8 = 0 OR version = $8)
+			  RETURNING updated_at, version`
+	row := r.db.conn(ctx).QueryRow(ctx, query, a.ID, a.Name, a.InitialBalance, a.Color, a.Icon, a.UpdatedBy, a.TenantID, a.Version)
+	if err := row.Scan(&a.UpdatedAt, &a.Version); err != nil {
+		if r.db.versionMismatch(ctx, err, "accounts", a.ID, a.TenantID, a.Version) {
+			return domain.ErrVersionMismatch
+		}
+		return translateError(err, domain.ErrAccountNotFound, "failed to update account")
 	}
 	return nil
 }
 
 func (r *AccountRepository) Delete(ctx context.Context, id, tenantID, userID string) error {
 	query := `UPDATE accounts SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $2 WHERE id = $1 AND tenant_id = $3`
-	_, err := r.db.Pool.Exec(ctx, query, id, userID, tenantID)
+	_, err := r.db.conn(ctx).Exec(ctx, query, id, userID, tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to delete account: %w", err)
 	}
 	return nil
 }
 
-func (r *AccountRepository) GetCreditCardInfo(ctx context.Context, accountID string) (*domain.CreditCardInfo, error) {
-	query := `SELECT id, account_id, last_four, name, brand, closing_date, due_date, created_at, created_by, updated_at, updated_by, deactivated_at, deactivated_by FROM credit_card_info WHERE account_id = $1 AND deactivated_at IS NULL`
+// postingsCTE turns the transactions table and its payment ledger of tenant $1 into signed
+// postings per account. Credits add to from_account_id, debits subtract from it, and payments move
+// the amount from from_account_id to to_account_id. A transfer is two legs: the outgoing one
+// subtracts its amount and fee from its from_account_id, the incoming one adds its amount to its own.
+// Each partial payment posts its amount on its own payment date, from the account it was made from;
+// the rest of the transaction (all of it when it has no payments) posts on the transaction's dates.
+const postingsCTE = `WITH legs AS (
+		SELECT from_account_id, to_account_id, transaction_type, transfer_direction, amount - settled_amount + fee AS amount, due_date, payment_date
+		FROM transactions
+		WHERE tenant_id = $1 AND deactivated_at IS NULL
+		UNION ALL
+		SELECT p.account_id, t.to_account_id, t.transaction_type, t.transfer_direction, p.amount, t.due_date, p.payment_date
+		FROM transaction_payments p
+		JOIN transactions t ON t.id = p.transaction_id
+		WHERE p.tenant_id = $1 AND p.deactivated_at IS NULL AND t.deactivated_at IS NULL
+	), postings AS (
+		SELECT from_account_id AS account_id,
+			   CASE WHEN transaction_type = 'credit' OR transfer_direction = 'in' THEN amount ELSE -amount END AS amount,
+			   due_date, payment_date
+		FROM legs
+		UNION ALL
+		SELECT to_account_id AS account_id, amount, due_date, payment_date
+		FROM legs
+		WHERE to_account_id IS NOT NULL AND transaction_type = 'payment'
+	)`
+
+// balanceQuery derives account balances from their postings.
+// A posting counts as cleared once its payment_date is on or before the as-of date ($2),
+// and as due once its due_date is; everything else only affects the projected balance.
+const balanceQuery = postingsCTE + `
+	SELECT a.id, a.currency, a.initial_balance,
+		   a.initial_balance + COALESCE(SUM(p.amount) FILTER (WHERE p.payment_date <= $2::date OR p.due_date <= $2::date), 0) AS current,
+		   a.initial_balance + COALESCE(SUM(p.amount) FILTER (WHERE p.payment_date <= $2::date), 0) AS cleared,
+		   a.initial_balance + COALESCE(SUM(p.amount), 0) AS projected
+	FROM accounts a
+	LEFT JOIN postings p ON p.account_id = a.id
+	WHERE a.tenant_id = $1 AND a.deactivated_at IS NULL`
+
+func (r *AccountRepository) GetBalance(ctx context.Context, id, tenantID string, asOf time.Time) (*domain.AccountBalance, error) {
+	query := balanceQuery + ` AND a.id = $3 GROUP BY a.id, a.currency, a.initial_balance`
+	b := domain.AccountBalance{AsOf: asOf}
+	err := r.db.conn(ctx).QueryRow(ctx, query, tenantID, asOf, id).Scan(
+		&b.AccountID, &b.Currency, &b.InitialBalance, &b.Current, &b.Cleared, &b.Projected,
+	)
+	if err != nil {
+		return nil, translateError(err, domain.ErrAccountNotFound, "failed to get account balance")
+	}
+	applyBalanceCurrency(&b)
+	return &b, nil
+}
+
+func (r *AccountRepository) ListBalances(ctx context.Context, tenantID string, accountIDs []string, asOf time.Time) ([]domain.AccountBalance, error) {
+	query := balanceQuery + ` AND a.id = ANY($3::uuid[]) GROUP BY a.id, a.currency, a.initial_balance`
+	rows, err := r.db.conn(ctx).Query(ctx, query, tenantID, asOf, accountIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account balances: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []domain.AccountBalance
+	for rows.Next() {
+		b := domain.AccountBalance{AsOf: asOf}
+		if err := rows.Scan(&b.AccountID, &b.Currency, &b.InitialBalance, &b.Current, &b.Cleared, &b.Projected); err != nil {
+			return nil, fmt.Errorf("failed to scan account balance: %w", err)
+		}
+		applyBalanceCurrency(&b)
+		balances = append(balances, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list account balances: %w", err)
+	}
+	return balances, nil
+}
+
+// applyBalanceCurrency tags the scanned amounts with the account currency.
+func applyBalanceCurrency(b *domain.AccountBalance) {
+	b.InitialBalance.Currency = b.Currency
+	b.Current.Currency = b.Currency
+	b.Cleared.Currency = b.Currency
+	b.Projected.Currency = b.Currency
+}
+
+func (r *AccountRepository) GetCreditCardInfo(ctx context.Context, accountID, tenantID string) (*domain.CreditCardInfo, error) {
+	query := `SELECT c.id, c.account_id, c.last_four, c.name, c.brand, c.closing_date, c.due_date, c.created_at, c.created_by, c.updated_at, c.updated_by, c.deactivated_at, c.deactivated_by
+			  FROM credit_card_info c
+			  JOIN accounts a ON a.id = c.account_id
+			  WHERE c.account_id = $1 AND a.tenant_id = $2 AND c.deactivated_at IS NULL`
 	var info domain.CreditCardInfo
-	err := r.db.Pool.QueryRow(ctx, query, accountID).Scan(
+	err := r.db.conn(ctx).QueryRow(ctx, query, accountID, tenantID).Scan(
 		&info.ID, &info.AccountID, &info.LastFour, &info.Name, &info.Brand, &info.ClosingDate, &info.DueDate, &info.CreatedAt, &info.CreatedBy, &info.UpdatedAt, &info.UpdatedBy, &info.DeactivatedAt, &info.DeactivatedBy,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get credit card info: %w", err)
+		return nil, translateError(err, domain.ErrCreditCardInfoNotFound, "failed to get credit card info")
 	}
 	return &info, nil
 }
 
+func (r *AccountRepository) ListCreditCardVersions(ctx context.Context, accountID, tenantID string) ([]domain.CreditCardInfo, error) {
+	query := `SELECT c.id, c.account_id, c.last_four, c.name, c.brand, c.closing_date, c.due_date, c.created_at, c.created_by, c.updated_at, c.updated_by, c.deactivated_at, c.deactivated_by
+			  FROM credit_card_info c
+			  JOIN accounts a ON a.id = c.account_id
+			  WHERE c.account_id = $1 AND a.tenant_id = $2
+			  ORDER BY c.created_at, c.id`
+	rows, err := r.db.conn(ctx).Query(ctx, query, accountID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit card versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []domain.CreditCardInfo
+	for rows.Next() {
+		var info domain.CreditCardInfo
+		if err := rows.Scan(&info.ID, &info.AccountID, &info.LastFour, &info.Name, &info.Brand, &info.ClosingDate, &info.DueDate, &info.CreatedAt, &info.CreatedBy, &info.UpdatedAt, &info.UpdatedBy, &info.DeactivatedAt, &info.DeactivatedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan credit card info: %w", err)
+		}
+		versions = append(versions, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list credit card versions: %w", err)
+	}
+	return versions, nil
+}
+
+func (r *AccountRepository) ListCreditCardInfo(ctx context.Context, tenantID string) ([]domain.CreditCardInfo, error) {
+	query := `SELECT c.id, c.account_id, c.last_four, c.name, c.brand, c.closing_date, c.due_date, c.created_at, c.created_by, c.updated_at, c.updated_by, c.deactivated_at, c.deactivated_by
+			  FROM credit_card_info c
+			  JOIN accounts a ON a.id = c.account_id
+			  WHERE a.tenant_id = $1 AND a.deactivated_at IS NULL AND c.deactivated_at IS NULL`
+	rows, err := r.db.conn(ctx).Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit card info: %w", err)
+	}
+	defer rows.Close()
+
+	var infos []domain.CreditCardInfo
+	for rows.Next() {
+		var info domain.CreditCardInfo
+		if err := rows.Scan(&info.ID, &info.AccountID, &info.LastFour, &info.Name, &info.Brand, &info.ClosingDate, &info.DueDate, &info.CreatedAt, &info.CreatedBy, &info.UpdatedAt, &info.UpdatedBy, &info.DeactivatedAt, &info.DeactivatedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan credit card info: %w", err)
+		}
+		infos = append(infos, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list credit card info: %w", err)
+	}
+	return infos, nil
+}
+
+// UpsertCreditCardInfo stores a new version of the card info.
+// The current version, if any, is deactivated in the same transaction so the history is kept.
+// The account row is locked first, so concurrent upserts of the same card run one after the other.
 func (r *AccountRepository) UpsertCreditCardInfo(ctx context.Context, info *domain.CreditCardInfo) error {
-	query := `INSERT INTO credit_card_info (account_id, last_four, name, brand, closing_date, due_date, created_by, updated_by)
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var accountID string
+	if err := tx.QueryRow(ctx, `SELECT id FROM accounts WHERE id = $1 AND deactivated_at IS NULL FOR UPDATE`, info.AccountID).Scan(&accountID); err != nil {
+		return translateError(err, domain.ErrAccountNotFound, "failed to lock account")
+	}
+
+	deactivateQuery := `UPDATE credit_card_info SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $2 WHERE account_id = $1 AND deactivated_at IS NULL`
+	if _, err := tx.Exec(ctx, deactivateQuery, info.AccountID, info.UpdatedBy); err != nil {
+		return fmt.Errorf("failed to deactivate previous credit card info: %w", err)
+	}
+
+	insertQuery := `INSERT INTO credit_card_info (account_id, last_four, name, brand, closing_date, due_date, created_by, updated_by)
 			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-			  ON CONFLICT (account_id, deactivated_at) DO UPDATE SET
-				last_four = EXCLUDED.last_four,
-				name = EXCLUDED.name,
-				brand = EXCLUDED.brand,
-				closing_date = EXCLUDED.closing_date,
-				due_date = EXCLUDED.due_date,
-				updated_at = CURRENT_TIMESTAMP,
-				updated_by = EXCLUDED.updated_by
 			  RETURNING id, created_at, updated_at`
-	row := r.db.Pool.QueryRow(ctx, query, info.AccountID, info.LastFour, info.Name, info.Brand, info.ClosingDate, info.DueDate, info.CreatedBy, info.UpdatedBy)
+	row := tx.QueryRow(ctx, insertQuery, info.AccountID, info.LastFour, info.Name, info.Brand, info.ClosingDate, info.DueDate, info.CreatedBy, info.UpdatedBy)
 	if err := row.Scan(&info.ID, &info.CreatedAt, &info.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to upsert credit card info: %w", err)
+		return translateError(err, nil, "failed to upsert credit card info")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *AccountRepository) DeleteCreditCardInfo(ctx context.Context, accountID, tenantID, userID string) error {
+	query := `UPDATE credit_card_info c SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $3
+			  FROM accounts a
+			  WHERE c.account_id = $1 AND a.id = c.account_id AND a.tenant_id = $2 AND c.deactivated_at IS NULL`
+	tag, err := r.db.conn(ctx).Exec(ctx, query, accountID, tenantID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete credit card info: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrCreditCardInfoNotFound
 	}
 	return nil
 }
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type BudgetRepository struct {
+	db *DB
+}
+
+func NewBudgetRepository(db *DB) *BudgetRepository {
+	return &BudgetRepository{db: db}
+}
+
+// budgetColumns is the column list read by every budget query, in the order scanBudget expects.
+const budgetColumns = `id, tenant_id, category_id, tag_id, month, end_month, recurring, currency, amount, rollover, created_at, created_by, updated_at, updated_by, version, deactivated_at, deactivated_by`
+
+func scanBudget(row pgx.Row) (*domain.Budget, error) {
+	var b domain.Budget
+	err := row.Scan(&b.ID, &b.TenantID, &b.CategoryID, &b.TagID, &b.Month, &b.EndMonth, &b.Recurring, &b.Currency, &b.Amount, &b.Rollover, &b.CreatedAt, &b.CreatedBy, &b.UpdatedAt, &b.UpdatedBy, &b.Version, &b.DeactivatedAt, &b.DeactivatedBy)
+	if err != nil {
+		return nil, err
+	}
+	b.Amount.Currency = b.Currency
+	return &b, nil
+}
+
+// budgetKeyset orders budgets by their (first) month.
+var budgetKeyset = keyset{columns: []string{"month", "id"}}
+
+func budgetCursor(b *domain.Budget) string {
+	return domain.EncodeCursor(b.Month, b.ID)
+}
+
+func (r *BudgetRepository) GetByID(ctx context.Context, id, tenantID string) (*domain.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	b, err := scanBudget(r.db.conn(ctx).QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		return nil, translateError(err, domain.ErrBudgetNotFound, "failed to get budget by id")
+	}
+	return b, nil
+}
+
+func (r *BudgetRepository) List(ctx context.Context, tenantID string, page domain.PageRequest) (*domain.Page[domain.Budget], error) {
+	from := `FROM budgets WHERE tenant_id = $1 AND deactivated_at IS NULL`
+	args := []any{tenantID}
+
+	var after []any
+	if page.Cursor != "" {
+		values, err := decodeCursor(page.Cursor, 2)
+		if err != nil {
+			return nil, err
+		}
+		after = []any{values[0], values[1]}
+	}
+
+	query, queryArgs := budgetKeyset.apply(`SELECT `+budgetColumns+` `+from, args, page, after)
+	budgets, err := r.queryBudgets(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := pageOf(budgets, page, budgetCursor)
+	if page.IncludeTotal {
+		if result.Total, err = r.db.countRows(ctx, from, args); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (r *BudgetRepository) ListForMonth(ctx context.Context, tenantID, month string) ([]domain.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets
+			  WHERE tenant_id = $1 AND deactivated_at IS NULL
+			    AND (month = $2 OR (recurring AND month <= $2 AND (end_month IS NULL OR end_month >= $2)))
+			  ORDER BY month, id`
+	return r.queryBudgets(ctx, query, tenantID, month)
+}
+
+func (r *BudgetRepository) queryBudgets(ctx context.Context, query string, args ...any) ([]domain.Budget, error) {
+	rows, err := r.db.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+	defer rows.Close()
+
+	var budgets []domain.Budget
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan budget: %w", err)
+		}
+		budgets = append(budgets, *b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+	return budgets, nil
+}
+
+func (r *BudgetRepository) Create(ctx context.Context, b *domain.Budget) error {
+	query := `INSERT INTO budgets (tenant_id, category_id, tag_id, month, end_month, recurring, currency, amount, rollover, created_by, updated_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+			  RETURNING id, created_at, updated_at, version`
+	row := r.db.conn(ctx).QueryRow(ctx, query, b.TenantID, b.CategoryID, b.TagID, b.Month, b.EndMonth, b.Recurring, b.Currency, b.Amount, b.Rollover, b.CreatedBy)
+	if err := row.Scan(&b.ID, &b.CreatedAt, &b.UpdatedAt, &b.Version); err != nil {
+		return translateError(err, nil, "failed to create budget")
+	}
+	b.UpdatedBy = b.CreatedBy
+	return nil
+}
+
+func (r *BudgetRepository) Update(ctx context.Context, b *domain.Budget) error {
+	query := `UPDATE budgets SET category_id = $3, tag_id = $4, month = $5, end_month = $6, recurring = $7, currency = $8, amount = $9, rollover = $10,
+			  updated_by = $11, updated_at = CURRENT_TIMESTAMP, version = version + 1
+			  WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL AND ($12 = 0 OR version = $12)
+			  RETURNING updated_at, version`
+	err := r.db.conn(ctx).QueryRow(ctx, query, b.ID, b.TenantID, b.CategoryID, b.TagID, b.Month, b.EndMonth, b.Recurring, b.Currency, b.Amount, b.Rollover, b.UpdatedBy, b.Version).Scan(&b.UpdatedAt, &b.Version)
+	if err != nil {
+		if r.db.versionMismatch(ctx, err, "budgets", b.ID, b.TenantID, b.Version) {
+			return domain.ErrVersionMismatch
+		}
+		return translateError(err, domain.ErrBudgetNotFound, "failed to update budget")
+	}
+	return nil
+}
+
+func (r *BudgetRepository) Delete(ctx context.Context, id, tenantID, userID string) error {
+	query := `UPDATE budgets SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $3 WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	tag, err := r.db.conn(ctx).Exec(ctx, query, id, tenantID, userID)
+	if err != nil {
+		return translateError(err, domain.ErrBudgetNotFound, "failed to delete budget")
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrBudgetNotFound
+	}
+	return nil
+}
+
+// Spending sums the credit and debit transactions in expense categories counted against each budget:
+// those in its category or any of its subcategories, or tagged with its tag, in its currency.
+func (r *BudgetRepository) Spending(ctx context.Context, tenantID string, budgets []domain.Budget, month string) ([]domain.BudgetSpending, error) {
+	if len(budgets) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(budgets))
+	for i := range budgets {
+		ids[i] = budgets[i].ID
+	}
+
+	query := `WITH RECURSIVE tree AS (
+			SELECT id AS root_id, id FROM categories WHERE tenant_id = $1
+			UNION
+			SELECT tree.root_id, c.id FROM categories c JOIN tree ON c.parent_category = tree.id WHERE c.tenant_id = $1
+		)
+		SELECT b.id, t.accrual_month,
+			   SUM(CASE WHEN t.transaction_type = 'debit' THEN t.amount ELSE -t.amount END)
+		FROM budgets b
+		JOIN transactions t ON t.tenant_id = b.tenant_id AND t.currency = b.currency
+		JOIN categories c ON c.id = t.category_id
+		WHERE b.tenant_id = $1 AND b.id = ANY($2::uuid[])
+		  AND t.deactivated_at IS NULL AND t.transaction_type IN ('credit', 'debit') AND c.type = 'expense'
+		  AND t.accrual_month <= $3
+		  AND t.accrual_month >= CASE WHEN b.rollover THEN b.month ELSE $3 END
+		  AND (b.category_id IS NULL OR EXISTS (SELECT 1 FROM tree WHERE tree.root_id = b.category_id AND tree.id = t.category_id))
+		  AND (b.tag_id IS NULL OR EXISTS (SELECT 1 FROM transactions_tags tt WHERE tt.transaction_id = t.id AND tt.tag_id = b.tag_id))
+		GROUP BY b.id, t.accrual_month`
+	rows, err := r.db.conn(ctx).Query(ctx, query, tenantID, ids, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget spending: %w", err)
+	}
+	defer rows.Close()
+
+	var spending []domain.BudgetSpending
+	for rows.Next() {
+		var s domain.BudgetSpending
+		if err := rows.Scan(&s.BudgetID, &s.Month, &s.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan budget spending: %w", err)
+		}
+		spending = append(spending, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get budget spending: %w", err)
+	}
+	return spending, nil
+}
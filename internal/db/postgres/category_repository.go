@@ -16,21 +16,28 @@ func NewCategoryRepository(db *DB) *CategoryRepository {
 }
 
 func (r *CategoryRepository) GetByID(ctx context.Context, id, tenantID string) (*domain.Category, error) {
-	query := `SELECT id, parent_category, tenant_id, name, type, deactivated_at, color, icon, created_at, created_by, updated_at, updated_by, deactivated_by FROM categories WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	query := `SELECT id, parent_category, tenant_id, name, type, deactivated_at, color, icon, created_at, created_by, updated_at, updated_by, version, deactivated_by FROM categories WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
 	var c domain.Category
-	err := r.db.Pool.QueryRow(ctx, query, id, tenantID).Scan(
+	err := r.db.conn(ctx).QueryRow(ctx, query, id, tenantID).Scan(
 		&c.ID, &c.ParentCategoryID, &c.TenantID, &c.Name, &c.Type, &c.DeactivatedAt, &c.Color, &c.Icon,
-		&c.CreatedAt, &c.CreatedBy, &c.UpdatedAt, &c.UpdatedBy, &c.DeactivatedBy,
+		&c.CreatedAt, &c.CreatedBy, &c.UpdatedAt, &c.UpdatedBy, &c.Version, &c.DeactivatedBy,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get category by id: %w", err)
+		return nil, translateError(err, domain.ErrCategoryNotFound, "failed to get category by id")
 	}
 	return &c, nil
 }
 
-func (r *CategoryRepository) List(ctx context.Context, tenantID string) ([]domain.Category, error) {
-	query := `SELECT id, parent_category, tenant_id, name, type, deactivated_at, color, icon, created_at, created_by, updated_at, updated_by, deactivated_by FROM categories WHERE tenant_id = $1 AND deactivated_at IS NULL`
-	rows, err := r.db.Pool.Query(ctx, query, tenantID)
+func (r *CategoryRepository) List(ctx context.Context, tenantID string, page domain.PageRequest) (*domain.Page[domain.Category], error) {
+	from := `FROM categories WHERE tenant_id = $1 AND deactivated_at IS NULL`
+	args := []any{tenantID}
+
+	after, err := afterName(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	query, queryArgs := nameKeyset.apply(`SELECT id, parent_category, tenant_id, name, type, deactivated_at, color, icon, created_at, created_by, updated_at, updated_by, version, deactivated_by `+from, args, page, after)
+	rows, err := r.db.conn(ctx).Query(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list categories: %w", err)
 	}
@@ -39,37 +46,52 @@ func (r *CategoryRepository) List(ctx context.Context, tenantID string) ([]domai
 	var categories []domain.Category
 	for rows.Next() {
 		var c domain.Category
-		if err := rows.Scan(&c.ID, &c.ParentCategoryID, &c.TenantID, &c.Name, &c.Type, &c.DeactivatedAt, &c.Color, &c.Icon, &c.CreatedAt, &c.CreatedBy, &c.UpdatedAt, &c.UpdatedBy, &c.DeactivatedBy); err != nil {
+		if err := rows.Scan(&c.ID, &c.ParentCategoryID, &c.TenantID, &c.Name, &c.Type, &c.DeactivatedAt, &c.Color, &c.Icon, &c.CreatedAt, &c.CreatedBy, &c.UpdatedAt, &c.UpdatedBy, &c.Version, &c.DeactivatedBy); err != nil {
 			return nil, fmt.Errorf("failed to scan category: %w", err)
 		}
 		categories = append(categories, c)
 	}
-	return categories, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	result := pageOf(categories, page, func(c *domain.Category) string { return nameCursor(c.Name, c.ID) })
+	if page.IncludeTotal {
+		if result.Total, err = r.db.countRows(ctx, from, args); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 func (r *CategoryRepository) Create(ctx context.Context, c *domain.Category) error {
 	query := `INSERT INTO categories (parent_category, tenant_id, name, type, color, icon, created_by, updated_by)
 			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-			  RETURNING id, created_at, updated_at`
-	row := r.db.Pool.QueryRow(ctx, query, c.ParentCategoryID, c.TenantID, c.Name, c.Type, c.Color, c.Icon, c.CreatedBy, c.UpdatedBy)
-	if err := row.Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to create category: %w", err)
+			  RETURNING id, created_at, updated_at, version`
+	row := r.db.conn(ctx).QueryRow(ctx, query, c.ParentCategoryID, c.TenantID, c.Name, c.Type, c.Color, c.Icon, c.CreatedBy, c.UpdatedBy)
+	if err := row.Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt, &c.Version); err != nil {
+		return translateError(err, nil, "failed to create category")
 	}
 	return nil
 }
 
 func (r *CategoryRepository) Update(ctx context.Context, c *domain.Category) error {
-	query := `UPDATE categories SET parent_category = $2, name = $3, color = $4, icon = $5, updated_by = $6, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND tenant_id = $7 RETURNING updated_at`
-	err := r.db.Pool.QueryRow(ctx, query, c.ID, c.ParentCategoryID, c.Name, c.Color, c.Icon, c.UpdatedBy, c.TenantID).Scan(&c.UpdatedAt)
+	query := `UPDATE categories SET parent_category = $2, name = $3, color = $4, icon = $5, updated_by = $6, updated_at = CURRENT_TIMESTAMP, version = version + 1
+			  WHERE id = $1 AND tenant_id = $7 AND ($8 = 0 OR version = $8)
+			  RETURNING updated_at, version`
+	err := r.db.conn(ctx).QueryRow(ctx, query, c.ID, c.ParentCategoryID, c.Name, c.Color, c.Icon, c.UpdatedBy, c.TenantID, c.Version).Scan(&c.UpdatedAt, &c.Version)
 	if err != nil {
-		return fmt.Errorf("failed to update category: %w", err)
+		if r.db.versionMismatch(ctx, err, "categories", c.ID, c.TenantID, c.Version) {
+			return domain.ErrVersionMismatch
+		}
+		return translateError(err, domain.ErrCategoryNotFound, "failed to update category")
 	}
 	return nil
 }
 
 func (r *CategoryRepository) Delete(ctx context.Context, id, tenantID, userID string) error {
 	query := `UPDATE categories SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $2 WHERE id = $1 AND tenant_id = $3`
-	_, err := r.db.Pool.Exec(ctx, query, id, userID, tenantID)
+	_, err := r.db.conn(ctx).Exec(ctx, query, id, userID, tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
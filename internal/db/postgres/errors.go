@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// translateError converts a query error into a domain error. No row, or a malformed ID that
+// cannot match one, becomes notFound (when given); integrity constraint violations become
+// validation or conflict errors. Other errors are wrapped with message.
+func translateError(err error, notFound error, message string) error {
+	if notFound != nil && (errors.Is(err, pgx.ErrNoRows) || isInvalidTextRepresentation(err)) {
+		return notFound
+	}
+	if cerr := constraintError(err); cerr != nil {
+		return cerr
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// constraintError converts an integrity constraint violation or a malformed value into a
+// domain error, or returns nil for other errors.
+func constraintError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+	columns := keyColumns(pgErr.Detail)
+	switch pgErr.Code {
+	case "23505": // unique_violation
+		if len(columns) == 0 {
+			return domain.NewConflictError("record already exists")
+		}
+		return domain.NewConflictError(fmt.Sprintf("a record with this %s already exists", strings.Join(columns, ", ")))
+	case "23503": // foreign_key_violation
+		if strings.Contains(pgErr.Detail, "is still referenced") {
+			return domain.NewConflictError("record is still referenced by other records")
+		}
+		if len(columns) == 1 {
+			return domain.InvalidField(columns[0], columns[0]+" references a record that does not exist")
+		}
+		return domain.NewValidationError("references a record that does not exist")
+	case "23502": // not_null_violation
+		return domain.InvalidField(pgErr.ColumnName, pgErr.ColumnName+" is required")
+	case "23514": // check_violation
+		return domain.NewValidationError(fmt.Sprintf("value violates check constraint %q", pgErr.ConstraintName))
+	case "22P02": // invalid_text_representation
+		return domain.NewValidationError(pgErr.Message)
+	}
+	return nil
+}
+
+// keyColumns returns the columns named in the detail of a constraint violation,
+// e.g. "Key (tenant_id, name)=(…) already exists.", leaving out tenant_id.
+func keyColumns(detail string) []string {
+	rest, ok := strings.CutPrefix(detail, "Key (")
+	if !ok {
+		return nil
+	}
+	end := strings.Index(rest, ")=(")
+	if end < 0 {
+		return nil
+	}
+	var columns []string
+	for _, column := range strings.Split(rest[:end], ",") {
+		if column = strings.TrimSpace(column); column != "tenant_id" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// versionMismatch reports whether err comes from a versioned UPDATE of table that matched no row
+// because the row exists with another version than the expected one (0 for unversioned updates).
+func (db *DB) versionMismatch(ctx context.Context, err error, table, id, tenantID string, version int) bool {
+	if version == 0 || !errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM ` + table + ` WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL)`
+	if err := db.conn(ctx).QueryRow(ctx, query, id, tenantID).Scan(&exists); err != nil {
+		return false
+	}
+	return exists
+}
+
+// isUniqueViolation reports whether err is caused by a unique constraint.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isInvalidTextRepresentation reports whether err is caused by a malformed value, such as an invalid UUID.
+func isInvalidTextRepresentation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "22P02"
+}
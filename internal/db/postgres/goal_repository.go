@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type GoalRepository struct {
+	db *DB
+}
+
+func NewGoalRepository(db *DB) *GoalRepository {
+	return &GoalRepository{db: db}
+}
+
+// goalColumns is the column list read by every goal query (aliased g), in the order scanGoal expects.
+const goalColumns = `g.id, g.tenant_id, g.name, g.currency, g.target_amount, g.target_date, g.tag_id,
+	ARRAY(SELECT ga.account_id::text FROM goals_accounts ga WHERE ga.goal_id = g.id ORDER BY ga.account_id),
+	g.created_at, g.created_by, g.updated_at, g.updated_by, g.version, g.deactivated_at, g.deactivated_by`
+
+func scanGoal(row pgx.Row) (*domain.Goal, error) {
+	var g domain.Goal
+	err := row.Scan(&g.ID, &g.TenantID, &g.Name, &g.Currency, &g.TargetAmount, &g.TargetDate, &g.TagID, &g.AccountIDs, &g.CreatedAt, &g.CreatedBy, &g.UpdatedAt, &g.UpdatedBy, &g.Version, &g.DeactivatedAt, &g.DeactivatedBy)
+	if err != nil {
+		return nil, err
+	}
+	g.TargetAmount.Currency = g.Currency
+	if len(g.AccountIDs) == 0 {
+		g.AccountIDs = nil
+	}
+	return &g, nil
+}
+
+var goalKeyset = keyset{columns: []string{"g.name", "g.id"}}
+
+func goalCursor(g *domain.Goal) string {
+	return domain.EncodeCursor(g.Name, g.ID)
+}
+
+func (r *GoalRepository) GetByID(ctx context.Context, id, tenantID string) (*domain.Goal, error) {
+	query := `SELECT ` + goalColumns + ` FROM goals g WHERE g.id = $1 AND g.tenant_id = $2 AND g.deactivated_at IS NULL`
+	g, err := scanGoal(r.db.conn(ctx).QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		return nil, translateError(err, domain.ErrGoalNotFound, "failed to get goal by id")
+	}
+	return g, nil
+}
+
+func (r *GoalRepository) List(ctx context.Context, tenantID string, page domain.PageRequest) (*domain.Page[domain.Goal], error) {
+	from := `FROM goals g WHERE g.tenant_id = $1 AND g.deactivated_at IS NULL`
+	args := []any{tenantID}
+
+	var after []any
+	if page.Cursor != "" {
+		values, err := decodeCursor(page.Cursor, 2)
+		if err != nil {
+			return nil, err
+		}
+		after = []any{values[0], values[1]}
+	}
+
+	query, queryArgs := goalKeyset.apply(`SELECT `+goalColumns+` `+from, args, page, after)
+	rows, err := r.db.conn(ctx).Query(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+	defer rows.Close()
+
+	var goals []domain.Goal
+	for rows.Next() {
+		g, err := scanGoal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan goal: %w", err)
+		}
+		goals = append(goals, *g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+
+	result := pageOf(goals, page, goalCursor)
+	if page.IncludeTotal {
+		if result.Total, err = r.db.countRows(ctx, from, args); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (r *GoalRepository) Create(ctx context.Context, g *domain.Goal) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO goals (tenant_id, name, currency, target_amount, target_date, tag_id, created_by, updated_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+			  RETURNING id, created_at, updated_at, version`
+	row := tx.QueryRow(ctx, query, g.TenantID, g.Name, g.Currency, g.TargetAmount, g.TargetDate, g.TagID, g.CreatedBy)
+	if err := row.Scan(&g.ID, &g.CreatedAt, &g.UpdatedAt, &g.Version); err != nil {
+		return translateError(err, nil, "failed to create goal")
+	}
+	if err := insertGoalAccounts(ctx, tx, g); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	g.UpdatedBy = g.CreatedBy
+	return nil
+}
+
+func (r *GoalRepository) Update(ctx context.Context, g *domain.Goal) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE goals SET name = $3, currency = $4, target_amount = $5, target_date = $6, tag_id = $7,
+			  updated_by = $8, updated_at = CURRENT_TIMESTAMP, version = version + 1
+			  WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL AND ($9 = 0 OR version = $9)
+			  RETURNING updated_at, version`
+	err = tx.QueryRow(ctx, query, g.ID, g.TenantID, g.Name, g.Currency, g.TargetAmount, g.TargetDate, g.TagID, g.UpdatedBy, g.Version).Scan(&g.UpdatedAt, &g.Version)
+	if err != nil {
+		if r.db.versionMismatch(ctx, err, "goals", g.ID, g.TenantID, g.Version) {
+			return domain.ErrVersionMismatch
+		}
+		return translateError(err, domain.ErrGoalNotFound, "failed to update goal")
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM goals_accounts WHERE goal_id = $1`, g.ID); err != nil {
+		return fmt.Errorf("failed to delete goal accounts: %w", err)
+	}
+	if err := insertGoalAccounts(ctx, tx, g); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertGoalAccounts links the accounts of g to it.
+func insertGoalAccounts(ctx context.Context, tx pgx.Tx, g *domain.Goal) error {
+	if len(g.AccountIDs) == 0 {
+		return nil
+	}
+	query := `INSERT INTO goals_accounts (goal_id, account_id) SELECT $1, unnest($2::uuid[])`
+	if _, err := tx.Exec(ctx, query, g.ID, g.AccountIDs); err != nil {
+		return translateError(err, nil, "failed to link goal accounts")
+	}
+	return nil
+}
+
+func (r *GoalRepository) Delete(ctx context.Context, id, tenantID, userID string) error {
+	query := `UPDATE goals SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $3 WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	tag, err := r.db.conn(ctx).Exec(ctx, query, id, tenantID, userID)
+	if err != nil {
+		return translateError(err, domain.ErrGoalNotFound, "failed to delete goal")
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrGoalNotFound
+	}
+	return nil
+}
+
+// goalAccountContributionsQuery sums the cleared postings of the linked accounts ($2) per month of
+// their payment date, so transfers into them count as contributions and transfers out as withdrawals.
+const goalAccountContributionsQuery = postingsCTE + `
+	SELECT to_char(p.payment_date, 'YYYYMM') AS month, SUM(p.amount)
+	FROM postings p
+	WHERE p.account_id = ANY($2::uuid[]) AND p.payment_date <= $3::date
+	GROUP BY month
+	ORDER BY month`
+
+// goalTagContributionsQuery sums the paid transactions tagged with $2 in currency $3 per month of
+// their payment date: debits and outgoing transfer legs add their amount, credits subtract it. The
+// incoming leg of a transfer is tagged alike and left out. Partial payments count on their own dates.
+const goalTagContributionsQuery = `WITH tagged AS (
+		SELECT t.id, t.transaction_type, t.amount - t.settled_amount AS amount, t.payment_date
+		FROM transactions t
+		JOIN transactions_tags tt ON tt.transaction_id = t.id
+		WHERE t.tenant_id = $1 AND tt.tag_id = $2 AND t.currency = $3 AND t.deactivated_at IS NULL
+		  AND (t.transaction_type IN ('credit', 'debit') OR (t.transaction_type = 'transfer' AND t.transfer_direction = 'out'))
+	), paid AS (
+		SELECT transaction_type, amount, payment_date FROM tagged
+		UNION ALL
+		SELECT g.transaction_type, p.amount, p.payment_date
+		FROM transaction_payments p
+		JOIN tagged g ON g.id = p.transaction_id
+		WHERE p.deactivated_at IS NULL
+	)
+	SELECT to_char(payment_date, 'YYYYMM') AS month,
+		   SUM(CASE WHEN transaction_type = 'credit' THEN -amount ELSE amount END)
+	FROM paid
+	WHERE payment_date <= $4::date
+	GROUP BY month
+	ORDER BY month`
+
+func (r *GoalRepository) Contributions(ctx context.Context, g *domain.Goal, asOf time.Time) ([]domain.GoalContribution, error) {
+	var rows pgx.Rows
+	var err error
+	if g.TagID != nil {
+		rows, err = r.db.conn(ctx).Query(ctx, goalTagContributionsQuery, g.TenantID, *g.TagID, g.Currency, asOf)
+	} else {
+		rows, err = r.db.conn(ctx).Query(ctx, goalAccountContributionsQuery, g.TenantID, g.AccountIDs, asOf)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal contributions: %w", err)
+	}
+	defer rows.Close()
+
+	var contributions []domain.GoalContribution
+	for rows.Next() {
+		var c domain.GoalContribution
+		if err := rows.Scan(&c.Month, &c.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan goal contribution: %w", err)
+		}
+		c.Amount.Currency = g.Currency
+		contributions = append(contributions, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get goal contributions: %w", err)
+	}
+	return contributions, nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type IdempotencyKeyRepository struct {
+	db *DB
+}
+
+func NewIdempotencyKeyRepository(db *DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{db: db}
+}
+
+// Reserve inserts the key, taking over an expired or stale record, or returns the live record holding it.
+// A record released between the insert and the lookup is retried.
+func (r *IdempotencyKeyRepository) Reserve(ctx context.Context, key *domain.IdempotencyKey, staleBefore time.Time) (*domain.IdempotencyKey, error) {
+	insert := `INSERT INTO idempotency_keys (tenant_id, user_id, key, request_hash, created_at, expires_at)
+			   VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5, $6)
+			   ON CONFLICT (tenant_id, user_id, key) DO UPDATE
+			   SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL, response_body = NULL,
+			       created_at = EXCLUDED.created_at, completed_at = NULL, expires_at = EXCLUDED.expires_at
+			   WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+			      OR (idempotency_keys.completed_at IS NULL AND idempotency_keys.created_at < $7)
+			   RETURNING id`
+	lookup := `SELECT id, request_hash, status_code, content_type, response_body, created_at, completed_at, expires_at
+			   FROM idempotency_keys
+			   WHERE tenant_id IS NOT DISTINCT FROM NULLIF($1, '')::uuid AND user_id = $2 AND key = $3`
+
+	// Complete and Release match the reservation on created_at, stored with microsecond precision
+	key.CreatedAt = key.CreatedAt.Truncate(time.Microsecond)
+	for attempt := 0; attempt < 3; attempt++ {
+		err := r.db.conn(ctx).QueryRow(ctx, insert, key.TenantID, key.UserID, key.Key, key.RequestHash, key.CreatedAt, key.ExpiresAt, staleBefore).Scan(&key.ID)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+
+		existing := domain.IdempotencyKey{TenantID: key.TenantID, UserID: key.UserID, Key: key.Key}
+		var statusCode *int
+		var contentType *string
+		err = r.db.conn(ctx).QueryRow(ctx, lookup, key.TenantID, key.UserID, key.Key).Scan(
+			&existing.ID, &existing.RequestHash, &statusCode, &contentType, &existing.ResponseBody,
+			&existing.CreatedAt, &existing.CompletedAt, &existing.ExpiresAt,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+		if statusCode != nil {
+			existing.StatusCode = *statusCode
+		}
+		if contentType != nil {
+			existing.ContentType = *contentType
+		}
+		return &existing, nil
+	}
+	return nil, errors.New("failed to reserve idempotency key: record keeps changing")
+}
+
+// Complete only updates the reservation made by this request, not one that took it over after it went stale.
+func (r *IdempotencyKeyRepository) Complete(ctx context.Context, key *domain.IdempotencyKey) error {
+	query := `UPDATE idempotency_keys
+			  SET status_code = $3, content_type = $4, response_body = $5, completed_at = $6
+			  WHERE id = $1 AND created_at = $2 AND completed_at IS NULL`
+	_, err := r.db.conn(ctx).Exec(ctx, query, key.ID, key.CreatedAt, key.StatusCode, key.ContentType, key.ResponseBody, key.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *IdempotencyKeyRepository) Release(ctx context.Context, key *domain.IdempotencyKey) error {
+	query := `DELETE FROM idempotency_keys WHERE id = $1 AND created_at = $2 AND completed_at IS NULL`
+	if _, err := r.db.conn(ctx).Exec(ctx, query, key.ID, key.CreatedAt); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *IdempotencyKeyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.conn(ctx).Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
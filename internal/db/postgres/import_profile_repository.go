@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type ImportProfileRepository struct {
+	db *DB
+}
+
+func NewImportProfileRepository(db *DB) *ImportProfileRepository {
+	return &ImportProfileRepository{db: db}
+}
+
+// importProfileColumns is the column list read by every import profile query, in the order scanImportProfile expects.
+const importProfileColumns = `id, tenant_id, name, delimiter, has_header, skip_rows, date_format, decimal_separator, sign_convention, columns, created_at, created_by, updated_at, updated_by, version, deactivated_at, deactivated_by`
+
+func scanImportProfile(row pgx.Row) (*domain.ImportProfile, error) {
+	var p domain.ImportProfile
+	err := row.Scan(&p.ID, &p.TenantID, &p.Name, &p.Delimiter, &p.HasHeader, &p.SkipRows, &p.DateFormat, &p.DecimalSeparator, &p.SignConvention, &p.Columns, &p.CreatedAt, &p.CreatedBy, &p.UpdatedAt, &p.UpdatedBy, &p.Version, &p.DeactivatedAt, &p.DeactivatedBy)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+var importProfileKeyset = keyset{columns: []string{"name", "id"}}
+
+func importProfileCursor(p *domain.ImportProfile) string {
+	return domain.EncodeCursor(p.Name, p.ID)
+}
+
+func (r *ImportProfileRepository) GetByID(ctx context.Context, id, tenantID string) (*domain.ImportProfile, error) {
+	query := `SELECT ` + importProfileColumns + ` FROM import_profiles WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	p, err := scanImportProfile(r.db.conn(ctx).QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		return nil, translateError(err, domain.ErrImportProfileNotFound, "failed to get import profile by id")
+	}
+	return p, nil
+}
+
+func (r *ImportProfileRepository) List(ctx context.Context, tenantID string, page domain.PageRequest) (*domain.Page[domain.ImportProfile], error) {
+	from := `FROM import_profiles WHERE tenant_id = $1 AND deactivated_at IS NULL`
+	args := []any{tenantID}
+
+	var after []any
+	if page.Cursor != "" {
+		values, err := decodeCursor(page.Cursor, 2)
+		if err != nil {
+			return nil, err
+		}
+		after = []any{values[0], values[1]}
+	}
+
+	query, queryArgs := importProfileKeyset.apply(`SELECT `+importProfileColumns+` `+from, args, page, after)
+	rows, err := r.db.conn(ctx).Query(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []domain.ImportProfile
+	for rows.Next() {
+		p, err := scanImportProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import profile: %w", err)
+		}
+		profiles = append(profiles, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list import profiles: %w", err)
+	}
+
+	result := pageOf(profiles, page, importProfileCursor)
+	if page.IncludeTotal {
+		if result.Total, err = r.db.countRows(ctx, from, args); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (r *ImportProfileRepository) Create(ctx context.Context, p *domain.ImportProfile) error {
+	query := `INSERT INTO import_profiles (tenant_id, name, delimiter, has_header, skip_rows, date_format, decimal_separator, sign_convention, columns, created_by, updated_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+			  RETURNING id, created_at, updated_at, version`
+	row := r.db.conn(ctx).QueryRow(ctx, query, p.TenantID, p.Name, p.Delimiter, p.HasHeader, p.SkipRows, p.DateFormat, p.DecimalSeparator, p.SignConvention, p.Columns, p.CreatedBy)
+	if err := row.Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt, &p.Version); err != nil {
+		return translateError(err, nil, "failed to create import profile")
+	}
+	p.UpdatedBy = p.CreatedBy
+	return nil
+}
+
+func (r *ImportProfileRepository) Update(ctx context.Context, p *domain.ImportProfile) error {
+	query := `UPDATE import_profiles SET name = $3, delimiter = $4, has_header = $5, skip_rows = $6, date_format = $7, decimal_separator = $8, sign_convention = $9, columns = $10,
+			  updated_by = $11, updated_at = CURRENT_TIMESTAMP, version = version + 1
+			  WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL AND ($12 = 0 OR version = $12)
+			  RETURNING updated_at, version`
+	err := r.db.conn(ctx).QueryRow(ctx, query, p.ID, p.TenantID, p.Name, p.Delimiter, p.HasHeader, p.SkipRows, p.DateFormat, p.DecimalSeparator, p.SignConvention, p.Columns, p.UpdatedBy, p.Version).Scan(&p.UpdatedAt, &p.Version)
+	if err != nil {
+		if r.db.versionMismatch(ctx, err, "import_profiles", p.ID, p.TenantID, p.Version) {
+			return domain.ErrVersionMismatch
+		}
+		return translateError(err, domain.ErrImportProfileNotFound, "failed to update import profile")
+	}
+	return nil
+}
+
+func (r *ImportProfileRepository) Delete(ctx context.Context, id, tenantID, userID string) error {
+	query := `UPDATE import_profiles SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $3 WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	tag, err := r.db.conn(ctx).Exec(ctx, query, id, tenantID, userID)
+	if err != nil {
+		return translateError(err, domain.ErrImportProfileNotFound, "failed to delete import profile")
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrImportProfileNotFound
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type ImportRepository struct {
+	db *DB
+}
+
+func NewImportRepository(db *DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+func (r *ImportRepository) GetByID(ctx context.Context, id, tenantID string) (*domain.Import, error) {
+	query := `SELECT id, tenant_id, account_id, format, profile_id, file_name, currency, status, committed_at, already_imported, ledger_balance, ledger_balance_date,
+			  created_at, created_by, updated_at, updated_by, deactivated_at, deactivated_by
+			  FROM imports WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	var imp domain.Import
+	err := r.db.conn(ctx).QueryRow(ctx, query, id, tenantID).Scan(&imp.ID, &imp.TenantID, &imp.AccountID, &imp.Format, &imp.ProfileID, &imp.FileName, &imp.Currency, &imp.Status, &imp.CommittedAt, &imp.AlreadyImported, &imp.LedgerBalance, &imp.LedgerBalanceDate,
+		&imp.CreatedAt, &imp.CreatedBy, &imp.UpdatedAt, &imp.UpdatedBy, &imp.DeactivatedAt, &imp.DeactivatedBy)
+	if err != nil {
+		return nil, translateError(err, domain.ErrImportNotFound, "failed to get import by id")
+	}
+	if imp.LedgerBalance != nil {
+		imp.LedgerBalance.Currency = imp.Currency
+	}
+
+	rowsQuery := `SELECT id, import_id, line, external_id, date, description, amount, COALESCE(transaction_type::text, ''), error, duplicate_transaction_id, suggested_category_id, transaction_id
+				  FROM import_rows WHERE import_id = $1 ORDER BY line`
+	rows, err := r.db.conn(ctx).Query(ctx, rowsQuery, imp.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row domain.ImportRow
+		if err := rows.Scan(&row.ID, &row.ImportID, &row.Line, &row.ExternalID, &row.Date, &row.Description, &row.Amount, &row.TransactionType, &row.Error, &row.DuplicateTransactionID, &row.SuggestedCategoryID, &row.TransactionID); err != nil {
+			return nil, fmt.Errorf("failed to scan import row: %w", err)
+		}
+		row.Amount.Currency = imp.Currency
+		imp.Rows = append(imp.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get import rows: %w", err)
+	}
+	return &imp, nil
+}
+
+// importRowArrays holds the rows of an import column by column, to be passed to unnest.
+type importRowArrays struct {
+	lines        []int
+	externalIDs  []*string
+	dates        []*time.Time
+	descriptions []string
+	amounts      []string
+	types        []*string
+	errors       []*string
+	duplicate    []*string
+	suggested    []*string
+}
+
+func newImportRowArrays(rows []domain.ImportRow) importRowArrays {
+	var a importRowArrays
+	for _, row := range rows {
+		var txType *string
+		if row.TransactionType != "" {
+			s := string(row.TransactionType)
+			txType = &s
+		}
+		a.lines = append(a.lines, row.Line)
+		a.externalIDs = append(a.externalIDs, row.ExternalID)
+		a.dates = append(a.dates, row.Date)
+		a.descriptions = append(a.descriptions, row.Description)
+		a.amounts = append(a.amounts, row.Amount.String())
+		a.types = append(a.types, txType)
+		a.errors = append(a.errors, row.Error)
+		a.duplicate = append(a.duplicate, row.DuplicateTransactionID)
+		a.suggested = append(a.suggested, row.SuggestedCategoryID)
+	}
+	return a
+}
+
+func (r *ImportRepository) Create(ctx context.Context, imp *domain.Import) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO imports (tenant_id, account_id, format, profile_id, file_name, currency, status, already_imported, ledger_balance, ledger_balance_date, created_by, updated_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11)
+			  RETURNING id, created_at, updated_at`
+	row := tx.QueryRow(ctx, query, imp.TenantID, imp.AccountID, imp.Format, imp.ProfileID, imp.FileName, imp.Currency, imp.Status, imp.AlreadyImported, imp.LedgerBalance, imp.LedgerBalanceDate, imp.CreatedBy)
+	if err := row.Scan(&imp.ID, &imp.CreatedAt, &imp.UpdatedAt); err != nil {
+		return translateError(err, nil, "failed to create import")
+	}
+
+	if len(imp.Rows) > 0 {
+		a := newImportRowArrays(imp.Rows)
+		rowsQuery := `INSERT INTO import_rows (import_id, line, external_id, date, description, amount, transaction_type, error, duplicate_transaction_id, suggested_category_id)
+					  SELECT $1, r.line, r.external_id, r.date, r.description, r.amount::numeric, r.transaction_type::transaction_type, r.error, r.duplicate_transaction_id, r.suggested_category_id
+					  FROM unnest($2::int[], $3::text[], $4::date[], $5::text[], $6::text[], $7::text[], $8::text[], $9::uuid[], $10::uuid[])
+					    AS r(line, external_id, date, description, amount, transaction_type, error, duplicate_transaction_id, suggested_category_id)
+					  RETURNING id, line`
+		rows, err := tx.Query(ctx, rowsQuery, imp.ID, a.lines, a.externalIDs, a.dates, a.descriptions, a.amounts, a.types, a.errors, a.duplicate, a.suggested)
+		if err != nil {
+			return translateError(err, nil, "failed to create import rows")
+		}
+		ids := make(map[int]string, len(imp.Rows))
+		for rows.Next() {
+			var id string
+			var line int
+			if err := rows.Scan(&id, &line); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan import row: %w", err)
+			}
+			ids[line] = id
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return translateError(err, nil, "failed to create import rows")
+		}
+		for i := range imp.Rows {
+			imp.Rows[i].ID = ids[imp.Rows[i].Line]
+			imp.Rows[i].ImportID = imp.ID
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	imp.UpdatedBy = imp.CreatedBy
+	return nil
+}
+
+func (r *ImportRepository) MarkCommitted(ctx context.Context, imp *domain.Import) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE imports SET status = 'committed', committed_at = $3, updated_by = $4, updated_at = CURRENT_TIMESTAMP
+			  WHERE id = $1 AND tenant_id = $2 AND status = 'staged' AND deactivated_at IS NULL
+			  RETURNING updated_at`
+	err = tx.QueryRow(ctx, query, imp.ID, imp.TenantID, imp.CommittedAt, imp.UpdatedBy).Scan(&imp.UpdatedAt)
+	if err != nil {
+		return translateError(err, domain.ErrImportCommitted, "failed to commit import")
+	}
+
+	// External IDs whose transaction was deleted can be imported again, so their old rows release them.
+	var externalIDs []string
+	for _, row := range imp.Rows {
+		if row.TransactionID != nil && row.ExternalID != nil {
+			externalIDs = append(externalIDs, *row.ExternalID)
+		}
+	}
+	if len(externalIDs) > 0 {
+		releaseQuery := `UPDATE import_rows r SET account_id = NULL
+						 FROM transactions t
+						 WHERE t.id = r.transaction_id AND t.deactivated_at IS NOT NULL
+						   AND r.account_id = $1 AND r.external_id = ANY($2::text[])`
+		if _, err := tx.Exec(ctx, releaseQuery, imp.AccountID, externalIDs); err != nil {
+			return fmt.Errorf("failed to release imported external ids: %w", err)
+		}
+	}
+
+	for _, row := range imp.Rows {
+		if row.TransactionID == nil {
+			continue
+		}
+		_, err := tx.Exec(ctx, `UPDATE import_rows SET transaction_id = $2, account_id = $3 WHERE id = $1`, row.ID, *row.TransactionID, imp.AccountID)
+		if isUniqueViolation(err) {
+			return domain.ErrImportRowImported
+		}
+		if err != nil {
+			return translateError(err, nil, "failed to link import row")
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	imp.Status = domain.ImportStatusCommitted
+	return nil
+}
+
+func (r *ImportRepository) Delete(ctx context.Context, id, tenantID, userID string) error {
+	query := `UPDATE imports SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $3 WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	tag, err := r.db.conn(ctx).Exec(ctx, query, id, tenantID, userID)
+	if err != nil {
+		return translateError(err, domain.ErrImportNotFound, "failed to delete import")
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrImportNotFound
+	}
+	return nil
+}
+
+// importDuplicatesQuery picks, for each parsed row, the closest transaction of account $2 with the
+// same amount and direction dated (paid, or due when unpaid) within $7 days of it. Debit rows match
+// debits, card payments and outgoing transfer legs; credit rows match credits and incoming legs.
+const importDuplicatesQuery = `SELECT r.line, d.id
+	FROM unnest($3::int[], $4::date[], $5::numeric[], $6::text[]) AS r(line, date, amount, transaction_type)
+	CROSS JOIN LATERAL (
+		SELECT t.id
+		FROM transactions t
+		WHERE t.tenant_id = $1 AND t.from_account_id = $2 AND t.deactivated_at IS NULL
+		  AND t.amount = r.amount
+		  AND CASE r.transaction_type
+		      WHEN 'debit' THEN t.transaction_type IN ('debit', 'payment') OR t.transfer_direction = 'out'
+		      ELSE t.transaction_type = 'credit' OR t.transfer_direction = 'in'
+		      END
+		  AND COALESCE(t.payment_date, t.due_date) BETWEEN r.date - $7::int AND r.date + $7::int
+		ORDER BY abs(COALESCE(t.payment_date, t.due_date) - r.date), t.id
+		LIMIT 1
+	) d`
+
+func (r *ImportRepository) FindDuplicates(ctx context.Context, tenantID, accountID string, rows []domain.ImportRow) (map[int]string, error) {
+	a := parsedImportRows(rows)
+	if len(a.lines) == 0 {
+		return map[int]string{}, nil
+	}
+	result, err := r.queryByLine(ctx, importDuplicatesQuery, tenantID, accountID, a.lines, a.dates, a.amounts, a.types, domain.ImportDuplicateDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate transactions: %w", err)
+	}
+	return result, nil
+}
+
+// importSuggestionsQuery picks, for each parsed row, the category of the most transactions of the
+// same type whose normalized comments (as in domain.NormalizeImportDescription) match the
+// normalized description, breaking ties by the most recently used.
+const importSuggestionsQuery = `WITH history AS (
+		SELECT btrim(regexp_replace(regexp_replace(lower(t.comments), '[0-9]+', '', 'g'), '\s+', ' ', 'g')) AS description,
+			   t.transaction_type::text AS transaction_type, t.category_id, COUNT(*) AS uses, MAX(t.due_date) AS last_used
+		FROM transactions t
+		WHERE t.tenant_id = $1 AND t.deactivated_at IS NULL AND t.comments IS NOT NULL
+		  AND t.transaction_type IN ('credit', 'debit')
+		GROUP BY 1, 2, 3
+	)
+	SELECT DISTINCT ON (r.line) r.line, h.category_id
+	FROM unnest($2::int[], $3::text[], $4::text[]) AS r(line, description, transaction_type)
+	JOIN history h ON h.description = r.description AND h.transaction_type = r.transaction_type
+	WHERE r.description <> ''
+	ORDER BY r.line, h.uses DESC, h.last_used DESC`
+
+func (r *ImportRepository) SuggestCategories(ctx context.Context, tenantID string, rows []domain.ImportRow) (map[int]string, error) {
+	a := parsedImportRows(rows)
+	if len(a.lines) == 0 {
+		return map[int]string{}, nil
+	}
+	descriptions := make([]string, len(a.descriptions))
+	for i, description := range a.descriptions {
+		descriptions[i] = domain.NormalizeImportDescription(description)
+	}
+	result, err := r.queryByLine(ctx, importSuggestionsQuery, tenantID, a.lines, descriptions, a.types)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest categories: %w", err)
+	}
+	return result, nil
+}
+
+func (r *ImportRepository) ImportedExternalIDs(ctx context.Context, tenantID, accountID string, externalIDs []string) (map[string]string, error) {
+	imported := make(map[string]string)
+	if len(externalIDs) == 0 {
+		return imported, nil
+	}
+	query := `SELECT r.external_id, r.transaction_id
+			  FROM import_rows r
+			  JOIN transactions t ON t.id = r.transaction_id
+			  WHERE t.tenant_id = $1 AND r.account_id = $2
+			    AND r.external_id = ANY($3::text[]) AND t.deactivated_at IS NULL`
+	rows, err := r.db.conn(ctx).Query(ctx, query, tenantID, accountID, externalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get imported external ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var externalID, transactionID string
+		if err := rows.Scan(&externalID, &transactionID); err != nil {
+			return nil, fmt.Errorf("failed to scan imported external id: %w", err)
+		}
+		imported[externalID] = transactionID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get imported external ids: %w", err)
+	}
+	return imported, nil
+}
+
+func (r *ImportRepository) LockAccount(ctx context.Context, tenantID, accountID string) error {
+	var id string
+	query := `SELECT id FROM accounts WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL FOR UPDATE`
+	if err := r.db.conn(ctx).QueryRow(ctx, query, accountID, tenantID).Scan(&id); err != nil {
+		return translateError(err, domain.ErrAccountNotFound, "failed to lock account")
+	}
+	return nil
+}
+
+// parsedImportRows returns the arrays of the rows that were parsed.
+func parsedImportRows(rows []domain.ImportRow) importRowArrays {
+	var parsed []domain.ImportRow
+	for _, row := range rows {
+		if row.Error == nil && row.Date != nil {
+			parsed = append(parsed, row)
+		}
+	}
+	return newImportRowArrays(parsed)
+}
+
+// queryByLine runs a query returning a line and an ID per row.
+func (r *ImportRepository) queryByLine(ctx context.Context, query string, args ...any) (map[int]string, error) {
+	rows, err := r.db.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int]string)
+	for rows.Next() {
+		var line int
+		var id string
+		if err := rows.Scan(&line, &id); err != nil {
+			return nil, err
+		}
+		result[line] = id
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type InvitationRepository struct {
+	db *DB
+}
+
+func NewInvitationRepository(db *DB) *InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+const invitationColumns = `id, tenant_id, inviter_user_id, email, role, token_hash, status, expires_at, accepted_by, accepted_at, created_at, updated_at`
+
+func scanInvitation(row pgx.Row) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	err := row.Scan(&inv.ID, &inv.TenantID, &inv.InviterUserID, &inv.Email, &inv.Role, &inv.TokenHash, &inv.Status, &inv.ExpiresAt, &inv.AcceptedBy, &inv.AcceptedAt, &inv.CreatedAt, &inv.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *InvitationRepository) Create(ctx context.Context, inv *domain.Invitation) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// An expired invitation that is still pending must not block a new one for the same email.
+	expireQuery := `UPDATE invitations SET status = 'expired', updated_at = CURRENT_TIMESTAMP
+					WHERE tenant_id = $1 AND lower(email) = lower($2) AND status = 'pending' AND expires_at <= CURRENT_TIMESTAMP`
+	if _, err := tx.Exec(ctx, expireQuery, inv.TenantID, inv.Email); err != nil {
+		return fmt.Errorf("failed to expire previous invitations: %w", err)
+	}
+
+	query := `INSERT INTO invitations (tenant_id, inviter_user_id, email, role, token_hash, expires_at)
+			  VALUES ($1, $2, $3, $4, $5, $6)
+			  RETURNING id, status, created_at, updated_at`
+	row := tx.QueryRow(ctx, query, inv.TenantID, inv.InviterUserID, inv.Email, inv.Role, inv.TokenHash, inv.ExpiresAt)
+	if err := row.Scan(&inv.ID, &inv.Status, &inv.CreatedAt, &inv.UpdatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrInvitationAlreadyExists
+		}
+		return translateError(err, nil, "failed to create invitation")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *InvitationRepository) GetByID(ctx context.Context, tenantID, id string) (*domain.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitations WHERE id = $1 AND tenant_id = $2`
+	inv, err := scanInvitation(r.db.conn(ctx).QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		return nil, translateError(err, domain.ErrInvitationNotFound, "failed to get invitation by id")
+	}
+	return inv, nil
+}
+
+func (r *InvitationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitations WHERE token_hash = $1`
+	inv, err := scanInvitation(r.db.conn(ctx).QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		return nil, translateError(err, domain.ErrInvitationNotFound, "failed to get invitation by token")
+	}
+	return inv, nil
+}
+
+func (r *InvitationRepository) ListPending(ctx context.Context, tenantID string) ([]domain.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitations
+			  WHERE tenant_id = $1 AND status = 'pending' AND expires_at > CURRENT_TIMESTAMP
+			  ORDER BY created_at DESC, id`
+	rows, err := r.db.conn(ctx).Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending invitations: %w", err)
+	}
+	defer rows.Close()
+
+	var invitations []domain.Invitation
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invitation: %w", err)
+		}
+		invitations = append(invitations, *inv)
+	}
+	return invitations, nil
+}
+
+func (r *InvitationRepository) Revoke(ctx context.Context, tenantID, id string) error {
+	query := `UPDATE invitations SET status = 'revoked', updated_at = CURRENT_TIMESTAMP
+			  WHERE id = $1 AND tenant_id = $2 AND status = 'pending'`
+	tag, err := r.db.conn(ctx).Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrInvitationNotPending
+	}
+	return nil
+}
+
+func (r *InvitationRepository) MarkAccepted(ctx context.Context, inv *domain.Invitation, userID string) error {
+	// The status check makes concurrent accepts of the same invitation fail instead of both succeeding.
+	query := `UPDATE invitations SET status = 'accepted', accepted_by = $2, accepted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			  WHERE id = $1 AND status = 'pending'
+			  RETURNING status, accepted_by, accepted_at, updated_at`
+	err := r.db.conn(ctx).QueryRow(ctx, query, inv.ID, userID).Scan(&inv.Status, &inv.AcceptedBy, &inv.AcceptedAt, &inv.UpdatedAt)
+	if err != nil {
+		return translateError(err, domain.ErrInvitationNotPending, "failed to accept invitation")
+	}
+	return nil
+}
+
+func (r *InvitationRepository) ExpirePending(ctx context.Context, now time.Time) (int64, error) {
+	query := `UPDATE invitations SET status = 'expired', updated_at = CURRENT_TIMESTAMP
+			  WHERE status = 'pending' AND expires_at <= $1`
+	tag, err := r.db.conn(ctx).Exec(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire invitations: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
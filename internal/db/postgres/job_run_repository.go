@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// jobLockPrefix namespaces the advisory lock keys of background jobs.
+const jobLockPrefix = "fintrack:job:"
+
+type JobRunRepository struct {
+	db *DB
+}
+
+func NewJobRunRepository(db *DB) *JobRunRepository {
+	return &JobRunRepository{db: db}
+}
+
+const jobRunColumns = `id, job_name, scheduled_at, started_at, finished_at, status, summary, error, instance`
+
+func scanJobRun(row pgx.Row) (*domain.JobRun, error) {
+	var run domain.JobRun
+	err := row.Scan(&run.ID, &run.JobName, &run.ScheduledAt, &run.StartedAt, &run.FinishedAt, &run.Status, &run.Summary, &run.Error, &run.Instance)
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// TryLock takes a session-level advisory lock on a dedicated connection, which is held until release.
+func (r *JobRunRepository) TryLock(ctx context.Context, jobName string) (func(), bool, error) {
+	conn, err := r.db.Pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	key := jobLockPrefix + jobName
+	var acquired bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, key).Scan(&acquired); err != nil {
+		conn.Release()
+		return nil, false, fmt.Errorf("failed to take job lock: %w", err)
+	}
+	if !acquired {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	release := func() {
+		// The lock is released with the session if unlocking fails, so never return such a connection to the pool.
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock(hashtext($1))`, key); err != nil {
+			_ = conn.Hijack().Close(unlockCtx)
+			return
+		}
+		conn.Release()
+	}
+	return release, true, nil
+}
+
+func (r *JobRunRepository) StartRun(ctx context.Context, run *domain.JobRun) (bool, error) {
+	query := `INSERT INTO job_runs (job_name, scheduled_at, started_at, status, instance)
+			  VALUES ($1, $2, $3, 'running', $4)
+			  ON CONFLICT (job_name, scheduled_at) DO NOTHING
+			  RETURNING id, status`
+	err := r.db.conn(ctx).QueryRow(ctx, query, run.JobName, run.ScheduledAt, run.StartedAt, run.Instance).Scan(&run.ID, &run.Status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to start job run: %w", err)
+	}
+	return true, nil
+}
+
+func (r *JobRunRepository) FinishRun(ctx context.Context, run *domain.JobRun) error {
+	query := `UPDATE job_runs SET finished_at = $2, status = $3, summary = $4, error = $5 WHERE id = $1`
+	if _, err := r.db.conn(ctx).Exec(ctx, query, run.ID, run.FinishedAt, run.Status, run.Summary, run.Error); err != nil {
+		return fmt.Errorf("failed to finish job run: %w", err)
+	}
+	return nil
+}
+
+func (r *JobRunRepository) ListLatest(ctx context.Context) ([]domain.JobRun, error) {
+	query := `SELECT DISTINCT ON (job_name) ` + jobRunColumns + ` FROM job_runs ORDER BY job_name, started_at DESC`
+	rows, err := r.db.conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []domain.JobRun
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		runs = append(runs, *run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// keyset describes the deterministic sort order of a paginated list.
+// The last column must be unique (usually id) so that every row has a distinct position.
+type keyset struct {
+	columns []string
+	desc    bool
+}
+
+// apply appends the cursor condition, ORDER BY and LIMIT to a query whose WHERE clause is already open.
+// after holds the sort key values of the last row of the previous page, or nil for the first page.
+// One row more than the limit is fetched so that pageOf can tell whether there is a next page.
+func (k keyset) apply(query string, args []any, page domain.PageRequest, after []any) (string, []any) {
+	op, dir := ">", "ASC"
+	if k.desc {
+		op, dir = "<", "DESC"
+	}
+
+	if after != nil {
+		placeholders := make([]string, len(after))
+		for i, v := range after {
+			args = append(args, v)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		query += fmt.Sprintf(" AND (%s) %s (%s)", strings.Join(k.columns, ", "), op, strings.Join(placeholders, ", "))
+	}
+
+	order := make([]string, len(k.columns))
+	for i, c := range k.columns {
+		order[i] = c + " " + dir
+	}
+	query += " ORDER BY " + strings.Join(order, ", ")
+
+	if page.Limit > 0 {
+		args = append(args, page.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	return query, args
+}
+
+// pageOf trims the extra row fetched by keyset.apply and sets the cursor of the next page.
+func pageOf[T any](items []T, page domain.PageRequest, cursor func(*T) string) *domain.Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	p := &domain.Page[T]{Items: items}
+	if page.Limit > 0 && len(items) > page.Limit {
+		p.Items = items[:page.Limit]
+		p.NextCursor = cursor(&p.Items[page.Limit-1])
+	}
+	return p
+}
+
+// countRows counts the rows matched by a "FROM ... WHERE ..." clause, before pagination.
+func (db *DB) countRows(ctx context.Context, from string, args []any) (*int64, error) {
+	var total int64
+	if err := db.conn(ctx).QueryRow(ctx, "SELECT COUNT(*) "+from, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count rows: %w", err)
+	}
+	return &total, nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// decodeCursor decodes a cursor of n sort key values whose last value is a row ID.
+func decodeCursor(cursor string, n int) ([]string, error) {
+	values, err := domain.DecodeCursor(cursor, n)
+	if err != nil {
+		return nil, err
+	}
+	if !uuidPattern.MatchString(values[n-1]) {
+		return nil, domain.ErrInvalidCursor
+	}
+	return values, nil
+}
+
+// nameKeyset orders lists alphabetically, for accounts, categories and tags.
+var nameKeyset = keyset{columns: []string{"name", "id"}}
+
+func nameCursor(name, id string) string {
+	return domain.EncodeCursor(name, id)
+}
+
+// afterName decodes a nameKeyset cursor; an empty cursor selects the first page.
+func afterName(cursor string) ([]any, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	values, err := decodeCursor(cursor, 2)
+	if err != nil {
+		return nil, err
+	}
+	return []any{values[0], values[1]}, nil
+}
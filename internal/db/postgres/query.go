@@ -0,0 +1,12 @@
+package postgres
+
+import "fmt"
+
+// queryArgs collects the positional arguments of a query built at runtime.
+type queryArgs []any
+
+// add appends a value and returns its placeholder ($1, $2, ...).
+func (a *queryArgs) add(v any) string {
+	*a = append(*a, v)
+	return fmt.Sprintf("$%d", len(*a))
+}
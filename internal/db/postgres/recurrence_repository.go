@@ -0,0 +1,392 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type RecurrenceRuleRepository struct {
+	db *DB
+}
+
+func NewRecurrenceRuleRepository(db *DB) *RecurrenceRuleRepository {
+	return &RecurrenceRuleRepository{db: db}
+}
+
+// recurrenceRuleColumns is the column list read by every rule query, in the order scanRecurrenceRule expects.
+const recurrenceRuleColumns = `id, tenant_id, from_account_id, to_account_id, category_id, transaction_type, currency, amount, comments, tag_ids, frequency, interval_count, month_day, last_business_day, start_date, end_date, occurrence_count, materialized_until, created_at, created_by, updated_at, updated_by, deactivated_at, deactivated_by`
+
+func scanRecurrenceRule(row pgx.Row) (*domain.RecurrenceRule, error) {
+	var r domain.RecurrenceRule
+	err := row.Scan(&r.ID, &r.TenantID, &r.FromAccountID, &r.ToAccountID, &r.CategoryID, &r.TransactionType, &r.Currency, &r.Amount, &r.Comments, &r.TagIDs, &r.Frequency, &r.Interval, &r.MonthDay, &r.LastBusinessDay, &r.StartDate, &r.EndDate, &r.Count, &r.MaterializedUntil, &r.CreatedAt, &r.CreatedBy, &r.UpdatedAt, &r.UpdatedBy, &r.DeactivatedAt, &r.DeactivatedBy)
+	if err != nil {
+		return nil, err
+	}
+	r.Amount.Currency = r.Currency
+	return &r, nil
+}
+
+// recurrenceKeyset orders rules by start date.
+var recurrenceKeyset = keyset{columns: []string{"start_date", "id"}}
+
+func recurrenceCursor(r *domain.RecurrenceRule) string {
+	return domain.EncodeCursor(r.StartDate.Format(time.DateOnly), r.ID)
+}
+
+func (r *RecurrenceRuleRepository) GetByID(ctx context.Context, tenantID, id string) (*domain.RecurrenceRule, error) {
+	query := `SELECT ` + recurrenceRuleColumns + ` FROM recurrence_rules WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	rule, err := scanRecurrenceRule(r.db.conn(ctx).QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		return nil, translateError(err, domain.ErrRecurrenceRuleNotFound, "failed to get recurrence rule by id")
+	}
+	if err := r.loadScheduleDetails(ctx, []*domain.RecurrenceRule{rule}); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *RecurrenceRuleRepository) List(ctx context.Context, tenantID string, page domain.PageRequest) (*domain.Page[domain.RecurrenceRule], error) {
+	from := `FROM recurrence_rules WHERE tenant_id = $1 AND deactivated_at IS NULL`
+	args := []any{tenantID}
+
+	var after []any
+	if page.Cursor != "" {
+		values, err := decodeCursor(page.Cursor, 2)
+		if err != nil {
+			return nil, err
+		}
+		startDate, err := time.Parse(time.DateOnly, values[0])
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		after = []any{startDate, values[1]}
+	}
+
+	query, queryArgs := recurrenceKeyset.apply(`SELECT `+recurrenceRuleColumns+` `+from, args, page, after)
+	rules, err := r.queryRules(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := pageOf(rules, page, recurrenceCursor)
+	ptrs := make([]*domain.RecurrenceRule, len(result.Items))
+	for i := range result.Items {
+		ptrs[i] = &result.Items[i]
+	}
+	if err := r.loadScheduleDetails(ctx, ptrs); err != nil {
+		return nil, err
+	}
+	if page.IncludeTotal {
+		if result.Total, err = r.db.countRows(ctx, from, args); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (r *RecurrenceRuleRepository) ListToMaterialize(ctx context.Context, tenantID string, until time.Time) ([]domain.RecurrenceRule, error) {
+	var args queryArgs
+	query := `SELECT ` + recurrenceRuleColumns + ` FROM recurrence_rules
+			  WHERE deactivated_at IS NULL AND (materialized_until IS NULL OR materialized_until < ` + args.add(until) + `)`
+	if tenantID != "" {
+		query += ` AND tenant_id = ` + args.add(tenantID)
+	}
+	query += ` ORDER BY start_date, id`
+	rules, err := r.queryRules(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	ptrs := make([]*domain.RecurrenceRule, len(rules))
+	for i := range rules {
+		ptrs[i] = &rules[i]
+	}
+	if err := r.loadScheduleDetails(ctx, ptrs); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *RecurrenceRuleRepository) queryRules(ctx context.Context, query string, args ...any) ([]domain.RecurrenceRule, error) {
+	rows, err := r.db.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recurrence rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []domain.RecurrenceRule
+	for rows.Next() {
+		rule, err := scanRecurrenceRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recurrence rule: %w", err)
+		}
+		rules = append(rules, *rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list recurrence rules: %w", err)
+	}
+	return rules, nil
+}
+
+// loadScheduleDetails loads the amount changes and skipped dates of the given rules, one query each.
+func (r *RecurrenceRuleRepository) loadScheduleDetails(ctx context.Context, rules []*domain.RecurrenceRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	byID := make(map[string]*domain.RecurrenceRule, len(rules))
+	ids := make([]string, len(rules))
+	for i, rule := range rules {
+		byID[rule.ID] = rule
+		ids[i] = rule.ID
+	}
+
+	rows, err := r.db.conn(ctx).Query(ctx, `SELECT rule_id, effective_from, amount FROM recurrence_amount_changes WHERE rule_id = ANY($1::uuid[]) ORDER BY effective_from`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list amount changes: %w", err)
+	}
+	for rows.Next() {
+		var ruleID string
+		var c domain.RecurrenceAmountChange
+		if err := rows.Scan(&ruleID, &c.EffectiveFrom, &c.Amount); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan amount change: %w", err)
+		}
+		rule := byID[ruleID]
+		c.Amount.Currency = rule.Currency
+		rule.AmountChanges = append(rule.AmountChanges, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list amount changes: %w", err)
+	}
+
+	rows, err = r.db.conn(ctx).Query(ctx, `SELECT rule_id, occurrence_date FROM recurrence_skipped_dates WHERE rule_id = ANY($1::uuid[]) ORDER BY occurrence_date`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list skipped dates: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var ruleID string
+		var date time.Time
+		if err := rows.Scan(&ruleID, &date); err != nil {
+			return fmt.Errorf("failed to scan skipped date: %w", err)
+		}
+		byID[ruleID].SkippedDates = append(byID[ruleID].SkippedDates, date)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list skipped dates: %w", err)
+	}
+	return nil
+}
+
+func (r *RecurrenceRuleRepository) Create(ctx context.Context, rule *domain.RecurrenceRule) error {
+	query := `INSERT INTO recurrence_rules (tenant_id, from_account_id, to_account_id, category_id, transaction_type, currency, amount, comments, tag_ids, frequency, interval_count, month_day, last_business_day, start_date, end_date, occurrence_count, created_by, updated_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::uuid[], $10, $11, $12, $13, $14, $15, $16, $17, $17)
+			  RETURNING id, created_at, updated_at`
+	row := r.db.conn(ctx).QueryRow(ctx, query, rule.TenantID, rule.FromAccountID, rule.ToAccountID, rule.CategoryID, rule.TransactionType, rule.Currency, rule.Amount, rule.Comments, nonNilStrings(rule.TagIDs), rule.Frequency, rule.Interval, rule.MonthDay, rule.LastBusinessDay, rule.StartDate, rule.EndDate, rule.Count, rule.CreatedBy)
+	if err := row.Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return translateError(err, nil, "failed to create recurrence rule")
+	}
+	rule.UpdatedBy = rule.CreatedBy
+	return nil
+}
+
+func (r *RecurrenceRuleRepository) Update(ctx context.Context, rule *domain.RecurrenceRule, from time.Time) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE recurrence_rules SET from_account_id = $3, to_account_id = $4, category_id = $5, transaction_type = $6, currency = $7, amount = $8, comments = $9, tag_ids = $10::uuid[],
+				frequency = $11, interval_count = $12, month_day = $13, last_business_day = $14, start_date = $15, end_date = $16, occurrence_count = $17,
+				materialized_until = CASE WHEN materialized_until >= $18::date THEN $18::date - 1 ELSE materialized_until END,
+				updated_at = CURRENT_TIMESTAMP, updated_by = $19
+			  WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL
+			  RETURNING materialized_until, updated_at`
+	row := tx.QueryRow(ctx, query, rule.ID, rule.TenantID, rule.FromAccountID, rule.ToAccountID, rule.CategoryID, rule.TransactionType, rule.Currency, rule.Amount, rule.Comments, nonNilStrings(rule.TagIDs),
+		rule.Frequency, rule.Interval, rule.MonthDay, rule.LastBusinessDay, rule.StartDate, rule.EndDate, rule.Count, from, rule.UpdatedBy)
+	if err := row.Scan(&rule.MaterializedUntil, &rule.UpdatedAt); err != nil {
+		return translateError(err, domain.ErrRecurrenceRuleNotFound, "failed to update recurrence rule")
+	}
+
+	if err := cancelOccurrences(ctx, tx, rule.TenantID, rule.ID, from, rule.UpdatedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *RecurrenceRuleRepository) Delete(ctx context.Context, tenantID, id, userID string, from time.Time) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE recurrence_rules SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $3 WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	tag, err := tx.Exec(ctx, query, id, tenantID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurrence rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrRecurrenceRuleNotFound
+	}
+
+	if err := cancelOccurrences(ctx, tx, tenantID, id, from, userID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// unpaidOccurrence matches the occurrences not paid yet. A transfer occurrence counts as paid as soon
+// as either of its legs is, so both legs are always changed together.
+const unpaidOccurrence = `(payment_date IS NULL OR payment_date > CURRENT_DATE)
+	AND NOT EXISTS (SELECT 1 FROM transactions leg WHERE leg.id = transactions.linked_transaction_id AND leg.payment_date <= CURRENT_DATE)`
+
+// cancelOccurrences soft-deletes the unpaid materialized occurrences of a rule from the given date.
+// A future payment date (a card purchase not posted yet) does not count as paid.
+func cancelOccurrences(ctx context.Context, tx pgx.Tx, tenantID, ruleID string, from time.Time, userID string) error {
+	query := `UPDATE transactions SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $4
+			  WHERE tenant_id = $1 AND recurrence_rule_id = $2 AND recurrence_date >= $3 AND ` + unpaidOccurrence + ` AND deactivated_at IS NULL`
+	if _, err := tx.Exec(ctx, query, tenantID, ruleID, from, userID); err != nil {
+		return fmt.Errorf("failed to cancel occurrences: %w", err)
+	}
+	return nil
+}
+
+func (r *RecurrenceRuleRepository) SkipOccurrence(ctx context.Context, tenantID, ruleID string, date time.Time, userID string) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Both legs of a transfer occurrence carry its date; either being paid keeps it.
+	var paid *bool
+	err = tx.QueryRow(ctx, `SELECT bool_or(payment_date <= CURRENT_DATE) FROM transactions
+							WHERE tenant_id = $1 AND recurrence_rule_id = $2 AND recurrence_date = $3 AND deactivated_at IS NULL`,
+		tenantID, ruleID, date).Scan(&paid)
+	if err != nil {
+		return fmt.Errorf("failed to get occurrence: %w", err)
+	}
+	if paid != nil && *paid {
+		return domain.ErrOccurrencePaid
+	}
+
+	if _, err := tx.Exec(ctx, `INSERT INTO recurrence_skipped_dates (rule_id, occurrence_date, created_by) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, ruleID, date, userID); err != nil {
+		return fmt.Errorf("failed to skip occurrence: %w", err)
+	}
+	query := `UPDATE transactions SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $4
+			  WHERE tenant_id = $1 AND recurrence_rule_id = $2 AND recurrence_date = $3 AND deactivated_at IS NULL`
+	if _, err := tx.Exec(ctx, query, tenantID, ruleID, date, userID); err != nil {
+		return fmt.Errorf("failed to cancel occurrence: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *RecurrenceRuleRepository) ChangeAmount(ctx context.Context, tenantID, ruleID string, change domain.RecurrenceAmountChange, userID string) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	upsert := `INSERT INTO recurrence_amount_changes (rule_id, effective_from, amount, created_by) VALUES ($1, $2, $3, $4)
+			   ON CONFLICT (rule_id, effective_from) DO UPDATE SET amount = EXCLUDED.amount`
+	if _, err := tx.Exec(ctx, upsert, ruleID, change.EffectiveFrom, change.Amount, userID); err != nil {
+		return fmt.Errorf("failed to save amount change: %w", err)
+	}
+
+	// Materialized occurrences take the new amount until the next later change, if any.
+	query := `UPDATE transactions SET amount = $4, updated_at = CURRENT_TIMESTAMP, updated_by = $5, version = version + 1
+			  WHERE tenant_id = $1 AND recurrence_rule_id = $2 AND recurrence_date >= $3 AND ` + unpaidOccurrence + ` AND deactivated_at IS NULL
+			  AND recurrence_date < COALESCE((SELECT MIN(effective_from) FROM recurrence_amount_changes WHERE rule_id = $2 AND effective_from > $3), 'infinity'::date)`
+	if _, err := tx.Exec(ctx, query, tenantID, ruleID, change.EffectiveFrom, change.Amount, userID); err != nil {
+		return fmt.Errorf("failed to update occurrence amounts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *RecurrenceRuleRepository) Materialize(ctx context.Context, rule *domain.RecurrenceRule, occurrences []domain.Transaction, until time.Time) (int, error) {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Occurrences that already exist are left alone, so materializing twice is harmless.
+	// A transfer occurrence is its outgoing leg; the incoming leg is inserted with it.
+	insert := `INSERT INTO transactions (tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, recurrence_rule_id, recurrence_date, transfer_direction, exchange_rate, created_by, updated_by)
+			   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+			   ON CONFLICT (recurrence_rule_id, recurrence_date) WHERE deactivated_at IS NULL AND transfer_direction IS DISTINCT FROM 'in' DO NOTHING
+			   RETURNING id`
+	var created []string
+	for _, t := range occurrences {
+		err := tx.QueryRow(ctx, insert, t.TenantID, t.FromAccountID, t.ToAccountID, t.Currency, t.Amount, t.AccrualMonth, t.TransactionType, t.CategoryID, t.Comments, t.DueDate, t.PaymentDate, t.RecurrenceRuleID, t.RecurrenceDate, t.TransferDirection, t.ExchangeRate, t.CreatedBy, t.UpdatedBy).Scan(&t.ID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return 0, translateError(err, nil, "failed to create occurrence")
+		}
+		created = append(created, t.ID)
+
+		if t.IsTransferLeg() {
+			in := t.IncomingLeg(t.Currency)
+			if err := insertTransferLeg(ctx, tx, &in); err != nil {
+				return 0, err
+			}
+			if _, err := tx.Exec(ctx, `UPDATE transactions SET linked_transaction_id = $2 WHERE id = $1`, t.ID, in.ID); err != nil {
+				return 0, fmt.Errorf("failed to link transfer legs: %w", err)
+			}
+			created = append(created, in.ID)
+		}
+	}
+
+	if len(created) > 0 && len(rule.TagIDs) > 0 {
+		query := `INSERT INTO transactions_tags (transaction_id, tag_id)
+				  SELECT t, g FROM unnest($1::uuid[]) t CROSS JOIN unnest($2::uuid[]) g`
+		if _, err := tx.Exec(ctx, query, created, rule.TagIDs); err != nil {
+			return 0, fmt.Errorf("failed to link tags: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE recurrence_rules SET materialized_until = $3 WHERE id = $1 AND tenant_id = $2`, rule.ID, rule.TenantID, until); err != nil {
+		return 0, fmt.Errorf("failed to update materialized_until: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	rule.MaterializedUntil = &until
+	return len(created), nil
+}
+
+// nonNilStrings returns an empty slice for nil, so NOT NULL array columns get '{}' instead of NULL.
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type ReportRepository struct {
+	db *DB
+}
+
+func NewReportRepository(db *DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// reportTransactions builds the conditions on the credit and debit transactions (aliased t) a
+// report counts, returning the tenant placeholder with them. Transfer legs and card payments move
+// money between the tenant's own accounts and are left out.
+func reportTransactions(args *queryArgs, tenantID string, f domain.ReportFilter) (string, string) {
+	tenant := args.add(tenantID)
+	conds := []string{
+		"t.tenant_id = " + tenant,
+		"t.deactivated_at IS NULL",
+		"t.transaction_type IN ('credit', 'debit')",
+		"t.accrual_month BETWEEN " + args.add(f.MonthFrom) + " AND " + args.add(f.MonthTo),
+	}
+	if f.AccountID != "" {
+		conds = append(conds, "t.from_account_id = "+args.add(f.AccountID))
+	}
+	if f.Currency != "" {
+		conds = append(conds, "t.currency = "+args.add(f.Currency))
+	}
+	return tenant, strings.Join(conds, " AND ")
+}
+
+// SpendByCategory returns the net spend (debits minus credits) of every expense category with
+// activity in each month, rolled up through parent_category: Amount is the category's own spend and
+// Total includes its subcategories at any depth. Ancestors without activity of their own are included.
+func (r *ReportRepository) SpendByCategory(ctx context.Context, tenantID string, filter domain.ReportFilter) ([]domain.CategorySpend, error) {
+	var args queryArgs
+	tenant, conds := reportTransactions(&args, tenantID, filter)
+	query := `WITH RECURSIVE spend AS (
+			SELECT t.accrual_month AS month, t.category_id, t.currency,
+				   SUM(CASE WHEN t.transaction_type = 'debit' THEN t.amount ELSE -t.amount END) AS amount
+			FROM transactions t
+			JOIN categories c ON c.id = t.category_id
+			WHERE ` + conds + ` AND c.type = 'expense'
+			GROUP BY t.accrual_month, t.category_id, t.currency
+		), ancestry AS (
+			SELECT id AS category_id, id AS ancestor_id FROM categories WHERE tenant_id = ` + tenant + `
+			UNION
+			SELECT a.category_id, p.id
+			FROM ancestry a
+			JOIN categories c ON c.id = a.ancestor_id AND c.tenant_id = ` + tenant + `
+			JOIN categories p ON p.id = c.parent_category AND p.tenant_id = ` + tenant + `
+		), rolled AS (
+			SELECT s.month, a.ancestor_id AS category_id, s.currency,
+				   COALESCE(SUM(s.amount) FILTER (WHERE a.ancestor_id = s.category_id), 0) AS amount,
+				   SUM(s.amount) AS total
+			FROM spend s
+			JOIN ancestry a ON a.category_id = s.category_id
+			GROUP BY s.month, a.ancestor_id, s.currency
+		)
+		SELECT r.month, c.id, p.id, c.name, r.currency, r.amount, r.total
+		FROM rolled r
+		JOIN categories c ON c.id = r.category_id AND c.tenant_id = ` + tenant + `
+		LEFT JOIN categories p ON p.id = c.parent_category AND p.tenant_id = ` + tenant + `
+		ORDER BY r.currency, r.month, r.total DESC, c.name`
+	rows, err := r.db.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to report spend by category: %w", err)
+	}
+	defer rows.Close()
+
+	var spend []domain.CategorySpend
+	for rows.Next() {
+		var s domain.CategorySpend
+		if err := rows.Scan(&s.Month, &s.CategoryID, &s.ParentCategoryID, &s.Name, &s.Currency, &s.Amount, &s.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan category spend: %w", err)
+		}
+		s.Amount.Currency = s.Currency
+		s.Total.Currency = s.Currency
+		spend = append(spend, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to report spend by category: %w", err)
+	}
+	return spend, nil
+}
+
+// IncomeExpense returns the income (credits minus debits in income categories) and the expense
+// (debits minus credits in expense categories) of each month with activity.
+func (r *ReportRepository) IncomeExpense(ctx context.Context, tenantID string, filter domain.ReportFilter) ([]domain.IncomeExpense, error) {
+	var args queryArgs
+	_, conds := reportTransactions(&args, tenantID, filter)
+	query := `SELECT t.accrual_month, t.currency,
+				COALESCE(SUM(CASE WHEN t.transaction_type = 'credit' THEN t.amount ELSE -t.amount END) FILTER (WHERE c.type = 'income'), 0),
+				COALESCE(SUM(CASE WHEN t.transaction_type = 'debit' THEN t.amount ELSE -t.amount END) FILTER (WHERE c.type = 'expense'), 0)
+			  FROM transactions t
+			  JOIN categories c ON c.id = t.category_id
+			  WHERE ` + conds + ` AND c.type IN ('income', 'expense')
+			  GROUP BY t.accrual_month, t.currency
+			  ORDER BY t.currency, t.accrual_month`
+	rows, err := r.db.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to report income and expense: %w", err)
+	}
+	defer rows.Close()
+
+	var months []domain.IncomeExpense
+	for rows.Next() {
+		var m domain.IncomeExpense
+		if err := rows.Scan(&m.Month, &m.Currency, &m.Income, &m.Expense); err != nil {
+			return nil, fmt.Errorf("failed to scan income and expense: %w", err)
+		}
+		m.Income.Currency = m.Currency
+		m.Expense.Currency = m.Currency
+		months = append(months, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to report income and expense: %w", err)
+	}
+	return months, nil
+}
+
+// CashFlow returns the cleared postings of each month, by payment date, in the currency of their
+// account: inflows are the money added to an account and outflows the money taken from it.
+// Transfers and card payments count on both of their accounts.
+func (r *ReportRepository) CashFlow(ctx context.Context, tenantID string, filter domain.ReportFilter) ([]domain.CashFlow, error) {
+	args := queryArgs{tenantID}
+	conds := []string{
+		"a.tenant_id = $1",
+		"a.deactivated_at IS NULL",
+		"p.payment_date IS NOT NULL",
+		"to_char(p.payment_date, 'YYYYMM') BETWEEN " + args.add(filter.MonthFrom) + " AND " + args.add(filter.MonthTo),
+	}
+	if filter.AccountID != "" {
+		conds = append(conds, "a.id = "+args.add(filter.AccountID))
+	}
+	if filter.Currency != "" {
+		conds = append(conds, "a.currency = "+args.add(filter.Currency))
+	}
+	query := postingsCTE + `
+		SELECT to_char(p.payment_date, 'YYYYMM') AS month, a.currency,
+			   COALESCE(SUM(p.amount) FILTER (WHERE p.amount > 0), 0),
+			   COALESCE(-SUM(p.amount) FILTER (WHERE p.amount < 0), 0)
+		FROM postings p
+		JOIN accounts a ON a.id = p.account_id
+		WHERE ` + strings.Join(conds, " AND ") + `
+		GROUP BY month, a.currency
+		ORDER BY a.currency, month`
+	rows, err := r.db.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to report cash flow: %w", err)
+	}
+	defer rows.Close()
+
+	var months []domain.CashFlow
+	for rows.Next() {
+		var m domain.CashFlow
+		if err := rows.Scan(&m.Month, &m.Currency, &m.Inflow, &m.Outflow); err != nil {
+			return nil, fmt.Errorf("failed to scan cash flow: %w", err)
+		}
+		m.Inflow.Currency = m.Currency
+		m.Outflow.Currency = m.Currency
+		months = append(months, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to report cash flow: %w", err)
+	}
+	return months, nil
+}
+
+// TopTags returns the tags with the highest net spend (debits minus credits in expense categories)
+// over the whole range, at most limit per currency. Deleted tags are left out.
+func (r *ReportRepository) TopTags(ctx context.Context, tenantID string, filter domain.ReportFilter, limit int) ([]domain.TagSpend, error) {
+	var args queryArgs
+	_, conds := reportTransactions(&args, tenantID, filter)
+	query := `WITH spend AS (
+			SELECT tg.id, tg.name, t.currency,
+				   SUM(CASE WHEN t.transaction_type = 'debit' THEN t.amount ELSE -t.amount END) AS amount,
+				   COUNT(*) AS transactions
+			FROM transactions t
+			JOIN categories c ON c.id = t.category_id
+			JOIN transactions_tags tt ON tt.transaction_id = t.id
+			JOIN tags tg ON tg.id = tt.tag_id
+			WHERE ` + conds + ` AND c.type = 'expense' AND tg.deactivated_at IS NULL
+			GROUP BY tg.id, tg.name, t.currency
+		), ranked AS (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY currency ORDER BY amount DESC, name) AS rank
+			FROM spend
+		)
+		SELECT id, name, currency, amount, transactions
+		FROM ranked
+		WHERE rank <= ` + args.add(limit) + `
+		ORDER BY currency, rank`
+	rows, err := r.db.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to report top tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []domain.TagSpend
+	for rows.Next() {
+		var s domain.TagSpend
+		if err := rows.Scan(&s.TagID, &s.Name, &s.Currency, &s.Amount, &s.TransactionCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag spend: %w", err)
+		}
+		s.Amount.Currency = s.Currency
+		tags = append(tags, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to report top tags: %w", err)
+	}
+	return tags, nil
+}
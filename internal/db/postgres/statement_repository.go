@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type StatementRepository struct {
+	db *DB
+}
+
+func NewStatementRepository(db *DB) *StatementRepository {
+	return &StatementRepository{db: db}
+}
+
+// statementTotalsQuery groups a card's activity by statement period (accrual_month).
+// Debits and credits made with the card ($2 as from_account_id) are the statement charges and refunds;
+// payment transactions into the card ($2 as to_account_id) settle the statement of their accrual month,
+// as postingsCTE counts them: the unsettled rest of the paid ones, plus the partial payments in their ledger.
+// $3 optionally restricts the result to a single period.
+const statementTotalsQuery = `WITH charges AS (
+		SELECT accrual_month AS period,
+			   COALESCE(SUM(amount) FILTER (WHERE transaction_type = 'debit'), 0) AS charges,
+			   COALESCE(SUM(amount) FILTER (WHERE transaction_type = 'credit'), 0) AS credits
+		FROM transactions
+		WHERE tenant_id = $1 AND from_account_id = $2 AND deactivated_at IS NULL
+		  AND transaction_type IN ('debit', 'credit')
+		  AND ($3 = '' OR accrual_month = $3)
+		GROUP BY accrual_month
+	), settlements AS (
+		SELECT accrual_month AS period, amount - settled_amount AS amount
+		FROM transactions
+		WHERE tenant_id = $1 AND to_account_id = $2 AND deactivated_at IS NULL
+		  AND transaction_type = 'payment' AND payment_date IS NOT NULL
+		  AND ($3 = '' OR accrual_month = $3)
+		UNION ALL
+		SELECT t.accrual_month, p.amount
+		FROM transaction_payments p
+		JOIN transactions t ON t.id = p.transaction_id
+		WHERE p.tenant_id = $1 AND p.deactivated_at IS NULL
+		  AND t.to_account_id = $2 AND t.deactivated_at IS NULL AND t.transaction_type = 'payment'
+		  AND ($3 = '' OR t.accrual_month = $3)
+	), payments AS (
+		SELECT period, SUM(amount) AS paid
+		FROM settlements
+		GROUP BY period
+	)
+	SELECT COALESCE(c.period, p.period) AS period,
+		   COALESCE(c.charges, 0), COALESCE(c.credits, 0), COALESCE(p.paid, 0)
+	FROM charges c
+	FULL OUTER JOIN payments p ON p.period = c.period
+	ORDER BY period DESC`
+
+func (r *StatementRepository) ListTotals(ctx context.Context, tenantID, accountID string) ([]domain.Statement, error) {
+	rows, err := r.db.conn(ctx).Query(ctx, statementTotalsQuery, tenantID, accountID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statement totals: %w", err)
+	}
+	defer rows.Close()
+
+	var statements []domain.Statement
+	for rows.Next() {
+		s := domain.Statement{AccountID: accountID}
+		if err := rows.Scan(&s.Period, &s.Charges, &s.Credits, &s.Paid); err != nil {
+			return nil, fmt.Errorf("failed to scan statement totals: %w", err)
+		}
+		statements = append(statements, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list statement totals: %w", err)
+	}
+	return statements, nil
+}
+
+// GetTotals returns the totals of a single period. A period without activity yields zero totals.
+func (r *StatementRepository) GetTotals(ctx context.Context, tenantID, accountID, period string) (*domain.Statement, error) {
+	s := domain.Statement{AccountID: accountID, Period: period}
+	err := r.db.conn(ctx).QueryRow(ctx, statementTotalsQuery, tenantID, accountID, period).Scan(&s.Period, &s.Charges, &s.Credits, &s.Paid)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get statement totals: %w", err)
+	}
+	return &s, nil
+}
+
+// ListItems returns the card purchases, refunds and payments of a statement period.
+func (r *StatementRepository) ListItems(ctx context.Context, tenantID, accountID, period string) ([]domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + `
+			  FROM transactions
+			  WHERE tenant_id = $1 AND accrual_month = $3 AND deactivated_at IS NULL
+			    AND ((from_account_id = $2 AND transaction_type IN ('debit', 'credit'))
+			      OR (to_account_id = $2 AND transaction_type = 'payment'))
+			  ORDER BY COALESCE(payment_date, due_date), created_at`
+	rows, err := r.db.conn(ctx).Query(ctx, query, tenantID, accountID, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statement items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []domain.Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan statement item: %w", err)
+		}
+		items = append(items, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list statement items: %w", err)
+	}
+	return items, nil
+}
@@ -16,20 +16,27 @@ func NewTagRepository(db *DB) *TagRepository {
 }
 
 func (r *TagRepository) GetByID(ctx context.Context, id, tenantID string) (*domain.Tag, error) {
-	query := `SELECT id, tenant_id, name, created_at, created_by, updated_at, updated_by, deactivated_at, deactivated_by FROM tags WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	query := `SELECT id, tenant_id, name, created_at, created_by, updated_at, updated_by, version, deactivated_at, deactivated_by FROM tags WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
 	var t domain.Tag
-	err := r.db.Pool.QueryRow(ctx, query, id, tenantID).Scan(
-		&t.ID, &t.TenantID, &t.Name, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.DeactivatedAt, &t.DeactivatedBy,
+	err := r.db.conn(ctx).QueryRow(ctx, query, id, tenantID).Scan(
+		&t.ID, &t.TenantID, &t.Name, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.Version, &t.DeactivatedAt, &t.DeactivatedBy,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tag by id: %w", err)
+		return nil, translateError(err, domain.ErrTagNotFound, "failed to get tag by id")
 	}
 	return &t, nil
 }
 
-func (r *TagRepository) List(ctx context.Context, tenantID string) ([]domain.Tag, error) {
-	query := `SELECT id, tenant_id, name, created_at, created_by, updated_at, updated_by, deactivated_at, deactivated_by FROM tags WHERE tenant_id = $1 AND deactivated_at IS NULL`
-	rows, err := r.db.Pool.Query(ctx, query, tenantID)
+func (r *TagRepository) List(ctx context.Context, tenantID string, page domain.PageRequest) (*domain.Page[domain.Tag], error) {
+	from := `FROM tags WHERE tenant_id = $1 AND deactivated_at IS NULL`
+	args := []any{tenantID}
+
+	after, err := afterName(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	query, queryArgs := nameKeyset.apply(`SELECT id, tenant_id, name, created_at, created_by, updated_at, updated_by, version, deactivated_at, deactivated_by `+from, args, page, after)
+	rows, err := r.db.conn(ctx).Query(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tags: %w", err)
 	}
@@ -38,21 +45,31 @@ func (r *TagRepository) List(ctx context.Context, tenantID string) ([]domain.Tag
 	var tags []domain.Tag
 	for rows.Next() {
 		var t domain.Tag
-		if err := rows.Scan(&t.ID, &t.TenantID, &t.Name, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.DeactivatedAt, &t.DeactivatedBy); err != nil {
+		if err := rows.Scan(&t.ID, &t.TenantID, &t.Name, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.Version, &t.DeactivatedAt, &t.DeactivatedBy); err != nil {
 			return nil, fmt.Errorf("failed to scan tag: %w", err)
 		}
 		tags = append(tags, t)
 	}
-	return tags, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	result := pageOf(tags, page, func(t *domain.Tag) string { return nameCursor(t.Name, t.ID) })
+	if page.IncludeTotal {
+		if result.Total, err = r.db.countRows(ctx, from, args); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 func (r *TagRepository) Create(ctx context.Context, t *domain.Tag) error {
 	query := `INSERT INTO tags (tenant_id, name, created_by, updated_by)
 			  VALUES ($1, $2, $3, $3)
-			  RETURNING id, created_at, updated_at`
-	row := r.db.Pool.QueryRow(ctx, query, t.TenantID, t.Name, t.CreatedBy)
-	if err := row.Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to create tag: %w", err)
+			  RETURNING id, created_at, updated_at, version`
+	row := r.db.conn(ctx).QueryRow(ctx, query, t.TenantID, t.Name, t.CreatedBy)
+	if err := row.Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt, &t.Version); err != nil {
+		return translateError(err, nil, "failed to create tag")
 	}
 	// Initial state setup
 	t.UpdatedBy = t.CreatedBy
@@ -60,16 +77,21 @@ func (r *TagRepository) Create(ctx context.Context, t *domain.Tag) error {
 }
 
 func (r *TagRepository) Update(ctx context.Context, t *domain.Tag) error {
-	query := `UPDATE tags SET name = $2, updated_by = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND tenant_id = $4 RETURNING updated_at`
-	if err := r.db.Pool.QueryRow(ctx, query, t.ID, t.Name, t.UpdatedBy, t.TenantID).Scan(&t.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to update tag: %w", err)
+	query := `UPDATE tags SET name = $2, updated_by = $3, updated_at = CURRENT_TIMESTAMP, version = version + 1
+			  WHERE id = $1 AND tenant_id = $4 AND ($5 = 0 OR version = $5)
+			  RETURNING updated_at, version`
+	if err := r.db.conn(ctx).QueryRow(ctx, query, t.ID, t.Name, t.UpdatedBy, t.TenantID, t.Version).Scan(&t.UpdatedAt, &t.Version); err != nil {
+		if r.db.versionMismatch(ctx, err, "tags", t.ID, t.TenantID, t.Version) {
+			return domain.ErrVersionMismatch
+		}
+		return translateError(err, domain.ErrTagNotFound, "failed to update tag")
 	}
 	return nil
 }
 
 func (r *TagRepository) Delete(ctx context.Context, id, tenantID, userID string) error {
 	query := `UPDATE tags SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $3 WHERE id = $1 AND tenant_id = $2`
-	_, err := r.db.Pool.Exec(ctx, query, id, tenantID, userID)
+	_, err := r.db.conn(ctx).Exec(ctx, query, id, tenantID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete tag: %w", err)
 	}
@@ -81,7 +103,7 @@ func (r *TagRepository) ValidateTags(ctx context.Context, tenantID string, tagID
 	}
 	query := `SELECT COUNT(*) FROM tags WHERE tenant_id = $1 AND id = ANY($2) AND deactivated_at IS NULL`
 	var count int
-	err := r.db.Pool.QueryRow(ctx, query, tenantID, tagIDs).Scan(&count)
+	err := r.db.conn(ctx).QueryRow(ctx, query, tenantID, tagIDs).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to validate tags: %w", err)
 	}
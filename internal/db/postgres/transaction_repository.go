@@ -3,8 +3,12 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
 )
 
 type TransactionRepository struct {
@@ -15,40 +19,151 @@ func NewTransactionRepository(db *DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-func (r *TransactionRepository) GetByID(ctx context.Context, tenantID, id string) (*domain.Transaction, error) {
-	query := `SELECT id, parent_transaction_id, tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, created_at, created_by, updated_at, updated_by, deactivated_at, deactivated_by FROM transactions WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+// transactionColumns is the column list read by every transaction query, in the order scanTransaction expects.
+const transactionColumns = `id, parent_transaction_id, installment_number, installment_count, recurrence_rule_id, recurrence_date, tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, settled_amount, linked_transaction_id, transfer_direction, exchange_rate, fee, created_at, created_by, updated_at, updated_by, version, deactivated_at, deactivated_by`
+
+func scanTransaction(row pgx.Row) (*domain.Transaction, error) {
 	var t domain.Transaction
-	err := r.db.Pool.QueryRow(ctx, query, id, tenantID).Scan(
-		&t.ID, &t.ParentTransactionID, &t.TenantID, &t.FromAccountID, &t.ToAccountID, &t.Currency, &t.Amount, &t.AccrualMonth, &t.TransactionType, &t.CategoryID, &t.Comments, &t.DueDate, &t.PaymentDate, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.DeactivatedAt, &t.DeactivatedBy,
-	)
+	err := row.Scan(&t.ID, &t.ParentTransactionID, &t.InstallmentNumber, &t.InstallmentCount, &t.RecurrenceRuleID, &t.RecurrenceDate, &t.TenantID, &t.FromAccountID, &t.ToAccountID, &t.Currency, &t.Amount, &t.AccrualMonth, &t.TransactionType, &t.CategoryID, &t.Comments, &t.DueDate, &t.PaymentDate, &t.SettledAmount, &t.LinkedTransactionID, &t.TransferDirection, &t.ExchangeRate, &t.Fee, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.Version, &t.DeactivatedAt, &t.DeactivatedBy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction by id: %w", err)
+		return nil, err
 	}
+	t.Amount.Currency = t.Currency
+	t.SettledAmount.Currency = t.Currency
+	t.Fee.Currency = t.Currency
 	return &t, nil
 }
 
-func (r *TransactionRepository) List(ctx context.Context, tenantID string, filter domain.TransactionFilter) ([]domain.Transaction, error) {
-	query := `SELECT id, parent_transaction_id, tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, created_at, created_by, updated_at, updated_by, deactivated_at, deactivated_by FROM transactions WHERE tenant_id = $1 AND deactivated_at IS NULL`
-	args := []interface{}{tenantID}
-	argIdx := 2
+// transactionFilterClause builds the "FROM transactions WHERE ..." clause of a filtered list.
+// Every filter value is bound as a parameter.
+func transactionFilterClause(tenantID string, f domain.TransactionFilter) (string, []any) {
+	var args queryArgs
+	tenant := args.add(tenantID)
+	conds := []string{"tenant_id = " + tenant, "deactivated_at IS NULL"}
 
-	if filter.AccrualMonth != "" {
-		query += fmt.Sprintf(" AND accrual_month = $%d", argIdx)
-		args = append(args, filter.AccrualMonth)
-		argIdx++
+	if f.AccrualMonth != "" {
+		conds = append(conds, "accrual_month = "+args.add(f.AccrualMonth))
+	}
+	if f.AccrualMonthFrom != "" {
+		conds = append(conds, "accrual_month >= "+args.add(f.AccrualMonthFrom))
+	}
+	if f.AccrualMonthTo != "" {
+		conds = append(conds, "accrual_month <= "+args.add(f.AccrualMonthTo))
+	}
+	if f.AccountID != "" {
+		conds = append(conds, "from_account_id = "+args.add(f.AccountID))
+	}
+	if f.TransactionType != "" {
+		conds = append(conds, "transaction_type = "+args.add(f.TransactionType))
 	}
-	if filter.AccountID != "" {
-		query += fmt.Sprintf(" AND from_account_id = $%d", argIdx)
-		args = append(args, filter.AccountID)
-		argIdx++
+	if f.DueDateFrom != nil {
+		conds = append(conds, "due_date >= "+args.add(*f.DueDateFrom))
 	}
-	if filter.TransactionType != "" {
-		query += fmt.Sprintf(" AND transaction_type = $%d", argIdx)
-		args = append(args, filter.TransactionType)
-		argIdx++
+	if f.DueDateTo != nil {
+		conds = append(conds, "due_date <= "+args.add(*f.DueDateTo))
+	}
+	if f.PaymentDateFrom != nil {
+		conds = append(conds, "payment_date >= "+args.add(*f.PaymentDateFrom))
+	}
+	if f.PaymentDateTo != nil {
+		conds = append(conds, "payment_date <= "+args.add(*f.PaymentDateTo))
+	}
+	if f.MinAmount != nil {
+		conds = append(conds, "amount >= "+args.add(*f.MinAmount))
+	}
+	if f.MaxAmount != nil {
+		conds = append(conds, "amount <= "+args.add(*f.MaxAmount))
+	}
+	if len(f.CategoryIDs) > 0 {
+		ids := args.add(f.CategoryIDs)
+		if f.IncludeSubcategories {
+			conds = append(conds, `category_id IN (
+				WITH RECURSIVE tree AS (
+					SELECT id FROM categories WHERE tenant_id = `+tenant+` AND id = ANY(`+ids+`::uuid[])
+					UNION
+					SELECT c.id FROM categories c JOIN tree ON c.parent_category = tree.id WHERE c.tenant_id = `+tenant+`
+				)
+				SELECT id FROM tree)`)
+		} else {
+			conds = append(conds, "category_id = ANY("+ids+"::uuid[])")
+		}
+	}
+	if len(f.TagIDs) > 0 {
+		tagIDs := slices.Clone(f.TagIDs)
+		slices.Sort(tagIDs)
+		tagIDs = slices.Compact(tagIDs)
+		ids := args.add(tagIDs)
+		if f.TagMatch == domain.TagMatchAll {
+			conds = append(conds, `(SELECT COUNT(DISTINCT tt.tag_id) FROM transactions_tags tt
+				WHERE tt.transaction_id = transactions.id AND tt.tag_id = ANY(`+ids+`::uuid[])) = `+args.add(len(tagIDs)))
+		} else {
+			conds = append(conds, `EXISTS (SELECT 1 FROM transactions_tags tt
+				WHERE tt.transaction_id = transactions.id AND tt.tag_id = ANY(`+ids+`::uuid[]))`)
+		}
+	}
+	switch f.Status {
+	case domain.TransactionStatusPaid:
+		conds = append(conds, "payment_date IS NOT NULL")
+	case domain.TransactionStatusUnpaid:
+		conds = append(conds, "payment_date IS NULL")
+	case domain.TransactionStatusOverdue:
+		conds = append(conds, "payment_date IS NULL AND due_date < CURRENT_DATE")
+	case domain.TransactionStatusPending:
+		conds = append(conds, "payment_date IS NULL AND due_date >= CURRENT_DATE AND settled_amount = 0")
+	case domain.TransactionStatusPartiallyPaid:
+		conds = append(conds, "payment_date IS NULL AND due_date >= CURRENT_DATE AND settled_amount > 0")
+	}
+	if f.ParentTransactionID != "" {
+		parent := args.add(f.ParentTransactionID)
+		conds = append(conds, "(id = "+parent+" OR parent_transaction_id = "+parent+")")
+	}
+	if f.Currency != "" {
+		conds = append(conds, "currency = "+args.add(f.Currency))
+	}
+	if f.Search != "" {
+		conds = append(conds, "comments ILIKE '%' || "+args.add(likeEscaper.Replace(f.Search))+" || '%'")
 	}
 
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	return "FROM transactions WHERE " + strings.Join(conds, " AND "), args
+}
+
+// likeEscaper escapes the LIKE wildcards of user input, so it is matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// transactionKeyset orders transactions newest due date first.
+var transactionKeyset = keyset{columns: []string{"due_date", "id"}, desc: true}
+
+func transactionCursor(t *domain.Transaction) string {
+	return domain.EncodeCursor(t.DueDate.Format(time.DateOnly), t.ID)
+}
+
+func (r *TransactionRepository) GetByID(ctx context.Context, tenantID, id string) (*domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	t, err := scanTransaction(r.db.conn(ctx).QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		return nil, translateError(err, domain.ErrTransactionNotFound, "failed to get transaction by id")
+	}
+	return t, nil
+}
+
+func (r *TransactionRepository) List(ctx context.Context, tenantID string, filter domain.TransactionFilter, page domain.PageRequest) (*domain.Page[domain.Transaction], error) {
+	from, args := transactionFilterClause(tenantID, filter)
+
+	var after []any
+	if page.Cursor != "" {
+		values, err := decodeCursor(page.Cursor, 2)
+		if err != nil {
+			return nil, err
+		}
+		dueDate, err := time.Parse(time.DateOnly, values[0])
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		after = []any{dueDate, values[1]}
+	}
+
+	query, queryArgs := transactionKeyset.apply(`SELECT `+transactionColumns+` `+from, args, page, after)
+	rows, err := r.db.conn(ctx).Query(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transactions: %w", err)
 	}
@@ -56,39 +171,136 @@ func (r *TransactionRepository) List(ctx context.Context, tenantID string, filte
 
 	var transactions []domain.Transaction
 	for rows.Next() {
-		var t domain.Transaction
-		if err := rows.Scan(&t.ID, &t.ParentTransactionID, &t.TenantID, &t.FromAccountID, &t.ToAccountID, &t.Currency, &t.Amount, &t.AccrualMonth, &t.TransactionType, &t.CategoryID, &t.Comments, &t.DueDate, &t.PaymentDate, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.DeactivatedAt, &t.DeactivatedBy); err != nil {
+		t, err := scanTransaction(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan transaction: %w", err)
 		}
-		transactions = append(transactions, t)
+		transactions = append(transactions, *t)
 	}
-	return transactions, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	result := pageOf(transactions, page, transactionCursor)
+	if page.IncludeTotal {
+		if result.Total, err = r.db.countRows(ctx, from, args); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 func (r *TransactionRepository) Create(ctx context.Context, t *domain.Transaction) error {
-	query := `INSERT INTO transactions (parent_transaction_id, tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, created_by, updated_by)
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-			  RETURNING id, created_at, updated_at`
-	row := r.db.Pool.QueryRow(ctx, query, t.ParentTransactionID, t.TenantID, t.FromAccountID, t.ToAccountID, t.Currency, t.Amount, t.AccrualMonth, t.TransactionType, t.CategoryID, t.Comments, t.DueDate, t.PaymentDate, t.CreatedBy, t.UpdatedBy)
-	if err := row.Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to create transaction: %w", err)
+	query := `INSERT INTO transactions (parent_transaction_id, installment_number, installment_count, tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, created_by, updated_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			  RETURNING id, created_at, updated_at, version`
+	row := r.db.conn(ctx).QueryRow(ctx, query, t.ParentTransactionID, t.InstallmentNumber, t.InstallmentCount, t.TenantID, t.FromAccountID, t.ToAccountID, t.Currency, t.Amount, t.AccrualMonth, t.TransactionType, t.CategoryID, t.Comments, t.DueDate, t.PaymentDate, t.CreatedBy, t.UpdatedBy)
+	if err := row.Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt, &t.Version); err != nil {
+		return translateError(err, nil, "failed to create transaction")
 	}
 	t.UpdatedBy = t.CreatedBy // Initial state
 	return nil
 }
 
+// updateTransactionQuery saves the editable columns of a transaction at its version. The series
+// columns (parent_transaction_id, installment_*) are left as they are; series changes go through ApplySeriesChange.
+const updateTransactionQuery = `UPDATE transactions SET from_account_id = $2, to_account_id = $3, currency = $4, amount = $5, accrual_month = $6, transaction_type = $7, category_id = $8, comments = $9, due_date = $10, payment_date = $11, exchange_rate = $15, fee = $16, updated_at = CURRENT_TIMESTAMP, updated_by = $12, version = version + 1
+	WHERE id = $1 AND tenant_id = $13 AND deactivated_at IS NULL AND ($14 = 0 OR version = $14)
+	RETURNING parent_transaction_id, installment_number, installment_count, settled_amount, updated_at, version`
+
+// rowQuerier is implemented by both the pool and a pgx transaction.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// update saves t through q.
+func (r *TransactionRepository) update(ctx context.Context, q rowQuerier, t *domain.Transaction) error {
+	row := q.QueryRow(ctx, updateTransactionQuery, t.ID, t.FromAccountID, t.ToAccountID, t.Currency, t.Amount, t.AccrualMonth, t.TransactionType, t.CategoryID, t.Comments, t.DueDate, t.PaymentDate, t.UpdatedBy, t.TenantID, t.Version, t.ExchangeRate, t.Fee)
+	if err := row.Scan(&t.ParentTransactionID, &t.InstallmentNumber, &t.InstallmentCount, &t.SettledAmount, &t.UpdatedAt, &t.Version); err != nil {
+		if r.db.versionMismatch(ctx, err, "transactions", t.ID, t.TenantID, t.Version) {
+			return domain.ErrVersionMismatch
+		}
+		return translateError(err, domain.ErrTransactionNotFound, "failed to update transaction")
+	}
+	t.SettledAmount.Currency = t.Currency
+	return nil
+}
+
 func (r *TransactionRepository) Update(ctx context.Context, t *domain.Transaction) error {
-	query := `UPDATE transactions SET parent_transaction_id = $2, from_account_id = $3, to_account_id = $4, currency = $5, amount = $6, accrual_month = $7, transaction_type = $8, category_id = $9, comments = $10, due_date = $11, payment_date = $12, updated_at = CURRENT_TIMESTAMP, updated_by = $13 WHERE id = $1 AND tenant_id = $14 RETURNING updated_at`
-	row := r.db.Pool.QueryRow(ctx, query, t.ID, t.ParentTransactionID, t.FromAccountID, t.ToAccountID, t.Currency, t.Amount, t.AccrualMonth, t.TransactionType, t.CategoryID, t.Comments, t.DueDate, t.PaymentDate, t.UpdatedBy, t.TenantID)
-	if err := row.Scan(&t.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to update transaction: %w", err)
+	return r.update(ctx, r.db.conn(ctx), t)
+}
+
+// UpdateTransfer saves both legs of a transfer, each at its own version, in a single database transaction.
+func (r *TransactionRepository) UpdateTransfer(ctx context.Context, out, in *domain.Transaction) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, t := range []*domain.Transaction{out, in} {
+		if err := r.update(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	return nil
 }
 
+// CreateTransfer inserts both legs of a transfer, links them to each other and tags both, in a single database transaction.
+func (r *TransactionRepository) CreateTransfer(ctx context.Context, out, in *domain.Transaction, tagIDs []string) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertTransferLeg(ctx, tx, out); err != nil {
+		return err
+	}
+	in.LinkedTransactionID = &out.ID
+	if err := insertTransferLeg(ctx, tx, in); err != nil {
+		return err
+	}
+	out.LinkedTransactionID = &in.ID
+	if _, err := tx.Exec(ctx, `UPDATE transactions SET linked_transaction_id = $2 WHERE id = $1`, out.ID, in.ID); err != nil {
+		return fmt.Errorf("failed to link transfer legs: %w", err)
+	}
+
+	if len(tagIDs) > 0 {
+		insertQuery := `INSERT INTO transactions_tags (transaction_id, tag_id)
+						SELECT t, g FROM unnest($1::uuid[]) t CROSS JOIN unnest($2::uuid[]) g`
+		if _, err := tx.Exec(ctx, insertQuery, []string{out.ID, in.ID}, tagIDs); err != nil {
+			return translateError(err, nil, "failed to link tags")
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertTransferLeg inserts one leg of a transfer within tx.
+func insertTransferLeg(ctx context.Context, tx pgx.Tx, t *domain.Transaction) error {
+	query := `INSERT INTO transactions (tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, recurrence_rule_id, recurrence_date, linked_transaction_id, transfer_direction, exchange_rate, fee, created_by, updated_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			  RETURNING id, created_at, updated_at, version`
+	row := tx.QueryRow(ctx, query, t.TenantID, t.FromAccountID, t.ToAccountID, t.Currency, t.Amount, t.AccrualMonth, t.TransactionType, t.CategoryID, t.Comments, t.DueDate, t.PaymentDate, t.RecurrenceRuleID, t.RecurrenceDate, t.LinkedTransactionID, t.TransferDirection, t.ExchangeRate, t.Fee, t.CreatedBy, t.UpdatedBy)
+	if err := row.Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt, &t.Version); err != nil {
+		return translateError(err, nil, "failed to create transfer leg")
+	}
+	t.UpdatedBy = t.CreatedBy
+	return nil
+}
+
+// Delete soft-deletes a transaction, together with the other leg when it is a transfer.
 func (r *TransactionRepository) Delete(ctx context.Context, tenantID, id string, userID string) error {
-	query := `UPDATE transactions SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $2 WHERE id = $1 AND tenant_id = $3`
-	_, err := r.db.Pool.Exec(ctx, query, id, userID, tenantID)
+	query := `UPDATE transactions SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $2 WHERE (id = $1 OR linked_transaction_id = $1) AND tenant_id = $3`
+	_, err := r.db.conn(ctx).Exec(ctx, query, id, userID, tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to delete transaction: %w", err)
 	}
@@ -109,15 +321,15 @@ func (r *TransactionRepository) AddTagsToTransaction(ctx context.Context, transa
 	}
 	query = query[:len(query)-1] // Remove trailing comma
 
-	_, err := r.db.Pool.Exec(ctx, query, values...)
+	_, err := r.db.conn(ctx).Exec(ctx, query, values...)
 	if err != nil {
-		return fmt.Errorf("failed to add tags to transaction: %w", err)
+		return translateError(err, nil, "failed to add tags to transaction")
 	}
 	return nil
 }
 
 func (r *TransactionRepository) ReplaceTags(ctx context.Context, transactionID string, tagIDs []string) error {
-	tx, err := r.db.Pool.Begin(ctx)
+	tx, err := r.db.conn(ctx).Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -141,7 +353,7 @@ func (r *TransactionRepository) ReplaceTags(ctx context.Context, transactionID s
 		insertQuery = insertQuery[:len(insertQuery)-1] // Remove trailing comma
 
 		if _, err := tx.Exec(ctx, insertQuery, values...); err != nil {
-			return fmt.Errorf("failed to insert new tags: %w", err)
+			return translateError(err, nil, "failed to insert new tags")
 		}
 	}
 
@@ -153,49 +365,59 @@ func (r *TransactionRepository) ReplaceTags(ctx context.Context, transactionID s
 
 func (r *TransactionRepository) RemoveTagFromTransaction(ctx context.Context, transactionID, tagID string) error {
 	query := `DELETE FROM transactions_tags WHERE transaction_id = $1 AND tag_id = $2`
-	_, err := r.db.Pool.Exec(ctx, query, transactionID, tagID)
+	_, err := r.db.conn(ctx).Exec(ctx, query, transactionID, tagID)
 	if err != nil {
 		return fmt.Errorf("failed to remove tag from transaction: %w", err)
 	}
 	return nil
 }
 
-func (r *TransactionRepository) ListTransactionTags(ctx context.Context, transactionID string) ([]domain.Tag, error) {
-	query := `SELECT t.id, t.tenant_id, t.name, t.deactivated_at FROM tags t
-			  JOIN transactions_tags tt ON t.id = tt.tag_id
-			  WHERE tt.transaction_id = $1 AND t.deactivated_at IS NULL`
-	rows, err := r.db.Pool.Query(ctx, query, transactionID)
+func (r *TransactionRepository) ListTagsForTransactions(ctx context.Context, transactionIDs []string) (map[string][]domain.Tag, error) {
+	tags := make(map[string][]domain.Tag, len(transactionIDs))
+	if len(transactionIDs) == 0 {
+		return tags, nil
+	}
+
+	query := `SELECT tt.transaction_id, t.id, t.tenant_id, t.name, t.created_at, t.created_by, t.updated_at, t.updated_by, t.version, t.deactivated_at, t.deactivated_by
+			  FROM transactions_tags tt
+			  JOIN tags t ON t.id = tt.tag_id
+			  WHERE tt.transaction_id = ANY($1::uuid[]) AND t.deactivated_at IS NULL
+			  ORDER BY t.name, t.id`
+	rows, err := r.db.conn(ctx).Query(ctx, query, transactionIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transaction tags: %w", err)
 	}
 	defer rows.Close()
 
-	var tags []domain.Tag
 	for rows.Next() {
+		var transactionID string
 		var t domain.Tag
-		if err := rows.Scan(&t.ID, &t.TenantID, &t.Name, &t.DeactivatedAt); err != nil {
+		if err := rows.Scan(&transactionID, &t.ID, &t.TenantID, &t.Name, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.Version, &t.DeactivatedAt, &t.DeactivatedBy); err != nil {
 			return nil, fmt.Errorf("failed to scan tag: %w", err)
 		}
-		tags = append(tags, t)
+		tags[transactionID] = append(tags[transactionID], t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list transaction tags: %w", err)
 	}
 	return tags, nil
 }
 
 func (r *TransactionRepository) CreateWithInstallments(ctx context.Context, parent *domain.Transaction, children []domain.Transaction, tagIDs []string) error {
-	tx, err := r.db.Pool.Begin(ctx)
+	tx, err := r.db.conn(ctx).Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
 	// 1. Insert Parent
-	queryParent := `INSERT INTO transactions (parent_transaction_id, tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, created_by, updated_by)
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-			  RETURNING id, created_at, updated_at`
+	queryParent := `INSERT INTO transactions (parent_transaction_id, installment_number, installment_count, tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, created_by, updated_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			  RETURNING id, created_at, updated_at, version`
 
-	row := tx.QueryRow(ctx, queryParent, parent.ParentTransactionID, parent.TenantID, parent.FromAccountID, parent.ToAccountID, parent.Currency, parent.Amount, parent.AccrualMonth, parent.TransactionType, parent.CategoryID, parent.Comments, parent.DueDate, parent.PaymentDate, parent.CreatedBy, parent.UpdatedBy)
-	if err := row.Scan(&parent.ID, &parent.CreatedAt, &parent.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to create parent transaction: %w", err)
+	row := tx.QueryRow(ctx, queryParent, parent.ParentTransactionID, parent.InstallmentNumber, parent.InstallmentCount, parent.TenantID, parent.FromAccountID, parent.ToAccountID, parent.Currency, parent.Amount, parent.AccrualMonth, parent.TransactionType, parent.CategoryID, parent.Comments, parent.DueDate, parent.PaymentDate, parent.CreatedBy, parent.UpdatedBy)
+	if err := row.Scan(&parent.ID, &parent.CreatedAt, &parent.UpdatedAt, &parent.Version); err != nil {
+		return translateError(err, nil, "failed to create parent transaction")
 	}
 	parent.UpdatedBy = parent.CreatedBy
 
@@ -205,7 +427,7 @@ func (r *TransactionRepository) CreateWithInstallments(ctx context.Context, pare
 	// 2. Insert Children (if any)
 	if len(children) > 0 {
 		// Prepare bulk insert
-		queryChildren := `INSERT INTO transactions (parent_transaction_id, tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, created_by, updated_by) VALUES `
+		queryChildren := `INSERT INTO transactions (parent_transaction_id, installment_number, installment_count, tenant_id, from_account_id, to_account_id, currency, amount, accrual_month, transaction_type, category_id, comments, due_date, payment_date, created_by, updated_by) VALUES `
 		values := []interface{}{}
 		argIdx := 1
 
@@ -213,11 +435,11 @@ func (r *TransactionRepository) CreateWithInstallments(ctx context.Context, pare
 			// Link to Parent
 			child.ParentTransactionID = &parent.ID
 
-			queryChildren += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d),",
-				argIdx, argIdx+1, argIdx+2, argIdx+3, argIdx+4, argIdx+5, argIdx+6, argIdx+7, argIdx+8, argIdx+9, argIdx+10, argIdx+11, argIdx+12, argIdx+13)
+			queryChildren += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d),",
+				argIdx, argIdx+1, argIdx+2, argIdx+3, argIdx+4, argIdx+5, argIdx+6, argIdx+7, argIdx+8, argIdx+9, argIdx+10, argIdx+11, argIdx+12, argIdx+13, argIdx+14, argIdx+15)
 
-			values = append(values, child.ParentTransactionID, child.TenantID, child.FromAccountID, child.ToAccountID, child.Currency, child.Amount, child.AccrualMonth, child.TransactionType, child.CategoryID, child.Comments, child.DueDate, child.PaymentDate, child.CreatedBy, child.UpdatedBy)
-			argIdx += 14
+			values = append(values, child.ParentTransactionID, child.InstallmentNumber, child.InstallmentCount, child.TenantID, child.FromAccountID, child.ToAccountID, child.Currency, child.Amount, child.AccrualMonth, child.TransactionType, child.CategoryID, child.Comments, child.DueDate, child.PaymentDate, child.CreatedBy, child.UpdatedBy)
+			argIdx += 16
 		}
 
 		queryChildren = queryChildren[:len(queryChildren)-1] // Remove trailing comma
@@ -272,20 +494,199 @@ func (r *TransactionRepository) CreateWithInstallments(ctx context.Context, pare
 	return nil
 }
 
+// ListSeries returns the active installments of the series whose parent is parentID, in installment order.
+func (r *TransactionRepository) ListSeries(ctx context.Context, tenantID, parentID string) ([]domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions
+			  WHERE tenant_id = $1 AND (id = $2 OR parent_transaction_id = $2) AND installment_number IS NOT NULL AND deactivated_at IS NULL
+			  ORDER BY installment_number, due_date, id`
+	rows, err := r.db.conn(ctx).Query(ctx, query, tenantID, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list series: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []domain.Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list series: %w", err)
+	}
+	return transactions, nil
+}
+
+// ApplySeriesChange rewrites, re-tags and cancels installments of a series in a single database transaction,
+// moving the payments of the cancelled installments first when change.PaymentsTo is set.
+func (r *TransactionRepository) ApplySeriesChange(ctx context.Context, tenantID, userID string, change domain.SeriesChange) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if change.PaymentsTo != "" && len(change.Cancelled) > 0 {
+		moveQuery := `UPDATE transaction_payments SET transaction_id = $1
+					  WHERE transaction_id = ANY($2::uuid[]) AND tenant_id = $3 AND deactivated_at IS NULL`
+		if _, err := tx.Exec(ctx, moveQuery, change.PaymentsTo, change.Cancelled, tenantID); err != nil {
+			return fmt.Errorf("failed to move installment payments: %w", err)
+		}
+	}
+
+	updateQuery := `UPDATE transactions SET amount = $3, accrual_month = $4, category_id = $5, comments = $6, due_date = $7, payment_date = $8, settled_amount = $10, updated_at = CURRENT_TIMESTAMP, updated_by = $9, version = version + 1
+					WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	updatedIDs := make([]string, 0, len(change.Updated))
+	for _, t := range change.Updated {
+		tag, err := tx.Exec(ctx, updateQuery, t.ID, tenantID, t.Amount, t.AccrualMonth, t.CategoryID, t.Comments, t.DueDate, t.PaymentDate, userID, t.SettledAmount)
+		if err != nil {
+			return translateError(err, nil, "failed to update installment")
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrTransactionNotFound
+		}
+		updatedIDs = append(updatedIDs, t.ID)
+	}
+
+	if change.TagIDs != nil && len(updatedIDs) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM transactions_tags WHERE transaction_id = ANY($1::uuid[])`, updatedIDs); err != nil {
+			return fmt.Errorf("failed to delete existing tags: %w", err)
+		}
+		if len(change.TagIDs) > 0 {
+			insertQuery := `INSERT INTO transactions_tags (transaction_id, tag_id)
+							SELECT t, g FROM unnest($1::uuid[]) t CROSS JOIN unnest($2::uuid[]) g`
+			if _, err := tx.Exec(ctx, insertQuery, updatedIDs, change.TagIDs); err != nil {
+				return translateError(err, nil, "failed to insert new tags")
+			}
+		}
+	}
+
+	if len(change.Cancelled) > 0 {
+		cancelQuery := `UPDATE transactions SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $3
+						WHERE id = ANY($1::uuid[]) AND tenant_id = $2 AND deactivated_at IS NULL`
+		if _, err := tx.Exec(ctx, cancelQuery, change.Cancelled, tenantID, userID); err != nil {
+			return fmt.Errorf("failed to cancel installments: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// saveSettlementQuery saves the settlement state of a transaction at its version.
+const saveSettlementQuery = `UPDATE transactions SET settled_amount = $3, payment_date = $4, updated_at = CURRENT_TIMESTAMP, updated_by = $5, version = version + 1
+	WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL AND ($6 = 0 OR version = $6)
+	RETURNING updated_at, version`
+
+// saveSettlement saves the settlement state of t within tx.
+func (r *TransactionRepository) saveSettlement(ctx context.Context, tx pgx.Tx, t *domain.Transaction) error {
+	row := tx.QueryRow(ctx, saveSettlementQuery, t.ID, t.TenantID, t.SettledAmount, t.PaymentDate, t.UpdatedBy, t.Version)
+	if err := row.Scan(&t.UpdatedAt, &t.Version); err != nil {
+		if r.db.versionMismatch(ctx, err, "transactions", t.ID, t.TenantID, t.Version) {
+			return domain.ErrVersionMismatch
+		}
+		return translateError(err, domain.ErrTransactionNotFound, "failed to save transaction settlement")
+	}
+	return nil
+}
+
+func (r *TransactionRepository) AddPayments(ctx context.Context, txs []*domain.Transaction, payments []domain.TransactionPayment) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, t := range txs {
+		if err := r.saveSettlement(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+
+	insertQuery := `INSERT INTO transaction_payments (tenant_id, transaction_id, account_id, amount, payment_date, created_by)
+					VALUES ($1, $2, $3, $4, $5, $6)
+					RETURNING id, created_at`
+	for i := range payments {
+		p := &payments[i]
+		row := tx.QueryRow(ctx, insertQuery, p.TenantID, p.TransactionID, p.AccountID, p.Amount, p.PaymentDate, p.CreatedBy)
+		if err := row.Scan(&p.ID, &p.CreatedAt); err != nil {
+			return translateError(err, nil, "failed to add payment")
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *TransactionRepository) ClearPayments(ctx context.Context, t *domain.Transaction, userID string) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.saveSettlement(ctx, tx, t); err != nil {
+		return err
+	}
+	clearQuery := `UPDATE transaction_payments SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $3
+				   WHERE transaction_id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	if _, err := tx.Exec(ctx, clearQuery, t.ID, t.TenantID, userID); err != nil {
+		return fmt.Errorf("failed to clear payments: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListPayments returns the active payments of a transaction, oldest first.
+func (r *TransactionRepository) ListPayments(ctx context.Context, tenantID, transactionID string) ([]domain.TransactionPayment, error) {
+	query := `SELECT p.id, p.tenant_id, p.transaction_id, p.account_id, p.amount, t.currency, p.payment_date, p.created_at, p.created_by, p.deactivated_at, p.deactivated_by
+			  FROM transaction_payments p
+			  JOIN transactions t ON t.id = p.transaction_id
+			  WHERE p.tenant_id = $1 AND p.transaction_id = $2 AND p.deactivated_at IS NULL
+			  ORDER BY p.payment_date, p.created_at`
+	rows, err := r.db.conn(ctx).Query(ctx, query, tenantID, transactionID)
+	if err != nil {
+		return nil, translateError(err, nil, "failed to list payments")
+	}
+	defer rows.Close()
+
+	var payments []domain.TransactionPayment
+	for rows.Next() {
+		var p domain.TransactionPayment
+		if err := rows.Scan(&p.ID, &p.TenantID, &p.TransactionID, &p.AccountID, &p.Amount, &p.Amount.Currency, &p.PaymentDate, &p.CreatedAt, &p.CreatedBy, &p.DeactivatedAt, &p.DeactivatedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan payment: %w", err)
+		}
+		payments = append(payments, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+	return payments, nil
+}
+
 func (r *TransactionRepository) AddAttachment(ctx context.Context, a *domain.TransactionAttachment) error {
 	query := `INSERT INTO transaction_attachments (transaction_id, name, path, created_by, updated_by)
 			  VALUES ($1, $2, $3, $4, $5)
 			  RETURNING id, created_at, updated_at`
-	row := r.db.Pool.QueryRow(ctx, query, a.TransactionID, a.Name, a.Path, a.CreatedBy, a.UpdatedBy)
+	row := r.db.conn(ctx).QueryRow(ctx, query, a.TransactionID, a.Name, a.Path, a.CreatedBy, a.UpdatedBy)
 	if err := row.Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to add attachment: %w", err)
+		return translateError(err, nil, "failed to add attachment")
 	}
 	return nil
 }
 
 func (r *TransactionRepository) RemoveAttachment(ctx context.Context, id string, userID string) error {
 	query := `UPDATE transaction_attachments SET deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $2 WHERE id = $1`
-	_, err := r.db.Pool.Exec(ctx, query, id, userID)
+	_, err := r.db.conn(ctx).Exec(ctx, query, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to remove attachment: %w", err)
 	}
@@ -294,7 +695,7 @@ func (r *TransactionRepository) RemoveAttachment(ctx context.Context, id string,
 
 func (r *TransactionRepository) ListAttachments(ctx context.Context, transactionID string) ([]domain.TransactionAttachment, error) {
 	query := `SELECT id, transaction_id, name, path, created_at, created_by, updated_at, updated_by, deactivated_at, deactivated_by FROM transaction_attachments WHERE transaction_id = $1 AND deactivated_at IS NULL`
-	rows, err := r.db.Pool.Query(ctx, query, transactionID)
+	rows, err := r.db.conn(ctx).Query(ctx, query, transactionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list attachments: %w", err)
 	}
@@ -3,8 +3,10 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/igoventura/fintrack-api/domain"
+	"github.com/jackc/pgx/v5"
 )
 
 type UserRepository struct {
@@ -18,11 +20,11 @@ func NewUserRepository(db *DB) *UserRepository {
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	query := `SELECT id, supabase_id, name, email, created_at, updated_at, deactivated_at FROM users WHERE id = $1 AND deactivated_at IS NULL`
 	var u domain.User
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.conn(ctx).QueryRow(ctx, query, id).Scan(
 		&u.ID, &u.SupabaseID, &u.Name, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DeactivatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by id: %w", err)
+		return nil, translateError(err, domain.ErrUserNotFound, "failed to get user by id")
 	}
 	return &u, nil
 }
@@ -30,11 +32,11 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User,
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `SELECT id, supabase_id, name, email, created_at, updated_at, deactivated_at FROM users WHERE email = $1 AND deactivated_at IS NULL`
 	var u domain.User
-	err := r.db.Pool.QueryRow(ctx, query, email).Scan(
+	err := r.db.conn(ctx).QueryRow(ctx, query, email).Scan(
 		&u.ID, &u.SupabaseID, &u.Name, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DeactivatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by email: %w", err)
+		return nil, translateError(err, domain.ErrUserNotFound, "failed to get user by email")
 	}
 	return &u, nil
 }
@@ -42,11 +44,11 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 func (r *UserRepository) GetBySupabaseID(ctx context.Context, supabaseID string) (*domain.User, error) {
 	query := `SELECT id, supabase_id, name, email, created_at, updated_at, deactivated_at FROM users WHERE supabase_id = $1 AND deactivated_at IS NULL`
 	var u domain.User
-	err := r.db.Pool.QueryRow(ctx, query, supabaseID).Scan(
+	err := r.db.conn(ctx).QueryRow(ctx, query, supabaseID).Scan(
 		&u.ID, &u.SupabaseID, &u.Name, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DeactivatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by supabase id: %w", err)
+		return nil, translateError(err, domain.ErrUserNotFound, "failed to get user by supabase id")
 	}
 	return &u, nil
 }
@@ -55,46 +57,73 @@ func (r *UserRepository) Create(ctx context.Context, u *domain.User) error {
 	query := `INSERT INTO users (supabase_id, name, email)
 			  VALUES ($1, $2, $3)
 			  RETURNING id, created_at, updated_at`
-	row := r.db.Pool.QueryRow(ctx, query, u.SupabaseID, u.Name, u.Email)
+	row := r.db.conn(ctx).QueryRow(ctx, query, u.SupabaseID, u.Name, u.Email)
 	if err := row.Scan(&u.ID, &u.CreatedAt, &u.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+		return translateError(err, nil, "failed to create user")
 	}
 	return nil
 }
 
 func (r *UserRepository) Update(ctx context.Context, u *domain.User) error {
 	query := `UPDATE users SET name = $2, email = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING updated_at`
-	row := r.db.Pool.QueryRow(ctx, query, u.ID, u.Name, u.Email)
+	row := r.db.conn(ctx).QueryRow(ctx, query, u.ID, u.Name, u.Email)
 	if err := row.Scan(&u.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
+		return translateError(err, domain.ErrUserNotFound, "failed to update user")
 	}
 	return nil
 }
 
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	query := `UPDATE users SET deactivated_at = CURRENT_TIMESTAMP WHERE id = $1`
-	_, err := r.db.Pool.Exec(ctx, query, id)
+	_, err := r.db.conn(ctx).Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 	return nil
 }
 
-func (r *UserRepository) AddUserToTenant(ctx context.Context, userID, tenantID string) error {
-	query := `INSERT INTO users_tenants (user_id, tenant_id) VALUES ($1, $2)`
-	_, err := r.db.Pool.Exec(ctx, query, userID, tenantID)
+func (r *UserRepository) AddUserToTenant(ctx context.Context, userID, tenantID string, role domain.Role) error {
+	query := `INSERT INTO users_tenants (user_id, tenant_id, role) VALUES ($1, $2, $3)
+			  ON CONFLICT (user_id, tenant_id) DO UPDATE SET
+				role = EXCLUDED.role,
+				deactivated_at = NULL,
+				updated_at = CURRENT_TIMESTAMP`
+	_, err := r.db.conn(ctx).Exec(ctx, query, userID, tenantID, role)
 	if err != nil {
-		return fmt.Errorf("failed to add user to tenant: %w", err)
+		return translateError(err, nil, "failed to add user to tenant")
 	}
 	return nil
 }
 
+// RemoveUserFromTenant deactivates a membership.
+// The owners of the tenant are locked first, so that two owners removed at the same time cannot leave it without one.
 func (r *UserRepository) RemoveUserFromTenant(ctx context.Context, userID, tenantID string) error {
-	query := `UPDATE users_tenants SET deactivated_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND tenant_id = $2`
-	_, err := r.db.Pool.Exec(ctx, query, userID, tenantID)
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	owners, err := lockTenantOwners(ctx, tx, tenantID)
+	if err != nil {
+		return err
+	}
+	if slices.Equal(owners, []string{userID}) {
+		return domain.ErrLastOwner
+	}
+
+	query := `UPDATE users_tenants SET deactivated_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	tag, err := tx.Exec(ctx, query, userID, tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to remove user from tenant: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotTenantMember
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
@@ -103,7 +132,7 @@ func (r *UserRepository) ListUserTenants(ctx context.Context, userID string) ([]
 			  FROM users_tenants ut
 			  JOIN tenants t ON ut.tenant_id = t.id
 			  WHERE user_id = $1 AND ut.deactivated_at IS NULL AND t.deactivated_at IS NULL`
-	rows, err := r.db.Pool.Query(ctx, query, userID)
+	rows, err := r.db.conn(ctx).Query(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list user tenants: %w", err)
 	}
@@ -119,3 +148,99 @@ func (r *UserRepository) ListUserTenants(ctx context.Context, userID string) ([]
 	}
 	return tenants, nil
 }
+
+func (r *UserRepository) GetUserTenant(ctx context.Context, userID, tenantID string) (*domain.UserTenant, error) {
+	query := `SELECT ut.user_id, ut.tenant_id, ut.role, ut.created_at, ut.updated_at, ut.deactivated_at
+			  FROM users_tenants ut
+			  JOIN tenants t ON ut.tenant_id = t.id
+			  WHERE ut.user_id = $1 AND ut.tenant_id = $2 AND ut.deactivated_at IS NULL AND t.deactivated_at IS NULL`
+	var ut domain.UserTenant
+	err := r.db.conn(ctx).QueryRow(ctx, query, userID, tenantID).Scan(&ut.UserID, &ut.TenantID, &ut.Role, &ut.CreatedAt, &ut.UpdatedAt, &ut.DeactivatedAt)
+	if err != nil {
+		return nil, translateError(err, domain.ErrNotTenantMember, "failed to get user tenant")
+	}
+	return &ut, nil
+}
+
+func (r *UserRepository) ListTenantMembers(ctx context.Context, tenantID string) ([]domain.TenantMember, error) {
+	query := `SELECT u.id, u.name, u.email, ut.role, ut.created_at
+			  FROM users_tenants ut
+			  JOIN users u ON ut.user_id = u.id
+			  WHERE ut.tenant_id = $1 AND ut.deactivated_at IS NULL AND u.deactivated_at IS NULL
+			  ORDER BY u.name, u.id`
+	rows, err := r.db.conn(ctx).Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenant members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []domain.TenantMember
+	for rows.Next() {
+		var m domain.TenantMember
+		if err := rows.Scan(&m.UserID, &m.Name, &m.Email, &m.Role, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant member: %w", err)
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tenant members: %w", err)
+	}
+	return members, nil
+}
+
+// UpdateUserTenantRole changes the role of a member.
+// Like RemoveUserFromTenant, it locks the owners of the tenant first so that the last one cannot be demoted.
+func (r *UserRepository) UpdateUserTenantRole(ctx context.Context, userID, tenantID string, role domain.Role) error {
+	tx, err := r.db.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	owners, err := lockTenantOwners(ctx, tx, tenantID)
+	if err != nil {
+		return err
+	}
+	if role != domain.RoleOwner && slices.Equal(owners, []string{userID}) {
+		return domain.ErrLastOwner
+	}
+
+	query := `UPDATE users_tenants SET role = $3, updated_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND tenant_id = $2 AND deactivated_at IS NULL`
+	tag, err := tx.Exec(ctx, query, userID, tenantID, role)
+	if err != nil {
+		return fmt.Errorf("failed to update user tenant role: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotTenantMember
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// lockTenantOwners locks the active owner memberships of a tenant and returns their user IDs.
+// A concurrent transaction waiting on the lock re-reads the rows once it is released,
+// so it sees an owner demoted or removed in the meantime.
+func lockTenantOwners(ctx context.Context, tx pgx.Tx, tenantID string) ([]string, error) {
+	query := `SELECT user_id FROM users_tenants WHERE tenant_id = $1 AND role = $2 AND deactivated_at IS NULL FOR UPDATE`
+	rows, err := tx.Query(ctx, query, tenantID, domain.RoleOwner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock tenant owners: %w", err)
+	}
+	defer rows.Close()
+
+	var owners []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant owner: %w", err)
+		}
+		owners = append(owners, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock tenant owners: %w", err)
+	}
+	return owners, nil
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	// 2024-01-31 is a Wednesday
+	from := time.Date(2024, 1, 31, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "*/15 * * * *", want: time.Date(2024, 1, 31, 10, 15, 0, 0, time.UTC)},
+		{expr: "@daily", want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "30 9 * * 1-5", want: time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC)},
+		{expr: "0 0 31 * *", want: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 8 1 * 0", want: time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC)}, // Day of month or Sunday
+		{expr: "0 12 * * 7", want: time.Date(2024, 2, 4, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@yearly"} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("ParseSchedule(%q) expected an error", expr)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// Job is a periodic task. Run returns a short summary of what it did, recorded with the run.
+type Job struct {
+	Name     string
+	Schedule string // Cron expression, in UTC
+	Run      func(ctx context.Context) (string, error)
+}
+
+// Clock abstracts time so the scheduler can be driven by a fake clock in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Status is the state of a registered job, as reported by the admin endpoint.
+type Status struct {
+	Name     string
+	Schedule string
+	NextRun  time.Time      // Zero when the scheduler is not running on this instance
+	LastRun  *domain.JobRun // Across all instances; nil if the job never ran
+}
+
+// Scheduler runs registered jobs on their cron schedules.
+//
+// Every replica may run a scheduler: a job's advisory lock keeps two instances from running it at
+// the same time, and its run history records each scheduled slot once, so a slot is run by a single
+// instance. Due jobs run one after the other; a slot missed while the process was down is not caught up.
+type Scheduler struct {
+	runs     domain.JobRunRepository
+	clock    Clock
+	instance string
+
+	mu      sync.Mutex
+	entries []*entry
+	running bool
+}
+
+type entry struct {
+	job      Job
+	schedule *Schedule
+	next     time.Time
+}
+
+func NewScheduler(runs domain.JobRunRepository) *Scheduler {
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "unknown"
+	}
+	return &Scheduler{runs: runs, clock: realClock{}, instance: instance}
+}
+
+// Register adds a job. Its first run is the next slot of its schedule.
+func (s *Scheduler) Register(job Job) error {
+	schedule, err := ParseSchedule(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if e.job.Name == job.Name {
+			return fmt.Errorf("job %s is already registered", job.Name)
+		}
+	}
+	s.entries = append(s.entries, &entry{job: job, schedule: schedule, next: schedule.Next(s.clock.Now())})
+	return nil
+}
+
+// Run runs the due jobs until ctx is cancelled. Jobs are scheduled from the time it starts.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.start()
+	defer s.stop()
+	for {
+		var wait <-chan time.Time
+		if next := s.nextRun(); !next.IsZero() {
+			wait = s.clock.After(max(next.Sub(s.clock.Now()), 0))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-wait:
+			s.runDue(ctx)
+		}
+	}
+}
+
+// Status returns the registered jobs with their next run on this instance and their latest run.
+func (s *Scheduler) Status(ctx context.Context) ([]Status, error) {
+	latest, err := s.runs.ListLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*domain.JobRun, len(latest))
+	for i := range latest {
+		byName[latest[i].JobName] = &latest[i]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]Status, 0, len(s.entries))
+	for _, e := range s.entries {
+		status := Status{
+			Name:     e.job.Name,
+			Schedule: e.schedule.String(),
+			LastRun:  byName[e.job.Name],
+		}
+		if s.running {
+			status.NextRun = e.next
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (s *Scheduler) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	for _, e := range s.entries {
+		e.next = e.schedule.Next(now)
+	}
+	s.running = true
+}
+
+func (s *Scheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+}
+
+// nextRun returns the earliest next run of the registered jobs, or the zero time if there are none.
+func (s *Scheduler) nextRun() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, e := range s.entries {
+		if !e.next.IsZero() && (next.IsZero() || e.next.Before(next)) {
+			next = e.next
+		}
+	}
+	return next
+}
+
+// runDue runs the jobs whose next run has come and schedules their following one.
+func (s *Scheduler) runDue(ctx context.Context) {
+	now := s.clock.Now()
+
+	type due struct {
+		job         Job
+		scheduledAt time.Time
+	}
+	var dueJobs []due
+	s.mu.Lock()
+	for _, e := range s.entries {
+		if !e.next.IsZero() && !e.next.After(now) {
+			dueJobs = append(dueJobs, due{job: e.job, scheduledAt: e.next})
+			e.next = e.schedule.Next(now)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, d := range dueJobs {
+		if ctx.Err() != nil {
+			return
+		}
+		s.runJob(ctx, d.job, d.scheduledAt)
+	}
+}
+
+// runJob runs a job for a scheduled slot unless another instance holds its lock or already ran the slot.
+func (s *Scheduler) runJob(ctx context.Context, job Job, scheduledAt time.Time) {
+	release, acquired, err := s.runs.TryLock(ctx, job.Name)
+	if err != nil {
+		log.Printf("job %s: %v", job.Name, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	defer release()
+
+	run := &domain.JobRun{JobName: job.Name, ScheduledAt: scheduledAt, StartedAt: s.clock.Now(), Instance: s.instance}
+	started, err := s.runs.StartRun(ctx, run)
+	if err != nil {
+		log.Printf("job %s: %v", job.Name, err)
+		return
+	}
+	if !started {
+		return
+	}
+
+	summary, runErr := s.execute(ctx, job)
+	finishedAt := s.clock.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = domain.JobRunStatusSucceeded
+	if summary != "" {
+		run.Summary = &summary
+	}
+	if runErr != nil {
+		msg := runErr.Error()
+		run.Status = domain.JobRunStatusFailed
+		run.Error = &msg
+		log.Printf("job %s failed: %v", job.Name, runErr)
+	}
+
+	// Record the outcome even if the scheduler is shutting down.
+	if err := s.runs.FinishRun(context.WithoutCancel(ctx), run); err != nil {
+		log.Printf("job %s: %v", job.Name, err)
+	}
+}
+
+// execute runs the job, turning a panic into an error.
+func (s *Scheduler) execute(ctx context.Context, job Job) (summary string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
@@ -0,0 +1,138 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time                         { return c.now }
+func (c *fakeClock) After(d time.Duration) <-chan time.Time { return make(chan time.Time) }
+
+type fakeRunRepo struct {
+	domain.JobRunRepository
+	locked   bool
+	slots    map[string]bool
+	finished []domain.JobRun
+}
+
+func (r *fakeRunRepo) TryLock(ctx context.Context, jobName string) (func(), bool, error) {
+	if r.locked {
+		return nil, false, nil
+	}
+	return func() {}, true, nil
+}
+
+func (r *fakeRunRepo) StartRun(ctx context.Context, run *domain.JobRun) (bool, error) {
+	key := run.JobName + run.ScheduledAt.String()
+	if r.slots[key] {
+		return false, nil
+	}
+	r.slots[key] = true
+	return true, nil
+}
+
+func (r *fakeRunRepo) FinishRun(ctx context.Context, run *domain.JobRun) error {
+	r.finished = append(r.finished, *run)
+	return nil
+}
+
+func newTestScheduler(t *testing.T, repo *fakeRunRepo, clock *fakeClock, jobs ...Job) *Scheduler {
+	t.Helper()
+	s := NewScheduler(repo)
+	s.clock = clock
+	for _, job := range jobs {
+		if err := s.Register(job); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+	s.start()
+	return s
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 10, 0, 30, 0, time.UTC)}
+	repo := &fakeRunRepo{slots: map[string]bool{}}
+	calls := 0
+	s := newTestScheduler(t, repo, clock,
+		Job{Name: "count", Schedule: "*/5 * * * *", Run: func(ctx context.Context) (string, error) {
+			calls++
+			return "counted", nil
+		}},
+		Job{Name: "fail", Schedule: "0 * * * *", Run: func(ctx context.Context) (string, error) {
+			return "", errors.New("boom")
+		}},
+	)
+
+	// Not due yet
+	clock.now = time.Date(2024, 1, 1, 10, 4, 0, 0, time.UTC)
+	s.runDue(context.Background())
+	if calls != 0 {
+		t.Fatalf("expected no run before 10:05, got %d", calls)
+	}
+
+	// Due: runs once, and the next run moves to 10:10
+	clock.now = time.Date(2024, 1, 1, 10, 5, 1, 0, time.UTC)
+	s.runDue(context.Background())
+	s.runDue(context.Background())
+	if calls != 1 {
+		t.Fatalf("expected 1 run, got %d", calls)
+	}
+	if next := s.nextRun(); !next.Equal(time.Date(2024, 1, 1, 10, 10, 0, 0, time.UTC)) {
+		t.Errorf("nextRun() = %v, want 10:10", next)
+	}
+	if len(repo.finished) != 1 || repo.finished[0].Status != domain.JobRunStatusSucceeded || *repo.finished[0].Summary != "counted" {
+		t.Errorf("unexpected recorded run: %+v", repo.finished)
+	}
+
+	// Slots missed while stopped are not caught up: 11:00 runs each job once
+	clock.now = time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)
+	s.runDue(context.Background())
+	if calls != 2 {
+		t.Errorf("expected 2 runs, got %d", calls)
+	}
+	last := repo.finished[len(repo.finished)-1]
+	if last.JobName != "fail" || last.Status != domain.JobRunStatusFailed || *last.Error != "boom" {
+		t.Errorf("expected the failing job to be recorded as failed, got %+v", last)
+	}
+}
+
+func TestScheduler_SkipsWhenAnotherInstanceRuns(t *testing.T) {
+	tests := []struct {
+		name   string
+		locked bool
+		ran    bool // The slot was already run by another instance
+	}{
+		{name: "Lock Held Elsewhere", locked: true},
+		{name: "Slot Already Run", ran: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2024, 1, 1, 10, 0, 30, 0, time.UTC)}
+			repo := &fakeRunRepo{locked: tt.locked, slots: map[string]bool{}}
+			calls := 0
+			s := newTestScheduler(t, repo, clock, Job{Name: "count", Schedule: "@hourly", Run: func(ctx context.Context) (string, error) {
+				calls++
+				return "", nil
+			}})
+			slot := time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)
+			if tt.ran {
+				repo.slots["count"+slot.String()] = true
+			}
+
+			clock.now = slot
+			s.runDue(context.Background())
+			if calls != 0 || len(repo.finished) != 0 {
+				t.Errorf("expected the job to be skipped, got %d runs", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Overdue transactions and statement closing need no job: their status is derived from the
+// dates at read time (see domain.TransactionStatusOverdue and domain.Statement.Finalize).
+
+// InvitationExpirer is implemented by service.InvitationService.
+type InvitationExpirer interface {
+	ExpireInvitations(ctx context.Context) (int64, error)
+}
+
+// RecurrenceMaterializer is implemented by service.RecurrenceService.
+type RecurrenceMaterializer interface {
+	MaterializeAll(ctx context.Context) (int, error)
+}
+
+// ExpireInvitations marks pending invitations past their expiry as expired.
+func ExpireInvitations(invitations InvitationExpirer) Job {
+	return Job{
+		Name:     "expire-invitations",
+		Schedule: "*/15 * * * *",
+		Run: func(ctx context.Context) (string, error) {
+			n, err := invitations.ExpireInvitations(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("expired %d invitations", n), nil
+		},
+	}
+}
+
+// MaterializeRecurrences keeps the occurrences of every tenant's recurrence rules materialized up to the horizon.
+func MaterializeRecurrences(recurrences RecurrenceMaterializer) Job {
+	return Job{
+		Name:     "materialize-recurrences",
+		Schedule: "0 2 * * *",
+		Run: func(ctx context.Context) (string, error) {
+			n, err := recurrences.MaterializeAll(ctx)
+			return fmt.Sprintf("created %d transactions", n), err
+		},
+	}
+}
+
+// IdempotencyKeyPurger is implemented by postgres.IdempotencyKeyRepository.
+type IdempotencyKeyPurger interface {
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// PurgeIdempotencyKeys deletes the idempotency keys past their TTL.
+func PurgeIdempotencyKeys(keys IdempotencyKeyPurger) Job {
+	return Job{
+		Name:     "purge-idempotency-keys",
+		Schedule: "30 * * * *",
+		Run: func(ctx context.Context) (string, error) {
+			n, err := keys.DeleteExpired(ctx, time.Now())
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("deleted %d idempotency keys", n), nil
+		},
+	}
+}
@@ -0,0 +1,306 @@
+// Package ofx reads bank and credit card statements from OFX files, both 1.x (SGML, where elements
+// holding a value are not closed) and 2.x (XML).
+package ofx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+var (
+	ErrNotOFX      = errors.New("the file is not an OFX file")
+	ErrNoStatement = errors.New("the file has no bank or credit card statement")
+)
+
+// Statement is the first bank or credit card statement of an OFX file.
+type Statement struct {
+	Account      Account
+	Currency     string     // CURDEF
+	Start        *time.Time // DTSTART
+	End          *time.Time // DTEND
+	Transactions []Transaction
+	// LedgerBalance is the balance the institution reports at the end of the statement, when it does.
+	LedgerBalance *Balance
+}
+
+// Account identifies the account a statement belongs to.
+type Account struct {
+	BankID     string // Empty for credit cards
+	BranchID   string
+	AccountID  string // Account or card number, often masked
+	Type       string // CHECKING, SAVINGS, CREDITLINE...; CREDITCARD for credit card statements
+	CreditCard bool
+}
+
+// Transaction is a STMTTRN entry. Amount is signed: negative amounts leave the account.
+type Transaction struct {
+	FITID  string // Unique per account at the institution, so repeated downloads can be recognized
+	Type   string // TRNTYPE: CREDIT, DEBIT, PAYMENT, XFER...
+	Posted time.Time
+	Amount domain.Money
+	Name   string
+	Memo   string
+}
+
+// Description joins the name and the memo of the transaction, as banks put the details in either.
+func (t Transaction) Description() string {
+	switch {
+	case t.Name == "":
+		return t.Memo
+	case t.Memo == "" || strings.EqualFold(t.Memo, t.Name):
+		return t.Name
+	}
+	return t.Name + " - " + t.Memo
+}
+
+// Balance is a balance reported by the institution.
+type Balance struct {
+	Amount domain.Money
+	AsOf   time.Time
+}
+
+// IsOFX reports whether data looks like an OFX file.
+func IsOFX(data []byte) bool {
+	head := data[:min(len(data), 4096)]
+	return bytes.HasPrefix(bytes.TrimSpace(head), []byte("OFXHEADER")) || bytes.Contains(bytes.ToUpper(head), []byte("<OFX>"))
+}
+
+// Parse reads the first statement of an OFX file. Files not in UTF-8 are read as Latin-1, the
+// charset of most 1.x files.
+func Parse(data []byte) (*Statement, error) {
+	if !utf8.Valid(data) {
+		data = latin1ToUTF8(data)
+	}
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, ErrNotOFX
+	}
+	root := parseElements(string(data[start:]))
+
+	rs := root.find("STMTRS")
+	creditCard := false
+	if rs == nil {
+		rs = root.find("CCSTMTRS")
+		creditCard = true
+	}
+	if rs == nil {
+		return nil, ErrNoStatement
+	}
+
+	stmt := &Statement{Currency: strings.ToUpper(rs.value("CURDEF"))}
+	if creditCard {
+		from := rs.find("CCACCTFROM")
+		stmt.Account = Account{AccountID: from.value("ACCTID"), Type: "CREDITCARD", CreditCard: true}
+	} else {
+		from := rs.find("BANKACCTFROM")
+		stmt.Account = Account{BankID: from.value("BANKID"), BranchID: from.value("BRANCHID"), AccountID: from.value("ACCTID"), Type: from.value("ACCTTYPE")}
+	}
+
+	list := rs.find("BANKTRANLIST")
+	var err error
+	if stmt.Start, err = optionalDateTime(list.value("DTSTART")); err != nil {
+		return nil, fmt.Errorf("invalid DTSTART: %w", err)
+	}
+	if stmt.End, err = optionalDateTime(list.value("DTEND")); err != nil {
+		return nil, fmt.Errorf("invalid DTEND: %w", err)
+	}
+
+	for i, trn := range list.findAll("STMTTRN") {
+		t := Transaction{
+			FITID: trn.value("FITID"),
+			Type:  strings.ToUpper(trn.value("TRNTYPE")),
+			Name:  trn.value("NAME"),
+			Memo:  trn.value("MEMO"),
+		}
+		if t.FITID == "" {
+			return nil, fmt.Errorf("transaction %d has no FITID", i+1)
+		}
+		if t.Posted, err = ParseDateTime(trn.value("DTPOSTED")); err != nil {
+			return nil, fmt.Errorf("transaction %s: invalid DTPOSTED: %w", t.FITID, err)
+		}
+		if t.Amount, err = parseAmount(trn.value("TRNAMT"), stmt.Currency); err != nil {
+			return nil, fmt.Errorf("transaction %s: invalid TRNAMT %q", t.FITID, trn.value("TRNAMT"))
+		}
+		stmt.Transactions = append(stmt.Transactions, t)
+	}
+
+	if ledger := rs.find("LEDGERBAL"); ledger != nil {
+		amount, err := parseAmount(ledger.value("BALAMT"), stmt.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BALAMT %q", ledger.value("BALAMT"))
+		}
+		asOf, err := ParseDateTime(ledger.value("DTASOF"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid DTASOF: %w", err)
+		}
+		stmt.LedgerBalance = &Balance{Amount: amount, AsOf: asOf}
+	}
+	return stmt, nil
+}
+
+// ParseDateTime reads an OFX date and time, YYYYMMDD[HHMMSS[.XXX]][[offset[:name]]], e.g.
+// 20240305120000[-3:BRT]. Without an offset the time is in UTC.
+func ParseDateTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	loc := time.UTC
+	if i := strings.IndexByte(s, '['); i >= 0 {
+		zone := strings.TrimSuffix(s[i+1:], "]")
+		s = s[:i]
+		offset, name, _ := strings.Cut(zone, ":")
+		hours, err := strconv.ParseFloat(offset, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time zone %q", zone)
+		}
+		loc = time.FixedZone(name, int(hours*3600))
+	}
+	s, _, _ = strings.Cut(s, ".")
+
+	layouts := map[int]string{8: "20060102", 12: "200601021504", 14: "20060102150405"}
+	layout, ok := layouts[len(s)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	t, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	return t, nil
+}
+
+func optionalDateTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := ParseDateTime(s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseAmount reads an OFX amount. The spec allows a comma as the decimal separator, which some
+// banks also pair with dots separating thousands.
+func parseAmount(s, currency string) (domain.Money, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, ",") {
+		s = strings.ReplaceAll(strings.ReplaceAll(s, ".", ""), ",", ".")
+	}
+	return domain.ParseMoney(s, currency)
+}
+
+// element is an OFX aggregate, or a leaf element with its value.
+type element struct {
+	name     string
+	text     string
+	children []*element
+}
+
+// parseElements builds the element tree of an OFX body, starting at <OFX>. A tag followed by text is
+// a leaf whether or not it is closed, as in SGML; closing tags end the innermost open aggregate of
+// their name, closing any leaf left open inside it.
+func parseElements(body string) *element {
+	root := &element{}
+	stack := []*element{root}
+	for body != "" {
+		lt := strings.IndexByte(body, '<')
+		if lt < 0 {
+			lt = len(body)
+		}
+		if text := strings.TrimSpace(body[:lt]); text != "" && len(stack) > 1 {
+			top := stack[len(stack)-1]
+			if len(top.children) == 0 {
+				top.text = html.UnescapeString(text)
+				stack = stack[:len(stack)-1]
+			}
+		}
+		if lt == len(body) {
+			break
+		}
+		body = body[lt+1:]
+		gt := strings.IndexByte(body, '>')
+		if gt < 0 {
+			break
+		}
+		tag := strings.TrimSpace(body[:gt])
+		body = body[gt+1:]
+
+		switch {
+		case tag == "" || tag[0] == '?' || tag[0] == '!':
+			// Processing instructions and comments
+		case tag[0] == '/':
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+		default:
+			name, _, _ := strings.Cut(tag, " ")
+			e := &element{name: strings.ToUpper(strings.TrimSuffix(name, "/"))}
+			top := stack[len(stack)-1]
+			top.children = append(top.children, e)
+			if !strings.HasSuffix(tag, "/") {
+				stack = append(stack, e)
+			}
+		}
+	}
+	return root
+}
+
+// find returns the first element of the given name below e, depth first, or nil. Searching the
+// whole subtree copes with empty SGML leaves, which swallow the elements that follow them.
+func (e *element) find(name string) *element {
+	if e == nil {
+		return nil
+	}
+	for _, c := range e.children {
+		if c.name == name {
+			return c
+		}
+		if found := c.find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findAll returns the elements of the given name below e, not looking inside them.
+func (e *element) findAll(name string) []*element {
+	if e == nil {
+		return nil
+	}
+	var found []*element
+	for _, c := range e.children {
+		if c.name == name {
+			found = append(found, c)
+		} else {
+			found = append(found, c.findAll(name)...)
+		}
+	}
+	return found
+}
+
+// value returns the text of the first leaf of the given name below e, or "".
+func (e *element) value(name string) string {
+	if found := e.find(name); found != nil {
+		return found.text
+	}
+	return ""
+}
+
+func latin1ToUTF8(data []byte) []byte {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
@@ -0,0 +1,125 @@
+package ofx
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+func TestParse(t *testing.T) {
+	brt := time.FixedZone("BRT", -3*3600)
+	est := time.FixedZone("EST", -5*3600)
+	type want struct {
+		fitid, description string
+		posted             time.Time
+		cents              int64
+	}
+
+	tests := []struct {
+		file     string
+		account  Account
+		currency string
+		want     []want
+		ledger   Balance
+	}{
+		{
+			file:     "checking_sgml.ofx",
+			account:  Account{BankID: "0341", BranchID: "1234", AccountID: "56789-0", Type: "CHECKING"},
+			currency: "BRL",
+			want: []want{
+				{fitid: "202403050001", description: "PIX RECEBIDO JOÃO", posted: time.Date(2024, 3, 5, 0, 0, 0, 0, brt), cents: 150000},
+				{fitid: "202403060002", description: "COMPRA CARTÃO MERCADO & CIA", posted: time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), cents: -8990},
+				{fitid: "202403100003", description: "ALUGUEL", posted: time.Date(2024, 3, 10, 23, 59, 0, 0, brt), cents: -125000},
+			},
+			ledger: Balance{Amount: domain.NewMoney(216010, "BRL"), AsOf: time.Date(2024, 3, 31, 0, 0, 0, 0, brt)},
+		},
+		{
+			file:     "credit_card_xml.ofx",
+			account:  Account{AccountID: "XXXXXXXXXXXX4321", Type: "CREDITCARD", CreditCard: true},
+			currency: "USD",
+			want: []want{
+				{fitid: "2024032024692164", description: "BOOKSTORE <DOWNTOWN>", posted: time.Date(2024, 3, 20, 12, 0, 0, 0, est), cents: -4217},
+				{fitid: "2024040224692165", description: "REFUND - BOOKSTORE", posted: time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC), cents: 1500},
+			},
+			ledger: Balance{Amount: domain.NewMoney(-2717, "USD"), AsOf: time.Date(2024, 4, 15, 8, 30, 0, 0, est)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile("testdata/" + tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !IsOFX(data) {
+				t.Fatal("IsOFX() = false, want true")
+			}
+			stmt, err := Parse(data)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if stmt.Account != tt.account || stmt.Currency != tt.currency {
+				t.Errorf("Parse() account = %+v in %s, want %+v in %s", stmt.Account, stmt.Currency, tt.account, tt.currency)
+			}
+			if stmt.Start == nil || stmt.End == nil || !stmt.Start.Before(*stmt.End) {
+				t.Errorf("Parse() period = %v to %v, want DTSTART and DTEND", stmt.Start, stmt.End)
+			}
+			if len(stmt.Transactions) != len(tt.want) {
+				t.Fatalf("Parse() returned %d transactions, want %d", len(stmt.Transactions), len(tt.want))
+			}
+			for i, w := range tt.want {
+				got := stmt.Transactions[i]
+				if got.FITID != w.fitid || got.Description() != w.description || !got.Posted.Equal(w.posted) || got.Amount != domain.NewMoney(w.cents, tt.currency) {
+					t.Errorf("transaction %d = %+v (%q), want %+v", i, got, got.Description(), w)
+				}
+			}
+			if stmt.LedgerBalance == nil || stmt.LedgerBalance.Amount != tt.ledger.Amount || !stmt.LedgerBalance.AsOf.Equal(tt.ledger.AsOf) {
+				t.Errorf("Parse() ledger balance = %+v, want %+v", stmt.LedgerBalance, tt.ledger)
+			}
+		})
+	}
+}
+
+func TestParse_InvalidFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{name: "csv", data: "Data;Valor\n05/03/2024;10,00\n", wantErr: ErrNotOFX},
+		{name: "no statement", data: "OFXHEADER:100\n<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</STATUS></SONRS></SIGNONMSGSRSV1></OFX>", wantErr: ErrNoStatement},
+		{name: "transaction without FITID", data: "<OFX><STMTRS><CURDEF>BRL<BANKTRANLIST><STMTTRN><DTPOSTED>20240305<TRNAMT>1.00</STMTTRN></BANKTRANLIST></STMTRS></OFX>"},
+		{name: "invalid amount", data: "<OFX><STMTRS><CURDEF>BRL<BANKTRANLIST><STMTTRN><FITID>1<DTPOSTED>20240305<TRNAMT>abc</STMTTRN></BANKTRANLIST></STMTRS></OFX>"},
+		{name: "invalid date", data: "<OFX><STMTRS><CURDEF>BRL<BANKTRANLIST><STMTTRN><FITID>1<DTPOSTED>2024-03-05<TRNAMT>1.00</STMTTRN></BANKTRANLIST></STMTRS></OFX>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{in: "20240305", want: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{in: "202403051230", want: time.Date(2024, 3, 5, 12, 30, 0, 0, time.UTC)},
+		{in: "20240305123045.123[-3:BRT]", want: time.Date(2024, 3, 5, 15, 30, 45, 0, time.UTC)},
+		{in: "20240305000000[+5.5:IST]", want: time.Date(2024, 3, 4, 18, 30, 0, 0, time.UTC)},
+		{in: "20240305000000[-3]", want: time.Date(2024, 3, 5, 3, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseDateTime(tt.in)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseDateTime(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
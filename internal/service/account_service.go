@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/igoventura/fintrack-api/domain"
 )
@@ -21,25 +23,73 @@ func (s *AccountService) GetAccount(ctx context.Context, id string) (*domain.Acc
 	if err != nil {
 		return nil, fmt.Errorf("service failed to get account: %w", err)
 	}
+
+	if acc.Type == domain.AccountTypeCreditCard {
+		card, err := s.repo.GetCreditCardInfo(ctx, acc.ID, tenantID)
+		if err != nil && !errors.Is(err, domain.ErrCreditCardInfoNotFound) {
+			return nil, fmt.Errorf("service failed to get credit card info: %w", err)
+		}
+		acc.CreditCard = card
+	}
 	return acc, nil
 }
 
-func (s *AccountService) ListAccounts(ctx context.Context) ([]domain.Account, error) {
+func (s *AccountService) ListAccounts(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Account], error) {
 	tenantID := domain.GetTenantID(ctx)
-	accounts, err := s.repo.List(ctx, tenantID)
+	accounts, err := s.repo.List(ctx, tenantID, page)
 	if err != nil {
 		return nil, fmt.Errorf("service failed to list accounts: %w", err)
 	}
+
+	cards, err := s.repo.ListCreditCardInfo(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list credit card info: %w", err)
+	}
+	byAccount := make(map[string]*domain.CreditCardInfo, len(cards))
+	for i := range cards {
+		byAccount[cards[i].AccountID] = &cards[i]
+	}
+	for i := range accounts.Items {
+		accounts.Items[i].CreditCard = byAccount[accounts.Items[i].ID]
+	}
 	return accounts, nil
 }
 
+// GetBalance computes the balance of an account as of the given date.
+func (s *AccountService) GetBalance(ctx context.Context, id string, asOf time.Time) (*domain.AccountBalance, error) {
+	tenantID := domain.GetTenantID(ctx)
+	balance, err := s.repo.GetBalance(ctx, id, tenantID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get account balance: %w", err)
+	}
+	return balance, nil
+}
+
+// ListBalances computes the balances of the given tenant accounts as of the given date, keyed by account ID.
+func (s *AccountService) ListBalances(ctx context.Context, accountIDs []string, asOf time.Time) (map[string]domain.AccountBalance, error) {
+	if len(accountIDs) == 0 {
+		return map[string]domain.AccountBalance{}, nil
+	}
+	tenantID := domain.GetTenantID(ctx)
+	balances, err := s.repo.ListBalances(ctx, tenantID, accountIDs, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list account balances: %w", err)
+	}
+
+	byAccount := make(map[string]domain.AccountBalance, len(balances))
+	for _, b := range balances {
+		byAccount[b.AccountID] = b
+	}
+	return byAccount, nil
+}
+
 func (s *AccountService) CreateAccount(ctx context.Context, acc *domain.Account) error {
 	tenantID := domain.GetTenantID(ctx)
 	acc.TenantID = tenantID
 
 	// Business validation logic could go here
 	if acc.Name == "" {
-		return fmt.Errorf("account name is required")
+		return domain.InvalidField("name", "name is required")
 	}
 
 	if err := s.repo.Create(ctx, acc); err != nil {
@@ -58,6 +108,31 @@ func (s *AccountService) UpdateAccount(ctx context.Context, acc *domain.Account)
 	return nil
 }
 
+// PatchAccount applies a partial update to an account. Currency and type cannot be changed.
+// The update requires version when given, or else the version that was loaded.
+func (s *AccountService) PatchAccount(ctx context.Context, id string, patch domain.AccountPatch, version int) (*domain.Account, error) {
+	acc, err := s.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := patch.Apply(acc); err != nil {
+		return nil, err
+	}
+	acc.UpdatedBy = domain.GetUserID(ctx)
+	if version != 0 {
+		acc.Version = version
+	}
+
+	if valid, errs := acc.IsValid(); !valid {
+		return nil, domain.InvalidFields(errs)
+	}
+
+	if err := s.repo.Update(ctx, acc); err != nil {
+		return nil, fmt.Errorf("service failed to update account: %w", err)
+	}
+	return acc, nil
+}
+
 func (s *AccountService) DeleteAccount(ctx context.Context, id string, userID string) error {
 	tenantID := domain.GetTenantID(ctx)
 
@@ -66,3 +141,67 @@ func (s *AccountService) DeleteAccount(ctx context.Context, id string, userID st
 	}
 	return nil
 }
+
+// GetCreditCard returns the current card details of a credit card account.
+func (s *AccountService) GetCreditCard(ctx context.Context, accountID string) (*domain.CreditCardInfo, error) {
+	if _, err := s.getCreditCardAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	card, err := s.repo.GetCreditCardInfo(ctx, accountID, domain.GetTenantID(ctx))
+	if err != nil {
+		if errors.Is(err, domain.ErrCreditCardInfoNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service failed to get credit card info: %w", err)
+	}
+	return card, nil
+}
+
+// SetCreditCard stores new card details for a credit card account, keeping the previous ones as history.
+func (s *AccountService) SetCreditCard(ctx context.Context, card *domain.CreditCardInfo) error {
+	if _, err := s.getCreditCardAccount(ctx, card.AccountID); err != nil {
+		return err
+	}
+
+	userID := domain.GetUserID(ctx)
+	card.CreatedBy = userID
+	card.UpdatedBy = userID
+
+	if valid, errs := card.IsValid(); !valid {
+		return domain.InvalidFields(errs)
+	}
+
+	if err := s.repo.UpsertCreditCardInfo(ctx, card); err != nil {
+		return fmt.Errorf("service failed to set credit card info: %w", err)
+	}
+	return nil
+}
+
+// DeleteCreditCard deactivates the current card details of a credit card account.
+func (s *AccountService) DeleteCreditCard(ctx context.Context, accountID string) error {
+	if _, err := s.getCreditCardAccount(ctx, accountID); err != nil {
+		return err
+	}
+
+	userID := domain.GetUserID(ctx)
+	if err := s.repo.DeleteCreditCardInfo(ctx, accountID, domain.GetTenantID(ctx), userID); err != nil {
+		if errors.Is(err, domain.ErrCreditCardInfoNotFound) {
+			return err
+		}
+		return fmt.Errorf("service failed to delete credit card info: %w", err)
+	}
+	return nil
+}
+
+func (s *AccountService) getCreditCardAccount(ctx context.Context, accountID string) (*domain.Account, error) {
+	tenantID := domain.GetTenantID(ctx)
+	acc, err := s.repo.GetByID(ctx, accountID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get account: %w", err)
+	}
+	if acc.Type != domain.AccountTypeCreditCard {
+		return nil, domain.ErrNotCreditCardAccount
+	}
+	return acc, nil
+}
@@ -49,12 +49,6 @@ func (s *SupabaseAuthService) Register(ctx context.Context, email, password, ful
 		return nil, err
 	}
 
-	if tenantID := domain.GetTenantID(ctx); tenantID != "" {
-		if err := s.userService.AddTenantToUser(ctx, user.ID, tenantID); err != nil {
-			return nil, err
-		}
-	}
-
 	return &dto.AuthResponse{
 		AccessToken:  resp.AccessToken,
 		RefreshToken: resp.RefreshToken,
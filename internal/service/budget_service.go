@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type BudgetService struct {
+	repo         domain.BudgetRepository
+	categoryRepo domain.CategoryRepository
+	tagRepo      domain.TagRepository
+	now          func() time.Time
+}
+
+func NewBudgetService(repo domain.BudgetRepository, categoryRepo domain.CategoryRepository, tagRepo domain.TagRepository) *BudgetService {
+	return &BudgetService{repo: repo, categoryRepo: categoryRepo, tagRepo: tagRepo, now: time.Now}
+}
+
+func (s *BudgetService) GetBudget(ctx context.Context, id string) (*domain.Budget, error) {
+	budget, err := s.repo.GetByID(ctx, id, domain.GetTenantID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get budget: %w", err)
+	}
+	return budget, nil
+}
+
+func (s *BudgetService) ListBudgets(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Budget], error) {
+	budgets, err := s.repo.List(ctx, domain.GetTenantID(ctx), page)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list budgets: %w", err)
+	}
+	return budgets, nil
+}
+
+func (s *BudgetService) CreateBudget(ctx context.Context, budget *domain.Budget) error {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	budget.TenantID = domain.GetTenantID(ctx)
+	budget.CreatedBy = userID
+	budget.UpdatedBy = userID
+
+	if err := s.validate(ctx, budget); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, budget); err != nil {
+		return fmt.Errorf("service failed to create budget: %w", err)
+	}
+	return nil
+}
+
+// UpdateBudget replaces a budget. It requires budget.Version when set.
+func (s *BudgetService) UpdateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	budget.TenantID = domain.GetTenantID(ctx)
+	budget.UpdatedBy = userID
+
+	if err := s.validate(ctx, budget); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, budget); err != nil {
+		return nil, fmt.Errorf("service failed to update budget: %w", err)
+	}
+	return s.GetBudget(ctx, budget.ID)
+}
+
+func (s *BudgetService) DeleteBudget(ctx context.Context, id string) error {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	if err := s.repo.Delete(ctx, id, domain.GetTenantID(ctx), userID); err != nil {
+		return fmt.Errorf("service failed to delete budget: %w", err)
+	}
+	return nil
+}
+
+// Status returns the planned amount, actual spend and progress of every budget covering month
+// (YYYYMM, the current month when empty).
+func (s *BudgetService) Status(ctx context.Context, month string) ([]domain.BudgetStatus, error) {
+	if month == "" {
+		month = s.now().Format("200601")
+	}
+	if _, err := domain.ParsePeriod(month); err != nil {
+		return nil, domain.InvalidField("month", "month must be YYYYMM")
+	}
+	tenantID := domain.GetTenantID(ctx)
+
+	budgets, err := s.repo.ListForMonth(ctx, tenantID, month)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list budgets: %w", err)
+	}
+	spending, err := s.repo.Spending(ctx, tenantID, budgets, month)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get budget spending: %w", err)
+	}
+
+	spent := make(map[string]map[string]domain.Money, len(budgets))
+	for _, sp := range spending {
+		if spent[sp.BudgetID] == nil {
+			spent[sp.BudgetID] = map[string]domain.Money{}
+		}
+		spent[sp.BudgetID][sp.Month] = sp.Amount
+	}
+
+	statuses := make([]domain.BudgetStatus, 0, len(budgets))
+	for i := range budgets {
+		statuses = append(statuses, budgets[i].Status(month, spent[budgets[i].ID]))
+	}
+	return statuses, nil
+}
+
+// validate checks the budget fields and that its category, an expense one, or its tag belongs to the tenant.
+func (s *BudgetService) validate(ctx context.Context, budget *domain.Budget) error {
+	if valid, errs := budget.IsValid(); !valid {
+		return domain.InvalidFields(errs)
+	}
+	if budget.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, *budget.CategoryID, budget.TenantID)
+		if err != nil {
+			return referenceError(err, "category_id")
+		}
+		if category.Type != domain.CategoryTypeExpense {
+			return domain.ErrBudgetCategoryType
+		}
+	}
+	if budget.TagID != nil {
+		if _, err := s.tagRepo.GetByID(ctx, *budget.TagID, budget.TenantID); err != nil {
+			return referenceError(err, "tag_id")
+		}
+	}
+	return nil
+}
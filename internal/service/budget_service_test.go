@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type mockBudgetRepo struct {
+	domain.BudgetRepository
+	CreateFn       func(ctx context.Context, budget *domain.Budget) error
+	ListForMonthFn func(ctx context.Context, tenantID, month string) ([]domain.Budget, error)
+	SpendingFn     func(ctx context.Context, tenantID string, budgets []domain.Budget, month string) ([]domain.BudgetSpending, error)
+}
+
+func (m *mockBudgetRepo) Create(ctx context.Context, budget *domain.Budget) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, budget)
+	}
+	return nil
+}
+
+func (m *mockBudgetRepo) ListForMonth(ctx context.Context, tenantID, month string) ([]domain.Budget, error) {
+	if m.ListForMonthFn != nil {
+		return m.ListForMonthFn(ctx, tenantID, month)
+	}
+	return nil, nil
+}
+
+func (m *mockBudgetRepo) Spending(ctx context.Context, tenantID string, budgets []domain.Budget, month string) ([]domain.BudgetSpending, error) {
+	if m.SpendingFn != nil {
+		return m.SpendingFn(ctx, tenantID, budgets, month)
+	}
+	return nil, nil
+}
+
+func TestBudgetService_CreateBudget(t *testing.T) {
+	ctx := domain.WithUserID(domain.WithTenantID(context.Background(), "tenant-1"), "user-1")
+	categoryID := "cat-1"
+	newBudget := func() *domain.Budget {
+		return &domain.Budget{CategoryID: &categoryID, Month: "202403", Currency: "BRL", Amount: domain.NewMoney(50000, "BRL")}
+	}
+
+	t.Run("expense category", func(t *testing.T) {
+		categoryRepo := &mockCategoryRepo{GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Category, error) {
+			return &domain.Category{ID: id, TenantID: tenantID, Type: domain.CategoryTypeExpense}, nil
+		}}
+		svc := NewBudgetService(&mockBudgetRepo{}, categoryRepo, &mockTagRepo{})
+		b := newBudget()
+		if err := svc.CreateBudget(ctx, b); err != nil {
+			t.Fatalf("CreateBudget() error = %v", err)
+		}
+		if b.TenantID != "tenant-1" || b.CreatedBy != "user-1" || b.UpdatedBy != "user-1" {
+			t.Errorf("CreateBudget() budget = %+v, want the tenant and user from the context", b)
+		}
+	})
+
+	t.Run("income category", func(t *testing.T) {
+		categoryRepo := &mockCategoryRepo{GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Category, error) {
+			return &domain.Category{ID: id, TenantID: tenantID, Type: domain.CategoryTypeIncome}, nil
+		}}
+		svc := NewBudgetService(&mockBudgetRepo{}, categoryRepo, &mockTagRepo{})
+		if err := svc.CreateBudget(ctx, newBudget()); !errors.Is(err, domain.ErrBudgetCategoryType) {
+			t.Errorf("CreateBudget() error = %v, want ErrBudgetCategoryType", err)
+		}
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		categoryRepo := &mockCategoryRepo{GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Category, error) {
+			return nil, domain.ErrCategoryNotFound
+		}}
+		svc := NewBudgetService(&mockBudgetRepo{}, categoryRepo, &mockTagRepo{})
+		var verr *domain.ValidationError
+		if err := svc.CreateBudget(ctx, newBudget()); !errors.As(err, &verr) || verr.Fields["category_id"] == "" {
+			t.Errorf("CreateBudget() error = %v, want a category_id validation error", err)
+		}
+	})
+}
+
+func TestBudgetService_Status(t *testing.T) {
+	ctx := domain.WithTenantID(context.Background(), "tenant-1")
+	categoryID := "cat-1"
+	budgets := []domain.Budget{
+		{ID: "b-1", CategoryID: &categoryID, Month: "202403", Currency: "BRL", Amount: domain.NewMoney(50000, "BRL")},
+		{ID: "b-2", CategoryID: &categoryID, Month: "202401", Recurring: true, Rollover: true, Currency: "BRL", Amount: domain.NewMoney(10000, "BRL")},
+	}
+	var gotMonth string
+	repo := &mockBudgetRepo{
+		ListForMonthFn: func(ctx context.Context, tenantID, month string) ([]domain.Budget, error) {
+			gotMonth = month
+			return budgets, nil
+		},
+		SpendingFn: func(ctx context.Context, tenantID string, budgets []domain.Budget, month string) ([]domain.BudgetSpending, error) {
+			return []domain.BudgetSpending{
+				{BudgetID: "b-1", Month: "202403", Amount: domain.NewMoney(20000, "BRL")},
+				{BudgetID: "b-2", Month: "202401", Amount: domain.NewMoney(4000, "BRL")},
+				{BudgetID: "b-2", Month: "202403", Amount: domain.NewMoney(9000, "BRL")},
+			}, nil
+		},
+	}
+	svc := NewBudgetService(repo, &mockCategoryRepo{}, &mockTagRepo{})
+	svc.now = func() time.Time { return time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC) }
+
+	statuses, err := svc.Status(ctx, "")
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if gotMonth != "202403" || len(statuses) != 2 {
+		t.Fatalf("Status() month = %s, %d statuses, want 202403 and 2", gotMonth, len(statuses))
+	}
+	if statuses[0].Remaining != domain.NewMoney(30000, "BRL") || *statuses[0].PercentUsed != 40 {
+		t.Errorf("one-off status = %+v, want 300.00 remaining and 40%% used", statuses[0])
+	}
+	// 100.00 a month from January: 60.00 left in January and 100.00 in February carry over
+	if statuses[1].CarriedOver != domain.NewMoney(16000, "BRL") || statuses[1].Remaining != domain.NewMoney(17000, "BRL") {
+		t.Errorf("rollover status = %+v, want 160.00 carried over and 170.00 remaining", statuses[1])
+	}
+
+	if _, err := svc.Status(ctx, "2024-03"); err == nil {
+		t.Error("Status() with an invalid month succeeded, want a validation error")
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/igoventura/fintrack-api/domain"
@@ -24,9 +25,9 @@ func (s *CategoryService) GetCategory(ctx context.Context, id string) (*domain.C
 	return category, nil
 }
 
-func (s *CategoryService) ListCategories(ctx context.Context) ([]domain.Category, error) {
+func (s *CategoryService) ListCategories(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Category], error) {
 	tenantID := domain.GetTenantID(ctx)
-	categories, err := s.repo.List(ctx, tenantID)
+	categories, err := s.repo.List(ctx, tenantID, page)
 	if err != nil {
 		return nil, fmt.Errorf("service failed to list categories: %w", err)
 	}
@@ -39,7 +40,10 @@ func (s *CategoryService) CreateCategory(ctx context.Context, category *domain.C
 
 	isValid, validationErrors := category.IsValid()
 	if !isValid {
-		return fmt.Errorf("invalid category: %v", validationErrors)
+		return domain.InvalidFields(validationErrors)
+	}
+	if err := s.checkParent(ctx, category); err != nil {
+		return err
 	}
 
 	if err := s.repo.Create(ctx, category); err != nil {
@@ -64,12 +68,16 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, category *domain.C
 	existingCategory.Color = category.Color
 	existingCategory.Icon = category.Icon
 	existingCategory.UpdatedBy = domain.GetUserID(ctx)
+	existingCategory.Version = category.Version // Version required by the caller, if any
 	// Type is immutable, so we naturally keep existingCategory.Type
 
 	// 3. Validate merged category
 	isValid, validationErrors := existingCategory.IsValid()
 	if !isValid {
-		return fmt.Errorf("invalid category: %v", validationErrors)
+		return domain.InvalidFields(validationErrors)
+	}
+	if err := s.checkParent(ctx, existingCategory); err != nil {
+		return err
 	}
 
 	// 4. Update
@@ -79,6 +87,35 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, category *domain.C
 	return nil
 }
 
+// PatchCategory applies a partial update to a category. The type cannot be changed.
+// The update requires version when given, or else the version that was loaded.
+func (s *CategoryService) PatchCategory(ctx context.Context, id string, patch domain.CategoryPatch, version int) (*domain.Category, error) {
+	tenantID := domain.GetTenantID(ctx)
+	category, err := s.repo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get category for update (or unauthorized): %w", err)
+	}
+	if err := patch.Apply(category); err != nil {
+		return nil, err
+	}
+	category.UpdatedBy = domain.GetUserID(ctx)
+	if version != 0 {
+		category.Version = version
+	}
+
+	if valid, errs := category.IsValid(); !valid {
+		return nil, domain.InvalidFields(errs)
+	}
+	if err := s.checkParent(ctx, category); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, category); err != nil {
+		return nil, fmt.Errorf("service failed to update category: %w", err)
+	}
+	return category, nil
+}
+
 func (s *CategoryService) DeleteCategory(ctx context.Context, id, userID string) error {
 	tenantID := domain.GetTenantID(ctx)
 
@@ -93,3 +130,35 @@ func (s *CategoryService) DeleteCategory(ctx context.Context, id, userID string)
 	}
 	return nil
 }
+
+// checkParent requires the parent of category to be a category of its tenant that is neither the
+// category itself nor one of its subcategories.
+func (s *CategoryService) checkParent(ctx context.Context, category *domain.Category) error {
+	if category.ParentCategoryID == nil {
+		return nil
+	}
+	parent, err := s.repo.GetByID(ctx, *category.ParentCategoryID, category.TenantID)
+	if err != nil {
+		return referenceError(err, "parent_category_id")
+	}
+
+	// Walking up from the parent must not reach the category itself.
+	seen := map[string]bool{}
+	for !seen[parent.ID] {
+		if parent.ID == category.ID {
+			return domain.InvalidField("parent_category_id", "parent_category_id must not be the category or one of its subcategories")
+		}
+		seen[parent.ID] = true
+		if parent.ParentCategoryID == nil {
+			break
+		}
+		parent, err = s.repo.GetByID(ctx, *parent.ParentCategoryID, category.TenantID)
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			break // Deleted ancestor
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch parent category: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+func TestCategoryService_PatchCategory_Parent(t *testing.T) {
+	ctx := domain.WithUserID(domain.WithTenantID(context.Background(), "tenant-1"), "user-1")
+	ptr := func(s string) *string { return &s }
+
+	// food > groceries > market; other-tenant is not found in tenant-1
+	categories := map[string]*domain.Category{
+		"food":      {ID: "food"},
+		"groceries": {ID: "groceries", ParentCategoryID: ptr("food")},
+		"market":    {ID: "market", ParentCategoryID: ptr("groceries")},
+		"salary":    {ID: "salary"},
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		parent  *string
+		wantErr bool
+	}{
+		{name: "parent of the tenant", id: "salary", parent: ptr("food")},
+		{name: "no parent", id: "market", parent: nil},
+		{name: "parent of another tenant", id: "salary", parent: ptr("other-tenant"), wantErr: true},
+		{name: "itself", id: "food", parent: ptr("food"), wantErr: true},
+		{name: "one of its subcategories", id: "food", parent: ptr("market"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := false
+			repo := &mockCategoryRepo{
+				GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Category, error) {
+					c, ok := categories[id]
+					if !ok || tenantID != "tenant-1" {
+						return nil, domain.ErrCategoryNotFound
+					}
+					cat := *c
+					cat.TenantID = tenantID
+					cat.Name = id
+					cat.Color = "#000000"
+					cat.Type = domain.CategoryTypeExpense
+					return &cat, nil
+				},
+				UpdateFn: func(ctx context.Context, cat *domain.Category) error {
+					updated = true
+					return nil
+				},
+			}
+			s := NewCategoryService(repo)
+
+			patch := domain.CategoryPatch{ParentCategoryID: domain.Field[*string]{Value: tt.parent, Set: true}}
+			_, err := s.PatchCategory(ctx, tt.id, patch, 0)
+			var verr *domain.ValidationError
+			if tt.wantErr {
+				if !errors.As(err, &verr) || verr.Fields["parent_category_id"] == "" || updated {
+					t.Errorf("PatchCategory() error = %v, updated = %v, want a parent_category_id validation error", err, updated)
+				}
+				return
+			}
+			if err != nil || !updated {
+				t.Errorf("PatchCategory() error = %v, updated = %v, want the category updated", err, updated)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+var (
+	ErrInvalidRole = domain.InvalidField("role", "invalid role")
+)
+
+// referenceError reports a record referenced by the request that does not exist as an invalid field,
+// e.g. an unknown category_id. Other errors are wrapped.
+func referenceError(err error, field string) error {
+	var notFound *domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return domain.InvalidField(field, notFound.Message)
+	}
+	return fmt.Errorf("failed to fetch %s: %w", field, err)
+}
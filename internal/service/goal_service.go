@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type GoalService struct {
+	repo        domain.GoalRepository
+	accountRepo domain.AccountRepository
+	tagRepo     domain.TagRepository
+	now         func() time.Time
+}
+
+func NewGoalService(repo domain.GoalRepository, accountRepo domain.AccountRepository, tagRepo domain.TagRepository) *GoalService {
+	return &GoalService{repo: repo, accountRepo: accountRepo, tagRepo: tagRepo, now: time.Now}
+}
+
+func (s *GoalService) GetGoal(ctx context.Context, id string) (*domain.Goal, error) {
+	goal, err := s.repo.GetByID(ctx, id, domain.GetTenantID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get goal: %w", err)
+	}
+	return goal, nil
+}
+
+func (s *GoalService) ListGoals(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Goal], error) {
+	goals, err := s.repo.List(ctx, domain.GetTenantID(ctx), page)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list goals: %w", err)
+	}
+	return goals, nil
+}
+
+func (s *GoalService) CreateGoal(ctx context.Context, goal *domain.Goal) error {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	goal.TenantID = domain.GetTenantID(ctx)
+	goal.CreatedBy = userID
+	goal.UpdatedBy = userID
+
+	if err := s.validate(ctx, goal); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, goal); err != nil {
+		return fmt.Errorf("service failed to create goal: %w", err)
+	}
+	return nil
+}
+
+// UpdateGoal replaces a goal and its linked accounts. It requires goal.Version when set.
+func (s *GoalService) UpdateGoal(ctx context.Context, goal *domain.Goal) (*domain.Goal, error) {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	goal.TenantID = domain.GetTenantID(ctx)
+	goal.UpdatedBy = userID
+
+	if err := s.validate(ctx, goal); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, goal); err != nil {
+		return nil, fmt.Errorf("service failed to update goal: %w", err)
+	}
+	return s.GetGoal(ctx, goal.ID)
+}
+
+func (s *GoalService) DeleteGoal(ctx context.Context, id string) error {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	if err := s.repo.Delete(ctx, id, domain.GetTenantID(ctx), userID); err != nil {
+		return fmt.Errorf("service failed to delete goal: %w", err)
+	}
+	return nil
+}
+
+// Progress returns how far a goal is from its target today and when it is projected to be reached.
+// The amount saved is the current balance of the linked accounts, or the net of the tagged contributions.
+func (s *GoalService) Progress(ctx context.Context, id string) (*domain.GoalProgress, error) {
+	goal, err := s.GetGoal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	asOf := s.now()
+
+	contributions, err := s.repo.Contributions(ctx, goal, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get goal contributions: %w", err)
+	}
+
+	saved := domain.NewMoney(0, goal.Currency)
+	if len(goal.AccountIDs) > 0 {
+		for _, accountID := range goal.AccountIDs {
+			balance, err := s.accountRepo.GetBalance(ctx, accountID, goal.TenantID, asOf)
+			if errors.Is(err, domain.ErrAccountNotFound) {
+				continue // Deleted since it was linked
+			}
+			if err != nil {
+				return nil, fmt.Errorf("service failed to get account balance: %w", err)
+			}
+			saved = saved.Add(balance.Current.WithCurrency(goal.Currency))
+		}
+	} else {
+		for _, c := range contributions {
+			saved = saved.Add(c.Amount)
+		}
+	}
+
+	progress := goal.Progress(saved, contributions, asOf)
+	return &progress, nil
+}
+
+// validate checks the goal fields and that its linked accounts, all in the goal currency, or its
+// tag belong to the tenant.
+func (s *GoalService) validate(ctx context.Context, goal *domain.Goal) error {
+	if valid, errs := goal.IsValid(); !valid {
+		return domain.InvalidFields(errs)
+	}
+	for _, accountID := range goal.AccountIDs {
+		account, err := s.accountRepo.GetByID(ctx, accountID, goal.TenantID)
+		if err != nil {
+			return referenceError(err, "account_ids")
+		}
+		if account.Currency != goal.Currency {
+			return domain.ErrGoalAccountCurrency
+		}
+	}
+	if goal.TagID != nil {
+		if _, err := s.tagRepo.GetByID(ctx, *goal.TagID, goal.TenantID); err != nil {
+			return referenceError(err, "tag_id")
+		}
+	}
+	return nil
+}
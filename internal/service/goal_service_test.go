@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type mockGoalRepo struct {
+	domain.GoalRepository
+	GetByIDFn       func(ctx context.Context, id, tenantID string) (*domain.Goal, error)
+	CreateFn        func(ctx context.Context, goal *domain.Goal) error
+	ContributionsFn func(ctx context.Context, goal *domain.Goal, asOf time.Time) ([]domain.GoalContribution, error)
+}
+
+func (m *mockGoalRepo) GetByID(ctx context.Context, id, tenantID string) (*domain.Goal, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id, tenantID)
+	}
+	return nil, domain.ErrGoalNotFound
+}
+
+func (m *mockGoalRepo) Create(ctx context.Context, goal *domain.Goal) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, goal)
+	}
+	return nil
+}
+
+func (m *mockGoalRepo) Contributions(ctx context.Context, goal *domain.Goal, asOf time.Time) ([]domain.GoalContribution, error) {
+	if m.ContributionsFn != nil {
+		return m.ContributionsFn(ctx, goal, asOf)
+	}
+	return nil, nil
+}
+
+func TestGoalService_CreateGoal(t *testing.T) {
+	ctx := domain.WithUserID(domain.WithTenantID(context.Background(), "tenant-1"), "user-1")
+	accountRepo := &mockAccountRepo{GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Account, error) {
+		switch id {
+		case "acc-brl":
+			return &domain.Account{ID: id, TenantID: tenantID, Currency: "BRL"}, nil
+		case "acc-usd":
+			return &domain.Account{ID: id, TenantID: tenantID, Currency: "USD"}, nil
+		}
+		return nil, domain.ErrAccountNotFound
+	}}
+
+	tests := []struct {
+		name       string
+		accountIDs []string
+		wantErr    error
+		wantField  string
+	}{
+		{name: "accounts in the goal currency", accountIDs: []string{"acc-brl"}},
+		{name: "account in another currency", accountIDs: []string{"acc-brl", "acc-usd"}, wantErr: domain.ErrGoalAccountCurrency},
+		{name: "unknown account", accountIDs: []string{"acc-missing"}, wantField: "account_ids"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewGoalService(&mockGoalRepo{}, accountRepo, &mockTagRepo{})
+			goal := &domain.Goal{Name: "Trip", Currency: "BRL", TargetAmount: domain.NewMoney(500000, "BRL"), AccountIDs: tt.accountIDs}
+			err := svc.CreateGoal(ctx, goal)
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateGoal() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantField != "":
+				var verr *domain.ValidationError
+				if !errors.As(err, &verr) || verr.Fields[tt.wantField] == "" {
+					t.Errorf("CreateGoal() error = %v, want a %s validation error", err, tt.wantField)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("CreateGoal() error = %v", err)
+				}
+				if goal.TenantID != "tenant-1" || goal.CreatedBy != "user-1" {
+					t.Errorf("CreateGoal() goal = %+v, want the tenant and user from the context", goal)
+				}
+			}
+		})
+	}
+}
+
+func TestGoalService_Progress(t *testing.T) {
+	ctx := domain.WithTenantID(context.Background(), "tenant-1")
+	asOf := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	tagID := "tag-1"
+	contributions := []domain.GoalContribution{
+		{Month: "202404", Amount: domain.NewMoney(30000, "BRL")},
+		{Month: "202405", Amount: domain.NewMoney(50000, "BRL")},
+	}
+
+	tests := []struct {
+		name      string
+		goal      domain.Goal
+		wantSaved domain.Money
+	}{
+		{
+			name:      "balance of the linked accounts",
+			goal:      domain.Goal{ID: "goal-1", TenantID: "tenant-1", Currency: "BRL", TargetAmount: domain.NewMoney(500000, "BRL"), AccountIDs: []string{"acc-1", "acc-2", "acc-deleted"}},
+			wantSaved: domain.NewMoney(250000, "BRL"),
+		},
+		{
+			name:      "tagged contributions",
+			goal:      domain.Goal{ID: "goal-1", TenantID: "tenant-1", Currency: "BRL", TargetAmount: domain.NewMoney(500000, "BRL"), TagID: &tagID},
+			wantSaved: domain.NewMoney(80000, "BRL"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockGoalRepo{
+				GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Goal, error) {
+					g := tt.goal
+					return &g, nil
+				},
+				ContributionsFn: func(ctx context.Context, goal *domain.Goal, at time.Time) ([]domain.GoalContribution, error) {
+					if !at.Equal(asOf) {
+						t.Errorf("Contributions() asOf = %v, want %v", at, asOf)
+					}
+					return contributions, nil
+				},
+			}
+			accountRepo := &mockAccountRepo{GetBalanceFn: func(ctx context.Context, id, tenantID string, at time.Time) (*domain.AccountBalance, error) {
+				switch id {
+				case "acc-1":
+					return &domain.AccountBalance{AccountID: id, Currency: "BRL", Current: domain.NewMoney(200000, "BRL")}, nil
+				case "acc-2":
+					return &domain.AccountBalance{AccountID: id, Currency: "BRL", Current: domain.NewMoney(50000, "BRL")}, nil
+				}
+				return nil, domain.ErrAccountNotFound
+			}}
+			svc := NewGoalService(repo, accountRepo, &mockTagRepo{})
+			svc.now = func() time.Time { return asOf }
+
+			progress, err := svc.Progress(ctx, "goal-1")
+			if err != nil {
+				t.Fatalf("Progress() error = %v", err)
+			}
+			if progress.Saved != tt.wantSaved {
+				t.Errorf("Saved = %v, want %v", progress.Saved, tt.wantSaved)
+			}
+			if progress.AverageMonthlyContribution == nil || *progress.AverageMonthlyContribution != domain.NewMoney(40000, "BRL") {
+				t.Errorf("AverageMonthlyContribution = %v, want 400.00", progress.AverageMonthlyContribution)
+			}
+		})
+	}
+}
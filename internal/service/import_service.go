@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+	"github.com/igoventura/fintrack-api/internal/ofx"
+)
+
+type ImportService struct {
+	repo         domain.ImportRepository
+	profileRepo  domain.ImportProfileRepository
+	accountRepo  domain.AccountRepository
+	transactions *TransactionService
+	transactor   domain.Transactor
+	now          func() time.Time
+}
+
+func NewImportService(repo domain.ImportRepository, profileRepo domain.ImportProfileRepository, accountRepo domain.AccountRepository, transactions *TransactionService, transactor domain.Transactor) *ImportService {
+	return &ImportService{repo: repo, profileRepo: profileRepo, accountRepo: accountRepo, transactions: transactions, transactor: transactor, now: time.Now}
+}
+
+func (s *ImportService) GetProfile(ctx context.Context, id string) (*domain.ImportProfile, error) {
+	profile, err := s.profileRepo.GetByID(ctx, id, domain.GetTenantID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get import profile: %w", err)
+	}
+	return profile, nil
+}
+
+func (s *ImportService) ListProfiles(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.ImportProfile], error) {
+	profiles, err := s.profileRepo.List(ctx, domain.GetTenantID(ctx), page)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list import profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+func (s *ImportService) CreateProfile(ctx context.Context, profile *domain.ImportProfile) error {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	profile.TenantID = domain.GetTenantID(ctx)
+	profile.CreatedBy = userID
+	profile.UpdatedBy = userID
+
+	if valid, errs := profile.IsValid(); !valid {
+		return domain.InvalidFields(errs)
+	}
+	if err := s.profileRepo.Create(ctx, profile); err != nil {
+		return fmt.Errorf("service failed to create import profile: %w", err)
+	}
+	return nil
+}
+
+// UpdateProfile replaces an import profile. It requires profile.Version when set.
+func (s *ImportService) UpdateProfile(ctx context.Context, profile *domain.ImportProfile) (*domain.ImportProfile, error) {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	profile.TenantID = domain.GetTenantID(ctx)
+	profile.UpdatedBy = userID
+
+	if valid, errs := profile.IsValid(); !valid {
+		return nil, domain.InvalidFields(errs)
+	}
+	if err := s.profileRepo.Update(ctx, profile); err != nil {
+		return nil, fmt.Errorf("service failed to update import profile: %w", err)
+	}
+	return s.GetProfile(ctx, profile.ID)
+}
+
+func (s *ImportService) DeleteProfile(ctx context.Context, id string) error {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	if err := s.profileRepo.Delete(ctx, id, domain.GetTenantID(ctx), userID); err != nil {
+		return fmt.Errorf("service failed to delete import profile: %w", err)
+	}
+	return nil
+}
+
+// GetImport returns an import with its rows and, for OFX files, the computed balance of the account
+// on the date of the ledger balance of the statement.
+func (s *ImportService) GetImport(ctx context.Context, id string) (*domain.Import, error) {
+	imp, err := s.repo.GetByID(ctx, id, domain.GetTenantID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get import: %w", err)
+	}
+	if err := s.attachComputedBalance(ctx, imp); err != nil {
+		return nil, err
+	}
+	return imp, nil
+}
+
+// attachComputedBalance sets the cleared balance of the account on the ledger balance date of imp.
+func (s *ImportService) attachComputedBalance(ctx context.Context, imp *domain.Import) error {
+	if imp.LedgerBalanceDate == nil {
+		return nil
+	}
+	balance, err := s.accountRepo.GetBalance(ctx, imp.AccountID, imp.TenantID, *imp.LedgerBalanceDate)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		return nil // Deleted since the import
+	}
+	if err != nil {
+		return fmt.Errorf("service failed to get account balance: %w", err)
+	}
+	computed := balance.Cleared
+	imp.ComputedBalance = &computed
+	return nil
+}
+
+// Stage parses a statement of an account and saves its rows for review, each with the existing
+// transaction it may duplicate and the category suggested for it. OFX files are recognized by their
+// content; CSV files require a profile.
+func (s *ImportService) Stage(ctx context.Context, accountID, profileID, fileName string, data []byte) (*domain.Import, error) {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	tenantID := domain.GetTenantID(ctx)
+
+	account, err := s.accountRepo.GetByID(ctx, accountID, tenantID)
+	if err != nil {
+		return nil, referenceError(err, "account_id")
+	}
+
+	imp := &domain.Import{
+		TenantID:  tenantID,
+		AccountID: account.ID,
+		FileName:  fileName,
+		Currency:  account.Currency,
+		Status:    domain.ImportStatusStaged,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+	if ofx.IsOFX(data) {
+		imp.Format = domain.ImportFormatOFX
+		if err := s.readOFX(ctx, imp, account, data); err != nil {
+			return nil, err
+		}
+	} else {
+		if profileID == "" {
+			return nil, domain.InvalidField("profile_id", "profile_id is required for CSV files")
+		}
+		profile, err := s.profileRepo.GetByID(ctx, profileID, tenantID)
+		if err != nil {
+			return nil, referenceError(err, "profile_id")
+		}
+		imp.Format = domain.ImportFormatCSV
+		imp.ProfileID = &profile.ID
+		if imp.Rows, err = profile.Parse(data, account.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	rows := imp.Rows
+	duplicates, err := s.repo.FindDuplicates(ctx, tenantID, account.ID, rows)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to find duplicates: %w", err)
+	}
+	suggestions, err := s.repo.SuggestCategories(ctx, tenantID, rows)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to suggest categories: %w", err)
+	}
+	for i := range rows {
+		if id, ok := duplicates[rows[i].Line]; ok {
+			rows[i].DuplicateTransactionID = &id
+		}
+		if id, ok := suggestions[rows[i].Line]; ok {
+			rows[i].SuggestedCategoryID = &id
+		}
+	}
+
+	if err := s.repo.Create(ctx, imp); err != nil {
+		return nil, fmt.Errorf("service failed to create import: %w", err)
+	}
+	if err := s.attachComputedBalance(ctx, imp); err != nil {
+		return nil, err
+	}
+	return imp, nil
+}
+
+// readOFX sets the rows of imp from the transactions of an OFX statement of account, leaving out
+// those whose FITID was already imported into the account (or repeated in the file), along with
+// the ledger balance of the statement. A file with nothing new is ErrImportAlreadyImported.
+func (s *ImportService) readOFX(ctx context.Context, imp *domain.Import, account *domain.Account, data []byte) error {
+	stmt, err := ofx.Parse(data)
+	if err != nil {
+		return domain.InvalidField("file", err.Error())
+	}
+	if stmt.Currency != "" && stmt.Currency != account.Currency {
+		return domain.InvalidField("file", fmt.Sprintf("the statement is in %s and the account in %s", stmt.Currency, account.Currency))
+	}
+	if len(stmt.Transactions) > domain.MaxImportRows {
+		return domain.InvalidField("file", fmt.Sprintf("the file must not have more than %d rows", domain.MaxImportRows))
+	}
+	if err := s.checkCard(ctx, account, stmt.Account); err != nil {
+		return err
+	}
+
+	fitIDs := make([]string, len(stmt.Transactions))
+	for i, t := range stmt.Transactions {
+		fitIDs[i] = t.FITID
+	}
+	imported, err := s.repo.ImportedExternalIDs(ctx, imp.TenantID, account.ID, fitIDs)
+	if err != nil {
+		return fmt.Errorf("service failed to get imported transactions: %w", err)
+	}
+
+	seen := make(map[string]bool, len(stmt.Transactions))
+	for i, t := range stmt.Transactions {
+		if _, ok := imported[t.FITID]; ok {
+			imp.AlreadyImported++
+			continue
+		}
+		if seen[t.FITID] {
+			continue
+		}
+		seen[t.FITID] = true
+		imp.Rows = append(imp.Rows, ofxRow(i+1, t, account.Currency))
+	}
+	if len(imp.Rows) == 0 && imp.AlreadyImported > 0 {
+		return domain.ErrImportAlreadyImported
+	}
+
+	if stmt.LedgerBalance != nil {
+		amount := stmt.LedgerBalance.Amount.WithCurrency(account.Currency)
+		date := civilDate(stmt.LedgerBalance.AsOf)
+		imp.LedgerBalance = &amount
+		imp.LedgerBalanceDate = &date
+	}
+	return nil
+}
+
+// checkCard rejects the statement of a card other than the credit card account it is imported into,
+// when the account has its last four digits.
+func (s *ImportService) checkCard(ctx context.Context, account *domain.Account, stmtAccount ofx.Account) error {
+	if account.Type != domain.AccountTypeCreditCard || len(stmtAccount.AccountID) < 4 {
+		return nil
+	}
+	card, err := s.accountRepo.GetCreditCardInfo(ctx, account.ID, account.TenantID)
+	if errors.Is(err, domain.ErrCreditCardInfoNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch credit card info: %w", err)
+	}
+	if card.LastFour != "" && !strings.HasSuffix(stmtAccount.AccountID, card.LastFour) {
+		return domain.InvalidField("account_id", fmt.Sprintf("the statement is of card %s, not of the card ending in %s", stmtAccount.AccountID, card.LastFour))
+	}
+	return nil
+}
+
+// ofxRow converts the n-th transaction of an OFX statement into an import row.
+func ofxRow(n int, t ofx.Transaction, currency string) domain.ImportRow {
+	fitID := t.FITID
+	date := civilDate(t.Posted)
+	row := domain.ImportRow{
+		Line:        n,
+		ExternalID:  &fitID,
+		Date:        &date,
+		Description: t.Description(),
+		Amount:      t.Amount.Abs().WithCurrency(currency),
+	}
+	switch {
+	case t.Amount.IsZero():
+		message := "amount is zero"
+		row.Error = &message
+		row.Date = nil
+	case t.Amount.IsNegative():
+		row.TransactionType = domain.TransactionTypeDebit
+	default:
+		row.TransactionType = domain.TransactionTypeCredit
+	}
+	return row
+}
+
+// civilDate returns the date of t in its own time zone, at midnight UTC.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Commit creates a transaction for each selected row of a staged import, with the selected category
+// or else the suggested one, and marks the import committed. Either every row is created or none is.
+func (s *ImportService) Commit(ctx context.Context, id string, selections []domain.ImportSelection) (*domain.Import, error) {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	imp, err := s.GetImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if imp.Status != domain.ImportStatusStaged {
+		return nil, domain.ErrImportCommitted
+	}
+
+	selected, err := selectImportRows(imp, selections)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.InTx(ctx, func(ctx context.Context) error {
+		if err := s.checkNotImported(ctx, imp, selected); err != nil {
+			return err
+		}
+		for i, row := range selected {
+			t := &domain.Transaction{
+				FromAccountID:   imp.AccountID,
+				Currency:        imp.Currency,
+				Amount:          row.Amount,
+				TransactionType: row.TransactionType,
+				CategoryID:      *row.SuggestedCategoryID,
+				DueDate:         *row.Date,
+				PaymentDate:     row.Date,
+			}
+			if row.Description != "" {
+				t.Comments = &row.Description
+			}
+			if err := s.transactions.Create(ctx, t, nil, 1, false); err != nil {
+				return fmt.Errorf("failed to create the transaction of line %d: %w", row.Line, err)
+			}
+			selected[i].TransactionID = &t.ID
+		}
+
+		committedAt := s.now()
+		imp.Rows = selected
+		imp.CommittedAt = &committedAt
+		imp.UpdatedBy = userID
+		return s.repo.MarkCommitted(ctx, imp)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service failed to commit import: %w", err)
+	}
+	return s.GetImport(ctx, id)
+}
+
+// selectImportRows returns the selected rows of imp with the category to use as their
+// SuggestedCategoryID, or a validation error on the first selection that cannot be committed.
+func selectImportRows(imp *domain.Import, selections []domain.ImportSelection) ([]domain.ImportRow, error) {
+	if len(selections) == 0 {
+		return nil, domain.InvalidField("rows", "at least one row must be selected")
+	}
+	byID := make(map[string]domain.ImportRow, len(imp.Rows))
+	for _, row := range imp.Rows {
+		byID[row.ID] = row
+	}
+
+	selected := make([]domain.ImportRow, 0, len(selections))
+	seen := make(map[string]bool, len(selections))
+	for i, sel := range selections {
+		field := fmt.Sprintf("rows[%d]", i)
+		row, ok := byID[sel.RowID]
+		switch {
+		case !ok:
+			return nil, domain.InvalidField(field, "row not found in the import")
+		case seen[sel.RowID]:
+			return nil, domain.InvalidField(field, "row selected more than once")
+		case row.Error != nil:
+			return nil, domain.InvalidField(field, fmt.Sprintf("line %d could not be read: %s", row.Line, *row.Error))
+		}
+		seen[sel.RowID] = true
+		if sel.CategoryID != nil {
+			row.SuggestedCategoryID = sel.CategoryID
+		}
+		if row.SuggestedCategoryID == nil {
+			return nil, domain.InvalidField(field, fmt.Sprintf("line %d has no suggested category, so one is required", row.Line))
+		}
+		selected = append(selected, row)
+	}
+	return selected, nil
+}
+
+// checkNotImported rejects the selected rows whose external ID was committed on the account since
+// the import was staged, e.g. from another import of the same file. The account is locked first,
+// so that a concurrent commit of the same file waits and then sees the rows committed by this one.
+func (s *ImportService) checkNotImported(ctx context.Context, imp *domain.Import, selected []domain.ImportRow) error {
+	var externalIDs []string
+	for _, row := range selected {
+		if row.ExternalID != nil {
+			externalIDs = append(externalIDs, *row.ExternalID)
+		}
+	}
+	if len(externalIDs) == 0 {
+		return nil
+	}
+	if err := s.repo.LockAccount(ctx, imp.TenantID, imp.AccountID); err != nil {
+		return fmt.Errorf("failed to lock account: %w", err)
+	}
+	imported, err := s.repo.ImportedExternalIDs(ctx, imp.TenantID, imp.AccountID, externalIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get imported transactions: %w", err)
+	}
+	for i, row := range selected {
+		if row.ExternalID == nil {
+			continue
+		}
+		if transactionID, ok := imported[*row.ExternalID]; ok {
+			return domain.InvalidField(fmt.Sprintf("rows[%d]", i), fmt.Sprintf("line %d was already imported as transaction %s", row.Line, transactionID))
+		}
+	}
+	return nil
+}
+
+// DiscardImport deletes a staged import. Committed imports are kept as the record of where their
+// transactions came from.
+func (s *ImportService) DiscardImport(ctx context.Context, id string) error {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	imp, err := s.GetImport(ctx, id)
+	if err != nil {
+		return err
+	}
+	if imp.Status != domain.ImportStatusStaged {
+		return domain.ErrImportCommitted
+	}
+	if err := s.repo.Delete(ctx, id, imp.TenantID, userID); err != nil {
+		return fmt.Errorf("service failed to delete import: %w", err)
+	}
+	return nil
+}
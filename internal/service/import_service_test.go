@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type mockImportRepo struct {
+	domain.ImportRepository
+	GetByIDFn           func(ctx context.Context, id, tenantID string) (*domain.Import, error)
+	CreateFn            func(ctx context.Context, imp *domain.Import) error
+	MarkCommittedFn     func(ctx context.Context, imp *domain.Import) error
+	FindDuplicatesFn    func(ctx context.Context, tenantID, accountID string, rows []domain.ImportRow) (map[int]string, error)
+	SuggestCategoriesFn func(ctx context.Context, tenantID string, rows []domain.ImportRow) (map[int]string, error)
+	// ImportedExternalIDsFn reports the external IDs already committed on an account
+	ImportedExternalIDsFn func(ctx context.Context, tenantID, accountID string, externalIDs []string) (map[string]string, error)
+	LockAccountFn         func(ctx context.Context, tenantID, accountID string) error
+}
+
+func (m *mockImportRepo) GetByID(ctx context.Context, id, tenantID string) (*domain.Import, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id, tenantID)
+	}
+	return nil, domain.ErrImportNotFound
+}
+
+func (m *mockImportRepo) Create(ctx context.Context, imp *domain.Import) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, imp)
+	}
+	return nil
+}
+
+func (m *mockImportRepo) MarkCommitted(ctx context.Context, imp *domain.Import) error {
+	if m.MarkCommittedFn != nil {
+		return m.MarkCommittedFn(ctx, imp)
+	}
+	return nil
+}
+
+func (m *mockImportRepo) FindDuplicates(ctx context.Context, tenantID, accountID string, rows []domain.ImportRow) (map[int]string, error) {
+	if m.FindDuplicatesFn != nil {
+		return m.FindDuplicatesFn(ctx, tenantID, accountID, rows)
+	}
+	return map[int]string{}, nil
+}
+
+func (m *mockImportRepo) SuggestCategories(ctx context.Context, tenantID string, rows []domain.ImportRow) (map[int]string, error) {
+	if m.SuggestCategoriesFn != nil {
+		return m.SuggestCategoriesFn(ctx, tenantID, rows)
+	}
+	return map[int]string{}, nil
+}
+
+func (m *mockImportRepo) ImportedExternalIDs(ctx context.Context, tenantID, accountID string, externalIDs []string) (map[string]string, error) {
+	if m.ImportedExternalIDsFn != nil {
+		return m.ImportedExternalIDsFn(ctx, tenantID, accountID, externalIDs)
+	}
+	return map[string]string{}, nil
+}
+
+func (m *mockImportRepo) LockAccount(ctx context.Context, tenantID, accountID string) error {
+	if m.LockAccountFn != nil {
+		return m.LockAccountFn(ctx, tenantID, accountID)
+	}
+	return nil
+}
+
+type mockImportProfileRepo struct {
+	domain.ImportProfileRepository
+	GetByIDFn func(ctx context.Context, id, tenantID string) (*domain.ImportProfile, error)
+}
+
+func (m *mockImportProfileRepo) GetByID(ctx context.Context, id, tenantID string) (*domain.ImportProfile, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id, tenantID)
+	}
+	return nil, domain.ErrImportProfileNotFound
+}
+
+// mockTransactor runs fn directly, recording whether it was committed.
+type mockTransactor struct {
+	committed bool
+}
+
+func (m *mockTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	m.committed = true
+	return nil
+}
+
+func TestImportService_Stage(t *testing.T) {
+	ctx := domain.WithUserID(domain.WithTenantID(context.Background(), "tenant-1"), "user-1")
+	accountRepo := &mockAccountRepo{GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Account, error) {
+		return &domain.Account{ID: id, TenantID: tenantID, Currency: "BRL"}, nil
+	}}
+	profileRepo := &mockImportProfileRepo{GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.ImportProfile, error) {
+		return &domain.ImportProfile{
+			ID: id, TenantID: tenantID, Delimiter: ";", HasHeader: true, DateFormat: "dd/mm/yyyy", DecimalSeparator: ",",
+			SignConvention: domain.ImportSignNegativeDebit,
+			Columns:        map[domain.ImportField]string{domain.ImportFieldDate: "Data", domain.ImportFieldDescription: "Histórico", domain.ImportFieldAmount: "Valor"},
+		}, nil
+	}}
+
+	var created *domain.Import
+	repo := &mockImportRepo{
+		FindDuplicatesFn: func(ctx context.Context, tenantID, accountID string, rows []domain.ImportRow) (map[int]string, error) {
+			return map[int]string{2: "tx-existing"}, nil
+		},
+		SuggestCategoriesFn: func(ctx context.Context, tenantID string, rows []domain.ImportRow) (map[int]string, error) {
+			return map[int]string{3: "cat-groceries"}, nil
+		},
+		CreateFn: func(ctx context.Context, imp *domain.Import) error {
+			created = imp
+			return nil
+		},
+	}
+	svc := NewImportService(repo, profileRepo, accountRepo, nil, &mockTransactor{})
+
+	file := "Data;Histórico;Valor\n05/03/2024;PIX RECEBIDO;1.500,00\n06/03/2024;MERCADO;-89,90\n"
+	imp, err := svc.Stage(ctx, "acc-1", "profile-1", "extrato.csv", []byte(file))
+	if err != nil {
+		t.Fatalf("Stage() error = %v", err)
+	}
+	if imp != created || imp.Status != domain.ImportStatusStaged || imp.Currency != "BRL" || imp.CreatedBy != "user-1" || len(imp.Rows) != 2 {
+		t.Fatalf("Stage() = %+v, want a staged BRL import of 2 rows", imp)
+	}
+	if dup := imp.Rows[0].DuplicateTransactionID; dup == nil || *dup != "tx-existing" || imp.Rows[0].SuggestedCategoryID != nil {
+		t.Errorf("row 0 = %+v, want the duplicate of line 2", imp.Rows[0])
+	}
+	if cat := imp.Rows[1].SuggestedCategoryID; cat == nil || *cat != "cat-groceries" || imp.Rows[1].Amount != domain.NewMoney(8990, "BRL") {
+		t.Errorf("row 1 = %+v, want the suggested category of line 3", imp.Rows[1])
+	}
+
+	profileRepo.GetByIDFn = nil
+	_, err = svc.Stage(ctx, "acc-1", "profile-missing", "extrato.csv", []byte(file))
+	var verr *domain.ValidationError
+	if !errors.As(err, &verr) || verr.Fields["profile_id"] == "" {
+		t.Errorf("Stage() with an unknown profile error = %v, want a profile_id validation error", err)
+	}
+	_, err = svc.Stage(ctx, "acc-1", "", "extrato.csv", []byte(file))
+	if !errors.As(err, &verr) || verr.Fields["profile_id"] == "" {
+		t.Errorf("Stage() of a CSV file without a profile error = %v, want a profile_id validation error", err)
+	}
+}
+
+func TestImportService_StageOFX(t *testing.T) {
+	ctx := domain.WithUserID(domain.WithTenantID(context.Background(), "tenant-1"), "user-1")
+	file := `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>BRL
+<BANKACCTFROM><BANKID>0341<ACCTID>56789-0<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20240301<DTEND>20240331
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20240305120000[-3:BRT]<TRNAMT>1500.00<FITID>F1<NAME>PIX RECEBIDO</STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240306<TRNAMT>-89.90<FITID>F2<MEMO>MERCADO</STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240306<TRNAMT>-89.90<FITID>F2<MEMO>MERCADO</STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240310<TRNAMT>-1250.00<FITID>F3<NAME>ALUGUEL</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>2160.10<DTASOF>20240331</LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+	ledgerDate := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		imported        map[string]string // FITIDs committed before
+		wantErr         error
+		wantFITIDs      []string
+		alreadyImported int
+	}{
+		{name: "first import", imported: map[string]string{}, wantFITIDs: []string{"F1", "F2", "F3"}},
+		{name: "re-import of a longer statement", imported: map[string]string{"F1": "tx-1"}, wantFITIDs: []string{"F2", "F3"}, alreadyImported: 1},
+		{name: "same file twice", imported: map[string]string{"F1": "tx-1", "F2": "tx-2", "F3": "tx-3"}, wantErr: domain.ErrImportAlreadyImported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accountRepo := &mockAccountRepo{
+				GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Account, error) {
+					return &domain.Account{ID: id, TenantID: tenantID, Type: domain.AccountTypeBank, Currency: "BRL"}, nil
+				},
+				GetBalanceFn: func(ctx context.Context, id, tenantID string, asOf time.Time) (*domain.AccountBalance, error) {
+					if !asOf.Equal(ledgerDate) {
+						t.Errorf("GetBalance() as of %v, want %v", asOf, ledgerDate)
+					}
+					return &domain.AccountBalance{Cleared: domain.NewMoney(200000, "BRL")}, nil
+				},
+			}
+			created := false
+			repo := &mockImportRepo{
+				ImportedExternalIDsFn: func(ctx context.Context, tenantID, accountID string, externalIDs []string) (map[string]string, error) {
+					return tt.imported, nil
+				},
+				CreateFn: func(ctx context.Context, imp *domain.Import) error {
+					created = true
+					return nil
+				},
+			}
+			svc := NewImportService(repo, &mockImportProfileRepo{}, accountRepo, nil, &mockTransactor{})
+
+			imp, err := svc.Stage(ctx, "acc-1", "", "extrato.ofx", []byte(file))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || created {
+					t.Errorf("Stage() error = %v, created = %v, want %v and nothing created", err, created, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Stage() error = %v", err)
+			}
+
+			if imp.Format != domain.ImportFormatOFX || imp.ProfileID != nil || imp.AlreadyImported != tt.alreadyImported || len(imp.Rows) != len(tt.wantFITIDs) {
+				t.Fatalf("Stage() = %+v, want an OFX import of %d rows with %d already imported", imp, len(tt.wantFITIDs), tt.alreadyImported)
+			}
+			for i, fitID := range tt.wantFITIDs {
+				if row := imp.Rows[i]; row.ExternalID == nil || *row.ExternalID != fitID || row.Date == nil || row.Error != nil {
+					t.Errorf("row %d = %+v, want FITID %s", i, row, fitID)
+				}
+			}
+			if row := imp.Rows[len(imp.Rows)-1]; row.Description != "ALUGUEL" || row.Amount != domain.NewMoney(125000, "BRL") || row.TransactionType != domain.TransactionTypeDebit {
+				t.Errorf("last row = %+v, want a debit of 1250.00", row)
+			}
+			if imp.LedgerBalance == nil || *imp.LedgerBalance != domain.NewMoney(216010, "BRL") || imp.LedgerBalanceDate == nil || !imp.LedgerBalanceDate.Equal(ledgerDate) {
+				t.Errorf("Stage() ledger balance = %v on %v, want 2160.10 on %v", imp.LedgerBalance, imp.LedgerBalanceDate, ledgerDate)
+			}
+			if imp.ComputedBalance == nil || *imp.ComputedBalance != domain.NewMoney(200000, "BRL") {
+				t.Errorf("Stage() computed balance = %v, want 2000.00", imp.ComputedBalance)
+			}
+		})
+	}
+}
+
+func TestImportService_Commit(t *testing.T) {
+	ctx := domain.WithUserID(domain.WithTenantID(context.Background(), "tenant-1"), "user-1")
+	date := time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)
+	suggested := "cat-groceries"
+	chosen := "cat-salary"
+	parseErr := "invalid date"
+	errDB := errors.New("db down")
+	fitID := "F3"
+	staged := func() *domain.Import {
+		return &domain.Import{
+			ID: "imp-1", TenantID: "tenant-1", AccountID: "acc-1", Currency: "BRL", Status: domain.ImportStatusStaged,
+			Rows: []domain.ImportRow{
+				{ID: "row-1", Line: 2, Date: &date, Description: "PIX RECEBIDO", Amount: domain.NewMoney(150000, "BRL"), TransactionType: domain.TransactionTypeCredit},
+				{ID: "row-2", Line: 3, Date: &date, Description: "MERCADO", Amount: domain.NewMoney(8990, "BRL"), TransactionType: domain.TransactionTypeDebit, SuggestedCategoryID: &suggested},
+				{ID: "row-3", Line: 4, Error: &parseErr},
+				{ID: "row-4", Line: 5, ExternalID: &fitID, Date: &date, Description: "ALUGUEL", Amount: domain.NewMoney(125000, "BRL"), TransactionType: domain.TransactionTypeDebit, SuggestedCategoryID: &suggested},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		status     domain.ImportStatus
+		selections []domain.ImportSelection
+		createErr  error
+		wantErr    error
+		wantField  string
+		wantTxs    []string // Category of each transaction created
+	}{
+		{
+			name:       "selected and suggested categories",
+			selections: []domain.ImportSelection{{RowID: "row-1", CategoryID: &chosen}, {RowID: "row-2"}},
+			wantTxs:    []string{"cat-salary", "cat-groceries"},
+		},
+		{name: "already committed", status: domain.ImportStatusCommitted, selections: []domain.ImportSelection{{RowID: "row-2"}}, wantErr: domain.ErrImportCommitted},
+		{name: "no rows", wantField: "rows"},
+		{name: "row without a category", selections: []domain.ImportSelection{{RowID: "row-1"}}, wantField: "rows[0]"},
+		{name: "row that could not be read", selections: []domain.ImportSelection{{RowID: "row-2"}, {RowID: "row-3"}}, wantField: "rows[1]"},
+		{name: "row selected twice", selections: []domain.ImportSelection{{RowID: "row-2"}, {RowID: "row-2"}}, wantField: "rows[1]"},
+		{name: "unknown row", selections: []domain.ImportSelection{{RowID: "row-9"}}, wantField: "rows[0]"},
+		{name: "FITID imported since staged", selections: []domain.ImportSelection{{RowID: "row-2"}, {RowID: "row-4"}}, wantField: "rows[1]"},
+		{
+			name:       "transaction failure rolls back",
+			selections: []domain.ImportSelection{{RowID: "row-2"}},
+			createErr:  errDB,
+			wantErr:    errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var categories []string
+			txRepo := &mockRepo{CreateFn: func(ctx context.Context, tx *domain.Transaction) error {
+				if tt.createErr != nil {
+					return tt.createErr
+				}
+				if tx.FromAccountID != "acc-1" || tx.PaymentDate == nil || !tx.DueDate.Equal(date) || tx.Comments == nil {
+					t.Errorf("Create() transaction = %+v, want a paid transaction of acc-1 on %v", tx, date)
+				}
+				tx.ID = "tx-" + tx.CategoryID
+				categories = append(categories, tx.CategoryID)
+				return nil
+			}}
+			accountRepo := &mockAccountRepo{GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Account, error) {
+				return &domain.Account{ID: id, TenantID: tenantID, Type: domain.AccountTypeBank, Currency: "BRL"}, nil
+			}}
+			transactions := NewTransactionService(txRepo, accountRepo, &mockCategoryRepo{}, &mockTagRepo{}, &mockTransactor{})
+
+			var marked *domain.Import
+			locked := false
+			repo := &mockImportRepo{
+				GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Import, error) {
+					imp := staged()
+					if tt.status != "" {
+						imp.Status = tt.status
+					}
+					if marked != nil {
+						imp.Status = marked.Status
+					}
+					return imp, nil
+				},
+				MarkCommittedFn: func(ctx context.Context, imp *domain.Import) error {
+					imp.Status = domain.ImportStatusCommitted
+					marked = imp
+					return nil
+				},
+				ImportedExternalIDsFn: func(ctx context.Context, tenantID, accountID string, externalIDs []string) (map[string]string, error) {
+					if !locked {
+						t.Error("ImportedExternalIDs() called before the account was locked")
+					}
+					return map[string]string{"F3": "tx-other"}, nil
+				},
+				LockAccountFn: func(ctx context.Context, tenantID, accountID string) error {
+					if accountID != "acc-1" {
+						t.Errorf("LockAccount(%s), want acc-1", accountID)
+					}
+					locked = true
+					return nil
+				},
+			}
+			transactor := &mockTransactor{}
+			svc := NewImportService(repo, &mockImportProfileRepo{}, accountRepo, transactions, transactor)
+
+			imp, err := svc.Commit(ctx, "imp-1", tt.selections)
+			switch {
+			case tt.wantField != "":
+				var verr *domain.ValidationError
+				if !errors.As(err, &verr) || verr.Fields[tt.wantField] == "" {
+					t.Errorf("Commit() error = %v, want a %s validation error", err, tt.wantField)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Commit() error = %v, want %v", err, tt.wantErr)
+				}
+				if transactor.committed || marked != nil {
+					t.Error("Commit() committed the database transaction, want it rolled back")
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Commit() error = %v", err)
+				}
+				if !transactor.committed || imp.Status != domain.ImportStatusCommitted {
+					t.Errorf("Commit() = %+v, committed = %v, want a committed import", imp, transactor.committed)
+				}
+				if len(categories) != len(tt.wantTxs) {
+					t.Fatalf("Commit() created transactions in %v, want %v", categories, tt.wantTxs)
+				}
+				for i, want := range tt.wantTxs {
+					row := marked.Rows[i]
+					if categories[i] != want || row.TransactionID == nil || *row.TransactionID != "tx-"+want {
+						t.Errorf("transaction %d in %s for row %+v, want %s", i, categories[i], row, want)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type InvitationService struct {
+	repo       domain.InvitationRepository
+	userRepo   domain.UserRepository
+	transactor domain.Transactor
+	now        func() time.Time
+}
+
+func NewInvitationService(repo domain.InvitationRepository, userRepo domain.UserRepository, transactor domain.Transactor) *InvitationService {
+	return &InvitationService{
+		repo:       repo,
+		userRepo:   userRepo,
+		transactor: transactor,
+		now:        time.Now,
+	}
+}
+
+// Create invites an email address to the current tenant with the given role.
+// It returns the invitation and its token; the token is not stored and cannot be retrieved again.
+// Only owners can invite other owners.
+func (s *InvitationService) Create(ctx context.Context, email string, role domain.Role) (*domain.Invitation, string, error) {
+	if !role.IsValid() {
+		return nil, "", ErrInvalidRole
+	}
+	if err := checkOwnerChange(ctx, "", role); err != nil {
+		return nil, "", err
+	}
+
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, "", errors.New("user ID is required")
+	}
+
+	token, hash, err := domain.NewInvitationToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("service failed to generate invitation token: %w", err)
+	}
+
+	inv := &domain.Invitation{
+		TenantID:      domain.GetTenantID(ctx),
+		InviterUserID: userID,
+		Email:         strings.ToLower(strings.TrimSpace(email)),
+		Role:          role,
+		TokenHash:     hash,
+		ExpiresAt:     s.now().Add(domain.InvitationTTL),
+	}
+	if valid, errs := inv.IsValid(); !valid {
+		return nil, "", domain.InvalidFields(errs)
+	}
+
+	if err := s.repo.Create(ctx, inv); err != nil {
+		if errors.Is(err, domain.ErrInvitationAlreadyExists) {
+			return nil, "", err
+		}
+		return nil, "", fmt.Errorf("service failed to create invitation: %w", err)
+	}
+	return inv, token, nil
+}
+
+// ListPending returns the invitations of the current tenant that can still be accepted.
+func (s *InvitationService) ListPending(ctx context.Context) ([]domain.Invitation, error) {
+	invitations, err := s.repo.ListPending(ctx, domain.GetTenantID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// Revoke cancels a pending invitation of the current tenant.
+// Only owners can revoke an invitation for the owner role.
+func (s *InvitationService) Revoke(ctx context.Context, id string) error {
+	tenantID := domain.GetTenantID(ctx)
+	inv, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err := checkOwnerChange(ctx, "", inv.Role); err != nil {
+		return err
+	}
+	if inv.Status != domain.InvitationStatusPending {
+		return domain.ErrInvitationNotPending
+	}
+	return s.repo.Revoke(ctx, tenantID, id)
+}
+
+// Accept adds the current user to the invitation's tenant with the invitation's role.
+// The invitation must be pending, not expired, and addressed to the user's email. It is marked accepted
+// in the same database transaction that adds the member, so a failure leaves it pending to retry.
+func (s *InvitationService) Accept(ctx context.Context, token string) (*domain.Invitation, error) {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	inv, err := s.repo.GetByTokenHash(ctx, domain.HashInvitationToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status != domain.InvitationStatusPending {
+		return nil, domain.ErrInvitationNotPending
+	}
+	if inv.IsExpired(s.now()) {
+		return nil, domain.ErrInvitationExpired
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get user: %w", err)
+	}
+	if !strings.EqualFold(user.Email, inv.Email) {
+		return nil, domain.ErrInvitationEmailMismatch
+	}
+
+	// Accepting must never change the role of an existing member.
+	if _, err := s.userRepo.GetUserTenant(ctx, userID, inv.TenantID); err == nil {
+		return nil, domain.ErrAlreadyTenantMember
+	} else if !errors.Is(err, domain.ErrNotTenantMember) {
+		return nil, fmt.Errorf("service failed to check tenant membership: %w", err)
+	}
+
+	err = s.transactor.InTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.MarkAccepted(ctx, inv, userID); err != nil {
+			if errors.Is(err, domain.ErrInvitationNotPending) {
+				return err
+			}
+			return fmt.Errorf("service failed to accept invitation: %w", err)
+		}
+		if err := s.userRepo.AddUserToTenant(ctx, userID, inv.TenantID, inv.Role); err != nil {
+			return fmt.Errorf("service failed to add user to tenant: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// ExpireInvitations marks every pending invitation past its expiry as expired and returns how many were updated.
+func (s *InvitationService) ExpireInvitations(ctx context.Context) (int64, error) {
+	n, err := s.repo.ExpirePending(ctx, s.now())
+	if err != nil {
+		return 0, fmt.Errorf("service failed to expire invitations: %w", err)
+	}
+	return n, nil
+}
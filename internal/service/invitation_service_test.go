@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type mockInvitationRepo struct {
+	domain.InvitationRepository
+	GetByTokenHashFn func(ctx context.Context, tokenHash string) (*domain.Invitation, error)
+	MarkAcceptedFn   func(ctx context.Context, inv *domain.Invitation, userID string) error
+}
+
+func (m *mockInvitationRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	if m.GetByTokenHashFn != nil {
+		return m.GetByTokenHashFn(ctx, tokenHash)
+	}
+	return nil, domain.ErrInvitationNotFound
+}
+
+func (m *mockInvitationRepo) MarkAccepted(ctx context.Context, inv *domain.Invitation, userID string) error {
+	if m.MarkAcceptedFn != nil {
+		return m.MarkAcceptedFn(ctx, inv, userID)
+	}
+	inv.Status = domain.InvitationStatusAccepted
+	return nil
+}
+
+type mockUserRepo struct {
+	domain.UserRepository
+	GetByIDFn         func(ctx context.Context, id string) (*domain.User, error)
+	GetUserTenantFn   func(ctx context.Context, userID, tenantID string) (*domain.UserTenant, error)
+	AddUserToTenantFn func(ctx context.Context, userID, tenantID string, role domain.Role) error
+
+	ListTenantMembersFn    func(ctx context.Context, tenantID string) ([]domain.TenantMember, error)
+	UpdateUserTenantRoleFn func(ctx context.Context, userID, tenantID string, role domain.Role) error
+	RemoveUserFromTenantFn func(ctx context.Context, userID, tenantID string) error
+}
+
+func (m *mockUserRepo) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockUserRepo) GetUserTenant(ctx context.Context, userID, tenantID string) (*domain.UserTenant, error) {
+	if m.GetUserTenantFn != nil {
+		return m.GetUserTenantFn(ctx, userID, tenantID)
+	}
+	return nil, domain.ErrNotTenantMember
+}
+
+func (m *mockUserRepo) AddUserToTenant(ctx context.Context, userID, tenantID string, role domain.Role) error {
+	if m.AddUserToTenantFn != nil {
+		return m.AddUserToTenantFn(ctx, userID, tenantID, role)
+	}
+	return nil
+}
+
+func (m *mockUserRepo) ListTenantMembers(ctx context.Context, tenantID string) ([]domain.TenantMember, error) {
+	if m.ListTenantMembersFn != nil {
+		return m.ListTenantMembersFn(ctx, tenantID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepo) UpdateUserTenantRole(ctx context.Context, userID, tenantID string, role domain.Role) error {
+	if m.UpdateUserTenantRoleFn != nil {
+		return m.UpdateUserTenantRoleFn(ctx, userID, tenantID, role)
+	}
+	return nil
+}
+
+func (m *mockUserRepo) RemoveUserFromTenant(ctx context.Context, userID, tenantID string) error {
+	if m.RemoveUserFromTenantFn != nil {
+		return m.RemoveUserFromTenantFn(ctx, userID, tenantID)
+	}
+	return nil
+}
+
+func TestInvitationService_Accept(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), "user-1")
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	const token = "secret-token"
+	errDB := errors.New("db down")
+
+	pending := func() *domain.Invitation {
+		return &domain.Invitation{
+			ID:        "inv-1",
+			TenantID:  "tenant-1",
+			Email:     "jane@example.com",
+			Role:      domain.RoleEditor,
+			TokenHash: domain.HashInvitationToken(token),
+			Status:    domain.InvitationStatusPending,
+			ExpiresAt: now.Add(time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		invitation func() *domain.Invitation
+		email      string
+		member     bool
+		addErr     error
+		wantErr    error
+		wantAdded  bool
+	}{
+		{
+			name:       "Success adds the user with the invitation role",
+			token:      token,
+			invitation: pending,
+			email:      "Jane@Example.com",
+			wantAdded:  true,
+		},
+		{
+			name:       "Unknown token",
+			token:      "other-token",
+			invitation: pending,
+			email:      "jane@example.com",
+			wantErr:    domain.ErrInvitationNotFound,
+		},
+		{
+			name:  "Expired",
+			token: token,
+			invitation: func() *domain.Invitation {
+				inv := pending()
+				inv.ExpiresAt = now
+				return inv
+			},
+			email:   "jane@example.com",
+			wantErr: domain.ErrInvitationExpired,
+		},
+		{
+			name:  "Revoked",
+			token: token,
+			invitation: func() *domain.Invitation {
+				inv := pending()
+				inv.Status = domain.InvitationStatusRevoked
+				return inv
+			},
+			email:   "jane@example.com",
+			wantErr: domain.ErrInvitationNotPending,
+		},
+		{
+			name:       "Different email",
+			token:      token,
+			invitation: pending,
+			email:      "john@example.com",
+			wantErr:    domain.ErrInvitationEmailMismatch,
+		},
+		{
+			name:       "Already a member",
+			token:      token,
+			invitation: pending,
+			email:      "jane@example.com",
+			member:     true,
+			wantErr:    domain.ErrAlreadyTenantMember,
+		},
+		{
+			name:       "Membership failure rolls back the acceptance",
+			token:      token,
+			invitation: pending,
+			email:      "jane@example.com",
+			addErr:     errDB,
+			wantErr:    errDB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := tt.invitation()
+			repo := &mockInvitationRepo{
+				GetByTokenHashFn: func(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+					if tokenHash != inv.TokenHash {
+						return nil, domain.ErrInvitationNotFound
+					}
+					return inv, nil
+				},
+			}
+			added := false
+			userRepo := &mockUserRepo{
+				GetByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+					return &domain.User{ID: id, Email: tt.email}, nil
+				},
+				GetUserTenantFn: func(ctx context.Context, userID, tenantID string) (*domain.UserTenant, error) {
+					if tt.member {
+						return &domain.UserTenant{UserID: userID, TenantID: tenantID, Role: domain.RoleOwner}, nil
+					}
+					return nil, domain.ErrNotTenantMember
+				},
+				AddUserToTenantFn: func(ctx context.Context, userID, tenantID string, role domain.Role) error {
+					if userID != "user-1" || tenantID != "tenant-1" || role != domain.RoleEditor {
+						t.Errorf("AddUserToTenant(%s, %s, %s), want (user-1, tenant-1, editor)", userID, tenantID, role)
+					}
+					if tt.addErr != nil {
+						return tt.addErr
+					}
+					added = true
+					return nil
+				},
+			}
+			transactor := &mockTransactor{}
+
+			s := NewInvitationService(repo, userRepo, transactor)
+			s.now = func() time.Time { return now }
+
+			_, err := s.Accept(ctx, tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Accept() error = %v, want %v", err, tt.wantErr)
+			}
+			if added != tt.wantAdded {
+				t.Errorf("user added = %v, want %v", added, tt.wantAdded)
+			}
+			if transactor.committed != tt.wantAdded {
+				t.Errorf("committed = %v, want %v", transactor.committed, tt.wantAdded)
+			}
+		})
+	}
+}
+
+func TestInvitationService_Create_OwnerRoleRequiresOwner(t *testing.T) {
+	ctx := domain.WithUserID(context.Background(), "user-1")
+	ctx = domain.WithTenantID(ctx, "tenant-1")
+	ctx = domain.WithRole(ctx, domain.RoleAdmin)
+
+	s := NewInvitationService(&mockInvitationRepo{}, &mockUserRepo{}, &mockTransactor{})
+	if _, _, err := s.Create(ctx, "jane@example.com", domain.RoleOwner); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Create() error = %v, want %v", err, domain.ErrForbidden)
+	}
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// RecurrenceService manages recurrence rules and materializes their occurrences as transactions
+// up to a rolling horizon ahead of today.
+type RecurrenceService struct {
+	repo         domain.RecurrenceRuleRepository
+	accountRepo  domain.AccountRepository
+	categoryRepo domain.CategoryRepository
+	tagRepo      domain.TagRepository
+	horizon      time.Duration
+	now          func() time.Time
+}
+
+func NewRecurrenceService(
+	repo domain.RecurrenceRuleRepository,
+	accountRepo domain.AccountRepository,
+	categoryRepo domain.CategoryRepository,
+	tagRepo domain.TagRepository,
+	horizon time.Duration,
+) *RecurrenceService {
+	if horizon <= 0 {
+		horizon = domain.DefaultRecurrenceHorizon
+	}
+	return &RecurrenceService{
+		repo:         repo,
+		accountRepo:  accountRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		horizon:      horizon,
+		now:          time.Now,
+	}
+}
+
+func (s *RecurrenceService) GetRule(ctx context.Context, id string) (*domain.RecurrenceRule, error) {
+	return s.repo.GetByID(ctx, domain.GetTenantID(ctx), id)
+}
+
+func (s *RecurrenceService) ListRules(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.RecurrenceRule], error) {
+	return s.repo.List(ctx, domain.GetTenantID(ctx), page)
+}
+
+// CreateRule saves a rule and materializes its occurrences up to the horizon.
+func (s *RecurrenceService) CreateRule(ctx context.Context, rule *domain.RecurrenceRule) error {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	rule.TenantID = domain.GetTenantID(ctx)
+	rule.CreatedBy = userID
+	rule.UpdatedBy = userID
+
+	if err := s.validate(ctx, rule); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return fmt.Errorf("service failed to create recurrence rule: %w", err)
+	}
+	if _, err := s.materialize(ctx, rule); err != nil {
+		return fmt.Errorf("recurrence rule created but failed to materialize occurrences: %w", err)
+	}
+	return nil
+}
+
+// UpdateRule saves the template and schedule of a rule. Unpaid occurrences from today on are
+// replaced by ones following the new rule; past and paid occurrences are kept.
+func (s *RecurrenceService) UpdateRule(ctx context.Context, rule *domain.RecurrenceRule) (*domain.RecurrenceRule, error) {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	rule.TenantID = domain.GetTenantID(ctx)
+	rule.UpdatedBy = userID
+
+	if err := s.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, rule, s.today()); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.GetByID(ctx, rule.TenantID, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.materialize(ctx, updated); err != nil {
+		return nil, fmt.Errorf("recurrence rule updated but failed to materialize occurrences: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteRule deactivates a rule and cancels its unpaid occurrences from today on.
+func (s *RecurrenceService) DeleteRule(ctx context.Context, id string) error {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	return s.repo.Delete(ctx, domain.GetTenantID(ctx), id, userID, s.today())
+}
+
+// SkipOccurrence skips a single occurrence, cancelling its transaction if it was already materialized.
+func (s *RecurrenceService) SkipOccurrence(ctx context.Context, id string, date time.Time) (*domain.RecurrenceRule, error) {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	tenantID := domain.GetTenantID(ctx)
+
+	rule, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !rule.IsOccurrence(date) {
+		return nil, domain.ErrNotAnOccurrence
+	}
+	if err := s.repo.SkipOccurrence(ctx, tenantID, id, date, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, tenantID, id)
+}
+
+// ChangeAmount changes the amount of the occurrences from effectiveFrom onward, materialized ones included.
+func (s *RecurrenceService) ChangeAmount(ctx context.Context, id string, effectiveFrom time.Time, amount domain.Money) (*domain.RecurrenceRule, error) {
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	tenantID := domain.GetTenantID(ctx)
+
+	rule, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !amount.IsPositive() {
+		return nil, domain.InvalidField("amount", "amount must be greater than 0")
+	}
+
+	change := domain.RecurrenceAmountChange{EffectiveFrom: effectiveFrom, Amount: amount.WithCurrency(rule.Currency)}
+	if err := s.repo.ChangeAmount(ctx, tenantID, id, change, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, tenantID, id)
+}
+
+// Materialize creates the missing occurrences of every rule of the tenant up to the horizon.
+// It returns the number of transactions created.
+func (s *RecurrenceService) Materialize(ctx context.Context) (int, error) {
+	tenantID := domain.GetTenantID(ctx)
+	if tenantID == "" {
+		return 0, errors.New("tenant ID is required")
+	}
+	return s.materializeRules(ctx, tenantID)
+}
+
+// MaterializeAll creates the missing occurrences of the rules of every tenant up to the horizon.
+// It is meant for the background job and returns the number of transactions created.
+func (s *RecurrenceService) MaterializeAll(ctx context.Context) (int, error) {
+	return s.materializeRules(ctx, "")
+}
+
+// materializeRules materializes the rules of a tenant, or of every tenant when tenantID is empty.
+func (s *RecurrenceService) materializeRules(ctx context.Context, tenantID string) (int, error) {
+	rules, err := s.repo.ListToMaterialize(ctx, tenantID, s.horizonEnd())
+	if err != nil {
+		return 0, fmt.Errorf("service failed to list recurrence rules: %w", err)
+	}
+
+	total := 0
+	for i := range rules {
+		n, err := s.materialize(ctx, &rules[i])
+		if err != nil {
+			return total, fmt.Errorf("service failed to materialize rule %s: %w", rules[i].ID, err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// materialize creates the occurrences of a rule between its last materialized date and the horizon.
+// Occurrences on a credit card follow the card's statements, like card purchases do.
+func (s *RecurrenceService) materialize(ctx context.Context, rule *domain.RecurrenceRule) (int, error) {
+	until := s.horizonEnd()
+	from := rule.MaterializeFrom()
+	if from.After(until) {
+		return 0, nil
+	}
+
+	card, err := s.cardInfo(ctx, rule)
+	if err != nil {
+		return 0, err
+	}
+
+	var occurrences []domain.Transaction
+	for _, date := range rule.Occurrences(from, until) {
+		if rule.IsSkipped(date) {
+			continue
+		}
+		t := rule.Occurrence(date)
+		if card != nil && (t.TransactionType == domain.TransactionTypeCredit || t.TransactionType == domain.TransactionTypeDebit) {
+			t.AccrualMonth = card.StatementPeriod(date)
+			if _, _, t.DueDate, err = card.StatementDates(t.AccrualMonth); err != nil {
+				return 0, fmt.Errorf("failed to compute statement dates: %w", err)
+			}
+			purchaseDate := date
+			t.PaymentDate = &purchaseDate
+		}
+		occurrences = append(occurrences, t)
+	}
+
+	return s.repo.Materialize(ctx, rule, occurrences, until)
+}
+
+// cardInfo returns the credit card details of the rule's account, or nil when it is not a card or has none.
+func (s *RecurrenceService) cardInfo(ctx context.Context, rule *domain.RecurrenceRule) (*domain.CreditCardInfo, error) {
+	account, err := s.accountRepo.GetByID(ctx, rule.FromAccountID, rule.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch from_account: %w", err)
+	}
+	if account.Type != domain.AccountTypeCreditCard {
+		return nil, nil
+	}
+	card, err := s.accountRepo.GetCreditCardInfo(ctx, account.ID, account.TenantID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCreditCardInfoNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch credit card info: %w", err)
+	}
+	return card, nil
+}
+
+// validate defaults the currency to the account's and checks the rule and the ownership of what it references.
+func (s *RecurrenceService) validate(ctx context.Context, rule *domain.RecurrenceRule) error {
+	fromAccount, err := s.accountRepo.GetByID(ctx, rule.FromAccountID, rule.TenantID)
+	if err != nil {
+		return referenceError(err, "from_account_id")
+	}
+	if rule.Currency == "" {
+		rule.Currency = fromAccount.Currency
+	}
+	rule.Amount.Currency = rule.Currency
+	if rule.Interval == 0 {
+		rule.Interval = 1
+	}
+
+	if valid, errs := rule.IsValid(); !valid {
+		return domain.InvalidFields(errs)
+	}
+
+	if rule.ToAccountID != nil && *rule.ToAccountID != "" {
+		toAccount, err := s.accountRepo.GetByID(ctx, *rule.ToAccountID, rule.TenantID)
+		if err != nil {
+			return referenceError(err, "to_account_id")
+		}
+		if rule.TransactionType == domain.TransactionTypeTransfer && toAccount.Currency != rule.Currency {
+			return domain.InvalidField("to_account_id", "recurring transfers must be between accounts of the same currency")
+		}
+	}
+	if _, err := s.categoryRepo.GetByID(ctx, rule.CategoryID, rule.TenantID); err != nil {
+		return referenceError(err, "category_id")
+	}
+	if len(rule.TagIDs) > 0 {
+		valid, err := s.tagRepo.ValidateTags(ctx, rule.TenantID, rule.TagIDs)
+		if err != nil {
+			return fmt.Errorf("failed to validate tags: %w", err)
+		}
+		if !valid {
+			return domain.InvalidField("tag_ids", "one or more tags do not belong to this tenant")
+		}
+	}
+	return nil
+}
+
+func (s *RecurrenceService) today() time.Time {
+	year, month, day := s.now().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *RecurrenceService) horizonEnd() time.Time {
+	return s.today().Add(s.horizon)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type ReportService struct {
+	repo        domain.ReportRepository
+	accountRepo domain.AccountRepository
+}
+
+func NewReportService(repo domain.ReportRepository, accountRepo domain.AccountRepository) *ReportService {
+	return &ReportService{repo: repo, accountRepo: accountRepo}
+}
+
+// SpendByCategory returns the spend of each expense category per month, with subcategories rolled up.
+func (s *ReportService) SpendByCategory(ctx context.Context, filter domain.ReportFilter) ([]domain.CategorySpend, error) {
+	tenantID := domain.GetTenantID(ctx)
+	if err := s.prepare(ctx, tenantID, &filter); err != nil {
+		return nil, err
+	}
+
+	spend, err := s.repo.SpendByCategory(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to report spend by category: %w", err)
+	}
+	return spend, nil
+}
+
+// IncomeExpense returns the income, expense and net of every month of the range, per currency.
+func (s *ReportService) IncomeExpense(ctx context.Context, filter domain.ReportFilter) ([]domain.IncomeExpense, error) {
+	tenantID := domain.GetTenantID(ctx)
+	if err := s.prepare(ctx, tenantID, &filter); err != nil {
+		return nil, err
+	}
+
+	months, err := s.repo.IncomeExpense(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to report income and expense: %w", err)
+	}
+	return domain.FillIncomeExpense(months, filter.Months(), filter.Currency), nil
+}
+
+// CashFlow returns the inflow, outflow and net of every month of the range, per currency.
+func (s *ReportService) CashFlow(ctx context.Context, filter domain.ReportFilter) ([]domain.CashFlow, error) {
+	tenantID := domain.GetTenantID(ctx)
+	if err := s.prepare(ctx, tenantID, &filter); err != nil {
+		return nil, err
+	}
+
+	months, err := s.repo.CashFlow(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to report cash flow: %w", err)
+	}
+	return domain.FillCashFlow(months, filter.Months(), filter.Currency), nil
+}
+
+// TopTags returns the tags with the highest spend over the range, at most limit per currency.
+// A zero limit uses domain.DefaultTopTagsLimit.
+func (s *ReportService) TopTags(ctx context.Context, filter domain.ReportFilter, limit int) ([]domain.TagSpend, error) {
+	tenantID := domain.GetTenantID(ctx)
+	if err := s.prepare(ctx, tenantID, &filter); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = domain.DefaultTopTagsLimit
+	}
+	if limit > domain.MaxTopTagsLimit {
+		return nil, fmt.Errorf("%w: limit must be at most %d", domain.ErrInvalidReportFilter, domain.MaxTopTagsLimit)
+	}
+
+	tags, err := s.repo.TopTags(ctx, tenantID, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to report top tags: %w", err)
+	}
+	return tags, nil
+}
+
+// prepare normalizes the month range of filter and checks its account belongs to the tenant.
+// When the account is given without a currency, the report uses the account's.
+func (s *ReportService) prepare(ctx context.Context, tenantID string, filter *domain.ReportFilter) error {
+	if err := filter.Normalize(time.Now()); err != nil {
+		return err
+	}
+	if filter.AccountID == "" {
+		return nil
+	}
+	acc, err := s.accountRepo.GetByID(ctx, filter.AccountID, tenantID)
+	if err != nil {
+		return fmt.Errorf("service failed to get account: %w", err)
+	}
+	if filter.Currency == "" {
+		filter.Currency = acc.Currency
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type StatementService struct {
+	repo        domain.StatementRepository
+	accountRepo domain.AccountRepository
+}
+
+func NewStatementService(repo domain.StatementRepository, accountRepo domain.AccountRepository) *StatementService {
+	return &StatementService{repo: repo, accountRepo: accountRepo}
+}
+
+// List returns every statement of a credit card account with activity, newest first. Each one is dated
+// by the version of the card details in effect for its period.
+func (s *StatementService) List(ctx context.Context, accountID string) ([]domain.Statement, error) {
+	tenantID := domain.GetTenantID(ctx)
+	acc, versions, err := s.loadCard(ctx, accountID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := s.repo.ListTotals(ctx, tenantID, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list statements: %w", err)
+	}
+
+	now := time.Now()
+	for i := range statements {
+		card, err := domain.CreditCardVersionAt(versions, statements[i].Period)
+		if err != nil {
+			return nil, fmt.Errorf("service failed to list statements: %w", err)
+		}
+		if err := statements[i].Finalize(card, acc.Currency, now); err != nil {
+			return nil, fmt.Errorf("service failed to list statements: %w", err)
+		}
+	}
+	return statements, nil
+}
+
+// Get returns the statement of a credit card account for the given period (YYYYMM), including its line items.
+func (s *StatementService) Get(ctx context.Context, accountID, period string) (*domain.Statement, error) {
+	if _, err := domain.ParsePeriod(period); err != nil {
+		return nil, err
+	}
+
+	tenantID := domain.GetTenantID(ctx)
+	acc, versions, err := s.loadCard(ctx, accountID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	card, err := domain.CreditCardVersionAt(versions, period)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := s.repo.GetTotals(ctx, tenantID, accountID, period)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get statement: %w", err)
+	}
+	if err := statement.Finalize(card, acc.Currency, time.Now()); err != nil {
+		return nil, fmt.Errorf("service failed to get statement: %w", err)
+	}
+
+	statement.Items, err = s.repo.ListItems(ctx, tenantID, accountID, period)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get statement items: %w", err)
+	}
+	return statement, nil
+}
+
+// loadCard returns a credit card account with the versions of its card details, oldest first.
+// The card must have current details.
+func (s *StatementService) loadCard(ctx context.Context, accountID, tenantID string) (*domain.Account, []domain.CreditCardInfo, error) {
+	acc, err := s.accountRepo.GetByID(ctx, accountID, tenantID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("service failed to get account: %w", err)
+	}
+	if acc.Type != domain.AccountTypeCreditCard {
+		return nil, nil, domain.ErrNotCreditCardAccount
+	}
+
+	versions, err := s.accountRepo.ListCreditCardVersions(ctx, accountID, tenantID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("service failed to get credit card info: %w", err)
+	}
+	// The current version, when there is one, is the latest
+	if len(versions) == 0 || versions[len(versions)-1].DeactivatedAt != nil {
+		return nil, nil, domain.ErrCreditCardInfoNotFound
+	}
+	return acc, versions, nil
+}
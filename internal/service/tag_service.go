@@ -24,9 +24,9 @@ func (s *TagService) GetTag(ctx context.Context, id string) (*domain.Tag, error)
 	return tag, nil
 }
 
-func (s *TagService) ListTags(ctx context.Context) ([]domain.Tag, error) {
+func (s *TagService) ListTags(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Tag], error) {
 	tenantID := domain.GetTenantID(ctx)
-	tags, err := s.repo.List(ctx, tenantID)
+	tags, err := s.repo.List(ctx, tenantID, page)
 	if err != nil {
 		return nil, fmt.Errorf("service failed to list tags: %w", err)
 	}
@@ -38,7 +38,7 @@ func (s *TagService) CreateTag(ctx context.Context, tag *domain.Tag) error {
 	tag.TenantID = tenantID
 
 	if tag.Name == "" {
-		return fmt.Errorf("tag name is required")
+		return domain.InvalidField("name", "name is required")
 	}
 
 	if err := s.repo.Create(ctx, tag); err != nil {
@@ -57,6 +57,30 @@ func (s *TagService) UpdateTag(ctx context.Context, tag *domain.Tag) error {
 	return nil
 }
 
+// PatchTag applies a partial update to a tag.
+// The update requires version when given, or else the version that was loaded.
+func (s *TagService) PatchTag(ctx context.Context, id string, patch domain.TagPatch, version int) (*domain.Tag, error) {
+	tenantID := domain.GetTenantID(ctx)
+	tag, err := s.repo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get tag: %w", err)
+	}
+	patch.Apply(tag)
+	tag.UpdatedBy = domain.GetUserID(ctx)
+	if version != 0 {
+		tag.Version = version
+	}
+
+	if valid, errs := tag.IsValid(); !valid {
+		return nil, domain.InvalidFields(errs)
+	}
+
+	if err := s.repo.Update(ctx, tag); err != nil {
+		return nil, fmt.Errorf("service failed to update tag: %w", err)
+	}
+	return tag, nil
+}
+
 func (s *TagService) DeleteTag(ctx context.Context, id, userID string) error {
 	tenantID := domain.GetTenantID(ctx)
 
@@ -7,21 +7,29 @@ import (
 	"github.com/igoventura/fintrack-api/domain"
 )
 
+// MembershipCache is told when a membership changes, so that the previous role is not trusted any longer.
+// It is implemented by middleware.TenantMiddleware.
+type MembershipCache interface {
+	InvalidateMembership(userID, tenantID string)
+}
+
 type TenantService struct {
 	repo        domain.TenantRepository
 	userService *UserService
+	memberships MembershipCache
 }
 
-func NewTenantService(repo domain.TenantRepository, userService *UserService) *TenantService {
+func NewTenantService(repo domain.TenantRepository, userService *UserService, memberships MembershipCache) *TenantService {
 	return &TenantService{
 		repo:        repo,
 		userService: userService,
+		memberships: memberships,
 	}
 }
 
 func (s *TenantService) CreateTenant(ctx context.Context, name, creatorID string) (*domain.Tenant, error) {
 	if name == "" {
-		return nil, fmt.Errorf("tenant name is required")
+		return nil, domain.InvalidField("name", "name is required")
 	}
 
 	tenant := &domain.Tenant{
@@ -33,8 +41,8 @@ func (s *TenantService) CreateTenant(ctx context.Context, name, creatorID string
 		return nil, fmt.Errorf("service failed to create tenant: %w", err)
 	}
 
-	// Link creator to tenant
-	if err := s.userService.AddTenantToUser(ctx, creatorID, tenant.ID); err != nil {
+	// Link creator to tenant as its owner
+	if err := s.userService.AddTenantToUser(ctx, creatorID, tenant.ID, domain.RoleOwner); err != nil {
 		// Optimization: Ideally we should rollback the created tenant here if this fails,
 		// but for now we'll just return the error.
 		return nil, fmt.Errorf("service failed to link creator to tenant: %w", err)
@@ -42,3 +50,88 @@ func (s *TenantService) CreateTenant(ctx context.Context, name, creatorID string
 
 	return tenant, nil
 }
+
+// ListMembers returns the active members of the current tenant.
+func (s *TenantService) ListMembers(ctx context.Context) ([]domain.TenantMember, error) {
+	return s.userService.ListTenantMembers(ctx, domain.GetTenantID(ctx))
+}
+
+// UpdateMemberRole changes the role of a member of the current tenant.
+// Only owners can grant the owner role or change the role of another owner.
+// The last owner check is repeated by the repository under a lock, so concurrent changes cannot remove every owner.
+func (s *TenantService) UpdateMemberRole(ctx context.Context, userID string, role domain.Role) error {
+	if !role.IsValid() {
+		return ErrInvalidRole
+	}
+
+	tenantID := domain.GetTenantID(ctx)
+	member, owners, err := s.findMember(ctx, tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkOwnerChange(ctx, member.Role, role); err != nil {
+		return err
+	}
+	if member.Role == domain.RoleOwner && role != domain.RoleOwner && owners == 1 {
+		return domain.ErrLastOwner
+	}
+
+	if err := s.userService.UpdateTenantRole(ctx, userID, tenantID, role); err != nil {
+		return err
+	}
+	s.memberships.InvalidateMembership(userID, tenantID)
+	return nil
+}
+
+// RemoveMember removes a member from the current tenant.
+// Only owners can remove another owner, and the last owner cannot be removed.
+func (s *TenantService) RemoveMember(ctx context.Context, userID string) error {
+	tenantID := domain.GetTenantID(ctx)
+	member, owners, err := s.findMember(ctx, tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkOwnerChange(ctx, member.Role, ""); err != nil {
+		return err
+	}
+	if member.Role == domain.RoleOwner && owners == 1 {
+		return domain.ErrLastOwner
+	}
+
+	if err := s.userService.RemoveUserFromTenant(ctx, userID, tenantID); err != nil {
+		return err
+	}
+	s.memberships.InvalidateMembership(userID, tenantID)
+	return nil
+}
+
+// findMember returns the member with the given user ID and the number of owners of the tenant.
+func (s *TenantService) findMember(ctx context.Context, tenantID, userID string) (*domain.TenantMember, int, error) {
+	members, err := s.userService.ListTenantMembers(ctx, tenantID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var member *domain.TenantMember
+	owners := 0
+	for i := range members {
+		if members[i].Role == domain.RoleOwner {
+			owners++
+		}
+		if members[i].UserID == userID {
+			member = &members[i]
+		}
+	}
+	if member == nil {
+		return nil, 0, domain.ErrNotTenantMember
+	}
+	return member, owners, nil
+}
+
+// checkOwnerChange ensures only owners touch the owner role, either on the current or on the new role.
+func checkOwnerChange(ctx context.Context, current, next domain.Role) error {
+	if (current == domain.RoleOwner || next == domain.RoleOwner) && !domain.GetRole(ctx).Can(domain.PermissionManageOwners) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
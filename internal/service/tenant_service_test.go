@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+type mockMembershipCache struct {
+	invalidated []string
+}
+
+func (m *mockMembershipCache) InvalidateMembership(userID, tenantID string) {
+	m.invalidated = append(m.invalidated, userID+"/"+tenantID)
+}
+
+func TestTenantService_LastOwner(t *testing.T) {
+	ctx := domain.WithTenantID(context.Background(), "tenant-1")
+	ctx = domain.WithRole(ctx, domain.RoleOwner)
+
+	oneOwner := []domain.TenantMember{
+		{UserID: "user-1", Role: domain.RoleOwner},
+		{UserID: "user-2", Role: domain.RoleEditor},
+	}
+	twoOwners := []domain.TenantMember{
+		{UserID: "user-1", Role: domain.RoleOwner},
+		{UserID: "user-2", Role: domain.RoleOwner},
+	}
+
+	tests := []struct {
+		name    string
+		members []domain.TenantMember
+		// repoErr is returned by the repository, e.g. when another owner was removed concurrently.
+		repoErr     error
+		wantErr     error
+		wantChanges int
+	}{
+		{
+			name:        "Another owner is left",
+			members:     twoOwners,
+			wantChanges: 2,
+		},
+		{
+			name:    "Only owner",
+			members: oneOwner,
+			wantErr: domain.ErrLastOwner,
+		},
+		{
+			name:    "Other owner removed concurrently",
+			members: twoOwners,
+			repoErr: domain.ErrLastOwner,
+			wantErr: domain.ErrLastOwner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := 0
+			change := func() error {
+				if tt.repoErr != nil {
+					return tt.repoErr
+				}
+				changed++
+				return nil
+			}
+			userRepo := &mockUserRepo{
+				ListTenantMembersFn: func(ctx context.Context, tenantID string) ([]domain.TenantMember, error) {
+					return tt.members, nil
+				},
+				UpdateUserTenantRoleFn: func(ctx context.Context, userID, tenantID string, role domain.Role) error {
+					return change()
+				},
+				RemoveUserFromTenantFn: func(ctx context.Context, userID, tenantID string) error {
+					return change()
+				},
+			}
+			cache := &mockMembershipCache{}
+			s := NewTenantService(nil, NewUserService(userRepo), cache)
+
+			err := s.UpdateMemberRole(ctx, "user-1", domain.RoleEditor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateMemberRole() error = %v, want %v", err, tt.wantErr)
+			}
+			err = s.RemoveMember(ctx, "user-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RemoveMember() error = %v, want %v", err, tt.wantErr)
+			}
+			if changed != tt.wantChanges {
+				t.Errorf("repository changes = %d, want %d", changed, tt.wantChanges)
+			}
+			if len(cache.invalidated) != tt.wantChanges {
+				t.Errorf("invalidated memberships = %v, want %d", cache.invalidated, tt.wantChanges)
+			}
+			for _, key := range cache.invalidated {
+				if key != "user-1/tenant-1" {
+					t.Errorf("invalidated membership %s, want user-1/tenant-1", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// Settle records a payment against a transaction. A zero amount settles the outstanding amount and an
+// empty account ID pays from the transaction's own account. The transaction becomes paid once its
+// payments add up to its amount. The update requires version when given, or else the version that was loaded.
+func (s *TransactionService) Settle(ctx context.Context, id string, payment domain.TransactionPayment, version int) (*domain.Transaction, error) {
+	tenantID := domain.GetTenantID(ctx)
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	t, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get transaction: %w", err)
+	}
+	if version != 0 {
+		t.Version = version
+	}
+	if err := s.settle(ctx, t, &payment, userID, map[string]*domain.Account{}); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddPayments(ctx, []*domain.Transaction{t}, []domain.TransactionPayment{payment}); err != nil {
+		return nil, err
+	}
+	if err := s.attachTags(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// SettleMany settles the outstanding amount of each transaction, all at once. Transactions already
+// paid are left unchanged. An empty account ID pays each transaction from its own account.
+func (s *TransactionService) SettleMany(ctx context.Context, ids []string, paymentDate time.Time, accountID string) ([]domain.Transaction, error) {
+	tenantID := domain.GetTenantID(ctx)
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	accounts := map[string]*domain.Account{}
+	txs := make([]*domain.Transaction, 0, len(ids))
+	var settled []*domain.Transaction
+	var payments []domain.TransactionPayment
+	for _, id := range ids {
+		t, err := s.repo.GetByID(ctx, tenantID, id)
+		if err != nil {
+			return nil, fmt.Errorf("service failed to get transaction %s: %w", id, err)
+		}
+		txs = append(txs, t)
+		if t.PaymentDate != nil {
+			continue
+		}
+
+		payment := domain.TransactionPayment{AccountID: accountID, PaymentDate: paymentDate}
+		if err := s.settle(ctx, t, &payment, userID, accounts); err != nil {
+			return nil, err
+		}
+		settled = append(settled, t)
+		payments = append(payments, payment)
+	}
+
+	if len(payments) > 0 {
+		if err := s.repo.AddPayments(ctx, settled, payments); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.attachTags(ctx, txs...); err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Transaction, len(txs))
+	for i, t := range txs {
+		result[i] = *t
+	}
+	return result, nil
+}
+
+// settle applies payment to t after checking its account, which must belong to the tenant and share
+// the currency of t. accounts caches the accounts already checked.
+func (s *TransactionService) settle(ctx context.Context, t *domain.Transaction, payment *domain.TransactionPayment, userID string, accounts map[string]*domain.Account) error {
+	if payment.AccountID == "" {
+		payment.AccountID = t.FromAccountID
+	}
+	acc, ok := accounts[payment.AccountID]
+	if !ok {
+		var err error
+		if acc, err = s.accountRepo.GetByID(ctx, payment.AccountID, t.TenantID); err != nil {
+			return referenceError(err, "account_id")
+		}
+		accounts[payment.AccountID] = acc
+	}
+	if acc.Currency != t.Currency {
+		return domain.InvalidField("account_id", "account currency must match the transaction currency")
+	}
+
+	if err := t.Settle(payment); err != nil {
+		return err
+	}
+	payment.CreatedBy = userID
+	t.UpdatedBy = userID
+	return nil
+}
+
+// Unsettle reverts a transaction to unpaid, deactivating its payments. The update requires version
+// when given, or else the version that was loaded.
+func (s *TransactionService) Unsettle(ctx context.Context, id string, version int) (*domain.Transaction, error) {
+	tenantID := domain.GetTenantID(ctx)
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	t, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get transaction: %w", err)
+	}
+	if version != 0 {
+		t.Version = version
+	}
+	t.Unsettle()
+	t.UpdatedBy = userID
+
+	if err := s.repo.ClearPayments(ctx, t, userID); err != nil {
+		return nil, err
+	}
+	if err := s.attachTags(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ListPayments returns the payments recorded against a transaction, oldest first.
+func (s *TransactionService) ListPayments(ctx context.Context, id string) ([]domain.TransactionPayment, error) {
+	tenantID := domain.GetTenantID(ctx)
+	if _, err := s.repo.GetByID(ctx, tenantID, id); err != nil {
+		return nil, fmt.Errorf("service failed to get transaction: %w", err)
+	}
+	return s.repo.ListPayments(ctx, tenantID, id)
+}
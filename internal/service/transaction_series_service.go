@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// GetSeries returns the installment series the transaction belongs to, with tags attached.
+func (s *TransactionService) GetSeries(ctx context.Context, id string) (*domain.TransactionSeries, error) {
+	tenantID := domain.GetTenantID(ctx)
+	t, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := t.SeriesParentID()
+	if err != nil {
+		return nil, err
+	}
+	return s.loadSeries(ctx, parentID)
+}
+
+// loadSeries lists the active installments of the series with the given parent, which may itself be cancelled.
+func (s *TransactionService) loadSeries(ctx context.Context, parentID string) (*domain.TransactionSeries, error) {
+	tenantID := domain.GetTenantID(ctx)
+	installments, err := s.repo.ListSeries(ctx, tenantID, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list series: %w", err)
+	}
+	series := &domain.TransactionSeries{ParentID: parentID, Installments: installments, AsOf: s.now()}
+
+	txs := make([]*domain.Transaction, len(series.Installments))
+	for i := range series.Installments {
+		txs[i] = &series.Installments[i]
+	}
+	if err := s.attachTags(ctx, txs...); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// UpdateSeries sets the category and, when tagIDs is not nil, replaces the tags
+// of the installments the scope selects relative to the given one.
+func (s *TransactionService) UpdateSeries(ctx context.Context, id string, scope domain.SeriesScope, categoryID *string, tagIDs []string) (*domain.TransactionSeries, error) {
+	tenantID := domain.GetTenantID(ctx)
+
+	if categoryID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *categoryID, tenantID); err != nil {
+			return nil, referenceError(err, "category_id")
+		}
+	}
+	if len(tagIDs) > 0 {
+		valid, err := s.tagRepo.ValidateTags(ctx, tenantID, tagIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate tags: %w", err)
+		}
+		if !valid {
+			return nil, domain.InvalidField("tag_ids", "one or more tags do not belong to this tenant")
+		}
+	}
+
+	return s.changeSeries(ctx, id, func(series *domain.TransactionSeries) (domain.SeriesChange, error) {
+		selected, err := series.Select(id, scope)
+		if err != nil {
+			return domain.SeriesChange{}, err
+		}
+		if categoryID != nil {
+			for i := range selected {
+				selected[i].CategoryID = *categoryID
+			}
+		}
+		return domain.SeriesChange{Updated: selected, TagIDs: tagIDs}, nil
+	})
+}
+
+// CancelSeries cancels the unpaid installments of the series.
+func (s *TransactionService) CancelSeries(ctx context.Context, id string) (*domain.TransactionSeries, error) {
+	return s.changeSeries(ctx, id, func(series *domain.TransactionSeries) (domain.SeriesChange, error) {
+		return series.Cancel()
+	})
+}
+
+// RescheduleSeries moves the unpaid installments of the series to monthly due dates starting at firstDueDate.
+func (s *TransactionService) RescheduleSeries(ctx context.Context, id string, firstDueDate time.Time) (*domain.TransactionSeries, error) {
+	return s.changeSeries(ctx, id, func(series *domain.TransactionSeries) (domain.SeriesChange, error) {
+		return series.Reschedule(firstDueDate)
+	})
+}
+
+// PayOffSeries settles the series early, collapsing the unpaid installments into one payment.
+func (s *TransactionService) PayOffSeries(ctx context.Context, id string, paymentDate time.Time, discount domain.Money) (*domain.TransactionSeries, error) {
+	return s.changeSeries(ctx, id, func(series *domain.TransactionSeries) (domain.SeriesChange, error) {
+		return series.PayOff(paymentDate, discount)
+	})
+}
+
+// changeSeries loads the series of a transaction, applies the change built from it and returns the updated series.
+func (s *TransactionService) changeSeries(ctx context.Context, id string, build func(*domain.TransactionSeries) (domain.SeriesChange, error)) (*domain.TransactionSeries, error) {
+	tenantID := domain.GetTenantID(ctx)
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	series, err := s.GetSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	change, err := build(series)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ApplySeriesChange(ctx, tenantID, userID, change); err != nil {
+		return nil, fmt.Errorf("service failed to change series: %w", err)
+	}
+
+	// The given installment may have been cancelled, so reload by parent.
+	return s.loadSeries(ctx, series.ParentID)
+}
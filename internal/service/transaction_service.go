@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/igoventura/fintrack-api/domain"
 )
@@ -13,6 +14,8 @@ type TransactionService struct {
 	accountRepo  domain.AccountRepository
 	categoryRepo domain.CategoryRepository
 	tagRepo      domain.TagRepository
+	transactor   domain.Transactor
+	now          func() time.Time
 }
 
 func NewTransactionService(
@@ -20,37 +23,69 @@ func NewTransactionService(
 	accountRepo domain.AccountRepository,
 	categoryRepo domain.CategoryRepository,
 	tagRepo domain.TagRepository,
+	transactor domain.Transactor,
 ) *TransactionService {
 	return &TransactionService{
 		repo:         repo,
 		accountRepo:  accountRepo,
 		categoryRepo: categoryRepo,
 		tagRepo:      tagRepo,
+		transactor:   transactor,
+		now:          time.Now,
 	}
 }
 
 func (s *TransactionService) GetByID(ctx context.Context, id string) (*domain.Transaction, error) {
 	tenantID := domain.GetTenantID(ctx)
-	return s.repo.GetByID(ctx, tenantID, id)
+	t, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachTags(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
 }
 
-func (s *TransactionService) List(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error) {
+// List returns a page of transactions with their tags attached.
+func (s *TransactionService) List(ctx context.Context, filter domain.TransactionFilter, page domain.PageRequest) (*domain.Page[domain.Transaction], error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
 	tenantID := domain.GetTenantID(ctx)
-	return s.repo.List(ctx, tenantID, filter)
-}
-
-// GetTagIDsForTransaction retrieves the tag IDs associated with a transaction.
-func (s *TransactionService) GetTagIDsForTransaction(ctx context.Context, transactionID string) ([]string, error) {
-	tags, err := s.repo.ListTransactionTags(ctx, transactionID)
+	result, err := s.repo.List(ctx, tenantID, filter, page)
 	if err != nil {
 		return nil, err
 	}
 
-	tagIDs := make([]string, len(tags))
-	for i, tag := range tags {
-		tagIDs[i] = tag.ID
+	txs := make([]*domain.Transaction, len(result.Items))
+	for i := range result.Items {
+		txs[i] = &result.Items[i]
+	}
+	if err := s.attachTags(ctx, txs...); err != nil {
+		return nil, err
 	}
-	return tagIDs, nil
+	return result, nil
+}
+
+// attachTags loads the tags of the given transactions with a single query.
+func (s *TransactionService) attachTags(ctx context.Context, txs ...*domain.Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	ids := make([]string, len(txs))
+	for i, t := range txs {
+		ids[i] = t.ID
+	}
+
+	tags, err := s.repo.ListTagsForTransactions(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("service failed to load transaction tags: %w", err)
+	}
+	for _, t := range txs {
+		t.Tags = tags[t.ID]
+	}
+	return nil
 }
 
 func (s *TransactionService) Create(ctx context.Context, t *domain.Transaction, tagIDs []string, installments int, isRecurring bool) error {
@@ -67,10 +102,10 @@ func (s *TransactionService) Create(ctx context.Context, t *domain.Transaction,
 	// 1. Fetch FromAccount (Needed for Currency and PaymentDate logic)
 	fromAccount, err := s.accountRepo.GetByID(ctx, t.FromAccountID, tenantID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch from_account: %w", err)
+		return referenceError(err, "from_account_id")
 	}
 	if fromAccount.TenantID != tenantID {
-		return errors.New("from_account does not belong to this tenant")
+		return domain.InvalidField("from_account_id", "account not found")
 	}
 
 	// 2. Field Defaults
@@ -78,43 +113,62 @@ func (s *TransactionService) Create(ctx context.Context, t *domain.Transaction,
 	if t.Currency == "" {
 		t.Currency = fromAccount.Currency
 	}
+	t.Amount.Currency = t.Currency
+
+	// Credit card statements: for card purchases the given DueDate is the purchase date,
+	// and the transaction is billed on the statement the card's closing day assigns it to.
+	isCardPurchase := fromAccount.Type == domain.AccountTypeCreditCard &&
+		(t.TransactionType == domain.TransactionTypeCredit || t.TransactionType == domain.TransactionTypeDebit)
+	purchaseDate := t.DueDate
+	var card *domain.CreditCardInfo
+	if isCardPurchase {
+		card, err = s.accountRepo.GetCreditCardInfo(ctx, fromAccount.ID, fromAccount.TenantID)
+		if err != nil {
+			if !errors.Is(err, domain.ErrCreditCardInfoNotFound) {
+				return fmt.Errorf("failed to fetch credit card info: %w", err)
+			}
+			card = nil // No card details yet: fall back to the due date
+		}
+	}
+	if card != nil {
+		t.AccrualMonth = card.StatementPeriod(purchaseDate)
+		if _, _, t.DueDate, err = card.StatementDates(t.AccrualMonth); err != nil {
+			return fmt.Errorf("failed to compute statement dates: %w", err)
+		}
+	}
 
 	// AccrualMonth: Default to DueDate YYYYMM if not set
 	if t.AccrualMonth == "" {
 		t.AccrualMonth = t.DueDate.Format("200601")
 	}
 
-	// PaymentDate: Default to DueDate if Credit Card and type is Debit/Credit
+	// PaymentDate: Default to the purchase date if Credit Card and type is Debit/Credit
 	if t.PaymentDate == nil {
-		if fromAccount.Type == domain.AccountTypeCreditCard &&
-			(t.TransactionType == domain.TransactionTypeCredit || t.TransactionType == domain.TransactionTypeDebit) {
-			t.PaymentDate = &t.DueDate
+		if isCardPurchase {
+			t.PaymentDate = &purchaseDate
 		}
 	}
 
 	// Validate basic fields (Now that defaults are set)
 	if valid, errs := t.IsValid(); !valid {
-		var errMsg string
-		for field, err := range errs {
-			errMsg += fmt.Sprintf("%s: %s; ", field, err.Error())
-		}
-		return errors.New("validation failed: " + errMsg)
+		return domain.InvalidFields(errs)
 	}
 
 	// 3. ToAccount Validation (if applicable)
+	var toAccount *domain.Account
 	if t.ToAccountID != nil && *t.ToAccountID != "" {
-		toAccount, err := s.accountRepo.GetByID(ctx, *t.ToAccountID, tenantID)
+		toAccount, err = s.accountRepo.GetByID(ctx, *t.ToAccountID, tenantID)
 		if err != nil {
-			return fmt.Errorf("failed to fetch to_account: %w", err)
+			return referenceError(err, "to_account_id")
 		}
 		if toAccount.TenantID != tenantID {
-			return errors.New("to_account does not belong to this tenant")
+			return domain.InvalidField("to_account_id", "account not found")
 		}
 	}
 
 	// 4. Category Validation
 	if _, err := s.categoryRepo.GetByID(ctx, t.CategoryID, tenantID); err != nil {
-		return fmt.Errorf("invalid category: %w", err)
+		return referenceError(err, "category_id")
 	}
 
 	// 5. Tags Validation
@@ -124,11 +178,19 @@ func (s *TransactionService) Create(ctx context.Context, t *domain.Transaction,
 			return fmt.Errorf("failed to validate tags: %w", err)
 		}
 		if !valid {
-			return errors.New("one or more tags do not belong to this tenant")
+			return domain.InvalidField("tag_ids", "one or more tags do not belong to this tenant")
 		}
 	}
 
-	// 6. Installments Logic
+	// 6. Transfers: an outgoing leg on the source account and an incoming leg on the destination
+	if t.TransactionType == domain.TransactionTypeTransfer {
+		if installments > 1 {
+			return domain.ErrTransferInstallments
+		}
+		return s.createTransfer(ctx, t, fromAccount, toAccount, tagIDs)
+	}
+
+	// 7. Installments Logic
 	numInstallments := installments
 	if numInstallments < 1 {
 		numInstallments = 1
@@ -147,12 +209,8 @@ func (s *TransactionService) Create(ctx context.Context, t *domain.Transaction,
 		// Recalculate AccrualMonth based on the new DueDate
 		t.AccrualMonth = t.DueDate.Format("200601")
 
-		var comments string
-		if t.Comments != nil {
-			comments = *t.Comments
-		}
-		newComments := fmt.Sprintf("[Installment %d/%d] %s", calcInstallments[0].Number, numInstallments, comments)
-		t.Comments = &newComments
+		t.InstallmentNumber = &calcInstallments[0].Number
+		t.InstallmentCount = &numInstallments
 
 		// Prepare Children
 		children := make([]domain.Transaction, 0, numInstallments-1)
@@ -164,9 +222,20 @@ func (s *TransactionService) Create(ctx context.Context, t *domain.Transaction,
 			child.DueDate = inst.DueDate
 			child.AccrualMonth = child.DueDate.Format("200601")
 
-			// PaymentDate logic for children? same rules apply.
-			if fromAccount.Type == domain.AccountTypeCreditCard &&
-				(t.TransactionType == domain.TransactionTypeCredit || t.TransactionType == domain.TransactionTypeDebit) {
+			// Card installments are billed on the following statements, posted when each one closes.
+			if card != nil {
+				period, err := domain.AddPeriodMonths(t.AccrualMonth, i)
+				if err != nil {
+					return fmt.Errorf("failed to compute installment statement: %w", err)
+				}
+				_, closing, due, err := card.StatementDates(period)
+				if err != nil {
+					return fmt.Errorf("failed to compute installment statement: %w", err)
+				}
+				child.AccrualMonth = period
+				child.DueDate = due
+				child.PaymentDate = &closing
+			} else if isCardPurchase {
 				child.PaymentDate = &child.DueDate
 			} else {
 				// Future installments are generally unpaid, so clear PaymentDate unless explicitly handled above.
@@ -175,13 +244,11 @@ func (s *TransactionService) Create(ctx context.Context, t *domain.Transaction,
 				}
 			}
 			// Apply rule again just in case
-			if fromAccount.Type == domain.AccountTypeCreditCard &&
-				(t.TransactionType == domain.TransactionTypeCredit || t.TransactionType == domain.TransactionTypeDebit) {
+			if card == nil && isCardPurchase {
 				child.PaymentDate = &child.DueDate
 			}
 
-			childComments := fmt.Sprintf("[Installment %d/%d] %s", inst.Number, numInstallments, comments)
-			child.Comments = &childComments
+			child.InstallmentNumber = &inst.Number
 
 			// Tenant/User are copied
 			child.ParentTransactionID = nil // Will be linked in Repo
@@ -207,9 +274,11 @@ func (s *TransactionService) Create(ctx context.Context, t *domain.Transaction,
 		}
 	}
 
-	return nil
+	return s.attachTags(ctx, t)
 }
 
+// Update replaces the editable fields of a transaction. Editing a leg of a transfer updates its other
+// leg as well, in the same database transaction.
 func (s *TransactionService) Update(ctx context.Context, t *domain.Transaction, tagIDs []string) error {
 	tenantID := domain.GetTenantID(ctx)
 	t.TenantID = tenantID // Ensure we don't overwrite with wrong tenant
@@ -220,6 +289,21 @@ func (s *TransactionService) Update(ctx context.Context, t *domain.Transaction,
 	}
 	t.UpdatedBy = userID
 
+	stored, err := s.repo.GetByID(ctx, tenantID, t.ID)
+	if err != nil {
+		return fmt.Errorf("service failed to get transaction: %w", err)
+	}
+	if err := checkTransferType(stored, t); err != nil {
+		return err
+	}
+	t.Currency = stored.Currency
+	t.Amount.Currency = stored.Currency
+	t.Fee.Currency = stored.Currency
+	t.SettledAmount = stored.SettledAmount
+	if valid, errs := t.IsValid(); !valid {
+		return domain.InvalidFields(errs)
+	}
+
 	// Validate tags if provided
 	if len(tagIDs) > 0 {
 		valid, err := s.tagRepo.ValidateTags(ctx, tenantID, tagIDs)
@@ -227,24 +311,111 @@ func (s *TransactionService) Update(ctx context.Context, t *domain.Transaction,
 			return fmt.Errorf("failed to validate tags: %w", err)
 		}
 		if !valid {
-			return errors.New("one or more tags do not belong to this tenant")
+			return domain.InvalidField("tag_ids", "one or more tags do not belong to this tenant")
 		}
 	}
 
-	if err := s.repo.Update(ctx, t); err != nil {
+	// Save with the tags, if given (Replace strategy)
+	if err := s.save(ctx, stored, t, tagIDs, tagIDs != nil); err != nil {
 		return err
 	}
 
-	// Update tags (Replace strategy)
-	if tagIDs != nil {
-		if err := s.repo.ReplaceTags(ctx, t.ID, tagIDs); err != nil {
-			return fmt.Errorf("transaction updated but failed to update tags: %w", err)
+	return s.attachTags(ctx, t)
+}
+
+// Patch applies a partial update to a transaction: the stored transaction is loaded, the fields set
+// in the patch replace its own, and the result is validated and saved. Tags are replaced when tag_ids
+// is set. The update requires version when given, or else the version that was loaded.
+func (s *TransactionService) Patch(ctx context.Context, id string, patch domain.TransactionPatch, version int) (*domain.Transaction, error) {
+	tenantID := domain.GetTenantID(ctx)
+	userID := domain.GetUserID(ctx)
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	t, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get transaction: %w", err)
+	}
+	stored := *t
+	patch.Apply(t)
+	t.UpdatedBy = userID
+	if version != 0 {
+		t.Version = version
+	}
+
+	if valid, errs := t.IsValid(); !valid {
+		return nil, domain.InvalidFields(errs)
+	}
+	if err := checkTransferType(&stored, t); err != nil {
+		return nil, err
+	}
+
+	// References are checked when they change
+	if patch.FromAccountID.Set {
+		if _, err := s.accountRepo.GetByID(ctx, t.FromAccountID, tenantID); err != nil {
+			return nil, referenceError(err, "from_account_id")
+		}
+	}
+	if patch.ToAccountID.Set && t.ToAccountID != nil && *t.ToAccountID != "" {
+		if _, err := s.accountRepo.GetByID(ctx, *t.ToAccountID, tenantID); err != nil {
+			return nil, referenceError(err, "to_account_id")
+		}
+	}
+	if patch.CategoryID.Set {
+		if _, err := s.categoryRepo.GetByID(ctx, t.CategoryID, tenantID); err != nil {
+			return nil, referenceError(err, "category_id")
+		}
+	}
+	tagIDs := patch.TagIDs.Value
+	if patch.TagIDs.Set && len(tagIDs) > 0 {
+		valid, err := s.tagRepo.ValidateTags(ctx, tenantID, tagIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate tags: %w", err)
+		}
+		if !valid {
+			return nil, domain.InvalidField("tag_ids", "one or more tags do not belong to this tenant")
 		}
 	}
 
-	return nil
+	if err := s.save(ctx, &stored, t, tagIDs, patch.TagIDs.Set); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachTags(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// save writes t, the edited version of stored, together with the other leg when it is a transfer.
+// With replaceTags the tags of both legs are replaced by tagIDs. Everything is written in one
+// database transaction, so a failure leaves neither leg nor their tags changed.
+func (s *TransactionService) save(ctx context.Context, stored, t *domain.Transaction, tagIDs []string, replaceTags bool) error {
+	return s.transactor.InTx(ctx, func(ctx context.Context) error {
+		ids := []string{t.ID}
+		if stored.IsTransferLeg() {
+			otherID, err := s.updateTransfer(ctx, stored, t)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, otherID)
+		} else if err := s.repo.Update(ctx, t); err != nil {
+			return err
+		}
+
+		if replaceTags {
+			for _, id := range ids {
+				if err := s.repo.ReplaceTags(ctx, id, tagIDs); err != nil {
+					return fmt.Errorf("failed to update tags: %w", err)
+				}
+			}
+		}
+		return nil
+	})
 }
 
+// Delete soft-deletes a transaction; deleting either leg of a transfer deletes both.
 func (s *TransactionService) Delete(ctx context.Context, id string) error {
 	tenantID := domain.GetTenantID(ctx)
 	userID := domain.GetUserID(ctx)
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
@@ -12,7 +14,9 @@ import (
 // Mocks
 type mockAccountRepo struct {
 	domain.AccountRepository
-	GetByIDFn func(ctx context.Context, id, tenantID string) (*domain.Account, error)
+	GetByIDFn           func(ctx context.Context, id, tenantID string) (*domain.Account, error)
+	GetCreditCardInfoFn func(ctx context.Context, accountID, tenantID string) (*domain.CreditCardInfo, error)
+	GetBalanceFn        func(ctx context.Context, id, tenantID string, asOf time.Time) (*domain.AccountBalance, error)
 }
 
 func (m *mockAccountRepo) GetByID(ctx context.Context, id, tenantID string) (*domain.Account, error) {
@@ -22,10 +26,74 @@ func (m *mockAccountRepo) GetByID(ctx context.Context, id, tenantID string) (*do
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockAccountRepo) GetCreditCardInfo(ctx context.Context, accountID, tenantID string) (*domain.CreditCardInfo, error) {
+	if m.GetCreditCardInfoFn != nil {
+		return m.GetCreditCardInfoFn(ctx, accountID, tenantID)
+	}
+	return nil, domain.ErrCreditCardInfoNotFound
+}
+
+func (m *mockAccountRepo) GetBalance(ctx context.Context, id, tenantID string, asOf time.Time) (*domain.AccountBalance, error) {
+	if m.GetBalanceFn != nil {
+		return m.GetBalanceFn(ctx, id, tenantID, asOf)
+	}
+	return nil, errors.New("not implemented")
+}
+
 type mockRepo struct {
 	domain.TransactionRepository
 	CreateFn                 func(ctx context.Context, tx *domain.Transaction) error
 	CreateWithInstallmentsFn func(ctx context.Context, parent *domain.Transaction, children []domain.Transaction, tagIDs []string) error
+	ListFn                   func(ctx context.Context, tenantID string, filter domain.TransactionFilter, page domain.PageRequest) (*domain.Page[domain.Transaction], error)
+	ListTagsFn               func(ctx context.Context, transactionIDs []string) (map[string][]domain.Tag, error)
+	GetByIDFn                func(ctx context.Context, tenantID, id string) (*domain.Transaction, error)
+	UpdateFn                 func(ctx context.Context, tx *domain.Transaction) error
+	ReplaceTagsFn            func(ctx context.Context, transactionID string, tagIDs []string) error
+	AddPaymentsFn            func(ctx context.Context, txs []*domain.Transaction, payments []domain.TransactionPayment) error
+	CreateTransferFn         func(ctx context.Context, out, in *domain.Transaction, tagIDs []string) error
+	UpdateTransferFn         func(ctx context.Context, out, in *domain.Transaction) error
+}
+
+func (m *mockRepo) CreateTransfer(ctx context.Context, out, in *domain.Transaction, tagIDs []string) error {
+	if m.CreateTransferFn != nil {
+		return m.CreateTransferFn(ctx, out, in, tagIDs)
+	}
+	return nil
+}
+
+func (m *mockRepo) UpdateTransfer(ctx context.Context, out, in *domain.Transaction) error {
+	if m.UpdateTransferFn != nil {
+		return m.UpdateTransferFn(ctx, out, in)
+	}
+	return nil
+}
+
+func (m *mockRepo) AddPayments(ctx context.Context, txs []*domain.Transaction, payments []domain.TransactionPayment) error {
+	if m.AddPaymentsFn != nil {
+		return m.AddPaymentsFn(ctx, txs, payments)
+	}
+	return nil
+}
+
+func (m *mockRepo) GetByID(ctx context.Context, tenantID, id string) (*domain.Transaction, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, tenantID, id)
+	}
+	return nil, domain.ErrTransactionNotFound
+}
+
+func (m *mockRepo) Update(ctx context.Context, tx *domain.Transaction) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, tx)
+	}
+	return nil
+}
+
+func (m *mockRepo) ReplaceTags(ctx context.Context, transactionID string, tagIDs []string) error {
+	if m.ReplaceTagsFn != nil {
+		return m.ReplaceTagsFn(ctx, transactionID, tagIDs)
+	}
+	return nil
 }
 
 func (m *mockRepo) Create(ctx context.Context, tx *domain.Transaction) error {
@@ -35,6 +103,20 @@ func (m *mockRepo) Create(ctx context.Context, tx *domain.Transaction) error {
 	return nil
 }
 
+func (m *mockRepo) List(ctx context.Context, tenantID string, filter domain.TransactionFilter, page domain.PageRequest) (*domain.Page[domain.Transaction], error) {
+	if m.ListFn != nil {
+		return m.ListFn(ctx, tenantID, filter, page)
+	}
+	return &domain.Page[domain.Transaction]{}, nil
+}
+
+func (m *mockRepo) ListTagsForTransactions(ctx context.Context, transactionIDs []string) (map[string][]domain.Tag, error) {
+	if m.ListTagsFn != nil {
+		return m.ListTagsFn(ctx, transactionIDs)
+	}
+	return map[string][]domain.Tag{}, nil
+}
+
 func (m *mockRepo) CreateWithInstallments(ctx context.Context, parent *domain.Transaction, children []domain.Transaction, tagIDs []string) error {
 	if m.CreateWithInstallmentsFn != nil {
 		return m.CreateWithInstallmentsFn(ctx, parent, children, tagIDs)
@@ -45,6 +127,7 @@ func (m *mockRepo) CreateWithInstallments(ctx context.Context, parent *domain.Tr
 type mockCategoryRepo struct {
 	domain.CategoryRepository
 	GetByIDFn func(ctx context.Context, id, tenantID string) (*domain.Category, error)
+	UpdateFn  func(ctx context.Context, cat *domain.Category) error
 }
 
 func (m *mockCategoryRepo) GetByID(ctx context.Context, id, tenantID string) (*domain.Category, error) {
@@ -54,6 +137,13 @@ func (m *mockCategoryRepo) GetByID(ctx context.Context, id, tenantID string) (*d
 	return &domain.Category{ID: id, TenantID: tenantID}, nil
 }
 
+func (m *mockCategoryRepo) Update(ctx context.Context, cat *domain.Category) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, cat)
+	}
+	return nil
+}
+
 type mockTagRepo struct {
 	domain.TagRepository
 	ValidateTagsFn func(ctx context.Context, tenantID string, tagIDs []string) (bool, error)
@@ -74,6 +164,21 @@ func TestTransactionService_Create(t *testing.T) {
 	toAccID := "acc-2"
 	comments := "Test"
 
+	// Card closing on the 25th, due on the 5th of the following month.
+	card := &domain.CreditCardInfo{
+		AccountID:   "acc-cc",
+		ClosingDate: time.Date(2023, 1, 25, 0, 0, 0, 0, time.UTC),
+		DueDate:     time.Date(2023, 2, 5, 0, 0, 0, 0, time.UTC),
+	}
+	cardAccount := func(ar *mockAccountRepo) {
+		ar.GetByIDFn = func(ctx context.Context, id, tenantID string) (*domain.Account, error) {
+			return &domain.Account{ID: id, TenantID: tenantID, Type: domain.AccountTypeCreditCard, Currency: "BRL"}, nil
+		}
+		ar.GetCreditCardInfoFn = func(ctx context.Context, accountID, tenantID string) (*domain.CreditCardInfo, error) {
+			return card, nil
+		}
+	}
+
 	tests := []struct {
 		name         string
 		transaction  *domain.Transaction
@@ -86,7 +191,7 @@ func TestTransactionService_Create(t *testing.T) {
 			name: "Single Transaction - PaymentDate Logic (CC)",
 			transaction: &domain.Transaction{
 				FromAccountID:   "acc-cc",
-				Amount:          100,
+				Amount:          domain.NewMoney(10000, ""),
 				TransactionType: domain.TransactionTypeCredit,
 				Comments:        &comments,
 				CategoryID:      "cat-1",
@@ -114,7 +219,7 @@ func TestTransactionService_Create(t *testing.T) {
 			name: "Split Installments (3x)",
 			transaction: &domain.Transaction{
 				FromAccountID:   "acc-1",
-				Amount:          100,
+				Amount:          domain.NewMoney(10000, ""),
 				TransactionType: domain.TransactionTypeDebit,
 				CategoryID:      "cat-1",
 				DueDate:         time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
@@ -135,12 +240,18 @@ func TestTransactionService_Create(t *testing.T) {
 						t.Errorf("expected 2 children, got %d", len(children))
 					}
 					// Parent: 33.34
-					if parent.Amount != 33.34 {
-						t.Errorf("parent amount mismatch: got %f, want 33.34", parent.Amount)
+					if parent.Amount.Cents != 3334 {
+						t.Errorf("parent amount mismatch: got %s, want 33.34", parent.Amount)
 					}
 					// Children: 33.33 each
-					if children[0].Amount != 33.33 {
-						t.Errorf("child 0 amount mismatch: got %f, want 33.33", children[0].Amount)
+					if children[0].Amount.Cents != 3333 {
+						t.Errorf("child 0 amount mismatch: got %s, want 33.33", children[0].Amount)
+					}
+					if *parent.InstallmentNumber != 1 || *children[1].InstallmentNumber != 3 || *children[1].InstallmentCount != 3 {
+						t.Errorf("installment positions mismatch: parent %d, last child %d/%d", *parent.InstallmentNumber, *children[1].InstallmentNumber, *children[1].InstallmentCount)
+					}
+					if parent.Comments != nil {
+						t.Errorf("expected comments to be left empty, got %q", *parent.Comments)
 					}
 					return nil
 				}
@@ -151,7 +262,7 @@ func TestTransactionService_Create(t *testing.T) {
 			name: "Recurring Installments (3x)",
 			transaction: &domain.Transaction{
 				FromAccountID:   "acc-1",
-				Amount:          100,
+				Amount:          domain.NewMoney(10000, ""),
 				TransactionType: domain.TransactionTypeDebit,
 				CategoryID:      "cat-1",
 				DueDate:         time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
@@ -164,12 +275,72 @@ func TestTransactionService_Create(t *testing.T) {
 				}
 				r.CreateWithInstallmentsFn = func(ctx context.Context, parent *domain.Transaction, children []domain.Transaction, tagIDs []string) error {
 					// Parent: 100
-					if parent.Amount != 100 {
-						t.Errorf("parent amount mismatch: %f", parent.Amount)
+					if parent.Amount.Cents != 10000 {
+						t.Errorf("parent amount mismatch: %s", parent.Amount)
 					}
 					// Children: 100
-					if children[0].Amount != 100 {
-						t.Errorf("child amount mismatch: %f", children[0].Amount)
+					if children[0].Amount.Cents != 10000 {
+						t.Errorf("child amount mismatch: %s", children[0].Amount)
+					}
+					return nil
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "Credit Card Statement - Purchase Before Closing Day",
+			transaction: &domain.Transaction{
+				FromAccountID:   "acc-cc",
+				Amount:          domain.NewMoney(5000, ""),
+				TransactionType: domain.TransactionTypeDebit,
+				CategoryID:      "cat-1",
+				DueDate:         time.Date(2023, 1, 25, 0, 0, 0, 0, time.UTC),
+			},
+			installments: 1,
+			setupMocks: func(r *mockRepo, ar *mockAccountRepo) {
+				cardAccount(ar)
+				r.CreateFn = func(ctx context.Context, tx *domain.Transaction) error {
+					if tx.AccrualMonth != "202302" {
+						t.Errorf("accrual month mismatch: got %s, want 202302", tx.AccrualMonth)
+					}
+					if want := time.Date(2023, 2, 5, 0, 0, 0, 0, time.UTC); !tx.DueDate.Equal(want) {
+						t.Errorf("due date mismatch: got %v, want %v", tx.DueDate, want)
+					}
+					if want := time.Date(2023, 1, 25, 0, 0, 0, 0, time.UTC); tx.PaymentDate == nil || !tx.PaymentDate.Equal(want) {
+						t.Errorf("expected PaymentDate to be the purchase date %v, got %v", want, tx.PaymentDate)
+					}
+					return nil
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "Credit Card Statement - Installments After Closing Day",
+			transaction: &domain.Transaction{
+				FromAccountID:   "acc-cc",
+				Amount:          domain.NewMoney(10000, ""),
+				TransactionType: domain.TransactionTypeDebit,
+				CategoryID:      "cat-1",
+				DueDate:         time.Date(2023, 1, 26, 0, 0, 0, 0, time.UTC),
+			},
+			installments: 3,
+			setupMocks: func(r *mockRepo, ar *mockAccountRepo) {
+				cardAccount(ar)
+				r.CreateWithInstallmentsFn = func(ctx context.Context, parent *domain.Transaction, children []domain.Transaction, tagIDs []string) error {
+					if parent.AccrualMonth != "202303" {
+						t.Errorf("parent accrual month mismatch: got %s, want 202303", parent.AccrualMonth)
+					}
+					if want := time.Date(2023, 3, 5, 0, 0, 0, 0, time.UTC); !parent.DueDate.Equal(want) {
+						t.Errorf("parent due date mismatch: got %v, want %v", parent.DueDate, want)
+					}
+					wantPeriods := []string{"202304", "202305"}
+					for i, child := range children {
+						if child.AccrualMonth != wantPeriods[i] {
+							t.Errorf("child %d accrual month mismatch: got %s, want %s", i, child.AccrualMonth, wantPeriods[i])
+						}
+						if child.PaymentDate == nil || child.PaymentDate.Day() != 25 {
+							t.Errorf("child %d expected to post on the closing day, got %v", i, child.PaymentDate)
+						}
 					}
 					return nil
 				}
@@ -189,7 +360,7 @@ func TestTransactionService_Create(t *testing.T) {
 				tt.setupMocks(repo, accRepo)
 			}
 
-			s := NewTransactionService(repo, accRepo, catRepo, tagRepo)
+			s := NewTransactionService(repo, accRepo, catRepo, tagRepo, &mockTransactor{})
 			err := s.Create(ctx, tt.transaction, nil, tt.installments, tt.isRecurring)
 
 			if (err != nil) != tt.expectError {
@@ -198,3 +369,425 @@ func TestTransactionService_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionService_List_AttachesTagsInOneQuery(t *testing.T) {
+	ctx := domain.WithTenantID(context.Background(), "tenant-1")
+
+	calls := 0
+	repo := &mockRepo{
+		ListFn: func(ctx context.Context, tenantID string, filter domain.TransactionFilter, page domain.PageRequest) (*domain.Page[domain.Transaction], error) {
+			return &domain.Page[domain.Transaction]{Items: []domain.Transaction{{ID: "tx-1"}, {ID: "tx-2"}, {ID: "tx-3"}}}, nil
+		},
+		ListTagsFn: func(ctx context.Context, transactionIDs []string) (map[string][]domain.Tag, error) {
+			calls++
+			if len(transactionIDs) != 3 {
+				t.Errorf("expected 3 transaction IDs, got %v", transactionIDs)
+			}
+			return map[string][]domain.Tag{
+				"tx-1": {{ID: "tag-a", Name: "Food"}, {ID: "tag-b", Name: "Trip"}},
+				"tx-3": {{ID: "tag-b", Name: "Trip"}},
+			}, nil
+		},
+	}
+
+	s := NewTransactionService(repo, &mockAccountRepo{}, &mockCategoryRepo{}, &mockTagRepo{}, &mockTransactor{})
+	page, err := s.List(ctx, domain.TransactionFilter{}, domain.PageRequest{Limit: 50})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 tag query, got %d", calls)
+	}
+
+	want := map[string][]string{"tx-1": {"tag-a", "tag-b"}, "tx-2": {}, "tx-3": {"tag-b"}}
+	for _, tx := range page.Items {
+		if got := tx.TagIDs(); !slices.Equal(got, want[tx.ID]) {
+			t.Errorf("%s: tag IDs = %v, want %v", tx.ID, got, want[tx.ID])
+		}
+	}
+}
+
+func TestTransactionService_Patch(t *testing.T) {
+	ctx := context.Background()
+	ctx = domain.WithTenantID(ctx, "tenant-1")
+	ctx = domain.WithUserID(ctx, "user-1")
+
+	comments := "Groceries"
+	stored := func() *domain.Transaction {
+		return &domain.Transaction{
+			ID:              "tx-1",
+			TenantID:        "tenant-1",
+			FromAccountID:   "acc-1",
+			Currency:        "BRL",
+			Amount:          domain.NewMoney(1000, "BRL"),
+			AccrualMonth:    "202403",
+			TransactionType: domain.TransactionTypeDebit,
+			CategoryID:      "cat-1",
+			Comments:        &comments,
+			DueDate:         time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+			Version:         3,
+		}
+	}
+	patchOf := func(doc string) domain.TransactionPatch {
+		var patch domain.TransactionPatch
+		if err := json.Unmarshal([]byte(doc), &patch); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		return patch
+	}
+
+	tests := []struct {
+		name         string
+		patch        string
+		version      int
+		wantInvalid  bool
+		wantVersion  int
+		wantTags     []string
+		wantCategory bool // the category reference is checked
+	}{
+		{name: "amount only", patch: `{"amount": "12.00"}`, wantVersion: 3},
+		{name: "If-Match version", patch: `{"comments": null}`, version: 2, wantVersion: 2},
+		{name: "category change is checked", patch: `{"category_id": "cat-2"}`, wantVersion: 3, wantCategory: true},
+		{name: "tags replaced", patch: `{"tag_ids": ["tag-1"]}`, wantVersion: 3, wantTags: []string{"tag-1"}},
+		{name: "invalid result", patch: `{"accrual_month": "2024"}`, wantInvalid: true},
+		{name: "unknown category", patch: `{"category_id": "cat-x"}`, wantInvalid: true, wantCategory: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *domain.Transaction
+			var replacedTags []string
+			repo := &mockRepo{
+				GetByIDFn: func(ctx context.Context, tenantID, id string) (*domain.Transaction, error) {
+					return stored(), nil
+				},
+				UpdateFn: func(ctx context.Context, tx *domain.Transaction) error {
+					updated = tx
+					return nil
+				},
+				ReplaceTagsFn: func(ctx context.Context, transactionID string, tagIDs []string) error {
+					replacedTags = tagIDs
+					return nil
+				},
+			}
+			categoryChecked := false
+			categoryRepo := &mockCategoryRepo{
+				GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Category, error) {
+					categoryChecked = true
+					if id == "cat-x" {
+						return nil, domain.ErrCategoryNotFound
+					}
+					return &domain.Category{ID: id}, nil
+				},
+			}
+			tagRepo := &mockTagRepo{
+				ValidateTagsFn: func(ctx context.Context, tenantID string, tagIDs []string) (bool, error) {
+					return true, nil
+				},
+			}
+
+			s := NewTransactionService(repo, &mockAccountRepo{}, categoryRepo, tagRepo, &mockTransactor{})
+			tx, err := s.Patch(ctx, "tx-1", patchOf(tt.patch), tt.version)
+			if categoryChecked != tt.wantCategory {
+				t.Errorf("category checked = %v, want %v", categoryChecked, tt.wantCategory)
+			}
+			if tt.wantInvalid {
+				var verr *domain.ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("Patch() error = %v, want a validation error", err)
+				}
+				if updated != nil {
+					t.Error("an invalid patch was saved")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Patch() error = %v", err)
+			}
+			if updated == nil || updated.Version != tt.wantVersion || updated.UpdatedBy != "user-1" {
+				t.Errorf("updated = %+v, want version %d by user-1", updated, tt.wantVersion)
+			}
+			if !slices.Equal(replacedTags, tt.wantTags) {
+				t.Errorf("replaced tags = %v, want %v", replacedTags, tt.wantTags)
+			}
+			if tx.Amount.Currency != "BRL" {
+				t.Errorf("amount currency = %q, want BRL", tx.Amount.Currency)
+			}
+		})
+	}
+}
+
+func TestTransactionService_Transfers(t *testing.T) {
+	ctx := context.Background()
+	ctx = domain.WithTenantID(ctx, "tenant-1")
+	ctx = domain.WithUserID(ctx, "user-1")
+
+	accounts := &mockAccountRepo{
+		GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Account, error) {
+			currency := map[string]string{"acc-brl": "BRL", "acc-usd": "USD"}[id]
+			return &domain.Account{ID: id, TenantID: tenantID, Currency: currency, Type: domain.AccountTypeBank}, nil
+		},
+	}
+	rate, _ := domain.ParseExchangeRate("0.2")
+	dueDate := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	newTransfer := func() *domain.Transaction {
+		to := "acc-usd"
+		return &domain.Transaction{
+			FromAccountID:   "acc-brl",
+			ToAccountID:     &to,
+			Amount:          domain.NewMoney(50000, ""),
+			Fee:             domain.NewMoney(300, ""),
+			ExchangeRate:    rate,
+			TransactionType: domain.TransactionTypeTransfer,
+			CategoryID:      "cat-1",
+			DueDate:         dueDate,
+		}
+	}
+	// storedLegs returns a saved BRL -> USD transfer of 500.00 at 0.2, whose incoming leg is paid.
+	storedLegs := func() map[string]*domain.Transaction {
+		out := newTransfer()
+		out.ID, out.TenantID, out.AccrualMonth, out.Version = "out-1", "tenant-1", "202403", 4
+		in, err := out.PrepareTransfer("BRL", "USD")
+		if err != nil {
+			t.Fatalf("PrepareTransfer() error = %v", err)
+		}
+		in.ID, in.Version, in.PaymentDate = "in-1", 7, &dueDate
+		out.LinkedTransactionID = &in.ID
+		return map[string]*domain.Transaction{"out-1": out, "in-1": &in}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		var out, in *domain.Transaction
+		var tags []string
+		repo := &mockRepo{
+			CreateTransferFn: func(ctx context.Context, o, i *domain.Transaction, tagIDs []string) error {
+				out, in, tags = o, i, tagIDs
+				return nil
+			},
+		}
+		s := NewTransactionService(repo, accounts, &mockCategoryRepo{}, &mockTagRepo{}, &mockTransactor{})
+		if err := s.Create(ctx, newTransfer(), []string{"tag-1"}, 0, false); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if out == nil || in == nil {
+			t.Fatal("CreateTransfer was not called")
+		}
+		if out.Amount != domain.NewMoney(50000, "BRL") || out.Fee != domain.NewMoney(300, "BRL") || in.Amount != domain.NewMoney(10000, "USD") {
+			t.Errorf("legs = %v + %v fee -> %v, want 500.00 + 3.00 BRL -> 100.00 USD", out.Amount, out.Fee, in.Amount)
+		}
+		if !slices.Equal(tags, []string{"tag-1"}) {
+			t.Errorf("tags = %v, want both legs tagged with tag-1", tags)
+		}
+	})
+
+	t.Run("create rejects installments", func(t *testing.T) {
+		s := NewTransactionService(&mockRepo{}, accounts, &mockCategoryRepo{}, &mockTagRepo{}, &mockTransactor{})
+		if err := s.Create(ctx, newTransfer(), nil, 3, false); !errors.Is(err, domain.ErrTransferInstallments) {
+			t.Errorf("Create() error = %v, want ErrTransferInstallments", err)
+		}
+	})
+
+	errDB := errors.New("db down")
+	tests := []struct {
+		name    string
+		id      string
+		patch   string
+		tagErr  error
+		wantErr error
+		check   func(t *testing.T, out, in *domain.Transaction)
+	}{
+		{
+			name:  "amount of the outgoing leg converts into the incoming one",
+			id:    "out-1",
+			patch: `{"amount": "600.00"}`,
+			check: func(t *testing.T, out, in *domain.Transaction) {
+				if in.ID != "in-1" || in.Amount != domain.NewMoney(12000, "USD") || in.Version != 7 || in.PaymentDate == nil {
+					t.Errorf("incoming leg = %+v, want in-1 at 120.00 USD, still paid, at version 7", in)
+				}
+			},
+		},
+		{
+			name:  "shared fields of the incoming leg reach the outgoing one",
+			id:    "in-1",
+			patch: `{"comments": "Savings", "payment_date": null}`,
+			check: func(t *testing.T, out, in *domain.Transaction) {
+				if out.Comments == nil || *out.Comments != "Savings" || in.PaymentDate != nil {
+					t.Errorf("outgoing comments = %v, incoming payment date = %v", out.Comments, in.PaymentDate)
+				}
+				if out.Amount != domain.NewMoney(50000, "BRL") || out.Version != 4 {
+					t.Errorf("outgoing leg = %+v, want it unchanged at version 4", out)
+				}
+			},
+		},
+		{
+			name:  "tags of both legs are replaced",
+			id:    "in-1",
+			patch: `{"tag_ids": ["tag-1"]}`,
+			check: func(t *testing.T, out, in *domain.Transaction) {},
+		},
+		{name: "amount of the incoming leg is locked", id: "in-1", patch: `{"amount": "90.00"}`, wantErr: domain.ErrTransferLegLocked},
+		{name: "failed tag update rolls back both legs", id: "out-1", patch: `{"comments": "Savings", "tag_ids": ["tag-1"]}`, tagErr: errDB, wantErr: errDB},
+		{name: "type change", id: "out-1", patch: `{"transaction_type": "debit", "to_account_id": null, "exchange_rate": null, "fee": "0"}`, wantErr: domain.ErrTransferTypeChange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			legs := storedLegs()
+			var out, in *domain.Transaction
+			var replaced []string
+			repo := &mockRepo{
+				GetByIDFn: func(ctx context.Context, tenantID, id string) (*domain.Transaction, error) {
+					if leg, ok := legs[id]; ok {
+						clone := *leg
+						return &clone, nil
+					}
+					return nil, domain.ErrTransactionNotFound
+				},
+				UpdateFn: func(ctx context.Context, tx *domain.Transaction) error {
+					t.Error("a transfer leg was updated on its own")
+					return nil
+				},
+				UpdateTransferFn: func(ctx context.Context, o, i *domain.Transaction) error {
+					out, in = o, i
+					return nil
+				},
+				ReplaceTagsFn: func(ctx context.Context, transactionID string, tagIDs []string) error {
+					replaced = append(replaced, transactionID)
+					return tt.tagErr
+				},
+			}
+			var patch domain.TransactionPatch
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			transactor := &mockTransactor{}
+			s := NewTransactionService(repo, accounts, &mockCategoryRepo{}, &mockTagRepo{}, transactor)
+			_, err := s.Patch(ctx, tt.id, patch, 0)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Patch() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.tagErr == nil && out != nil {
+					t.Error("a rejected patch was saved")
+				}
+				if transactor.committed {
+					t.Error("a rejected patch was committed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Patch() error = %v", err)
+			}
+			if out == nil || out.ID != "out-1" || in == nil || in.ID != "in-1" {
+				t.Fatalf("UpdateTransfer(%v, %v), want out-1 and in-1", out, in)
+			}
+			tt.check(t, out, in)
+			if patch.TagIDs.Set && !slices.Equal(replaced, []string{"in-1", "out-1"}) {
+				t.Errorf("tags replaced on %v, want in-1 and out-1", replaced)
+			}
+		})
+	}
+}
+
+func TestTransactionService_SettleMany(t *testing.T) {
+	ctx := context.Background()
+	ctx = domain.WithTenantID(ctx, "tenant-1")
+	ctx = domain.WithUserID(ctx, "user-1")
+
+	paidDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	paymentDate := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	stored := map[string]domain.Transaction{
+		"tx-1": {ID: "tx-1", TenantID: "tenant-1", FromAccountID: "acc-1", Currency: "BRL", Amount: domain.NewMoney(10000, "BRL"), Version: 1},
+		"tx-2": {ID: "tx-2", TenantID: "tenant-1", FromAccountID: "acc-2", Currency: "BRL", Amount: domain.NewMoney(5000, "BRL"), SettledAmount: domain.NewMoney(2000, "BRL"), Version: 4},
+		"tx-3": {ID: "tx-3", TenantID: "tenant-1", FromAccountID: "acc-1", Currency: "BRL", Amount: domain.NewMoney(700, "BRL"), PaymentDate: &paidDate, Version: 2},
+		"tx-4": {ID: "tx-4", TenantID: "tenant-1", FromAccountID: "acc-usd", Currency: "USD", Amount: domain.NewMoney(700, "USD"), Version: 1},
+	}
+	accounts := map[string]string{"acc-1": "BRL", "acc-2": "BRL", "acc-usd": "USD"}
+
+	tests := []struct {
+		name         string
+		ids          []string
+		accountID    string
+		wantErr      bool
+		wantPayments map[string]int64 // cents per transaction
+		wantAccounts map[string]string
+	}{
+		{
+			name:         "outstanding amounts from each transaction's account, paid ones skipped",
+			ids:          []string{"tx-1", "tx-2", "tx-3"},
+			wantPayments: map[string]int64{"tx-1": 10000, "tx-2": 3000},
+			wantAccounts: map[string]string{"tx-1": "acc-1", "tx-2": "acc-2"},
+		},
+		{
+			name:         "shared account",
+			ids:          []string{"tx-1", "tx-2"},
+			accountID:    "acc-1",
+			wantPayments: map[string]int64{"tx-1": 10000, "tx-2": 3000},
+			wantAccounts: map[string]string{"tx-1": "acc-1", "tx-2": "acc-1"},
+		},
+		{name: "currency mismatch fails the whole batch", ids: []string{"tx-1", "tx-4"}, accountID: "acc-1", wantErr: true},
+		{name: "unknown account", ids: []string{"tx-1"}, accountID: "acc-x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved []domain.TransactionPayment
+			repo := &mockRepo{
+				GetByIDFn: func(ctx context.Context, tenantID, id string) (*domain.Transaction, error) {
+					tx, ok := stored[id]
+					if !ok {
+						return nil, domain.ErrTransactionNotFound
+					}
+					return &tx, nil
+				},
+				AddPaymentsFn: func(ctx context.Context, txs []*domain.Transaction, payments []domain.TransactionPayment) error {
+					if len(txs) != len(payments) {
+						t.Errorf("%d transactions saved for %d payments", len(txs), len(payments))
+					}
+					for _, tx := range txs {
+						if tx.PaymentDate == nil || !tx.PaymentDate.Equal(paymentDate) || tx.Version != stored[tx.ID].Version {
+							t.Errorf("%s saved with payment_date %v at version %d", tx.ID, tx.PaymentDate, tx.Version)
+						}
+					}
+					saved = payments
+					return nil
+				},
+			}
+			accountRepo := &mockAccountRepo{
+				GetByIDFn: func(ctx context.Context, id, tenantID string) (*domain.Account, error) {
+					currency, ok := accounts[id]
+					if !ok {
+						return nil, domain.ErrAccountNotFound
+					}
+					return &domain.Account{ID: id, TenantID: tenantID, Currency: currency}, nil
+				},
+			}
+
+			s := NewTransactionService(repo, accountRepo, &mockCategoryRepo{}, &mockTagRepo{}, &mockTransactor{})
+			txs, err := s.SettleMany(ctx, tt.ids, paymentDate, tt.accountID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("SettleMany() error = nil, want an error")
+				}
+				if saved != nil {
+					t.Error("payments were saved despite the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SettleMany() error = %v", err)
+			}
+			if len(txs) != len(tt.ids) {
+				t.Errorf("got %d transactions, want %d", len(txs), len(tt.ids))
+			}
+			if len(saved) != len(tt.wantPayments) {
+				t.Fatalf("saved %d payments, want %d", len(saved), len(tt.wantPayments))
+			}
+			for _, p := range saved {
+				if p.Amount.Cents != tt.wantPayments[p.TransactionID] || p.AccountID != tt.wantAccounts[p.TransactionID] || p.CreatedBy != "user-1" {
+					t.Errorf("payment = %+v, want %d cents from %s", p, tt.wantPayments[p.TransactionID], tt.wantAccounts[p.TransactionID])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/igoventura/fintrack-api/domain"
+)
+
+// createTransfer creates the outgoing leg t of a transfer and its incoming leg, tagged alike. The
+// incoming leg is in the destination account's currency, at the exchange rate of t.
+func (s *TransactionService) createTransfer(ctx context.Context, t *domain.Transaction, from, to *domain.Account, tagIDs []string) error {
+	in, err := t.PrepareTransfer(from.Currency, to.Currency)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.CreateTransfer(ctx, t, &in, tagIDs); err != nil {
+		return err
+	}
+	return s.attachTags(ctx, t)
+}
+
+// checkTransferType rejects turning a transfer leg into another type, or another type into a transfer.
+func checkTransferType(stored, t *domain.Transaction) error {
+	if stored.IsTransferLeg() != (t.TransactionType == domain.TransactionTypeTransfer) {
+		return domain.ErrTransferTypeChange
+	}
+	return nil
+}
+
+// updateTransfer saves t, an edited leg of the transfer stored as stored, together with its other leg,
+// and returns the ID of the other leg. The fields both legs share are copied onto the other leg.
+// The accounts, amount, exchange rate and fee can only change on the outgoing leg; the incoming leg
+// is then recomputed from them, keeping its own payment state.
+func (s *TransactionService) updateTransfer(ctx context.Context, stored, t *domain.Transaction) (string, error) {
+	other, err := s.repo.GetByID(ctx, t.TenantID, *stored.LinkedTransactionID)
+	if err != nil {
+		return "", fmt.Errorf("service failed to get linked transaction: %w", err)
+	}
+	t.LinkedTransactionID = stored.LinkedTransactionID
+	t.TransferDirection = stored.TransferDirection
+	t.Currency = stored.Currency
+	t.Amount.Currency = stored.Currency
+	t.Fee.Currency = stored.Currency
+
+	out, in := t, other
+	if t.IsIncomingLeg() {
+		if t.ExchangeRate.IsZero() {
+			t.ExchangeRate = stored.ExchangeRate
+		}
+		if !t.SameTransferTerms(stored) {
+			return "", domain.ErrTransferLegLocked
+		}
+		out, in = other, t
+		t.SyncLeg(out)
+		out.UpdatedBy = t.UpdatedBy
+	} else {
+		from, err := s.accountRepo.GetByID(ctx, t.FromAccountID, t.TenantID)
+		if err != nil {
+			return "", referenceError(err, "from_account_id")
+		}
+		to, err := s.accountRepo.GetByID(ctx, *t.ToAccountID, t.TenantID)
+		if err != nil {
+			return "", referenceError(err, "to_account_id")
+		}
+		leg, err := t.PrepareTransfer(from.Currency, to.Currency)
+		if err != nil {
+			return "", err
+		}
+		leg.ID = other.ID
+		leg.PaymentDate = other.PaymentDate
+		leg.SettledAmount = other.SettledAmount.WithCurrency(leg.Currency)
+		leg.CreatedAt = other.CreatedAt
+		leg.CreatedBy = other.CreatedBy
+		leg.Version = other.Version
+		in = &leg
+	}
+
+	for _, leg := range []*domain.Transaction{out, in} {
+		if valid, errs := leg.IsValid(); !valid {
+			return "", domain.InvalidFields(errs)
+		}
+	}
+	if err := s.repo.UpdateTransfer(ctx, out, in); err != nil {
+		return "", err
+	}
+	return other.ID, nil
+}
@@ -41,7 +41,7 @@ func (s *UserService) GetUserBySupabaseID(ctx context.Context, supabaseID string
 
 func (s *UserService) CreateUser(ctx context.Context, user *domain.User) error {
 	if user.Email == "" {
-		return fmt.Errorf("user email is required")
+		return domain.InvalidField("email", "email is required")
 	}
 	// Add other validation as needed
 
@@ -65,8 +65,8 @@ func (s *UserService) DeleteUser(ctx context.Context, id string) error {
 	return nil
 }
 
-func (s *UserService) AddTenantToUser(ctx context.Context, userID, tenantID string) error {
-	if err := s.repo.AddUserToTenant(ctx, userID, tenantID); err != nil {
+func (s *UserService) AddTenantToUser(ctx context.Context, userID, tenantID string, role domain.Role) error {
+	if err := s.repo.AddUserToTenant(ctx, userID, tenantID, role); err != nil {
 		return fmt.Errorf("service failed to add user to tenant: %w", err)
 	}
 	return nil
@@ -86,3 +86,18 @@ func (s *UserService) ListUserTenants(ctx context.Context, userID string) ([]dom
 	}
 	return tenants, nil
 }
+
+func (s *UserService) ListTenantMembers(ctx context.Context, tenantID string) ([]domain.TenantMember, error) {
+	members, err := s.repo.ListTenantMembers(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list tenant members: %w", err)
+	}
+	return members, nil
+}
+
+func (s *UserService) UpdateTenantRole(ctx context.Context, userID, tenantID string, role domain.Role) error {
+	if err := s.repo.UpdateUserTenantRole(ctx, userID, tenantID, role); err != nil {
+		return fmt.Errorf("service failed to update tenant role: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+CREATE TYPE "tenant_role" AS ENUM (
+  'owner',
+  'admin',
+  'editor',
+  'viewer'
+);
+
+-- Existing members created their tenants or joined them with full access, so they become owners.
+ALTER TABLE "users_tenants" ADD COLUMN "role" tenant_role NOT NULL DEFAULT 'owner';
+
+ALTER TABLE "users_tenants" ALTER COLUMN "role" DROP DEFAULT;
+
+---- create above / drop below ----
+
+ALTER TABLE "users_tenants" DROP COLUMN "role";
+DROP TYPE "tenant_role";
@@ -0,0 +1,35 @@
+CREATE TYPE "invitation_status" AS ENUM (
+  'pending',
+  'accepted',
+  'revoked',
+  'expired'
+);
+
+CREATE TABLE "invitations" (
+  "id" UUID PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "tenant_id" UUID NOT NULL,
+  "inviter_user_id" UUID NOT NULL,
+  "email" TEXT NOT NULL,
+  "role" tenant_role NOT NULL,
+  "token_hash" TEXT NOT NULL,
+  "status" invitation_status NOT NULL DEFAULT 'pending',
+  "expires_at" TIMESTAMPTZ NOT NULL,
+  "accepted_by" UUID,
+  "accepted_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP)
+);
+
+CREATE UNIQUE INDEX ON "invitations" ("token_hash");
+
+-- A tenant has at most one pending invitation per email.
+CREATE UNIQUE INDEX ON "invitations" ("tenant_id", lower("email")) WHERE "status" = 'pending';
+
+ALTER TABLE "invitations" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+ALTER TABLE "invitations" ADD FOREIGN KEY ("inviter_user_id") REFERENCES "users" ("id");
+ALTER TABLE "invitations" ADD FOREIGN KEY ("accepted_by") REFERENCES "users" ("id");
+
+---- create above / drop below ----
+
+DROP TABLE "invitations";
+DROP TYPE "invitation_status";
@@ -0,0 +1,12 @@
+-- Keyset pagination indexes, matching the ORDER BY of each list endpoint.
+CREATE INDEX "transactions_tenant_due_date_id_idx" ON "transactions" ("tenant_id", "due_date" DESC, "id" DESC) WHERE "deactivated_at" IS NULL;
+CREATE INDEX "accounts_tenant_name_id_idx" ON "accounts" ("tenant_id", "name", "id") WHERE "deactivated_at" IS NULL;
+CREATE INDEX "categories_tenant_name_id_idx" ON "categories" ("tenant_id", "name", "id") WHERE "deactivated_at" IS NULL;
+CREATE INDEX "tags_tenant_name_id_idx" ON "tags" ("tenant_id", "name", "id") WHERE "deactivated_at" IS NULL;
+
+---- create above / drop below ----
+
+DROP INDEX "tags_tenant_name_id_idx";
+DROP INDEX "categories_tenant_name_id_idx";
+DROP INDEX "accounts_tenant_name_id_idx";
+DROP INDEX "transactions_tenant_due_date_id_idx";
@@ -0,0 +1,31 @@
+-- Installment position within a series, replacing the "[Installment n/N] " comment prefix.
+-- Both columns are NULL for transactions that are not part of an installment series.
+ALTER TABLE "transactions"
+ADD COLUMN "installment_number" SMALLINT,
+ADD COLUMN "installment_count" SMALLINT,
+ADD CONSTRAINT "transactions_installment_check" CHECK (
+    ("installment_number" IS NULL AND "installment_count" IS NULL)
+    OR ("installment_number" BETWEEN 1 AND "installment_count")
+);
+
+-- Backfill from the comment prefix and strip it; comments that held only the prefix become NULL.
+UPDATE "transactions"
+SET "installment_number" = (regexp_match("comments", '^\[Installment (\d+)/(\d+)\]'))[1]::SMALLINT,
+    "installment_count" = (regexp_match("comments", '^\[Installment (\d+)/(\d+)\]'))[2]::SMALLINT,
+    "comments" = NULLIF(regexp_replace("comments", '^\[Installment \d+/\d+\] ?', ''), '')
+WHERE "comments" ~ '^\[Installment \d+/\d+\]';
+
+CREATE INDEX "transactions_parent_transaction_id_idx" ON "transactions" ("parent_transaction_id") WHERE "deactivated_at" IS NULL;
+
+---- create above / drop below ----
+
+DROP INDEX "transactions_parent_transaction_id_idx";
+
+UPDATE "transactions"
+SET "comments" = '[Installment ' || "installment_number" || '/' || "installment_count" || '] ' || COALESCE("comments", '')
+WHERE "installment_number" IS NOT NULL;
+
+ALTER TABLE "transactions"
+DROP CONSTRAINT "transactions_installment_check",
+DROP COLUMN "installment_count",
+DROP COLUMN "installment_number";
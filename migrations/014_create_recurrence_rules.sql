@@ -0,0 +1,82 @@
+CREATE TYPE "recurrence_frequency" AS ENUM (
+  'weekly',
+  'monthly',
+  'yearly'
+);
+
+-- A recurrence rule is a transaction template plus a schedule. Occurrences are materialized
+-- as regular transactions over a rolling horizon; materialized_until is the last date covered.
+CREATE TABLE "recurrence_rules" (
+  "id" UUID PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "tenant_id" UUID NOT NULL,
+  "from_account_id" UUID NOT NULL,
+  "to_account_id" UUID,
+  "category_id" UUID NOT NULL,
+  "transaction_type" transaction_type NOT NULL,
+  "currency" VARCHAR(3) NOT NULL,
+  "amount" NUMERIC(10,2) NOT NULL,
+  "comments" TEXT,
+  "tag_ids" UUID[] NOT NULL DEFAULT '{}',
+  "frequency" recurrence_frequency NOT NULL,
+  "interval_count" SMALLINT NOT NULL DEFAULT 1 CHECK ("interval_count" >= 1),
+  "month_day" SMALLINT CHECK ("month_day" BETWEEN 1 AND 31),
+  "last_business_day" BOOLEAN NOT NULL DEFAULT FALSE,
+  "start_date" DATE NOT NULL,
+  "end_date" DATE,
+  "occurrence_count" INT CHECK ("occurrence_count" >= 1),
+  "materialized_until" DATE,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "created_by" UUID NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "updated_by" UUID NOT NULL,
+  "deactivated_at" TIMESTAMPTZ,
+  "deactivated_by" UUID
+);
+
+-- Amount changes take effect on occurrences from effective_from onward.
+CREATE TABLE "recurrence_amount_changes" (
+  "rule_id" UUID NOT NULL,
+  "effective_from" DATE NOT NULL,
+  "amount" NUMERIC(10,2) NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "created_by" UUID NOT NULL,
+  PRIMARY KEY ("rule_id", "effective_from")
+);
+
+-- Skipped occurrences are never materialized.
+CREATE TABLE "recurrence_skipped_dates" (
+  "rule_id" UUID NOT NULL,
+  "occurrence_date" DATE NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "created_by" UUID NOT NULL,
+  PRIMARY KEY ("rule_id", "occurrence_date")
+);
+
+ALTER TABLE "recurrence_rules" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+ALTER TABLE "recurrence_rules" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "recurrence_rules" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "recurrence_rules" ADD FOREIGN KEY ("category_id") REFERENCES "categories" ("id");
+ALTER TABLE "recurrence_amount_changes" ADD FOREIGN KEY ("rule_id") REFERENCES "recurrence_rules" ("id") ON DELETE CASCADE;
+ALTER TABLE "recurrence_skipped_dates" ADD FOREIGN KEY ("rule_id") REFERENCES "recurrence_rules" ("id") ON DELETE CASCADE;
+
+CREATE INDEX "recurrence_rules_tenant_start_date_id_idx" ON "recurrence_rules" ("tenant_id", "start_date", "id") WHERE "deactivated_at" IS NULL;
+
+-- Materialized occurrences: at most one active transaction per rule and occurrence date.
+ALTER TABLE "transactions"
+ADD COLUMN "recurrence_rule_id" UUID REFERENCES "recurrence_rules" ("id"),
+ADD COLUMN "recurrence_date" DATE;
+
+CREATE UNIQUE INDEX "transactions_recurrence_occurrence_idx" ON "transactions" ("recurrence_rule_id", "recurrence_date") WHERE "deactivated_at" IS NULL;
+
+---- create above / drop below ----
+
+DROP INDEX "transactions_recurrence_occurrence_idx";
+
+ALTER TABLE "transactions"
+DROP COLUMN "recurrence_date",
+DROP COLUMN "recurrence_rule_id";
+
+DROP TABLE "recurrence_skipped_dates";
+DROP TABLE "recurrence_amount_changes";
+DROP TABLE "recurrence_rules";
+DROP TYPE "recurrence_frequency";
@@ -0,0 +1,27 @@
+CREATE TYPE "job_run_status" AS ENUM (
+  'running',
+  'succeeded',
+  'failed'
+);
+
+-- Run history of the background jobs. A scheduled slot is run at most once across replicas:
+-- the first instance to record (job_name, scheduled_at) runs it, the others skip it.
+CREATE TABLE "job_runs" (
+  "id" UUID PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "job_name" VARCHAR(100) NOT NULL,
+  "scheduled_at" TIMESTAMPTZ NOT NULL,
+  "started_at" TIMESTAMPTZ NOT NULL,
+  "finished_at" TIMESTAMPTZ,
+  "status" job_run_status NOT NULL DEFAULT 'running',
+  "summary" TEXT,
+  "error" TEXT,
+  "instance" VARCHAR(255) NOT NULL
+);
+
+CREATE UNIQUE INDEX "job_runs_job_name_scheduled_at_idx" ON "job_runs" ("job_name", "scheduled_at");
+CREATE INDEX "job_runs_job_name_started_at_idx" ON "job_runs" ("job_name", "started_at" DESC);
+
+---- create above / drop below ----
+
+DROP TABLE "job_runs";
+DROP TYPE "job_run_status";
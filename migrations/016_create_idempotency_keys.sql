@@ -0,0 +1,25 @@
+-- Requests sent with an Idempotency-Key header, and their responses once completed.
+-- Keys are scoped to a tenant (NULL for requests outside one, e.g. creating a tenant) and user.
+CREATE TABLE "idempotency_keys" (
+  "id" UUID PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "tenant_id" UUID,
+  "user_id" UUID NOT NULL,
+  "key" VARCHAR(255) NOT NULL,
+  "request_hash" CHAR(64) NOT NULL,
+  "status_code" INT,
+  "content_type" VARCHAR(255),
+  "response_body" BYTEA,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "completed_at" TIMESTAMPTZ,
+  "expires_at" TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX "idempotency_keys_tenant_id_user_id_key_idx" ON "idempotency_keys" ("tenant_id", "user_id", "key") NULLS NOT DISTINCT;
+CREATE INDEX "idempotency_keys_expires_at_idx" ON "idempotency_keys" ("expires_at");
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+---- create above / drop below ----
+
+DROP TABLE "idempotency_keys";
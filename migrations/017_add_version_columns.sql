@@ -0,0 +1,12 @@
+-- Optimistic concurrency: every update increments the version, exposed as the ETag of the resource.
+ALTER TABLE "accounts" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
+ALTER TABLE "categories" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
+ALTER TABLE "tags" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
+ALTER TABLE "transactions" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
+
+---- create above / drop below ----
+
+ALTER TABLE "transactions" DROP COLUMN "version";
+ALTER TABLE "tags" DROP COLUMN "version";
+ALTER TABLE "categories" DROP COLUMN "version";
+ALTER TABLE "accounts" DROP COLUMN "version";
@@ -0,0 +1,31 @@
+-- Partial settlements of a transaction. settled_amount on transactions is the sum of its active
+-- payments, kept by the repository in the same database transaction as the ledger.
+CREATE TABLE "transaction_payments" (
+  "id" UUID PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "tenant_id" UUID NOT NULL,
+  "transaction_id" UUID NOT NULL,
+  "account_id" UUID NOT NULL,
+  "amount" NUMERIC(10,2) NOT NULL CHECK ("amount" > 0),
+  "payment_date" DATE NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "created_by" UUID NOT NULL,
+  "deactivated_at" TIMESTAMPTZ,
+  "deactivated_by" UUID
+);
+
+ALTER TABLE "transaction_payments" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+ALTER TABLE "transaction_payments" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+ALTER TABLE "transaction_payments" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "transaction_payments" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+ALTER TABLE "transaction_payments" ADD FOREIGN KEY ("deactivated_by") REFERENCES "users" ("id");
+
+CREATE INDEX "transaction_payments_transaction_id_idx" ON "transaction_payments" ("transaction_id", "payment_date") WHERE "deactivated_at" IS NULL;
+CREATE INDEX "transaction_payments_tenant_account_idx" ON "transaction_payments" ("tenant_id", "account_id") WHERE "deactivated_at" IS NULL;
+
+ALTER TABLE "transactions" ADD COLUMN "settled_amount" NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+---- create above / drop below ----
+
+ALTER TABLE "transactions" DROP COLUMN "settled_amount";
+
+DROP TABLE "transaction_payments";
@@ -0,0 +1,62 @@
+CREATE TYPE "transfer_direction" AS ENUM (
+  'out',
+  'in'
+);
+
+-- A transfer is stored as two linked legs of type transfer. The outgoing leg debits its
+-- from_account_id (the source) by amount + fee; the incoming leg credits its from_account_id
+-- (the destination) by its own amount, in the destination currency at exchange_rate.
+-- to_account_id of each leg names the account on the other side.
+ALTER TABLE "transactions"
+ADD COLUMN "linked_transaction_id" UUID,
+ADD COLUMN "transfer_direction" transfer_direction,
+ADD COLUMN "exchange_rate" NUMERIC(18,8) CHECK ("exchange_rate" > 0),
+ADD COLUMN "fee" NUMERIC(10,2) NOT NULL DEFAULT 0 CHECK ("fee" >= 0);
+
+ALTER TABLE "transactions" ADD FOREIGN KEY ("linked_transaction_id") REFERENCES "transactions" ("id");
+
+CREATE INDEX "transactions_linked_transaction_id_idx" ON "transactions" ("linked_transaction_id") WHERE "linked_transaction_id" IS NOT NULL;
+
+-- Both legs of a materialized transfer carry its rule and occurrence date; the outgoing one is the occurrence.
+DROP INDEX "transactions_recurrence_occurrence_idx";
+
+-- Existing transfers become outgoing legs and get their incoming leg, in the same currency.
+UPDATE "transactions" SET "transfer_direction" = 'out', "exchange_rate" = 1
+WHERE "transaction_type" = 'transfer' AND "to_account_id" IS NOT NULL;
+
+INSERT INTO "transactions" ("tenant_id", "from_account_id", "to_account_id", "currency", "amount", "accrual_month", "transaction_type", "category_id", "comments", "due_date", "payment_date", "recurrence_rule_id", "recurrence_date", "created_at", "created_by", "updated_at", "updated_by", "deactivated_at", "deactivated_by", "linked_transaction_id", "transfer_direction", "exchange_rate")
+SELECT "tenant_id", "to_account_id", "from_account_id", "currency", "amount", "accrual_month", "transaction_type", "category_id", "comments", "due_date", "payment_date", "recurrence_rule_id", "recurrence_date", "created_at", "created_by", "updated_at", "updated_by", "deactivated_at", "deactivated_by", "id", 'in', 1
+FROM "transactions"
+WHERE "transfer_direction" = 'out';
+
+UPDATE "transactions" o SET "linked_transaction_id" = i."id"
+FROM "transactions" i
+WHERE i."linked_transaction_id" = o."id" AND i."transfer_direction" = 'in';
+
+INSERT INTO "transactions_tags" ("transaction_id", "tag_id")
+SELECT i."id", tt."tag_id"
+FROM "transactions" i
+JOIN "transactions_tags" tt ON tt."transaction_id" = i."linked_transaction_id"
+WHERE i."transfer_direction" = 'in';
+
+CREATE UNIQUE INDEX "transactions_recurrence_occurrence_idx" ON "transactions" ("recurrence_rule_id", "recurrence_date") WHERE "deactivated_at" IS NULL AND "transfer_direction" IS DISTINCT FROM 'in';
+
+---- create above / drop below ----
+
+DROP INDEX "transactions_recurrence_occurrence_idx";
+
+UPDATE "transactions" SET "linked_transaction_id" = NULL WHERE "transfer_direction" = 'out';
+DELETE FROM "transactions_tags" WHERE "transaction_id" IN (SELECT "id" FROM "transactions" WHERE "transfer_direction" = 'in');
+DELETE FROM "transaction_attachments" WHERE "transaction_id" IN (SELECT "id" FROM "transactions" WHERE "transfer_direction" = 'in');
+DELETE FROM "transaction_payments" WHERE "transaction_id" IN (SELECT "id" FROM "transactions" WHERE "transfer_direction" = 'in');
+DELETE FROM "transactions" WHERE "transfer_direction" = 'in';
+
+ALTER TABLE "transactions"
+DROP COLUMN "fee",
+DROP COLUMN "exchange_rate",
+DROP COLUMN "transfer_direction",
+DROP COLUMN "linked_transaction_id";
+
+DROP TYPE "transfer_direction";
+
+CREATE UNIQUE INDEX "transactions_recurrence_occurrence_idx" ON "transactions" ("recurrence_rule_id", "recurrence_date") WHERE "deactivated_at" IS NULL;
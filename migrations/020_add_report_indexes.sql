@@ -0,0 +1,6 @@
+-- Reports aggregate a tenant's transactions over a range of accrual months.
+CREATE INDEX "transactions_tenant_accrual_month_idx" ON "transactions" ("tenant_id", "accrual_month") WHERE "deactivated_at" IS NULL;
+
+---- create above / drop below ----
+
+DROP INDEX "transactions_tenant_accrual_month_idx";
@@ -0,0 +1,40 @@
+-- A budget caps the spend of an expense category (with its subcategories) or of a tag in one
+-- accrual month, or in every month from month on when recurring (until end_month, if set).
+-- With rollover, what a recurring budget left unspent, or overspent, carries into the next month.
+CREATE TABLE "budgets" (
+  "id" UUID PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "tenant_id" UUID NOT NULL,
+  "category_id" UUID,
+  "tag_id" UUID,
+  "month" VARCHAR(6) NOT NULL,
+  "end_month" VARCHAR(6),
+  "recurring" BOOLEAN NOT NULL DEFAULT FALSE,
+  "currency" VARCHAR(3) NOT NULL,
+  "amount" NUMERIC(10,2) NOT NULL CHECK ("amount" > 0),
+  "rollover" BOOLEAN NOT NULL DEFAULT FALSE,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "created_by" UUID NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "updated_by" UUID NOT NULL,
+  "version" INT NOT NULL DEFAULT 1,
+  "deactivated_at" TIMESTAMPTZ,
+  "deactivated_by" UUID,
+  CHECK (("category_id" IS NULL) <> ("tag_id" IS NULL)),
+  CHECK ("recurring" OR ("end_month" IS NULL AND NOT "rollover")),
+  CHECK ("end_month" IS NULL OR "end_month" >= "month")
+);
+
+COMMENT ON COLUMN "budgets"."month" IS 'Year and month (eg.: YYYYMM); the first month of a recurring budget';
+
+ALTER TABLE "budgets" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+ALTER TABLE "budgets" ADD FOREIGN KEY ("category_id") REFERENCES "categories" ("id");
+ALTER TABLE "budgets" ADD FOREIGN KEY ("tag_id") REFERENCES "tags" ("id");
+ALTER TABLE "budgets" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+ALTER TABLE "budgets" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("id");
+ALTER TABLE "budgets" ADD FOREIGN KEY ("deactivated_by") REFERENCES "users" ("id");
+
+CREATE INDEX "budgets_tenant_month_id_idx" ON "budgets" ("tenant_id", "month", "id") WHERE "deactivated_at" IS NULL;
+
+---- create above / drop below ----
+
+DROP TABLE "budgets";
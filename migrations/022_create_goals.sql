@@ -0,0 +1,40 @@
+-- A savings goal tracks the money set aside towards a target amount, either in the accounts linked
+-- in goals_accounts (their balance is the amount saved) or in the transactions tagged with tag_id.
+CREATE TABLE "goals" (
+  "id" UUID PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "tenant_id" UUID NOT NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "currency" VARCHAR(3) NOT NULL,
+  "target_amount" NUMERIC(10,2) NOT NULL CHECK ("target_amount" > 0),
+  "target_date" DATE,
+  "tag_id" UUID,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "created_by" UUID NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "updated_by" UUID NOT NULL,
+  "version" INT NOT NULL DEFAULT 1,
+  "deactivated_at" TIMESTAMPTZ,
+  "deactivated_by" UUID
+);
+
+CREATE TABLE "goals_accounts" (
+  "goal_id" UUID NOT NULL,
+  "account_id" UUID NOT NULL,
+  PRIMARY KEY ("goal_id", "account_id")
+);
+
+ALTER TABLE "goals" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+ALTER TABLE "goals" ADD FOREIGN KEY ("tag_id") REFERENCES "tags" ("id");
+ALTER TABLE "goals" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+ALTER TABLE "goals" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("id");
+ALTER TABLE "goals" ADD FOREIGN KEY ("deactivated_by") REFERENCES "users" ("id");
+
+ALTER TABLE "goals_accounts" ADD FOREIGN KEY ("goal_id") REFERENCES "goals" ("id");
+ALTER TABLE "goals_accounts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX "goals_tenant_name_id_idx" ON "goals" ("tenant_id", "name", "id") WHERE "deactivated_at" IS NULL;
+
+---- create above / drop below ----
+
+DROP TABLE "goals_accounts";
+DROP TABLE "goals";
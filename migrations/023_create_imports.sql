@@ -0,0 +1,98 @@
+CREATE TYPE "import_sign_convention" AS ENUM (
+  'negative_debit',
+  'positive_debit',
+  'split_columns'
+);
+
+CREATE TYPE "import_status" AS ENUM (
+  'staged',
+  'committed'
+);
+
+-- How the CSV statements of a bank are read: "columns" maps each field (date, description, amount,
+-- debit, credit) to a header name or a 1-based column number.
+CREATE TABLE "import_profiles" (
+  "id" UUID PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "tenant_id" UUID NOT NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "delimiter" VARCHAR(1) NOT NULL DEFAULT ',',
+  "has_header" BOOLEAN NOT NULL DEFAULT TRUE,
+  "skip_rows" INT NOT NULL DEFAULT 0 CHECK ("skip_rows" >= 0),
+  "date_format" VARCHAR(32) NOT NULL,
+  "decimal_separator" VARCHAR(1) NOT NULL DEFAULT '.',
+  "sign_convention" import_sign_convention NOT NULL DEFAULT 'negative_debit',
+  "columns" JSONB NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "created_by" UUID NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "updated_by" UUID NOT NULL,
+  "version" INT NOT NULL DEFAULT 1,
+  "deactivated_at" TIMESTAMPTZ,
+  "deactivated_by" UUID
+);
+
+-- A CSV file parsed into rows for review; committing creates the transactions of the selected rows.
+CREATE TABLE "imports" (
+  "id" UUID PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "tenant_id" UUID NOT NULL,
+  "account_id" UUID NOT NULL,
+  "profile_id" UUID NOT NULL,
+  "file_name" VARCHAR(255) NOT NULL,
+  "currency" VARCHAR(3) NOT NULL,
+  "status" import_status NOT NULL DEFAULT 'staged',
+  "committed_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "created_by" UUID NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "updated_by" UUID NOT NULL,
+  "deactivated_at" TIMESTAMPTZ,
+  "deactivated_by" UUID
+);
+
+-- Rows that could not be parsed keep their error, with no date or type.
+CREATE TABLE "import_rows" (
+  "id" UUID PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "import_id" UUID NOT NULL,
+  "line" INT NOT NULL,
+  "date" DATE,
+  "description" TEXT NOT NULL DEFAULT '',
+  "amount" NUMERIC(10,2) NOT NULL DEFAULT 0,
+  "transaction_type" transaction_type,
+  "error" TEXT,
+  "duplicate_transaction_id" UUID,
+  "suggested_category_id" UUID,
+  "transaction_id" UUID,
+  CHECK ("transaction_type" IS NULL OR "transaction_type" IN ('credit', 'debit'))
+);
+
+COMMENT ON COLUMN "import_rows"."line" IS 'Line of the row in the file, from 1';
+COMMENT ON COLUMN "import_rows"."transaction_id" IS 'Transaction created from the row on commit';
+
+ALTER TABLE "import_profiles" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+ALTER TABLE "import_profiles" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+ALTER TABLE "import_profiles" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("id");
+ALTER TABLE "import_profiles" ADD FOREIGN KEY ("deactivated_by") REFERENCES "users" ("id");
+
+ALTER TABLE "imports" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+ALTER TABLE "imports" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "imports" ADD FOREIGN KEY ("profile_id") REFERENCES "import_profiles" ("id");
+ALTER TABLE "imports" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+ALTER TABLE "imports" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("id");
+ALTER TABLE "imports" ADD FOREIGN KEY ("deactivated_by") REFERENCES "users" ("id");
+
+ALTER TABLE "import_rows" ADD FOREIGN KEY ("import_id") REFERENCES "imports" ("id");
+ALTER TABLE "import_rows" ADD FOREIGN KEY ("duplicate_transaction_id") REFERENCES "transactions" ("id");
+ALTER TABLE "import_rows" ADD FOREIGN KEY ("suggested_category_id") REFERENCES "categories" ("id");
+ALTER TABLE "import_rows" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+
+CREATE INDEX "import_profiles_tenant_name_id_idx" ON "import_profiles" ("tenant_id", "name", "id") WHERE "deactivated_at" IS NULL;
+CREATE INDEX "import_rows_import_id_line_idx" ON "import_rows" ("import_id", "line");
+
+---- create above / drop below ----
+
+DROP TABLE "import_rows";
+DROP TABLE "imports";
+DROP TABLE "import_profiles";
+
+DROP TYPE "import_status";
+DROP TYPE "import_sign_convention";
@@ -0,0 +1,37 @@
+CREATE TYPE "import_format" AS ENUM (
+  'csv',
+  'ofx'
+);
+
+-- OFX files are read without a profile and report the balance of the account at the institution.
+ALTER TABLE "imports"
+ALTER COLUMN "profile_id" DROP NOT NULL,
+ADD COLUMN "format" import_format NOT NULL DEFAULT 'csv',
+ADD COLUMN "already_imported" INT NOT NULL DEFAULT 0,
+ADD COLUMN "ledger_balance" NUMERIC(10,2),
+ADD COLUMN "ledger_balance_date" DATE;
+
+COMMENT ON COLUMN "imports"."already_imported" IS 'Transactions of the file left out because they were imported before';
+
+-- FITID of OFX transactions; a committed row with the same one on the account marks it as imported.
+ALTER TABLE "import_rows" ADD COLUMN "external_id" VARCHAR(255);
+
+CREATE INDEX "import_rows_external_id_idx" ON "import_rows" ("external_id") WHERE "external_id" IS NOT NULL AND "transaction_id" IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX "import_rows_external_id_idx";
+
+ALTER TABLE "import_rows" DROP COLUMN "external_id";
+
+DELETE FROM "import_rows" WHERE "import_id" IN (SELECT "id" FROM "imports" WHERE "format" = 'ofx');
+DELETE FROM "imports" WHERE "format" = 'ofx';
+
+ALTER TABLE "imports"
+DROP COLUMN "ledger_balance_date",
+DROP COLUMN "ledger_balance",
+DROP COLUMN "already_imported",
+DROP COLUMN "format",
+ALTER COLUMN "profile_id" SET NOT NULL;
+
+DROP TYPE "import_format";
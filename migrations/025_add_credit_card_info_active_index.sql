@@ -0,0 +1,17 @@
+-- Concurrent updates of a card could each leave an active version, as NULL deactivated_at values are
+-- distinct under the (account_id, deactivated_at) unique index. Keep only the latest one before enforcing it.
+WITH versions AS (
+  SELECT "id", ROW_NUMBER() OVER (PARTITION BY "account_id" ORDER BY "created_at" DESC, "id" DESC) AS "n"
+  FROM "credit_card_info"
+  WHERE "deactivated_at" IS NULL
+)
+UPDATE "credit_card_info" c
+SET "deactivated_at" = CURRENT_TIMESTAMP - v."n" * INTERVAL '1 microsecond', "deactivated_by" = c."updated_by"
+FROM versions v
+WHERE c."id" = v."id" AND v."n" > 1;
+
+CREATE UNIQUE INDEX "credit_card_info_active_account_idx" ON "credit_card_info" ("account_id") WHERE "deactivated_at" IS NULL;
+
+---- create above / drop below ----
+
+DROP INDEX "credit_card_info_active_account_idx";
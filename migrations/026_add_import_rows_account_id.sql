@@ -0,0 +1,31 @@
+-- Account a committed row was imported into, so that an external ID (FITID) can be committed only
+-- once per account. It is cleared when the row's transaction was deleted and the external ID is
+-- imported again.
+ALTER TABLE "import_rows" ADD COLUMN "account_id" UUID;
+
+ALTER TABLE "import_rows" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+-- Committed rows with an active transaction; if an external ID was committed twice on an account,
+-- the most recent transaction keeps it, like the check on commit did.
+UPDATE "import_rows" r SET "account_id" = c."account_id"
+FROM (
+  SELECT DISTINCT ON (i."account_id", r."external_id") r."id", i."account_id"
+  FROM "import_rows" r
+  JOIN "imports" i ON i."id" = r."import_id"
+  JOIN "transactions" t ON t."id" = r."transaction_id"
+  WHERE r."external_id" IS NOT NULL AND i."deactivated_at" IS NULL AND t."deactivated_at" IS NULL
+  ORDER BY i."account_id", r."external_id", t."created_at" DESC
+) c
+WHERE r."id" = c."id";
+
+DROP INDEX "import_rows_external_id_idx";
+
+CREATE UNIQUE INDEX "import_rows_account_id_external_id_idx" ON "import_rows" ("account_id", "external_id") WHERE "transaction_id" IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX "import_rows_account_id_external_id_idx";
+
+CREATE INDEX "import_rows_external_id_idx" ON "import_rows" ("external_id") WHERE "external_id" IS NOT NULL AND "transaction_id" IS NOT NULL;
+
+ALTER TABLE "import_rows" DROP COLUMN "account_id";